@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/admin"
 	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
 	"github.com/armory/spinnaker-operator/pkg/apis"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
@@ -21,5 +22,6 @@ func main() {
 	spinnakeraccount.TypesFactory = interfaces.DefaultTypesFactory
 	accounts.TypesFactory = interfaces.DefaultTypesFactory
 	kubernetes.TypesFactory = interfaces.DefaultTypesFactory
+	admin.TypesFactory = interfaces.DefaultTypesFactory
 	operator.Start(apis.AddToScheme)
 }
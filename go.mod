@@ -1,7 +1,9 @@
 module github.com/armory/spinnaker-operator
 
 require (
+	cloud.google.com/go/storage v1.1.1
 	github.com/armory/go-yaml-tools v0.0.0-20200316192928-75770481ad01
+	github.com/aws/aws-sdk-go v1.28.9
 	github.com/cenkalti/backoff/v4 v4.0.2
 	github.com/emicklei/go-restful v2.9.3+incompatible // indirect
 	github.com/evanphx/json-patch v4.5.0+incompatible
@@ -16,18 +18,21 @@ require (
 	github.com/mitchellh/mapstructure v1.1.2
 	github.com/onsi/ginkgo v1.10.1 // indirect
 	github.com/onsi/gomega v1.7.0 // indirect
+	github.com/open-policy-agent/opa v0.21.1
 	github.com/openshift/origin v0.0.0-20160503220234-8f127d736703
 	github.com/operator-framework/operator-sdk v0.10.1-0.20190926170752-c0a7fc360048
 	github.com/pkg/errors v0.8.1
-	github.com/prometheus/client_golang v1.1.0 // indirect
+	github.com/prometheus/client_golang v1.1.0
 	github.com/prometheus/client_model v0.0.0-20190812154241-14fe0d1b01d4 // indirect
 	github.com/prometheus/procfs v0.0.4 // indirect
 	github.com/spf13/pflag v1.0.3
 	github.com/stretchr/testify v1.4.0
 	go.uber.org/atomic v1.4.0 // indirect
 	golang.org/x/crypto v0.0.0-20190911031432-227b76d455e7 // indirect
+	golang.org/x/net v0.0.0-20191007182048-72f939374954
 	golang.org/x/sys v0.0.0-20190912141932-bc967efca4b8 // indirect
 	gomodules.xyz/jsonpatch/v2 v2.0.1
+	google.golang.org/api v0.11.0
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 	gopkg.in/yaml.v2 v2.2.2
 	k8s.io/api v0.0.0-20190918155943-95b840bb6a1f
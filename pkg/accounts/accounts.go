@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/accounts/cloudfoundry"
+	"github.com/armory/spinnaker-operator/pkg/accounts/dockerregistry"
 	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -27,7 +29,7 @@ func Register(accountTypes ...account.SpinnakerAccountType) {
 }
 
 func init() {
-	Register(&kubernetes.AccountType{})
+	Register(&kubernetes.AccountType{}, &cloudfoundry.AccountType{}, &dockerregistry.AccountType{})
 }
 
 func GetType(tp interfaces.AccountType) (account.SpinnakerAccountType, error) {
@@ -0,0 +1,122 @@
+package cloudfoundry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CloudFoundry accounts carry all their configuration as free form settings,
+// the CRD simply requires the API endpoint and credentials to be present.
+const (
+	ApiSettings      = "api"
+	UserSettings     = "user"
+	PasswordSettings = "password"
+)
+
+type AccountType struct{}
+
+func (c *AccountType) GetType() interfaces.AccountType {
+	return interfaces.CloudFoundryAccountType
+}
+
+func (c *AccountType) GetAccountsKey() string {
+	return "cloudfoundry.accounts"
+}
+
+func (c *AccountType) GetConfigAccountsKey() string {
+	return "providers.cloudfoundry.accounts"
+}
+
+func (c *AccountType) GetServices() []string {
+	return []string{"clouddriver"}
+}
+
+func (c *AccountType) newAccount() *Account {
+	return &Account{}
+}
+
+func (c *AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Providers {
+		if strings.ToLower(n) == strings.ToLower(string(interfaces.CloudFoundryAccountType)) {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+func (c *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := c.newAccount()
+	a.Name = account.GetName()
+	a.Settings = account.GetSpec().Settings
+	return a, a.validateFormat()
+}
+
+func (c *AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := c.newAccount()
+	n, ok := settings["name"]
+	if !ok {
+		return nil, fmt.Errorf("%s account missing name", a.GetType())
+	}
+	if name, ok := n.(string); ok {
+		a.Name = name
+	} else {
+		return nil, fmt.Errorf("name is not a string")
+	}
+	a.Settings = settings
+	return a, nil
+}
+
+type Account struct {
+	*account.BaseAccount
+	Name     string              `json:"name,omitempty"`
+	Settings interfaces.FreeForm `json:"settings,omitempty"`
+}
+
+func (a *Account) GetType() interfaces.AccountType {
+	return interfaces.CloudFoundryAccountType
+}
+
+func (a *Account) GetName() string {
+	return a.Name
+}
+
+func (a *Account) GetSettings() *interfaces.FreeForm {
+	return &a.Settings
+}
+
+func (a *Account) validateFormat() error {
+	if a.Name == "" {
+		return errors.New("Spinnaker account must have a name")
+	}
+	return nil
+}
+
+func (a *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	return a.BaseAccount.BaseToSpinnakerSettings(a), nil
+}
+
+func (a *Account) NewValidator() account.AccountValidator {
+	return &cloudFoundryAccountValidator{account: a}
+}
+
+type cloudFoundryAccountValidator struct {
+	account *Account
+}
+
+func (v *cloudFoundryAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	for _, s := range []string{ApiSettings, UserSettings, PasswordSettings} {
+		val, ok := v.account.Settings[s]
+		if !ok || val == "" {
+			return fmt.Errorf("cloudfoundry account \"%s\" missing required setting \"%s\"", v.account.Name, s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package cloudfoundry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+var TypesFactory = test.TypesFactory
+
+func TestFromCRD(t *testing.T) {
+	manifest := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: cf-prod
+spec:
+  type: CloudFoundry
+  settings:
+    api: api.sys.cf.example.com
+    user: spinnaker
+    password: secret
+`
+	sa := TypesFactory.NewAccount()
+	if !assert.Nil(t, yaml.Unmarshal([]byte(manifest), sa)) {
+		return
+	}
+	sa.SetName("cf-prod")
+	c := &AccountType{}
+	a, err := c.FromCRD(sa)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "cf-prod", a.GetName())
+		s, err := a.ToSpinnakerSettings(context.TODO())
+		if assert.Nil(t, err) {
+			assert.Equal(t, "cf-prod", s["name"])
+			assert.Equal(t, "api.sys.cf.example.com", s[ApiSettings])
+		}
+	}
+}
+
+func TestFromSpinnakerConfig(t *testing.T) {
+	c := &AccountType{}
+	a, err := c.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name": "cf1",
+		"api":  "api.sys.cf.example.com",
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "cf1", a.GetName())
+	}
+	_, err = c.FromSpinnakerConfig(context.TODO(), map[string]interface{}{"api": "api.sys.cf.example.com"})
+	assert.NotNil(t, err)
+}
+
+func TestValidate_missingCredentials(t *testing.T) {
+	a := &Account{Name: "cf1", Settings: map[string]interface{}{ApiSettings: "api.sys.cf.example.com"}}
+	err := a.NewValidator().Validate(nil, nil, context.TODO(), nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "missing required setting \"user\"")
+	}
+}
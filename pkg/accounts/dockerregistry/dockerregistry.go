@@ -0,0 +1,118 @@
+package dockerregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Docker registry accounts carry all their configuration as free form settings,
+// the CRD simply requires the registry address to be present.
+const (
+	AddressSettings = "address"
+)
+
+type AccountType struct{}
+
+func (d *AccountType) GetType() interfaces.AccountType {
+	return interfaces.DockerRegistryAccountType
+}
+
+func (d *AccountType) GetAccountsKey() string {
+	return "dockerRegistry.accounts"
+}
+
+func (d *AccountType) GetConfigAccountsKey() string {
+	return "providers.dockerRegistry.accounts"
+}
+
+func (d *AccountType) GetServices() []string {
+	return []string{"clouddriver"}
+}
+
+func (d *AccountType) newAccount() *Account {
+	return &Account{}
+}
+
+func (d *AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Providers {
+		if strings.ToLower(n) == strings.ToLower(string(interfaces.DockerRegistryAccountType)) {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+func (d *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := d.newAccount()
+	a.Name = account.GetName()
+	a.Settings = account.GetSpec().Settings
+	return a, a.validateFormat()
+}
+
+func (d *AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := d.newAccount()
+	n, ok := settings["name"]
+	if !ok {
+		return nil, fmt.Errorf("%s account missing name", a.GetType())
+	}
+	if name, ok := n.(string); ok {
+		a.Name = name
+	} else {
+		return nil, fmt.Errorf("name is not a string")
+	}
+	a.Settings = settings
+	return a, nil
+}
+
+type Account struct {
+	*account.BaseAccount
+	Name     string              `json:"name,omitempty"`
+	Settings interfaces.FreeForm `json:"settings,omitempty"`
+}
+
+func (a *Account) GetType() interfaces.AccountType {
+	return interfaces.DockerRegistryAccountType
+}
+
+func (a *Account) GetName() string {
+	return a.Name
+}
+
+func (a *Account) GetSettings() *interfaces.FreeForm {
+	return &a.Settings
+}
+
+func (a *Account) validateFormat() error {
+	if a.Name == "" {
+		return errors.New("Spinnaker account must have a name")
+	}
+	return nil
+}
+
+func (a *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	return a.BaseAccount.BaseToSpinnakerSettings(a), nil
+}
+
+func (a *Account) NewValidator() account.AccountValidator {
+	return &dockerRegistryAccountValidator{account: a}
+}
+
+type dockerRegistryAccountValidator struct {
+	account *Account
+}
+
+func (v *dockerRegistryAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	val, ok := v.account.Settings[AddressSettings]
+	if !ok || val == "" {
+		return fmt.Errorf("docker registry account \"%s\" missing required setting \"%s\"", v.account.Name, AddressSettings)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package dockerregistry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+var TypesFactory = test.TypesFactory
+
+func TestFromCRD(t *testing.T) {
+	manifest := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: dockerhub
+spec:
+  type: DockerRegistry
+  settings:
+    address: index.docker.io
+    repositories:
+    - library/nginx
+`
+	sa := TypesFactory.NewAccount()
+	if !assert.Nil(t, yaml.Unmarshal([]byte(manifest), sa)) {
+		return
+	}
+	sa.SetName("dockerhub")
+	d := &AccountType{}
+	a, err := d.FromCRD(sa)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "dockerhub", a.GetName())
+		s, err := a.ToSpinnakerSettings(context.TODO())
+		if assert.Nil(t, err) {
+			assert.Equal(t, "index.docker.io", s[AddressSettings])
+		}
+	}
+}
+
+func TestFromSpinnakerConfig(t *testing.T) {
+	d := &AccountType{}
+	a, err := d.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name":    "dockerhub",
+		"address": "index.docker.io",
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "dockerhub", a.GetName())
+	}
+	_, err = d.FromSpinnakerConfig(context.TODO(), map[string]interface{}{"address": "index.docker.io"})
+	assert.NotNil(t, err)
+}
+
+func TestValidate_missingAddress(t *testing.T) {
+	a := &Account{Name: "dockerhub", Settings: map[string]interface{}{}}
+	err := a.NewValidator().Validate(nil, nil, context.TODO(), nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "missing required setting \"address\"")
+	}
+}
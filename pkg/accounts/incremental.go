@@ -0,0 +1,68 @@
+package accounts
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+)
+
+const (
+	// IncrementalSyncThresholdEnvVar overrides the account count above which
+	// the controller syncs add/remove deltas instead of regenerating the
+	// complete account list
+	IncrementalSyncThresholdEnvVar  = "ACCOUNT_INCREMENTAL_SYNC_THRESHOLD"
+	defaultIncrementalSyncThreshold = 500
+)
+
+// IncrementalSyncThreshold returns the account count at which incremental
+// sync kicks in
+func IncrementalSyncThreshold() int {
+	if v := os.Getenv(IncrementalSyncThresholdEnvVar); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return defaultIncrementalSyncThreshold
+}
+
+// CountAccounts returns how many accounts of the given type the previously
+// generated settings hold
+func CountAccounts(settings map[string]interface{}, accountType account.SpinnakerAccountType) int {
+	existing, err := inspect.GetObjectArray(settings, accountType.GetAccountsKey())
+	if err != nil {
+		return 0
+	}
+	return len(existing)
+}
+
+// UpsertAccountSettings applies a single account delta to previously generated
+// service settings, returning whether the settings changed. Unchanged accounts
+// do not trigger a write, which keeps large fleets from churning the dynamic
+// config secret on every reconcile.
+func UpsertAccountSettings(settings map[string]interface{}, accountType account.SpinnakerAccountType, accountSettings map[string]interface{}, name string) (bool, error) {
+	existing, err := inspect.GetObjectArray(settings, accountType.GetAccountsKey())
+	if err == nil {
+		for _, e := range existing {
+			if e["name"] == name && reflect.DeepEqual(e, accountSettings) {
+				return false, nil
+			}
+		}
+	}
+	err = inspect.UpsertInSlice(settings, accountType.GetAccountsKey(), accountSettings, func(elem interface{}) bool {
+		m, ok := elem.(map[string]interface{})
+		return ok && m["name"] == name
+	})
+	return err == nil, err
+}
+
+// RemoveAccountSettings removes a single account from previously generated
+// service settings, returning whether the settings changed
+func RemoveAccountSettings(settings map[string]interface{}, accountType account.SpinnakerAccountType, name string) (bool, error) {
+	return inspect.RemoveFromSlice(settings, accountType.GetAccountsKey(), func(elem interface{}) bool {
+		m, ok := elem.(map[string]interface{})
+		return ok && m["name"] == name
+	})
+}
@@ -0,0 +1,75 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/stretchr/testify/assert"
+)
+
+func incrementalSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"kubernetes": map[string]interface{}{
+			"accounts": []interface{}{
+				map[string]interface{}{"name": "account1", "kubeconfigFile": "/tmp/kubeconfig-1.yml"},
+				map[string]interface{}{"name": "account2", "kubeconfigFile": "/tmp/kubeconfig-2.yml"},
+			},
+		},
+	}
+}
+
+func TestUpsertAccountSettings_addsNewAccount(t *testing.T) {
+	ss := incrementalSettings()
+	changed, err := UpsertAccountSettings(ss, &kubernetes.AccountType{}, map[string]interface{}{
+		"name": "account3", "kubeconfigFile": "/tmp/kubeconfig-3.yml",
+	}, "account3")
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	accts, err := inspect.GetObjectArray(ss, "kubernetes.accounts")
+	assert.Nil(t, err)
+	assert.Len(t, accts, 3)
+	assert.Equal(t, 3, CountAccounts(ss, &kubernetes.AccountType{}))
+}
+
+func TestUpsertAccountSettings_replacesExistingAccount(t *testing.T) {
+	ss := incrementalSettings()
+	changed, err := UpsertAccountSettings(ss, &kubernetes.AccountType{}, map[string]interface{}{
+		"name": "account2", "kubeconfigFile": "/tmp/kubeconfig-2b.yml",
+	}, "account2")
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	accts, err := inspect.GetObjectArray(ss, "kubernetes.accounts")
+	assert.Nil(t, err)
+	if assert.Len(t, accts, 2) {
+		assert.Equal(t, "/tmp/kubeconfig-2b.yml", accts[1]["kubeconfigFile"])
+	}
+}
+
+func TestUpsertAccountSettings_unchangedAccountSkipsWrite(t *testing.T) {
+	ss := incrementalSettings()
+	changed, err := UpsertAccountSettings(ss, &kubernetes.AccountType{}, map[string]interface{}{
+		"name": "account1", "kubeconfigFile": "/tmp/kubeconfig-1.yml",
+	}, "account1")
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
+
+func TestRemoveAccountSettings(t *testing.T) {
+	ss := incrementalSettings()
+	changed, err := RemoveAccountSettings(ss, &kubernetes.AccountType{}, "account1")
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	accts, err := inspect.GetObjectArray(ss, "kubernetes.accounts")
+	assert.Nil(t, err)
+	if assert.Len(t, accts, 1) {
+		assert.Equal(t, "account2", accts[0]["name"])
+	}
+
+	changed, err = RemoveAccountSettings(ss, &kubernetes.AccountType{}, "unknown")
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
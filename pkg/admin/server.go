@@ -0,0 +1,164 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/changefeed"
+	"github.com/armory/spinnaker-operator/pkg/chaos"
+	"github.com/armory/spinnaker-operator/pkg/kleat"
+	"github.com/armory/spinnaker-operator/pkg/provenance"
+	"github.com/armory/spinnaker-operator/pkg/topology"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.Log.WithName("admin")
+var TypesFactory interfaces.TypesFactory
+
+// Server exposes read-only admin endpoints, currently the config compare API.
+// It runs with the manager so it shares its cache-backed client.
+type Server struct {
+	Port   int32
+	Client client.Client
+}
+
+// Start implements manager.Runnable
+func (s *Server) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/compare", s.compareHandler)
+	mux.HandleFunc("/v1/kleat", s.kleatHandler)
+	mux.HandleFunc("/v1/provenance", s.provenanceHandler)
+	mux.HandleFunc("/v1/changefeed", s.changefeedHandler)
+	mux.HandleFunc("/v1/topology", s.topologyHandler)
+	// Failure injection endpoints, only present in builds with the chaos tag
+	chaos.Register(mux)
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", s.Port), Handler: mux}
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}()
+	log.Info(fmt.Sprintf("admin server listening on port %d", s.Port))
+	err := srv.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// compareHandler diffs the configs of two SpinnakerServices referenced as
+// "namespace/name" in the left and right query parameters
+func (s *Server) compareHandler(w http.ResponseWriter, req *http.Request) {
+	left, err := s.getService(req.Context(), req.URL.Query().Get("left"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("left: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	right, err := s.getService(req.Context(), req.URL.Query().Get("right"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("right: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	diff := interfaces.CompareConfigs(left.GetSpinnakerConfig(), right.GetSpinnakerConfig())
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(diff); err != nil {
+		log.Error(err, "error writing compare response")
+	}
+}
+
+// provenanceHandler returns which source produced each effective config value
+// of the last deployment of the SpinnakerService referenced as "namespace/name"
+// in the service query parameter, optionally filtered by a path prefix
+func (s *Server) provenanceHandler(w http.ResponseWriter, req *http.Request) {
+	ref := req.URL.Query().Get("service")
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, fmt.Sprintf("expected a service reference formatted as namespace/name, got %q", ref), http.StatusBadRequest)
+		return
+	}
+	records := provenance.Get(ref)
+	if prefix := req.URL.Query().Get("path"); prefix != "" {
+		filtered := make([]provenance.Record, 0)
+		for _, r := range records {
+			if strings.HasPrefix(r.Path, prefix) {
+				filtered = append(filtered, r)
+			}
+		}
+		records = filtered
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		log.Error(err, "error writing provenance response")
+	}
+}
+
+// changefeedHandler returns who changed what in the configuration of the
+// SpinnakerService referenced as "namespace/name" in the service query parameter
+func (s *Server) changefeedHandler(w http.ResponseWriter, req *http.Request) {
+	ref := req.URL.Query().Get("service")
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, fmt.Sprintf("expected a service reference formatted as namespace/name, got %q", ref), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(changefeed.Get(ref)); err != nil {
+		log.Error(err, "error writing changefeed response")
+	}
+}
+
+// topologyHandler returns the dependency graph of the resources generated by
+// the last deployment of the SpinnakerService referenced as "namespace/name"
+// in the service query parameter
+func (s *Server) topologyHandler(w http.ResponseWriter, req *http.Request) {
+	ref := req.URL.Query().Get("service")
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, fmt.Sprintf("expected a service reference formatted as namespace/name, got %q", ref), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(topology.Get(ref)); err != nil {
+		log.Error(err, "error writing topology response")
+	}
+}
+
+// kleatHandler converts the classic hal config of the SpinnakerService
+// referenced as "namespace/name" in the service query parameter into
+// kleat-style per-service configs
+func (s *Server) kleatHandler(w http.ResponseWriter, req *http.Request) {
+	svc, err := s.getService(req.Context(), req.URL.Query().Get("service"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("service: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	services, err := kleat.FromHal(svc.GetSpinnakerConfig().Config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(services); err != nil {
+		log.Error(err, "error writing kleat response")
+	}
+}
+
+func (s *Server) getService(ctx context.Context, ref string) (interfaces.SpinnakerService, error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("expected a service reference formatted as namespace/name, got %q", ref)
+	}
+	svc := TypesFactory.NewService()
+	if err := s.Client.Get(ctx, types.NamespacedName{Namespace: parts[0], Name: parts[1]}, svc); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
@@ -0,0 +1,66 @@
+package interfaces
+
+import (
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+)
+
+// ConfigDiff is a structured report of the differences between the configs of
+// two SpinnakerServices, e.g. staging vs prod during environment promotion reviews
+type ConfigDiff struct {
+	// Differences in the main hal config
+	Config []inspect.DiffEntry `json:"config,omitempty"`
+	// Differences in service profiles, keyed by service
+	Profiles map[string][]inspect.DiffEntry `json:"profiles,omitempty"`
+	// Differences in service settings, keyed by service
+	ServiceSettings map[string][]inspect.DiffEntry `json:"serviceSettings,omitempty"`
+	// Names of supporting files whose content differs or that only exist on one side
+	Files []string `json:"files,omitempty"`
+}
+
+// Empty returns true when the two configs are identical
+func (d *ConfigDiff) Empty() bool {
+	return len(d.Config) == 0 && len(d.Profiles) == 0 && len(d.ServiceSettings) == 0 && len(d.Files) == 0
+}
+
+// CompareConfigs diffs the configs of two SpinnakerServices
+func CompareConfigs(left, right *SpinnakerConfig) *ConfigDiff {
+	d := &ConfigDiff{
+		Config:          inspect.Diff(left.Config, right.Config),
+		Profiles:        diffFreeFormMaps(left.Profiles, right.Profiles),
+		ServiceSettings: diffFreeFormMaps(left.ServiceSettings, right.ServiceSettings),
+	}
+	for name := range left.Files {
+		if right.Files[name] != left.Files[name] {
+			d.Files = append(d.Files, name)
+		}
+	}
+	for name := range right.Files {
+		if _, ok := left.Files[name]; !ok {
+			d.Files = append(d.Files, name)
+		}
+	}
+	sort.Strings(d.Files)
+	return d
+}
+
+func diffFreeFormMaps(left, right map[string]FreeForm) map[string][]inspect.DiffEntry {
+	result := map[string][]inspect.DiffEntry{}
+	for name, l := range left {
+		if e := inspect.Diff(l, right[name]); len(e) > 0 {
+			result[name] = e
+		}
+	}
+	for name, r := range right {
+		if _, ok := left[name]; !ok {
+			if e := inspect.Diff(nil, r); len(e) > 0 {
+				result[name] = e
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
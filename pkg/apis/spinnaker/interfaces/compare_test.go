@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareConfigs(t *testing.T) {
+	left := &SpinnakerConfig{
+		Config:   FreeForm{"version": "1.17.1"},
+		Profiles: map[string]FreeForm{"gate": {"default": FreeForm{"apiPort": 8084}}},
+		Files:    map[string]string{"kubeconfig": "abc"},
+	}
+	right := &SpinnakerConfig{
+		Config:   FreeForm{"version": "1.18.0"},
+		Profiles: map[string]FreeForm{"gate": {"default": FreeForm{"apiPort": 8084}}},
+		Files:    map[string]string{"kubeconfig": "def", "saml.jks": "xyz"},
+	}
+	d := CompareConfigs(left, right)
+	assert.False(t, d.Empty())
+	if assert.Len(t, d.Config, 1) {
+		assert.Equal(t, "version", d.Config[0].Path)
+	}
+	assert.Nil(t, d.Profiles)
+	assert.Equal(t, []string{"kubeconfig", "saml.jks"}, d.Files)
+}
+
+func TestCompareConfigs_identical(t *testing.T) {
+	c := &SpinnakerConfig{Config: FreeForm{"version": "1.17.1"}}
+	assert.True(t, CompareConfigs(c, c.DeepCopy()).Empty())
+}
@@ -0,0 +1,41 @@
+package interfaces
+
+import (
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetCondition updates the condition of type condType, the transition time
+// only moves when the status actually flips
+func (s *SpinnakerServiceStatus) SetCondition(condType string, status SpinnakerConditionStatus, reason, message string, now time.Time) {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type != condType {
+			continue
+		}
+		if s.Conditions[i].Status != status {
+			s.Conditions[i].Status = status
+			s.Conditions[i].LastTransitionTime = v1.NewTime(now)
+		}
+		s.Conditions[i].Reason = reason
+		s.Conditions[i].Message = message
+		return
+	}
+	s.Conditions = append(s.Conditions, SpinnakerCondition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: v1.NewTime(now),
+	})
+}
+
+// GetCondition returns the condition of type condType or nil
+func (s *SpinnakerServiceStatus) GetCondition(condType string) *SpinnakerCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == condType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetCondition(t *testing.T) {
+	s := &SpinnakerServiceStatus{}
+	n1 := time.Now()
+	s.SetCondition(AvailableCondition, ConditionFalse, "Updating", "rolling out", n1)
+
+	c := s.GetCondition(AvailableCondition)
+	if !assert.NotNil(t, c) {
+		return
+	}
+	assert.Equal(t, ConditionFalse, c.Status)
+	assert.True(t, n1.Equal(c.LastTransitionTime.Time))
+
+	// Same status: reason refreshes, the transition time stays
+	n2 := n1.Add(time.Minute)
+	s.SetCondition(AvailableCondition, ConditionFalse, "Updating", "still rolling out", n2)
+	c = s.GetCondition(AvailableCondition)
+	assert.Equal(t, "still rolling out", c.Message)
+	assert.True(t, n1.Equal(c.LastTransitionTime.Time))
+
+	// Status flip moves the transition time
+	n3 := n2.Add(time.Minute)
+	s.SetCondition(AvailableCondition, ConditionTrue, "OK", "all services ready", n3)
+	c = s.GetCondition(AvailableCondition)
+	assert.Equal(t, ConditionTrue, c.Status)
+	assert.True(t, n3.Equal(c.LastTransitionTime.Time))
+}
+
+func TestGetCondition_missing(t *testing.T) {
+	s := &SpinnakerServiceStatus{}
+	assert.Nil(t, s.GetCondition(FailedCondition))
+}
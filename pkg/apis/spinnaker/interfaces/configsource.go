@@ -0,0 +1,107 @@
+package interfaces
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	halConfigKey           = "config"
+	profilePrefix          = "profiles__"
+	profileSuffix          = "-local.yml"
+	deckProfileKey         = "profiles__settings-local.js"
+	deckProfileSettingsKey = "settings-local.js"
+	serviceSettingsPrefix  = "service-settings__"
+	serviceSettingsSuffix  = ".yml"
+)
+
+// Halyard's own directory layout uses slashes, ConfigMap and Secret keys use
+// the double underscore encoding. Both are accepted.
+var keyAliases = map[string]string{
+	"profiles/":         profilePrefix,
+	"service-settings/": serviceSettingsPrefix,
+}
+
+func normalizeDataKey(key string) string {
+	for alias, prefix := range keyAliases {
+		if strings.HasPrefix(key, alias) {
+			return prefix + strings.TrimPrefix(key, alias)
+		}
+	}
+	return key
+}
+
+// FromConfigMap merges the keys of a ConfigMap into the config:
+//
+//	config                       the halconfig itself
+//	profiles__<svc>-local.yml    profile of <svc>, parsed as YAML
+//	profiles__settings-local.js  Deck's profile, kept verbatim
+//	service-settings__<svc>.yml  service settings of <svc>
+//	anything else                a supporting file, kept verbatim
+//
+// Profiles and settings end up in the same maps the Halyard generation request
+// is built from, so teams can tune service profiles without editing the CR.
+func (s *SpinnakerConfig) FromConfigMap(cm v1.ConfigMap) error {
+	for k, v := range cm.Data {
+		if err := s.fromDataKey(k, []byte(v)); err != nil {
+			return fmt.Errorf("error parsing ConfigMap %s key %s: %w", cm.Name, k, err)
+		}
+	}
+	return nil
+}
+
+// FromSecret merges the keys of a Secret into the config, same layout as
+// FromConfigMap
+func (s *SpinnakerConfig) FromSecret(sec v1.Secret) error {
+	for k, v := range sec.Data {
+		if err := s.fromDataKey(k, v); err != nil {
+			return fmt.Errorf("error parsing Secret %s key %s: %w", sec.Name, k, err)
+		}
+	}
+	return nil
+}
+
+func (s *SpinnakerConfig) fromDataKey(key string, data []byte) error {
+	key = normalizeDataKey(key)
+	switch {
+	case key == halConfigKey:
+		return yaml.Unmarshal(data, &s.Config)
+	case key == deckProfileKey:
+		if s.Profiles == nil {
+			s.Profiles = map[string]FreeForm{}
+		}
+		s.Profiles["deck"] = FreeForm{deckProfileSettingsKey: string(data)}
+		return nil
+	case strings.HasPrefix(key, profilePrefix) && strings.HasSuffix(key, profileSuffix):
+		svc := strings.TrimSuffix(strings.TrimPrefix(key, profilePrefix), profileSuffix)
+		p := FreeForm{}
+		if err := yaml.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		if s.Profiles == nil {
+			s.Profiles = map[string]FreeForm{}
+		}
+		s.Profiles[svc] = p
+		return nil
+	case strings.HasPrefix(key, serviceSettingsPrefix) && strings.HasSuffix(key, serviceSettingsSuffix):
+		svc := strings.TrimSuffix(strings.TrimPrefix(key, serviceSettingsPrefix), serviceSettingsSuffix)
+		ss := FreeForm{}
+		if err := yaml.Unmarshal(data, &ss); err != nil {
+			return err
+		}
+		if s.ServiceSettings == nil {
+			s.ServiceSettings = map[string]FreeForm{}
+		}
+		s.ServiceSettings[svc] = ss
+		return nil
+	default:
+		if s.Files == nil {
+			s.Files = map[string]string{}
+		}
+		s.Files[key] = string(data)
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+package interfaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFromConfigMap(t *testing.T) {
+	cm := v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinconfig"},
+		Data: map[string]string{
+			"config": `
+version: 1.17.1
+persistentStorage:
+  persistentStoreType: s3
+`,
+			"profiles__clouddriver-local.yml": `
+kubernetes:
+  jobs:
+    enabled: true
+`,
+			"profiles__settings-local.js": "window.spinnakerSettings = {};",
+			"service-settings__gate.yml":  "targetPort: 8084",
+			"ca.pem":                      "--- cert ---",
+		},
+	}
+	c := &SpinnakerConfig{}
+
+	if !assert.Nil(t, c.FromConfigMap(cm)) {
+		return
+	}
+
+	v, err := c.GetHalConfigPropString(context.TODO(), "persistentStorage.persistentStoreType")
+	assert.Nil(t, err)
+	assert.Equal(t, "s3", v)
+
+	v, err = c.GetServiceConfigPropString(context.TODO(), "clouddriver", "kubernetes.jobs.enabled")
+	assert.Nil(t, err)
+	assert.Equal(t, "true", v)
+
+	assert.Equal(t, "window.spinnakerSettings = {};", c.Profiles["deck"]["settings-local.js"])
+
+	v, err = c.GetServiceSettingsPropString(context.TODO(), "gate", "targetPort")
+	assert.Nil(t, err)
+	assert.Equal(t, "8084", v)
+
+	assert.Equal(t, "--- cert ---", c.Files["ca.pem"])
+}
+
+func TestFromSecret(t *testing.T) {
+	sec := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinconfig"},
+		Data: map[string][]byte{
+			"profiles__gate-local.yml": []byte("security:\n  basic:\n    enabled: true"),
+		},
+	}
+	c := &SpinnakerConfig{}
+
+	if !assert.Nil(t, c.FromSecret(sec)) {
+		return
+	}
+	v, err := c.GetServiceConfigPropString(context.TODO(), "gate", "security.basic.enabled")
+	assert.Nil(t, err)
+	assert.Equal(t, "true", v)
+}
+
+func TestFromSecret_slashKeys(t *testing.T) {
+	sec := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinconfig"},
+		Data: map[string][]byte{
+			"service-settings/echo.yml":  []byte("env:\n  JAVA_OPTS: -Xmx1g"),
+			"profiles/front50-local.yml": []byte("spinnaker:\n  s3:\n    versioning: true"),
+		},
+	}
+	c := &SpinnakerConfig{}
+
+	if !assert.Nil(t, c.FromSecret(sec)) {
+		return
+	}
+	v, err := c.GetServiceSettingsPropString(context.TODO(), "echo", "env.JAVA_OPTS")
+	assert.Nil(t, err)
+	assert.Equal(t, "-Xmx1g", v)
+
+	v, err = c.GetServiceConfigPropString(context.TODO(), "front50", "spinnaker.s3.versioning")
+	assert.Nil(t, err)
+	assert.Equal(t, "true", v)
+}
+
+func TestFromConfigMap_badYaml(t *testing.T) {
+	cm := v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinconfig"},
+		Data:       map[string]string{"profiles__orca-local.yml": "::not yaml"},
+	}
+	c := &SpinnakerConfig{}
+
+	err := c.FromConfigMap(cm)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "profiles__orca-local.yml")
+	}
+}
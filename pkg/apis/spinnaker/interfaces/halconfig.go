@@ -0,0 +1,60 @@
+package interfaces
+
+import (
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+)
+
+// HalConfig is a typed view over the sections of the hal config the operator
+// reads and writes. Sections not modeled here are preserved untouched in the
+// raw document when merging back with SetTypedHalConfig.
+type HalConfig struct {
+	Version           string                 `json:"version,omitempty"`
+	Providers         map[string]HalProvider `json:"providers,omitempty"`
+	Security          *HalSecurity           `json:"security,omitempty"`
+	PersistentStorage *HalPersistentStorage  `json:"persistentStorage,omitempty"`
+}
+
+// HalProvider holds the provider fields shared across all provider types,
+// provider-specific fields stay in the free form accounts
+type HalProvider struct {
+	Enabled        bool       `json:"enabled,omitempty"`
+	PrimaryAccount string     `json:"primaryAccount,omitempty"`
+	Accounts       []FreeForm `json:"accounts,omitempty"`
+}
+
+type HalSecurity struct {
+	ApiSecurity *HalSecurityEndpoint `json:"apiSecurity,omitempty"`
+	UiSecurity  *HalSecurityEndpoint `json:"uiSecurity,omitempty"`
+}
+
+type HalSecurityEndpoint struct {
+	OverrideBaseUrl string   `json:"overrideBaseUrl,omitempty"`
+	Ssl             FreeForm `json:"ssl,omitempty"`
+}
+
+type HalPersistentStorage struct {
+	PersistentStoreType string `json:"persistentStoreType,omitempty"`
+}
+
+// GetTypedHalConfig parses the typed sections out of the raw hal config
+func (s *SpinnakerConfig) GetTypedHalConfig() (*HalConfig, error) {
+	h := &HalConfig{}
+	if err := inspect.Convert(s.Config, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// SetTypedHalConfig merges the typed sections back into the raw hal config.
+// Only keys set on the typed struct are written, unknown keys are preserved.
+func (s *SpinnakerConfig) SetTypedHalConfig(h *HalConfig) error {
+	m := map[string]interface{}{}
+	if err := inspect.Convert(h, &m); err != nil {
+		return err
+	}
+	if s.Config == nil {
+		s.Config = FreeForm{}
+	}
+	s.Config = inspect.Overlay(s.Config, m)
+	return nil
+}
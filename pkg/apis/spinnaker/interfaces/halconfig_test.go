@@ -0,0 +1,60 @@
+package interfaces
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func TestTypedHalConfigRoundTrip(t *testing.T) {
+	hc := map[string]interface{}{}
+	var c = `
+name: default
+version: 1.14.2
+timezone: America/Los_Angeles
+providers:
+  kubernetes:
+    enabled: true
+    primaryAccount: spinnaker
+    accounts:
+    - name: spinnaker
+      cacheThreads: 1
+security:
+  apiSecurity:
+    overrideBaseUrl: http://acme.com
+persistentStorage:
+  persistentStoreType: s3
+`
+	if !assert.Nil(t, yaml.Unmarshal([]byte(c), &hc)) {
+		return
+	}
+	config := SpinnakerConfig{Config: hc}
+	h, err := config.GetTypedHalConfig()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "1.14.2", h.Version)
+	assert.Equal(t, "spinnaker", h.Providers["kubernetes"].PrimaryAccount)
+	assert.Equal(t, "http://acme.com", h.Security.ApiSecurity.OverrideBaseUrl)
+	assert.Equal(t, "s3", h.PersistentStorage.PersistentStoreType)
+
+	h.Version = "1.17.1"
+	h.PersistentStorage.PersistentStoreType = "gcs"
+	err = config.SetTypedHalConfig(h)
+	if !assert.Nil(t, err) {
+		return
+	}
+	v, err := config.GetRawHalConfigPropString("version")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.17.1", v)
+	st, err := config.GetRawHalConfigPropString("persistentStorage.persistentStoreType")
+	assert.Nil(t, err)
+	assert.Equal(t, "gcs", st)
+	// Keys not modeled by the typed struct are preserved
+	tz, err := config.GetRawHalConfigPropString("timezone")
+	assert.Nil(t, err)
+	assert.Equal(t, "America/Los_Angeles", tz)
+	ct, err := config.GetRawHalConfigPropString("providers.kubernetes.accounts.0.cacheThreads")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", ct)
+}
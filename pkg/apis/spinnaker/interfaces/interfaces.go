@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -14,8 +15,10 @@ const (
 	LatestVersion   = V1alpha2Version
 )
 const (
-	KubernetesAccountType AccountType = "Kubernetes"
-	AWSAccountType                    = "AWS"
+	KubernetesAccountType     AccountType = "Kubernetes"
+	AWSAccountType                        = "AWS"
+	CloudFoundryAccountType               = "CloudFoundry"
+	DockerRegistryAccountType             = "DockerRegistry"
 )
 const (
 	Read    Authorization = "READ"
@@ -53,8 +56,42 @@ type SpinnakerService interface {
 	GetSpinnakerValidation() *SpinnakerValidation
 	GetExposeConfig() *ExposeConfig
 	GetAccountConfig() *AccountConfig
+	GetApplyPolicyConfig() *ApplyPolicyConfig
+	GetArchitectureConfig() *ArchitectureConfig
+	GetArtifacts() map[string]ArtifactOverride
+	GetNetworkPolicyConfig() *NetworkPolicyConfig
+	GetImageRegistry() string
+	GetImageRegistryOverrides() map[string]string
+	GetGitOpsConfig() *GitOpsConfig
+	GetNamingConfig() *NamingConfig
+	GetPolicyConfig() *PolicyConfig
+	GetImageVerificationConfig() *ImageVerificationConfig
+	GetQosConfig() *QosConfig
+	GetSecretAgentConfig() *SecretAgentConfig
+	GetCacheWarmingConfig() *CacheWarmingConfig
+	GetTuning() map[string]ServiceTuning
+	GetStatefulServices() map[string]StatefulServiceConfig
+	GetManagementConfig() *ManagementConfig
+	GetRedisConfig() *RedisConfig
+	GetOrcaConfig() *OrcaConfig
+	GetFreezeConfig() *FreezeConfig
+	GetMonitoringConfig() *MonitoringConfig
+	GetDeckAssetsConfig() *DeckAssetsConfig
+	GetDeploymentPlan() string
+	GetProfile() string
+	GetProxyConfig() *ProxyConfig
+	GetSecretFiles() []SecretFile
+	GetTargetNamespaceConfig() *TargetNamespaceConfig
 	GetStatus() *SpinnakerServiceStatus
 	GetKustomization() map[string]ServiceKustomization
+	GetServiceOverrides() map[string]ServiceOverride
+	GetSecretEncryptionConfig() *SecretEncryptionConfig
+	GetServiceSpecs() map[string]ServiceSpec
+	GetSchedulingConfig() *SchedulingConfig
+	GetMetadataConfig() *MetadataConfig
+	GetTargetClusterConfig() *TargetClusterConfig
+	GetSecurityConfig() *SecurityConfig
+	GetLifecycle() map[string]ServiceLifecycle
 	DeepCopyInterface() SpinnakerService
 	DeepCopySpinnakerService() SpinnakerService
 }
@@ -97,6 +134,58 @@ type ServiceKustomization struct {
 	Deployment *Kustomization `json:"deployment,omitempty"`
 }
 
+// SecretEncryptionConfig enables envelope encryption of the values of generated
+// Secrets, for clusters without etcd encryption at rest. Each Secret's values are
+// encrypted with a fresh data encryption key, which is wrapped by the configured
+// provider and stored in an annotation. Decryption is left to an init container
+// or CSI provider in the pods mounting the Secret.
+// +k8s:openapi-gen=true
+type SecretEncryptionConfig struct {
+	// Provider wrapping the per-secret data encryption keys. "secret" uses a key
+	// encryption key read from a Kubernetes Secret, cloud KMS providers register
+	// under their own name
+	// +optional
+	Provider string `json:"provider,omitempty"`
+	// KeyId identifies the wrapping key: a KMS key ARN, or for the "secret"
+	// provider the name of the Secret holding the key encryption key
+	// +optional
+	KeyId string `json:"keyId,omitempty"`
+}
+
+// ServiceOverride tweaks a Service object generated by Halyard beyond what the
+// expose configuration covers, e.g. provider specific load balancer knobs
+// +k8s:openapi-gen=true
+type ServiceOverride struct {
+	// Annotations merged into the generated Service's annotations
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Ports overriding or adding to the generated ports, matched by name
+	// +optional
+	// +listType=list
+	Ports []ServicePortOverride `json:"ports,omitempty"`
+	// ExternalTrafficPolicy of the Service, "Cluster" or "Local"
+	// +optional
+	ExternalTrafficPolicy string `json:"externalTrafficPolicy,omitempty"`
+	// LoadBalancerSourceRanges restricts the CIDRs allowed through a cloud load balancer
+	// +optional
+	// +listType=list
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+}
+
+// ServicePortOverride overrides the named port of a generated Service,
+// the port is added when no port with that name exists
+// +k8s:openapi-gen=true
+type ServicePortOverride struct {
+	// Name of the port to override
+	Name string `json:"name"`
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// +optional
+	TargetPort int32 `json:"targetPort,omitempty"`
+	// +optional
+	NodePort int32 `json:"nodePort,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 type Kustomization struct {
 	// PatchesStrategicMerge specifies the relative path to a file
@@ -148,6 +237,10 @@ type SpinnakerValidation struct {
 	// Fail validation on the first failed validation, defaults to false
 	// +optional
 	FailFast bool `json:"failFast"`
+	// URL of an external validation webhook called with the resolved config and the
+	// generated manifests before they are applied. A non-2xx response blocks the deployment
+	// +optional
+	WebhookUrl string `json:"webhookUrl,omitempty"`
 	// +optional
 	Providers map[string]ValidationSetting `json:"providers,omitempty"`
 	// +optional
@@ -169,6 +262,27 @@ type SpinnakerValidation struct {
 type ExposeConfig struct {
 	Type    string              `json:"type,omitempty"`
 	Service ExposeConfigService `json:"service,omitempty"`
+	// Ingress generation settings, used with type "ingress". When a host is set
+	// the operator generates the Ingress instead of discovering an existing one
+	// +optional
+	Ingress ExposeConfigIngress `json:"ingress,omitempty"`
+}
+
+// ExposeConfigIngress represents the configuration for generating an Ingress
+// exposing Deck and Gate
+// +k8s:openapi-gen=true
+type ExposeConfigIngress struct {
+	// Host under which Deck and Gate are exposed
+	Host string `json:"host,omitempty"`
+	// Annotations added to the generated Ingress
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Path routed to Gate, defaults to /api
+	// +optional
+	GatePath string `json:"gatePath,omitempty"`
+	// TLS secret name, enables TLS on the generated Ingress
+	// +optional
+	TlsSecretName string `json:"tlsSecretName,omitempty"`
 }
 
 // ExposeConfigService represents the configuration for exposing Spinnaker using k8s services
@@ -178,6 +292,10 @@ type ExposeConfigService struct {
 	Annotations map[string]string                       `json:"annotations,omitempty"`
 	PublicPort  int32                                   `json:"publicPort,omitempty"`
 	Overrides   map[string]ExposeConfigServiceOverrides `json:"overrides,omitempty"`
+	// SessionAffinity set on Gate's service ("ClientIP") with Redis-backed Gate
+	// sessions, for multiple Gate replicas behind load balancers without sticky support
+	// +optional
+	SessionAffinity string `json:"sessionAffinity,omitempty"`
 }
 
 // ExposeConfigServiceOverrides represents expose configurations of type service, overriden by specific services
@@ -188,490 +306,2199 @@ type ExposeConfigServiceOverrides struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// ArchitectureConfig controls how workloads are scheduled on multi-architecture clusters
 // +k8s:openapi-gen=true
-type AccountConfig struct {
-	// Enable the injection of SpinnakerAccount
-	Enabled bool `json:"enabled,omitempty"`
-	// Enable accounts to be added dynamically
-	Dynamic bool `json:"dynamic,omitempty"`
+type ArchitectureConfig struct {
+	// Target node architectures, e.g. ["amd64", "arm64"].
+	// Defaults to the architectures of the cluster nodes
+	// +optional
+	// +listType=list
+	Architectures []string `json:"architectures,omitempty"`
+	// Per-service, per-architecture image overrides, e.g. clouddriver: {arm64: "myrepo/clouddriver:arm64"}
+	// +optional
+	ImageOverrides map[string]map[string]string `json:"imageOverrides,omitempty"`
 }
 
-// SpinnakerServiceSpec defines the desired state of SpinnakerService
+// ArtifactOverride pins a single service to a patched build, either by
+// swapping the version tag of the image Halyard produced or by replacing
+// the image outright
 // +k8s:openapi-gen=true
-type SpinnakerServiceSpec struct {
-	SpinnakerConfig SpinnakerConfig `json:"spinnakerConfig" protobuf:"bytes,1,opt,name=spinnakerConfig"`
+type ArtifactOverride struct {
+	// Version replaces the tag of the generated image, e.g. a patched
+	// 6.3.1-custom clouddriver build from the same registry
 	// +optional
-	Validation SpinnakerValidation `json:"validation,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Image replaces the generated image entirely. Takes precedence over version
 	// +optional
-	Expose ExposeConfig `json:"expose,omitempty"`
+	Image string `json:"image,omitempty"`
+}
+
+// GitOpsConfig commits the generated, transformed manifests to a Git
+// repository instead of applying them, so a GitOps tool like ArgoCD or Flux
+// owns the actual rollout
+// +k8s:openapi-gen=true
+type GitOpsConfig struct {
+	// Enabled switches the deployer from applying manifests to exporting them
 	// +optional
-	Accounts AccountConfig `json:"accounts,omitempty"`
-	// Patch Kustomization of service and deployment per service
+	Enabled bool `json:"enabled,omitempty"`
+	// Repo is the clone URL of the repository receiving the manifests
 	// +optional
-	Kustomize map[string]ServiceKustomization `json:"kustomize,omitempty"`
+	Repo string `json:"repo,omitempty"`
+	// Branch committed to, defaults to master
+	// +optional
+	Branch string `json:"branch,omitempty"`
+	// Path of the directory the manifests are written under, one subdirectory
+	// per service. The operator owns this directory, defaults to "spinnaker"
+	// +optional
+	Path string `json:"path,omitempty"`
 }
 
-// SpinnakerDeploymentStatus represents the deployment status of a single service
+// NetworkPolicyConfig generates a default-deny NetworkPolicy baseline with
+// explicit allows in the namespace receiving the deployment, so the namespace
+// is locked down without hand written policies
 // +k8s:openapi-gen=true
-type SpinnakerDeploymentStatus struct {
-	// Name of the service deployed
-	Name string `json:"name"`
-	// Image deployed
+type NetworkPolicyConfig struct {
+	// Enabled generates the baseline policies
 	// +optional
-	Image string `json:"image,omitempty"`
-	// Total number of non-terminated pods targeted by this deployment (their labels match the selector).
+	Enabled bool `json:"enabled,omitempty"`
+	// AllowedNamespaces whose pods may reach the exposed services (gate, deck)
+	// in addition to the Spinnaker namespace itself
 	// +optional
-	Replicas int32 `json:"replicas,omitempty" protobuf:"varint,2,opt,name=replicas"`
-	// Total number of ready pods targeted by this deployment.
+	// +listType=list
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+const (
+	FailFastPolicy = "fail-fast"
+	ContinuePolicy = "continue"
+)
+
+// ApplyPolicyConfig controls how failures are handled while applying generated manifests
+// +k8s:openapi-gen=true
+type ApplyPolicyConfig struct {
+	// FailurePolicy is "fail-fast" (default) to stop on the first failed manifest,
+	// or "continue" to keep applying the remaining manifests and report failures at the end
 	// +optional
-	ReadyReplicas int32 `json:"readyReplicas,omitempty" protobuf:"varint,7,opt,name=readyReplicas"`
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+	// MaxFailures bounds the number of tolerated failures when FailurePolicy is "continue", 0 means no limit
+	// +optional
+	MaxFailures int `json:"maxFailures,omitempty"`
+	// ServiceAccount in the service's namespace to impersonate while applying the
+	// generated manifests, so audit logs attribute workload changes to a dedicated
+	// deploy identity with its own RBAC
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// OrphanOnDelete strips owner references from the generated objects and
+	// exports their manifests when the SpinnakerService is deleted, so
+	// Spinnaker survives a decommission of the operator
+	// +optional
+	OrphanOnDelete bool `json:"orphanOnDelete,omitempty"`
+	// ProgressDeadlineSeconds marks the service Degraded when generated
+	// deployments are not ready this long after a deployment, 0 disables the check
+	// +optional
+	ProgressDeadlineSeconds int32 `json:"progressDeadlineSeconds,omitempty"`
 }
 
-// SpinnakerServiceStatus defines the observed state of SpinnakerService
+// NamingConfig customizes the names of generated objects
 // +k8s:openapi-gen=true
-type SpinnakerServiceStatus struct {
-	// Current deployed version of Spinnaker
+type NamingConfig struct {
+	// Prefix replacing the default "spin" prefix in generated object names, e.g. "team1" generates team1-gate
 	// +optional
-	Version string `json:"version,omitempty"`
-	// Last deployed hashes
+	Prefix string `json:"prefix,omitempty"`
+	// Suffix appended to generated object names, e.g. "prod" generates spin-gate-prod
 	// +optional
-	LastDeployed map[string]HashStatus `json:"lastDeployed,omitempty"`
-	// Services deployment information
+	Suffix string `json:"suffix,omitempty"`
+	// Labels stamped on every generated workload and its pods, e.g. team or
+	// cost-center for ownership and cost reporting
 	// +optional
-	// +listType=map
-	// +listMapKey=name
-	Services []SpinnakerDeploymentStatus `json:"services,omitempty"`
-	// Overall Spinnaker status
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// PolicyConfig configures Rego policy evaluation over the hal config and generated manifests
+// +k8s:openapi-gen=true
+type PolicyConfig struct {
+	// Name of a ConfigMap in the SpinnakerService namespace containing Rego policies
 	// +optional
-	Status string `json:"status,omitempty"`
-	// Number of services in Spinnaker
+	ConfigMap string `json:"configMap,omitempty"`
+	// When true, violations block the deployment, otherwise they are only reported as events
 	// +optional
-	ServiceCount int `json:"serviceCount,omitempty"`
-	// Exposed Deck URL
+	Enforce bool `json:"enforce,omitempty"`
+}
+
+// ServiceLifecycle overrides the pod termination behavior of one service,
+// for services needing longer drains than the generated default of 30s
+// +k8s:openapi-gen=true
+type ServiceLifecycle struct {
+	// Seconds the pod is given to finish in-flight work before being killed
 	// +optional
-	UIUrl string `json:"uiUrl"`
-	// Exposed Gate URL
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// Command run in the container's preStop hook
 	// +optional
-	APIUrl string `json:"apiUrl"`
-	// Number of accounts
+	// +listType=list
+	PreStopCommand []string `json:"preStopCommand,omitempty"`
+	// Command run in the container's postStart hook
 	// +optional
-	AccountCount int `json:"accountCount,omitempty"`
+	// +listType=list
+	PostStartCommand []string `json:"postStartCommand,omitempty"`
 }
 
+// ImageVerificationConfig configures cosign signature and digest verification
+// of every image referenced in generated manifests
 // +k8s:openapi-gen=true
-type HashStatus struct {
-	Hash          string  `json:"hash"`
-	LastUpdatedAt v1.Time `json:"lastUpdatedAt,omitempty"`
+type ImageVerificationConfig struct {
+	// PEM encoded cosign public keys trusted to have signed the images
+	// +optional
+	// +listType=list
+	CosignPublicKeys []string `json:"cosignPublicKeys,omitempty"`
+	// Image digests (sha256:...) explicitly trusted without a signature
+	// +optional
+	// +listType=list
+	TrustedDigests []string `json:"trustedDigests,omitempty"`
+	// When true, unsigned or unknown images block the deployment,
+	// otherwise failures are only reported as events
+	// +optional
+	Enforce bool `json:"enforce,omitempty"`
 }
 
-// SpinnakerAccountSpec defines the desired state of SpinnakerAccount
+// ServiceTuning maps connection pool sizes and timeouts onto the profile
+// properties of a service, replacing hand maintained profile snippets
 // +k8s:openapi-gen=true
-type SpinnakerAccountSpec struct {
-	Enabled bool        `json:"enabled"`
-	Type    AccountType `json:"type"`
+type ServiceTuning struct {
+	// Maximum redis connections in the pool
 	// +optional
-	Validation ValidationSetting `json:"validation"`
+	RedisPoolMaxTotal int32 `json:"redisPoolMaxTotal,omitempty"`
+	// Maximum idle redis connections kept in the pool
 	// +optional
-	Permissions AccountPermissions `json:"permissions"`
+	RedisPoolMaxIdle int32 `json:"redisPoolMaxIdle,omitempty"`
+	// Redis command timeout in milliseconds
 	// +optional
-	Kubernetes *KubernetesAuth `json:"kubernetes,omitempty"`
+	RedisTimeoutMs int32 `json:"redisTimeoutMs,omitempty"`
+	// Maximum SQL connections in the default pool
 	// +optional
-	Settings FreeForm `json:"settings,omitempty"`
+	SqlPoolMaxSize int32 `json:"sqlPoolMaxSize,omitempty"`
+	// SQL connection acquisition timeout in milliseconds
+	// +optional
+	SqlConnectionTimeoutMs int32 `json:"sqlConnectionTimeoutMs,omitempty"`
 }
 
+// ServiceSpec carries per service deployment settings that do not belong in
+// the halconfig blob
 // +k8s:openapi-gen=true
-type KubernetesAuth struct {
-	// KubeconfigFile referenced as an encrypted secret
+type ServiceSpec struct {
+	// Resources patched into the generated Deployment's service container
 	// +optional
-	KubeconfigFile string `json:"kubeconfigFile,omitempty"`
-	// Kubeconfig referenced as a Kubernetes secret
+	Resources *ServiceResources `json:"resources,omitempty"`
+	// Scheduling overrides the spec level scheduling settings for this service
 	// +optional
-	KubeconfigSecret *SecretInNamespaceReference `json:"kubeconfigSecret,omitempty"`
-	// Kubeconfig config referenced directly
+	Scheduling *SchedulingConfig `json:"scheduling,omitempty"`
+	// Sidecars appended to the generated Deployment, e.g. a cloudsql proxy
 	// +optional
-	Kubeconfig *clientv1.Config `json:"kubeconfig,omitempty"`
-	// UseServiceAccount authenticate to the target cluster using the service account mounted in Spinnaker's pods
+	// +listType=list
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+	// Volumes added to the generated pod template, shared with the sidecars
 	// +optional
-	UseServiceAccount bool `json:"useServiceAccount"`
+	// +listType=list
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// Metadata adds labels and annotations on top of the spec level metadata
+	// +optional
+	Metadata *MetadataConfig `json:"metadata,omitempty"`
 }
 
+// SecurityConfig groups UI and API security settings that do not belong in
+// the halconfig blob
 // +k8s:openapi-gen=true
-type SecretInNamespaceReference struct {
-	Name string `json:"name"`
-	Key  string `json:"key"`
+type SecurityConfig struct {
+	// Cross origin and content security policy settings for Gate and Deck
+	// +optional
+	Cors CorsConfig `json:"cors,omitempty"`
 }
 
-// SpinnakerAccountStatus defines the observed state of SpinnakerAccount
+// CorsConfig configures the origins Gate accepts and the CSP header Deck serves
 // +k8s:openapi-gen=true
-type SpinnakerAccountStatus struct {
-	InvalidReason   string        `json:"invalidReason"`
-	LastValidatedAt *v1.Timestamp `json:"lastValidatedAt"`
+type CorsConfig struct {
+	// AllowedOrigins Gate accepts, on top of the expose base URLs
+	// +optional
+	// +listType=list
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	// ContentSecurityPolicy header value served by Deck
+	// +optional
+	ContentSecurityPolicy string `json:"contentSecurityPolicy,omitempty"`
 }
 
-var _ TypesFactory = &TypesFactoryImpl{}
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityConfig) DeepCopyInto(out *SecurityConfig) {
+	*out = *in
+	in.Cors.DeepCopyInto(&out.Cors)
+	return
+}
 
-type TypesFactoryImpl struct {
-	Factories map[Version]TypesFactory
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityConfig.
+func (in *SecurityConfig) DeepCopy() *SecurityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityConfig)
+	in.DeepCopyInto(out)
+	return out
 }
 
-func (f *TypesFactoryImpl) NewService() SpinnakerService {
-	return f.Factories[LatestVersion].NewService()
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorsConfig) DeepCopyInto(out *CorsConfig) {
+	*out = *in
+	if in.AllowedOrigins != nil {
+		in, out := &in.AllowedOrigins, &out.AllowedOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
 }
 
-func (f *TypesFactoryImpl) NewServiceList() SpinnakerServiceList {
-	return f.Factories[LatestVersion].NewServiceList()
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorsConfig.
+func (in *CorsConfig) DeepCopy() *CorsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CorsConfig)
+	in.DeepCopyInto(out)
+	return out
 }
 
-func (f *TypesFactoryImpl) NewAccount() SpinnakerAccount {
-	return f.Factories[LatestVersion].NewAccount()
+// TargetClusterConfig deploys the generated manifests to a remote cluster
+// while the SpinnakerService and its status stay in the management cluster
+// +k8s:openapi-gen=true
+type TargetClusterConfig struct {
+	// KubeconfigSecret names a Secret in the CR namespace holding the
+	// kubeconfig of the target cluster under the kubeconfig key
+	// +optional
+	KubeconfigSecret string `json:"kubeconfigSecret,omitempty"`
+	// KubeconfigSecretKey overrides the Secret key, defaults to kubeconfig
+	// +optional
+	KubeconfigSecretKey string `json:"kubeconfigSecretKey,omitempty"`
+	// Context selects a kubeconfig context, defaults to the current context
+	// +optional
+	Context string `json:"context,omitempty"`
 }
 
-func (f *TypesFactoryImpl) NewAccountList() SpinnakerAccountList {
-	return f.Factories[LatestVersion].NewAccountList()
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetClusterConfig) DeepCopyInto(out *TargetClusterConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetClusterConfig.
+func (in *TargetClusterConfig) DeepCopy() *TargetClusterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetClusterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// MetadataConfig adds labels and annotations to every generated Deployment,
+// Service and pod template, e.g. for cost allocation or sidecar injection
+// +k8s:openapi-gen=true
+type MetadataConfig struct {
+	// Labels added to generated objects, keys already set by the generation are kept
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to generated objects, keys already set by the generation are kept
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataConfig) DeepCopyInto(out *MetadataConfig) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataConfig.
+func (in *MetadataConfig) DeepCopy() *MetadataConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// SchedulingConfig pins the generated pods to nodes, e.g. a dedicated node pool
+// +k8s:openapi-gen=true
+type SchedulingConfig struct {
+	// NodeSelector added to every generated pod template, existing keys are kept
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations appended to every generated pod template
+	// +optional
+	// +listType=list
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity set on generated pod templates that declare none
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+}
+
+// ServiceResources sets the compute resources of a service's container,
+// values use the usual Kubernetes quantity format
+// +k8s:openapi-gen=true
+type ServiceResources struct {
+	// Requests of cpu and memory, e.g. cpu: 500m
+	// +optional
+	Requests map[string]string `json:"requests,omitempty"`
+	// Limits of cpu and memory
+	// +optional
+	Limits map[string]string `json:"limits,omitempty"`
+}
+
+// StatefulServiceConfig generates a service as a StatefulSet with stable
+// identity and an optional PVC template instead of a Deployment, for services
+// with local caches or sticky identity requirements such as embedded Redis
+// or MinIO
+// +k8s:openapi-gen=true
+type StatefulServiceConfig struct {
+	// Enabled converts the generated Deployment into a StatefulSet
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// StorageClassName of the PVC template, empty uses the cluster default
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+	// StorageSize of the PVC template, e.g. "10Gi", empty skips the template
+	// +optional
+	StorageSize string `json:"storageSize,omitempty"`
+	// MountPath the persistent volume is mounted at, defaults to /data
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// CacheWarmingConfig staggers Clouddriver replica startup and ties its
+// readiness to cache population thresholds
+// +k8s:openapi-gen=true
+type CacheWarmingConfig struct {
+	// Enabled turns on staggered rollout and cache aware readiness for Clouddriver
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Seconds a new replica warms its caches before the first readiness check,
+	// defaults to 60 plus one second per configured provider account
+	// +optional
+	MinWarmupSeconds int32 `json:"minWarmupSeconds,omitempty"`
+	// Seconds a replica may spend warming up before readiness gives up, defaults to 600
+	// +optional
+	MaxWarmupSeconds int32 `json:"maxWarmupSeconds,omitempty"`
+	// Percentage of accounts that must be cached before the replica reports
+	// ready, defaults to 90
+	// +optional
+	MinCachedAccountsPercent int32 `json:"minCachedAccountsPercent,omitempty"`
+}
+
+// SecretAgentConfig injects a secret agent sidecar (Vault Agent or SPIRE)
+// into service deployments, rendering secrets next to the service instead of
+// resolving them operator side and storing them at rest
+// +k8s:openapi-gen=true
+type SecretAgentConfig struct {
+	// Agent to inject: "vault" or "spire"
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Sidecar image, defaults to a well known image for the agent type
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Vault server address the agent authenticates against
+	// +optional
+	VaultAddress string `json:"vaultAddress,omitempty"`
+	// Vault kubernetes auth role the agent authenticates with
+	// +optional
+	VaultRole string `json:"vaultRole,omitempty"`
+	// Host path of the SPIRE agent socket directory, defaults to /run/spire/sockets
+	// +optional
+	SpireSocketPath string `json:"spireSocketPath,omitempty"`
+	// Path where rendered secrets appear in service containers,
+	// defaults to /opt/spinnaker/secret-agent
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+	// Services getting the sidecar even without templates
+	// +optional
+	// +listType=list
+	Services []string `json:"services,omitempty"`
+	// Secret templates rendered by the agent, keyed by service then file name
+	// +optional
+	Templates map[string]map[string]string `json:"templates,omitempty"`
+}
+
+// QosGuaranteed is the only quality of service class that can be requested
+// in QosConfig
+const QosGuaranteed = "Guaranteed"
+
+// QosConfig requests a Kubernetes quality of service class for the
+// generated deployments
+// +k8s:openapi-gen=true
+type QosConfig struct {
+	// Quality of service class the service pods should run with,
+	// only "Guaranteed" is supported
+	// +optional
+	Class string `json:"class,omitempty"`
+	// When true, container requests are aligned with their limits to reach
+	// the class, otherwise containers missing it are only reported
+	// +optional
+	Enforce bool `json:"enforce,omitempty"`
+}
+
+// ManagementConfig moves the Spring Boot management endpoints (health,
+// metrics) of every Java service off the main server port
+// +k8s:openapi-gen=true
+type ManagementConfig struct {
+	// Enabled moves the management endpoints to a dedicated port
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Port the management endpoints listen on in every service, defaults to 8095
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// DeckAssetsConfig serves Deck's static assets from object storage or a CDN
+// instead of the in-cluster Deck pods
+// +k8s:openapi-gen=true
+type DeckAssetsConfig struct {
+	// Enabled publishes and serves the Deck assets externally
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// BaseUrl the assets are served from, e.g. https://cdn.acme.com/spinnaker
+	// +optional
+	BaseUrl string `json:"baseUrl,omitempty"`
+	// SyncCommand run in the Deck container after startup to upload the
+	// assets, e.g. "aws s3 sync /opt/deck/html s3://my-bucket/spinnaker"
+	// +optional
+	SyncCommand string `json:"syncCommand,omitempty"`
+}
+
+// OrcaConfig tunes Orca's keiko queue and execution repository
+// +k8s:openapi-gen=true
+type OrcaConfig struct {
+	// Number of keiko queue handler threads, 0 keeps Orca's default
+	// +optional
+	QueueThreads int32 `json:"queueThreads,omitempty"`
+	// Days completed executions are kept before cleanup, 0 keeps them forever
+	// +optional
+	ExecutionRetentionDays int32 `json:"executionRetentionDays,omitempty"`
+	// Enable detection and cleanup of zombie executions
+	// +optional
+	ZombieCleanup bool `json:"zombieCleanup,omitempty"`
+}
+
+// RedisConfig configures authentication and TLS for the Redis every service
+// connects to, embedded or external
+// +k8s:openapi-gen=true
+type RedisConfig struct {
+	// PasswordSecret names a Kubernetes secret in the SpinnakerService
+	// namespace holding the Redis password under the "password" key
+	// +optional
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+	// Tls connects to Redis over TLS (rediss://), requires an external Redis
+	// with a baseUrl service setting
+	// +optional
+	Tls bool `json:"tls,omitempty"`
+}
+
+// TargetNamespaceConfig points the deployment at a namespace other than the
+// SpinnakerService's own, optionally creating and managing it
+// +k8s:openapi-gen=true
+type TargetNamespaceConfig struct {
+	// Name of the namespace to deploy Spinnaker into, defaults to the SpinnakerService namespace
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Create the namespace when it does not exist
+	// +optional
+	Create bool `json:"create,omitempty"`
+	// Labels applied to the namespace, e.g. pod security admission level or Istio injection
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations applied to the namespace
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Delete the created namespace when the SpinnakerService is deleted
+	// +optional
+	ManageLifecycle bool `json:"manageLifecycle,omitempty"`
+}
+
+// MonitoringConfig drives generation of observability resources for the
+// deployed services
+// +k8s:openapi-gen=true
+type MonitoringConfig struct {
+	// Enabled injects the spinnaker-monitoring daemon sidecar into each
+	// service deployment, with its ConfigMaps generated alongside
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Prometheus enables the prometheus metric store of the daemon and marks
+	// the pods for scraping
+	// +optional
+	Prometheus bool `json:"prometheus,omitempty"`
+	// Image of the monitoring daemon, defaults to the upstream release
+	// +optional
+	Image string `json:"image,omitempty"`
+	// PrometheusRules emits a PrometheusRule object with recording and
+	// alerting rules for the golden signals of the deployed services
+	// +optional
+	PrometheusRules bool `json:"prometheusRules,omitempty"`
+	// RuleLabels are added to the generated PrometheusRule objects, e.g. the
+	// label the Prometheus operator instance selects rules by
+	// +optional
+	RuleLabels map[string]string `json:"ruleLabels,omitempty"`
+}
+
+// FreezeConfig declares change freeze windows during which the deployer
+// validates and queues changes instead of applying them
+// +k8s:openapi-gen=true
+type FreezeConfig struct {
+	// Recurring weekly freeze windows
+	// +optional
+	// +listType=list
+	Windows []FreezeWindow `json:"windows,omitempty"`
+	// TimeZone the windows are evaluated in (IANA name), defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// CalendarUrl is an HTTP endpoint consulted before every deployment,
+	// expected to answer {"frozen": bool, "reason": string}
+	// +optional
+	CalendarUrl string `json:"calendarUrl,omitempty"`
+}
+
+// FreezeWindow is a recurring weekly window during which deployments are frozen
+// +k8s:openapi-gen=true
+type FreezeWindow struct {
+	// Days of the week the window applies to ("Monday"...), empty means every day
+	// +optional
+	// +listType=list
+	Days []string `json:"days,omitempty"`
+	// Start of the window, "HH:MM" in 24h format
+	Start string `json:"start"`
+	// End of the window, "HH:MM" in 24h format, before Start means the window
+	// spans midnight
+	End string `json:"end"`
+	// Reason surfaced on the status and events while the window is active
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ProxyConfig declares an egress proxy injected into every service as env vars
+// and JVM flags, so air-gapped or proxied clusters are configured in one place
+// +k8s:openapi-gen=true
+type ProxyConfig struct {
+	// Proxy URL for http traffic, e.g. http://proxy.acme.com:3128
+	// +optional
+	Http string `json:"http,omitempty"`
+	// Proxy URL for https traffic, defaults to the http proxy
+	// +optional
+	Https string `json:"https,omitempty"`
+	// Comma separated list of hosts bypassing the proxy
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// SecretFile declares a secret file mounted only into the service that needs it,
+// instead of Halyard's default of distributing files to every service
+// +k8s:openapi-gen=true
+type SecretFile struct {
+	// Service receiving the file, e.g. "clouddriver"
+	Service string `json:"service"`
+	// Name of a Kubernetes secret in the SpinnakerService namespace holding the file
+	SecretName string `json:"secretName"`
+	// Key inside the secret, all keys are mounted when empty
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Directory to mount the secret under, defaults to /opt/<service>/secrets/<secretName>
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type AccountConfig struct {
+	// Enable the injection of SpinnakerAccount
+	Enabled bool `json:"enabled,omitempty"`
+	// Enable accounts to be added dynamically
+	Dynamic bool `json:"dynamic,omitempty"`
+	// Labels that kubeconfig Secrets must carry to be bootstrapped as Kubernetes accounts
+	// +optional
+	BootstrapSelector map[string]string `json:"bootstrapSelector,omitempty"`
+}
+
+// SpinnakerServiceSpec defines the desired state of SpinnakerService
+// +k8s:openapi-gen=true
+type SpinnakerServiceSpec struct {
+	SpinnakerConfig SpinnakerConfig `json:"spinnakerConfig" protobuf:"bytes,1,opt,name=spinnakerConfig"`
+	// Preset expanded into a set of defaults (replicas, resources, telemetry...)
+	// before user overrides are applied. One of "quickstart", "production-ha" or "airgapped"
+	// +optional
+	Profile string `json:"profile,omitempty"`
+	// +optional
+	Validation SpinnakerValidation `json:"validation,omitempty"`
+	// +optional
+	Expose ExposeConfig `json:"expose,omitempty"`
+	// +optional
+	Accounts AccountConfig `json:"accounts,omitempty"`
+	// Rego policies evaluated against the config and generated manifests
+	// +optional
+	Policies PolicyConfig `json:"policies,omitempty"`
+	// Signature and digest verification of the images referenced in generated manifests
+	// +optional
+	ImageVerification ImageVerificationConfig `json:"imageVerification,omitempty"`
+	// Quality of service class requested for the service pods
+	// +optional
+	Qos QosConfig `json:"qos,omitempty"`
+	// Secret agent sidecar injected into service deployments
+	// +optional
+	SecretAgent SecretAgentConfig `json:"secretAgent,omitempty"`
+	// Staggered startup and cache warming of Clouddriver replicas
+	// +optional
+	CacheWarming CacheWarmingConfig `json:"cacheWarming,omitempty"`
+	// Per-service connection pool tuning mapped to profile properties
+	// +optional
+	Tuning map[string]ServiceTuning `json:"tuning,omitempty"`
+	// Services generated as StatefulSets instead of Deployments, keyed by service name
+	// +optional
+	StatefulServices map[string]StatefulServiceConfig `json:"statefulServices,omitempty"`
+	// Separate port for the Spring Boot management endpoints of every service
+	// +optional
+	Management ManagementConfig `json:"management,omitempty"`
+	// Authentication and TLS for the Redis used by every service
+	// +optional
+	Redis RedisConfig `json:"redis,omitempty"`
+	// Orca queue and execution repository tuning, mapped to the orca profile
+	// +optional
+	Orca OrcaConfig `json:"orca,omitempty"`
+	// Serving of Deck's static assets from object storage or a CDN
+	// +optional
+	DeckAssets DeckAssetsConfig `json:"deckAssets,omitempty"`
+	// Architecture validation and per-arch image overrides
+	// +optional
+	Architecture ArchitectureConfig `json:"architecture,omitempty"`
+	// Pinned service builds rewritten onto the images Halyard produced, keyed by service name
+	// +optional
+	Artifacts map[string]ArtifactOverride `json:"artifacts,omitempty"`
+	// Default-deny NetworkPolicy baseline generated for the target namespace
+	// +optional
+	NetworkPolicy NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+	// Private registry mirror every container image reference in the generated
+	// Deployments is rewritten to, for air-gapped installs
+	// +optional
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+	// Per-service mirror overrides, keyed by service name
+	// +optional
+	ImageRegistryOverrides map[string]string `json:"imageRegistryOverrides,omitempty"`
+	// Export of the generated manifests to a Git repository instead of applying them
+	// +optional
+	GitOps GitOpsConfig `json:"gitops,omitempty"`
+	// Naming customization of generated objects
+	// +optional
+	Naming NamingConfig `json:"naming,omitempty"`
+	// Failure tolerance while applying generated manifests
+	// +optional
+	Apply ApplyPolicyConfig `json:"apply,omitempty"`
+	// Change freeze windows during which nothing is applied
+	// +optional
+	Freeze FreezeConfig `json:"freeze,omitempty"`
+	// Generated observability resources for the deployed services
+	// +optional
+	Monitoring MonitoringConfig `json:"monitoring,omitempty"`
+	// Plan of the deployer: "dryRun" renders and diffs without applying
+	// anything, "apply" (the default) deploys
+	// +optional
+	DeploymentPlan string `json:"deploymentPlan,omitempty"`
+	// Secret files mounted only into the services that need them
+	// +optional
+	// +listType=list
+	SecretFiles []SecretFile `json:"secretFiles,omitempty"`
+	// Egress proxy injected into all services
+	// +optional
+	Proxy ProxyConfig `json:"proxy,omitempty"`
+	// Namespace receiving the deployment, created and managed on demand
+	// +optional
+	TargetNamespace TargetNamespaceConfig `json:"targetNamespace,omitempty"`
+	// Patch Kustomization of service and deployment per service
+	// +optional
+	Kustomize map[string]ServiceKustomization `json:"kustomize,omitempty"`
+	// Overrides applied to the generated Service objects per service
+	// +optional
+	ServiceOverrides map[string]ServiceOverride `json:"serviceOverrides,omitempty"`
+	// Envelope encryption of generated Secrets
+	// +optional
+	SecretEncryption SecretEncryptionConfig `json:"secretEncryption,omitempty"`
+	// Per service deployment settings such as compute resources
+	// +optional
+	Services map[string]ServiceSpec `json:"services,omitempty"`
+	// Node scheduling of all generated pods, overridable per service
+	// +optional
+	Scheduling SchedulingConfig `json:"scheduling,omitempty"`
+	// Labels and annotations stamped on all generated objects, overridable per service
+	// +optional
+	Metadata MetadataConfig `json:"metadata,omitempty"`
+	// Remote cluster the generated manifests are applied to
+	// +optional
+	TargetCluster TargetClusterConfig `json:"targetCluster,omitempty"`
+	// CORS and CSP settings applied to Gate and Deck
+	// +optional
+	Security SecurityConfig `json:"security,omitempty"`
+	// Termination and lifecycle hook overrides per service
+	// +optional
+	Lifecycle map[string]ServiceLifecycle `json:"lifecycle,omitempty"`
+}
+
+// SpinnakerDeploymentStatus represents the deployment status of a single service
+// +k8s:openapi-gen=true
+type SpinnakerDeploymentStatus struct {
+	// Name of the service deployed
+	Name string `json:"name"`
+	// Image deployed
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Total number of non-terminated pods targeted by this deployment (their labels match the selector).
+	// +optional
+	Replicas int32 `json:"replicas,omitempty" protobuf:"varint,2,opt,name=replicas"`
+	// Total number of ready pods targeted by this deployment.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty" protobuf:"varint,7,opt,name=readyReplicas"`
+	// Last time the image or replica counts of the service changed
+	// +optional
+	LastTransitionTime v1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// SpinnakerConditionStatus is the status of a condition, True, False or Unknown
+type SpinnakerConditionStatus string
+
+const (
+	ConditionTrue    SpinnakerConditionStatus = "True"
+	ConditionFalse   SpinnakerConditionStatus = "False"
+	ConditionUnknown SpinnakerConditionStatus = "Unknown"
+)
+
+const (
+	// AvailableCondition is true when every service is deployed and ready
+	AvailableCondition = "Available"
+	// ProgressingCondition is true while a deployment is being rolled out
+	ProgressingCondition = "Progressing"
+	// FailedCondition is true when the deployment failed or a rollout is stuck
+	FailedCondition = "Failed"
+	// PreflightCondition is true when the configured providers passed the
+	// connectivity checks run before generating manifests
+	PreflightCondition = "PreflightPassed"
+)
+
+// SpinnakerCondition is a standard condition describing one aspect of the
+// state of the deployment
+// +k8s:openapi-gen=true
+type SpinnakerCondition struct {
+	// Type of the condition, Available, Progressing or Failed
+	Type string `json:"type"`
+	// Status of the condition, True, False or Unknown
+	Status SpinnakerConditionStatus `json:"status"`
+	// Machine readable reason for the last transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message detailing the last transition
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Last time the condition changed status
+	// +optional
+	LastTransitionTime v1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// SpinnakerServiceStatus defines the observed state of SpinnakerService
+// +k8s:openapi-gen=true
+type SpinnakerServiceStatus struct {
+	// Current deployed version of Spinnaker
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Spec generation last processed by the deploy path
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Last deployed hashes
+	// +optional
+	LastDeployed map[string]HashStatus `json:"lastDeployed,omitempty"`
+	// Services deployment information
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Services []SpinnakerDeploymentStatus `json:"services,omitempty"`
+	// Overall Spinnaker status
+	// +optional
+	Status string `json:"status,omitempty"`
+	// Number of services in Spinnaker
+	// +optional
+	ServiceCount int `json:"serviceCount,omitempty"`
+	// Exposed Deck URL
+	// +optional
+	UIUrl string `json:"uiUrl"`
+	// Exposed Gate URL
+	// +optional
+	APIUrl string `json:"apiUrl"`
+	// Number of accounts
+	// +optional
+	AccountCount int `json:"accountCount,omitempty"`
+	// Progress of an annotation triggered Redis migration
+	// +optional
+	RedisMigration *RedisMigrationStatus `json:"redisMigration,omitempty"`
+	// Results of the endpoint smoke tests run after the last deployment
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SmokeTests []SmokeTestResult `json:"smokeTests,omitempty"`
+	// Rollouts that exceeded the configured progress deadline
+	// +optional
+	// +listType=map
+	// +listMapKey=service
+	StuckRollouts []RolloutIssue `json:"stuckRollouts,omitempty"`
+	// Findings of the scheduled re-validation of the deployed configuration
+	// +optional
+	// +listType=list
+	Findings []ConfigFinding `json:"findings,omitempty"`
+	// Changes the last dry run would have applied
+	// +optional
+	// +listType=list
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+	// Standard conditions describing the state of the deployment
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []SpinnakerCondition `json:"conditions,omitempty"`
+	// Where the current config was promoted from, when it was
+	// +optional
+	LastPromotion *PromotionRecord `json:"lastPromotion,omitempty"`
+}
+
+// PromotionRecord is the lineage of an annotation triggered config promotion
+// from another SpinnakerService, e.g. staging to prod
+// +k8s:openapi-gen=true
+type PromotionRecord struct {
+	// From is the namespace/name of the source SpinnakerService
+	From string `json:"from"`
+	// FromGeneration is the spec generation of the source at promotion time
+	// +optional
+	FromGeneration int64 `json:"fromGeneration,omitempty"`
+	// Time the promotion happened
+	// +optional
+	Time v1.Time `json:"time,omitempty"`
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionRecord) DeepCopyInto(out *PromotionRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionRecord.
+func (in *PromotionRecord) DeepCopy() *PromotionRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// ConfigFinding is a problem detected by the scheduled re-validation of the
+// deployed configuration, surfaced before it causes an outage
+// +k8s:openapi-gen=true
+type ConfigFinding struct {
+	// Check that produced the finding, e.g. "tls-certificate" or "jenkins"
+	Check string `json:"check"`
+	// Resource or endpoint the finding is about
+	// +optional
+	Target string `json:"target,omitempty"`
+	// Human readable description of the problem
+	Message string `json:"message"`
+}
+
+// RolloutIssue describes a generated deployment that did not become ready
+// within the configured progress deadline
+// +k8s:openapi-gen=true
+type RolloutIssue struct {
+	// Name of the failing deployment
+	Service string `json:"service"`
+	// Summary of the warning events recorded for its pods
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SmokeTestResult is the outcome of a single endpoint smoke test
+// +k8s:openapi-gen=true
+type SmokeTestResult struct {
+	// Name of the smoke test
+	Name string `json:"name"`
+	// URL the test was run against
+	Url string `json:"url,omitempty"`
+	// Whether the endpoint responded as expected
+	Passed bool `json:"passed"`
+	// Failure detail when the test did not pass
+	// +optional
+	Detail string `json:"detail,omitempty"`
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SmokeTestResult) DeepCopyInto(out *SmokeTestResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SmokeTestResult.
+func (in *SmokeTestResult) DeepCopy() *SmokeTestResult {
+	if in == nil {
+		return nil
+	}
+	out := new(SmokeTestResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// RedisMigrationStatus tracks the phases of a migration from the embedded Redis
+// to an external backend
+// +k8s:openapi-gen=true
+type RedisMigrationStatus struct {
+	// Current phase: Switching, Verifying, Completed or RolledBack
+	Phase string `json:"phase,omitempty"`
+	// Redis URL the services are being switched to
+	TargetUrl string `json:"targetUrl,omitempty"`
+	// Redis URL in use before the migration, empty for the embedded Redis
+	PreviousUrl string `json:"previousUrl,omitempty"`
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisMigrationStatus) DeepCopyInto(out *RedisMigrationStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisMigrationStatus.
+func (in *RedisMigrationStatus) DeepCopy() *RedisMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// +k8s:openapi-gen=true
+type HashStatus struct {
+	Hash          string  `json:"hash"`
+	LastUpdatedAt v1.Time `json:"lastUpdatedAt,omitempty"`
+}
+
+// SpinnakerAccountSpec defines the desired state of SpinnakerAccount
+// +k8s:openapi-gen=true
+type SpinnakerAccountSpec struct {
+	Enabled bool        `json:"enabled"`
+	Type    AccountType `json:"type"`
+	// +optional
+	Validation ValidationSetting `json:"validation"`
+	// +optional
+	Permissions AccountPermissions `json:"permissions"`
+	// +optional
+	Kubernetes *KubernetesAuth `json:"kubernetes,omitempty"`
+	// +optional
+	Settings FreeForm `json:"settings,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type KubernetesAuth struct {
+	// KubeconfigFile referenced as an encrypted secret
+	// +optional
+	KubeconfigFile string `json:"kubeconfigFile,omitempty"`
+	// Kubeconfig referenced as a Kubernetes secret
+	// +optional
+	KubeconfigSecret *SecretInNamespaceReference `json:"kubeconfigSecret,omitempty"`
+	// Kubeconfig config referenced directly
+	// +optional
+	Kubeconfig *clientv1.Config `json:"kubeconfig,omitempty"`
+	// UseServiceAccount authenticate to the target cluster using the service account mounted in Spinnaker's pods
+	// +optional
+	UseServiceAccount bool `json:"useServiceAccount"`
+}
+
+// +k8s:openapi-gen=true
+type SecretInNamespaceReference struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// SpinnakerAccountStatus defines the observed state of SpinnakerAccount
+// +k8s:openapi-gen=true
+type SpinnakerAccountStatus struct {
+	InvalidReason   string        `json:"invalidReason"`
+	LastValidatedAt *v1.Timestamp `json:"lastValidatedAt"`
+}
+
+var _ TypesFactory = &TypesFactoryImpl{}
+
+type TypesFactoryImpl struct {
+	Factories map[Version]TypesFactory
+}
+
+func (f *TypesFactoryImpl) NewService() SpinnakerService {
+	return f.Factories[LatestVersion].NewService()
+}
+
+func (f *TypesFactoryImpl) NewServiceList() SpinnakerServiceList {
+	return f.Factories[LatestVersion].NewServiceList()
+}
+
+func (f *TypesFactoryImpl) NewAccount() SpinnakerAccount {
+	return f.Factories[LatestVersion].NewAccount()
+}
+
+func (f *TypesFactoryImpl) NewAccountList() SpinnakerAccountList {
+	return f.Factories[LatestVersion].NewAccountList()
+}
+
+func (f *TypesFactoryImpl) GetGroupVersion() schema.GroupVersion {
+	return f.Factories[LatestVersion].GetGroupVersion()
+}
+
+func (f *TypesFactoryImpl) DeepCopyLatestTypesFactory() TypesFactory {
+	return f.Factories[LatestVersion].DeepCopyLatestTypesFactory()
+}
+
+func IsNil(i interface{}) bool {
+	if i == nil {
+		return true
+	}
+	switch reflect.TypeOf(i).Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Array, reflect.Chan, reflect.Slice:
+		return reflect.ValueOf(i).IsNil()
+	}
+	return false
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpinnakerValidation) DeepCopyInto(out *SpinnakerValidation) {
+	*out = *in
+	if in.FailOnError != nil {
+		in, out := &in.FailOnError, &out.FailOnError
+		*out = new(bool)
+		**out = **in
+	}
+	out.FrequencySeconds = in.FrequencySeconds
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make(map[string]ValidationSetting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.PersistentStorage != nil {
+		in, out := &in.PersistentStorage, &out.PersistentStorage
+		*out = make(map[string]ValidationSetting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.MetricStores != nil {
+		in, out := &in.MetricStores, &out.MetricStores
+		*out = make(map[string]ValidationSetting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make(map[string]ValidationSetting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.CI != nil {
+		in, out := &in.CI, &out.CI
+		*out = make(map[string]ValidationSetting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Pubsub != nil {
+		in, out := &in.Pubsub, &out.Pubsub
+		*out = make(map[string]ValidationSetting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = make(map[string]ValidationSetting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerValidation.
+func (in *SpinnakerValidation) DeepCopy() *SpinnakerValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(SpinnakerValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationSetting) DeepCopyInto(out *ValidationSetting) {
+	*out = *in
+	if in.FailOnError != nil {
+		in, out := &in.FailOnError, &out.FailOnError
+		*out = new(bool)
+		**out = **in
+	}
+	out.FrequencySeconds = in.FrequencySeconds
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationSetting.
+func (in *ValidationSetting) DeepCopy() *ValidationSetting {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationSetting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeConfig) DeepCopyInto(out *ExposeConfig) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+	in.Ingress.DeepCopyInto(&out.Ingress)
+	return
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeConfigIngress) DeepCopyInto(out *ExposeConfigIngress) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfigIngress.
+func (in *ExposeConfigIngress) DeepCopy() *ExposeConfigIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeConfigIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfig.
+func (in *ExposeConfig) DeepCopy() *ExposeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeConfigService) DeepCopyInto(out *ExposeConfigService) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make(map[string]ExposeConfigServiceOverrides, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfigService.
+func (in *ExposeConfigService) DeepCopy() *ExposeConfigService {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeConfigService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeConfigServiceOverrides) DeepCopyInto(out *ExposeConfigServiceOverrides) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfigServiceOverrides.
+func (in *ExposeConfigServiceOverrides) DeepCopy() *ExposeConfigServiceOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeConfigServiceOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchitectureConfig) DeepCopyInto(out *ArchitectureConfig) {
+	*out = *in
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchitectureConfig.
+func (in *ArchitectureConfig) DeepCopy() *ArchitectureConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchitectureConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplyPolicyConfig) DeepCopyInto(out *ApplyPolicyConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyPolicyConfig.
+func (in *ApplyPolicyConfig) DeepCopy() *ApplyPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplyPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamingConfig) DeepCopyInto(out *NamingConfig) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamingConfig.
+func (in *NamingConfig) DeepCopy() *NamingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NamingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyConfig) DeepCopyInto(out *PolicyConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyConfig.
+func (in *PolicyConfig) DeepCopy() *PolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountConfig) DeepCopyInto(out *AccountConfig) {
+	*out = *in
+	if in.BootstrapSelector != nil {
+		in, out := &in.BootstrapSelector, &out.BootstrapSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountConfig.
+func (in *AccountConfig) DeepCopy() *AccountConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HashStatus) DeepCopyInto(out *HashStatus) {
+	*out = *in
+	in.LastUpdatedAt.DeepCopyInto(&out.LastUpdatedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashStatus.
+func (in *HashStatus) DeepCopy() *HashStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HashStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesAuth) DeepCopyInto(out *KubernetesAuth) {
+	*out = *in
+	if in.KubeconfigSecret != nil {
+		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = new(clientv1.Config)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesAuth.
+func (in *KubernetesAuth) DeepCopy() *KubernetesAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretInNamespaceReference) DeepCopyInto(out *SecretInNamespaceReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretInNamespaceReference.
+func (in *SecretInNamespaceReference) DeepCopy() *SecretInNamespaceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretInNamespaceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpinnakerAccountSpec) DeepCopyInto(out *SpinnakerAccountSpec) {
+	*out = *in
+	in.Validation.DeepCopyInto(&out.Validation)
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make(AccountPermissions, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(KubernetesAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Settings.DeepCopyInto(&out.Settings)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerAccountSpec.
+func (in *SpinnakerAccountSpec) DeepCopy() *SpinnakerAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SpinnakerAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpinnakerAccountStatus) DeepCopyInto(out *SpinnakerAccountStatus) {
+	*out = *in
+	if in.LastValidatedAt != nil {
+		in, out := &in.LastValidatedAt, &out.LastValidatedAt
+		*out = new(v1.Timestamp)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerAccountStatus.
+func (in *SpinnakerAccountStatus) DeepCopy() *SpinnakerAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SpinnakerAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpinnakerDeploymentStatus) DeepCopyInto(out *SpinnakerDeploymentStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerDeploymentStatus.
+func (in *SpinnakerDeploymentStatus) DeepCopy() *SpinnakerDeploymentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SpinnakerDeploymentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpinnakerCondition) DeepCopyInto(out *SpinnakerCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerCondition.
+func (in *SpinnakerCondition) DeepCopy() *SpinnakerCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(SpinnakerCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpinnakerServiceSpec) DeepCopyInto(out *SpinnakerServiceSpec) {
+	*out = *in
+	in.SpinnakerConfig.DeepCopyInto(&out.SpinnakerConfig)
+	in.Validation.DeepCopyInto(&out.Validation)
+	in.Expose.DeepCopyInto(&out.Expose)
+	in.Accounts.DeepCopyInto(&out.Accounts)
+	out.Policies = in.Policies
+	in.ImageVerification.DeepCopyInto(&out.ImageVerification)
+	out.Qos = in.Qos
+	in.SecretAgent.DeepCopyInto(&out.SecretAgent)
+	out.CacheWarming = in.CacheWarming
+	out.Management = in.Management
+	out.Redis = in.Redis
+	out.Orca = in.Orca
+	out.DeckAssets = in.DeckAssets
+	in.Architecture.DeepCopyInto(&out.Architecture)
+	in.Naming.DeepCopyInto(&out.Naming)
+	out.Apply = in.Apply
+	in.Freeze.DeepCopyInto(&out.Freeze)
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	if in.SecretFiles != nil {
+		in, out := &in.SecretFiles, &out.SecretFiles
+		*out = make([]SecretFile, len(*in))
+		copy(*out, *in)
+	}
+	out.Proxy = in.Proxy
+	in.TargetNamespace.DeepCopyInto(&out.TargetNamespace)
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = make(map[string]ServiceLifecycle, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	out.SecretEncryption = in.SecretEncryption
+	in.Scheduling.DeepCopyInto(&out.Scheduling)
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	out.TargetCluster = in.TargetCluster
+	in.Security.DeepCopyInto(&out.Security)
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make(map[string]ServiceSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ServiceOverrides != nil {
+		in, out := &in.ServiceOverrides, &out.ServiceOverrides
+		*out = make(map[string]ServiceOverride, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = make(map[string]ServiceTuning, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StatefulServices != nil {
+		in, out := &in.StatefulServices, &out.StatefulServices
+		*out = make(map[string]StatefulServiceConfig, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make(map[string]ArtifactOverride, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.NetworkPolicy.DeepCopyInto(&out.NetworkPolicy)
+	if in.ImageRegistryOverrides != nil {
+		in, out := &in.ImageRegistryOverrides, &out.ImageRegistryOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.GitOps = in.GitOps
+	return
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ServiceResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(SchedulingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(MetadataConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingConfig) DeepCopyInto(out *SchedulingConfig) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingConfig.
+func (in *SchedulingConfig) DeepCopy() *SchedulingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpec.
+func (in *ServiceSpec) DeepCopy() *ServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceResources) DeepCopyInto(out *ServiceResources) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceResources.
+func (in *ServiceResources) DeepCopy() *ServiceResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretEncryptionConfig) DeepCopyInto(out *SecretEncryptionConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretEncryptionConfig.
+func (in *SecretEncryptionConfig) DeepCopy() *SecretEncryptionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretEncryptionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceOverride) DeepCopyInto(out *ServiceOverride) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ServicePortOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOverride.
+func (in *ServiceOverride) DeepCopy() *ServiceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServicePortOverride) DeepCopyInto(out *ServicePortOverride) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServicePortOverride.
+func (in *ServicePortOverride) DeepCopy() *ServicePortOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ServicePortOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringConfig) DeepCopyInto(out *MonitoringConfig) {
+	*out = *in
+	if in.RuleLabels != nil {
+		in, out := &in.RuleLabels, &out.RuleLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfig.
+func (in *MonitoringConfig) DeepCopy() *MonitoringConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreezeConfig) DeepCopyInto(out *FreezeConfig) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]FreezeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreezeConfig.
+func (in *FreezeConfig) DeepCopy() *FreezeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FreezeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreezeWindow) DeepCopyInto(out *FreezeWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreezeWindow.
+func (in *FreezeWindow) DeepCopy() *FreezeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(FreezeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulServiceConfig) DeepCopyInto(out *StatefulServiceConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulServiceConfig.
+func (in *StatefulServiceConfig) DeepCopy() *StatefulServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceLifecycle) DeepCopyInto(out *ServiceLifecycle) {
+	*out = *in
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PreStopCommand != nil {
+		in, out := &in.PreStopCommand, &out.PreStopCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostStartCommand != nil {
+		in, out := &in.PostStartCommand, &out.PostStartCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceLifecycle.
+func (in *ServiceLifecycle) DeepCopy() *ServiceLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerificationConfig) DeepCopyInto(out *ImageVerificationConfig) {
+	*out = *in
+	if in.CosignPublicKeys != nil {
+		in, out := &in.CosignPublicKeys, &out.CosignPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrustedDigests != nil {
+		in, out := &in.TrustedDigests, &out.TrustedDigests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
 }
 
-func (f *TypesFactoryImpl) GetGroupVersion() schema.GroupVersion {
-	return f.Factories[LatestVersion].GetGroupVersion()
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerificationConfig.
+func (in *ImageVerificationConfig) DeepCopy() *ImageVerificationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerificationConfig)
+	in.DeepCopyInto(out)
+	return out
 }
 
-func (f *TypesFactoryImpl) DeepCopyLatestTypesFactory() TypesFactory {
-	return f.Factories[LatestVersion].DeepCopyLatestTypesFactory()
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsConfig) DeepCopyInto(out *GitOpsConfig) {
+	*out = *in
+	return
 }
 
-func IsNil(i interface{}) bool {
-	if i == nil {
-		return true
-	}
-	switch reflect.TypeOf(i).Kind() {
-	case reflect.Ptr, reflect.Map, reflect.Array, reflect.Chan, reflect.Slice:
-		return reflect.ValueOf(i).IsNil()
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsConfig.
+func (in *GitOpsConfig) DeepCopy() *GitOpsConfig {
+	if in == nil {
+		return nil
 	}
-	return false
+	out := new(GitOpsConfig)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SpinnakerValidation) DeepCopyInto(out *SpinnakerValidation) {
+func (in *NetworkPolicyConfig) DeepCopyInto(out *NetworkPolicyConfig) {
 	*out = *in
-	if in.FailOnError != nil {
-		in, out := &in.FailOnError, &out.FailOnError
-		*out = new(bool)
-		**out = **in
-	}
-	out.FrequencySeconds = in.FrequencySeconds
-	if in.Providers != nil {
-		in, out := &in.Providers, &out.Providers
-		*out = make(map[string]ValidationSetting, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.PersistentStorage != nil {
-		in, out := &in.PersistentStorage, &out.PersistentStorage
-		*out = make(map[string]ValidationSetting, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.MetricStores != nil {
-		in, out := &in.MetricStores, &out.MetricStores
-		*out = make(map[string]ValidationSetting, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.Notifications != nil {
-		in, out := &in.Notifications, &out.Notifications
-		*out = make(map[string]ValidationSetting, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.CI != nil {
-		in, out := &in.CI, &out.CI
-		*out = make(map[string]ValidationSetting, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.Pubsub != nil {
-		in, out := &in.Pubsub, &out.Pubsub
-		*out = make(map[string]ValidationSetting, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.Canary != nil {
-		in, out := &in.Canary, &out.Canary
-		*out = make(map[string]ValidationSetting, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerValidation.
-func (in *SpinnakerValidation) DeepCopy() *SpinnakerValidation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfig.
+func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SpinnakerValidation)
+	out := new(NetworkPolicyConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ValidationSetting) DeepCopyInto(out *ValidationSetting) {
+func (in *ArtifactOverride) DeepCopyInto(out *ArtifactOverride) {
 	*out = *in
-	if in.FailOnError != nil {
-		in, out := &in.FailOnError, &out.FailOnError
-		*out = new(bool)
-		**out = **in
-	}
-	out.FrequencySeconds = in.FrequencySeconds
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationSetting.
-func (in *ValidationSetting) DeepCopy() *ValidationSetting {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactOverride.
+func (in *ArtifactOverride) DeepCopy() *ArtifactOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(ValidationSetting)
+	out := new(ArtifactOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExposeConfig) DeepCopyInto(out *ExposeConfig) {
+func (in *ServiceTuning) DeepCopyInto(out *ServiceTuning) {
 	*out = *in
-	in.Service.DeepCopyInto(&out.Service)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfig.
-func (in *ExposeConfig) DeepCopy() *ExposeConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTuning.
+func (in *ServiceTuning) DeepCopy() *ServiceTuning {
 	if in == nil {
 		return nil
 	}
-	out := new(ExposeConfig)
+	out := new(ServiceTuning)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExposeConfigService) DeepCopyInto(out *ExposeConfigService) {
+func (in *CacheWarmingConfig) DeepCopyInto(out *CacheWarmingConfig) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Overrides != nil {
-		in, out := &in.Overrides, &out.Overrides
-		*out = make(map[string]ExposeConfigServiceOverrides, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfigService.
-func (in *ExposeConfigService) DeepCopy() *ExposeConfigService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheWarmingConfig.
+func (in *CacheWarmingConfig) DeepCopy() *CacheWarmingConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ExposeConfigService)
+	out := new(CacheWarmingConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExposeConfigServiceOverrides) DeepCopyInto(out *ExposeConfigServiceOverrides) {
+func (in *SecretAgentConfig) DeepCopyInto(out *SecretAgentConfig) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]map[string]string, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			var outVal map[string]string
+			if val != nil {
+				outVal = make(map[string]string, len(val))
+				for k, v := range val {
+					outVal[k] = v
+				}
+			}
+			(*out)[key] = outVal
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfigServiceOverrides.
-func (in *ExposeConfigServiceOverrides) DeepCopy() *ExposeConfigServiceOverrides {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretAgentConfig.
+func (in *SecretAgentConfig) DeepCopy() *SecretAgentConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ExposeConfigServiceOverrides)
+	out := new(SecretAgentConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AccountConfig) DeepCopyInto(out *AccountConfig) {
+func (in *QosConfig) DeepCopyInto(out *QosConfig) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountConfig.
-func (in *AccountConfig) DeepCopy() *AccountConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QosConfig.
+func (in *QosConfig) DeepCopy() *QosConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(AccountConfig)
+	out := new(QosConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HashStatus) DeepCopyInto(out *HashStatus) {
+func (in *ManagementConfig) DeepCopyInto(out *ManagementConfig) {
 	*out = *in
-	in.LastUpdatedAt.DeepCopyInto(&out.LastUpdatedAt)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashStatus.
-func (in *HashStatus) DeepCopy() *HashStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementConfig.
+func (in *ManagementConfig) DeepCopy() *ManagementConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(HashStatus)
+	out := new(ManagementConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubernetesAuth) DeepCopyInto(out *KubernetesAuth) {
+func (in *DeckAssetsConfig) DeepCopyInto(out *DeckAssetsConfig) {
 	*out = *in
-	if in.KubeconfigSecret != nil {
-		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
-		*out = new(SecretInNamespaceReference)
-		**out = **in
-	}
-	if in.Kubeconfig != nil {
-		in, out := &in.Kubeconfig, &out.Kubeconfig
-		*out = new(clientv1.Config)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesAuth.
-func (in *KubernetesAuth) DeepCopy() *KubernetesAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeckAssetsConfig.
+func (in *DeckAssetsConfig) DeepCopy() *DeckAssetsConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(KubernetesAuth)
+	out := new(DeckAssetsConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretInNamespaceReference) DeepCopyInto(out *SecretInNamespaceReference) {
+func (in *OrcaConfig) DeepCopyInto(out *OrcaConfig) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretInNamespaceReference.
-func (in *SecretInNamespaceReference) DeepCopy() *SecretInNamespaceReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrcaConfig.
+func (in *OrcaConfig) DeepCopy() *OrcaConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretInNamespaceReference)
+	out := new(OrcaConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SpinnakerAccountSpec) DeepCopyInto(out *SpinnakerAccountSpec) {
+func (in *RedisConfig) DeepCopyInto(out *RedisConfig) {
 	*out = *in
-	in.Validation.DeepCopyInto(&out.Validation)
-	if in.Permissions != nil {
-		in, out := &in.Permissions, &out.Permissions
-		*out = make(AccountPermissions, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.Kubernetes != nil {
-		in, out := &in.Kubernetes, &out.Kubernetes
-		*out = new(KubernetesAuth)
-		(*in).DeepCopyInto(*out)
-	}
-	in.Settings.DeepCopyInto(&out.Settings)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerAccountSpec.
-func (in *SpinnakerAccountSpec) DeepCopy() *SpinnakerAccountSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisConfig.
+func (in *RedisConfig) DeepCopy() *RedisConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SpinnakerAccountSpec)
+	out := new(RedisConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SpinnakerAccountStatus) DeepCopyInto(out *SpinnakerAccountStatus) {
+func (in *TargetNamespaceConfig) DeepCopyInto(out *TargetNamespaceConfig) {
 	*out = *in
-	if in.LastValidatedAt != nil {
-		in, out := &in.LastValidatedAt, &out.LastValidatedAt
-		*out = new(v1.Timestamp)
-		**out = **in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerAccountStatus.
-func (in *SpinnakerAccountStatus) DeepCopy() *SpinnakerAccountStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetNamespaceConfig.
+func (in *TargetNamespaceConfig) DeepCopy() *TargetNamespaceConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SpinnakerAccountStatus)
+	out := new(TargetNamespaceConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SpinnakerDeploymentStatus) DeepCopyInto(out *SpinnakerDeploymentStatus) {
+func (in *ProxyConfig) DeepCopyInto(out *ProxyConfig) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerDeploymentStatus.
-func (in *SpinnakerDeploymentStatus) DeepCopy() *SpinnakerDeploymentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfig.
+func (in *ProxyConfig) DeepCopy() *ProxyConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SpinnakerDeploymentStatus)
+	out := new(ProxyConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SpinnakerServiceSpec) DeepCopyInto(out *SpinnakerServiceSpec) {
+func (in *SecretFile) DeepCopyInto(out *SecretFile) {
 	*out = *in
-	in.SpinnakerConfig.DeepCopyInto(&out.SpinnakerConfig)
-	in.Validation.DeepCopyInto(&out.Validation)
-	in.Expose.DeepCopyInto(&out.Expose)
-	out.Accounts = in.Accounts
 	return
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretFile.
+func (in *SecretFile) DeepCopy() *SecretFile {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerServiceSpec.
 func (in *SpinnakerServiceSpec) DeepCopy() *SpinnakerServiceSpec {
 	if in == nil {
@@ -695,11 +2522,82 @@ func (in *SpinnakerServiceStatus) DeepCopyInto(out *SpinnakerServiceStatus) {
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]SpinnakerDeploymentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RedisMigration != nil {
+		in, out := &in.RedisMigration, &out.RedisMigration
+		*out = new(RedisMigrationStatus)
+		**out = **in
+	}
+	if in.SmokeTests != nil {
+		in, out := &in.SmokeTests, &out.SmokeTests
+		*out = make([]SmokeTestResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.StuckRollouts != nil {
+		in, out := &in.StuckRollouts, &out.StuckRollouts
+		*out = make([]RolloutIssue, len(*in))
 		copy(*out, *in)
 	}
+	if in.Findings != nil {
+		in, out := &in.Findings, &out.Findings
+		*out = make([]ConfigFinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]SpinnakerCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastPromotion != nil {
+		in, out := &in.LastPromotion, &out.LastPromotion
+		*out = new(PromotionRecord)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigFinding) DeepCopyInto(out *ConfigFinding) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigFinding.
+func (in *ConfigFinding) DeepCopy() *ConfigFinding {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigFinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutIssue) DeepCopyInto(out *RolloutIssue) {
+	*out = *in
 	return
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutIssue.
+func (in *RolloutIssue) DeepCopy() *RolloutIssue {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutIssue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerServiceStatus.
 func (in *SpinnakerServiceStatus) DeepCopy() *SpinnakerServiceStatus {
 	if in == nil {
@@ -27,10 +27,146 @@ func (s *SpinnakerService) GetAccountConfig() *interfaces.AccountConfig {
 	return &s.Spec.Accounts
 }
 
+func (s *SpinnakerService) GetArchitectureConfig() *interfaces.ArchitectureConfig {
+	return &s.Spec.Architecture
+}
+
+func (s *SpinnakerService) GetArtifacts() map[string]interfaces.ArtifactOverride {
+	return s.Spec.Artifacts
+}
+
+func (s *SpinnakerService) GetNetworkPolicyConfig() *interfaces.NetworkPolicyConfig {
+	return &s.Spec.NetworkPolicy
+}
+
+func (s *SpinnakerService) GetImageRegistry() string {
+	return s.Spec.ImageRegistry
+}
+
+func (s *SpinnakerService) GetImageRegistryOverrides() map[string]string {
+	return s.Spec.ImageRegistryOverrides
+}
+
+func (s *SpinnakerService) GetGitOpsConfig() *interfaces.GitOpsConfig {
+	return &s.Spec.GitOps
+}
+
+func (s *SpinnakerService) GetApplyPolicyConfig() *interfaces.ApplyPolicyConfig {
+	return &s.Spec.Apply
+}
+
+func (s *SpinnakerService) GetNamingConfig() *interfaces.NamingConfig {
+	return &s.Spec.Naming
+}
+
+func (s *SpinnakerService) GetPolicyConfig() *interfaces.PolicyConfig {
+	return &s.Spec.Policies
+}
+
+func (s *SpinnakerService) GetImageVerificationConfig() *interfaces.ImageVerificationConfig {
+	return &s.Spec.ImageVerification
+}
+
+func (s *SpinnakerService) GetQosConfig() *interfaces.QosConfig {
+	return &s.Spec.Qos
+}
+
+func (s *SpinnakerService) GetSecretAgentConfig() *interfaces.SecretAgentConfig {
+	return &s.Spec.SecretAgent
+}
+
+func (s *SpinnakerService) GetCacheWarmingConfig() *interfaces.CacheWarmingConfig {
+	return &s.Spec.CacheWarming
+}
+
+func (s *SpinnakerService) GetTuning() map[string]interfaces.ServiceTuning {
+	return s.Spec.Tuning
+}
+
+func (s *SpinnakerService) GetStatefulServices() map[string]interfaces.StatefulServiceConfig {
+	return s.Spec.StatefulServices
+}
+
+func (s *SpinnakerService) GetManagementConfig() *interfaces.ManagementConfig {
+	return &s.Spec.Management
+}
+
+func (s *SpinnakerService) GetRedisConfig() *interfaces.RedisConfig {
+	return &s.Spec.Redis
+}
+
+func (s *SpinnakerService) GetOrcaConfig() *interfaces.OrcaConfig {
+	return &s.Spec.Orca
+}
+
+func (s *SpinnakerService) GetFreezeConfig() *interfaces.FreezeConfig {
+	return &s.Spec.Freeze
+}
+
+func (s *SpinnakerService) GetMonitoringConfig() *interfaces.MonitoringConfig {
+	return &s.Spec.Monitoring
+}
+
+func (s *SpinnakerService) GetDeckAssetsConfig() *interfaces.DeckAssetsConfig {
+	return &s.Spec.DeckAssets
+}
+
+func (s *SpinnakerService) GetDeploymentPlan() string {
+	return s.Spec.DeploymentPlan
+}
+
+func (s *SpinnakerService) GetProfile() string {
+	return s.Spec.Profile
+}
+
+func (s *SpinnakerService) GetProxyConfig() *interfaces.ProxyConfig {
+	return &s.Spec.Proxy
+}
+
+func (s *SpinnakerService) GetSecretFiles() []interfaces.SecretFile {
+	return s.Spec.SecretFiles
+}
+
+func (s *SpinnakerService) GetTargetNamespaceConfig() *interfaces.TargetNamespaceConfig {
+	return &s.Spec.TargetNamespace
+}
+
 func (s *SpinnakerService) GetKustomization() map[string]interfaces.ServiceKustomization {
 	return s.Spec.Kustomize
 }
 
+func (s *SpinnakerService) GetServiceOverrides() map[string]interfaces.ServiceOverride {
+	return s.Spec.ServiceOverrides
+}
+
+func (s *SpinnakerService) GetSecretEncryptionConfig() *interfaces.SecretEncryptionConfig {
+	return &s.Spec.SecretEncryption
+}
+
+func (s *SpinnakerService) GetServiceSpecs() map[string]interfaces.ServiceSpec {
+	return s.Spec.Services
+}
+
+func (s *SpinnakerService) GetSchedulingConfig() *interfaces.SchedulingConfig {
+	return &s.Spec.Scheduling
+}
+
+func (s *SpinnakerService) GetMetadataConfig() *interfaces.MetadataConfig {
+	return &s.Spec.Metadata
+}
+
+func (s *SpinnakerService) GetTargetClusterConfig() *interfaces.TargetClusterConfig {
+	return &s.Spec.TargetCluster
+}
+
+func (s *SpinnakerService) GetSecurityConfig() *interfaces.SecurityConfig {
+	return &s.Spec.Security
+}
+
+func (s *SpinnakerService) GetLifecycle() map[string]interfaces.ServiceLifecycle {
+	return s.Spec.Lifecycle
+}
+
 func (s *SpinnakerService) GetStatus() *interfaces.SpinnakerServiceStatus {
 	return &s.Status
 }
@@ -0,0 +1,47 @@
+package changefeed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// maxEntriesPerService bounds the in-memory history per SpinnakerService
+const maxEntriesPerService = 100
+
+// Entry is one recorded configuration change: who changed what, when
+type Entry struct {
+	// Time the change was admitted
+	Time time.Time `json:"time"`
+	// Actor is the Kubernetes user that submitted the change
+	Actor string `json:"actor"`
+	// Operation is the admission operation, e.g. CREATE or UPDATE
+	Operation string `json:"operation"`
+	// Diff between the previous and the new configuration
+	Diff *interfaces.ConfigDiff `json:"diff,omitempty"`
+}
+
+var (
+	mtx      sync.RWMutex
+	registry = map[string][]Entry{}
+)
+
+// Append records a change for a service, keyed by "namespace/name", keeping
+// the most recent entries
+func Append(key string, e Entry) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	entries := append(registry[key], e)
+	if len(entries) > maxEntriesPerService {
+		entries = entries[len(entries)-maxEntriesPerService:]
+	}
+	registry[key] = entries
+}
+
+// Get returns the recorded changes of a service, most recent last
+func Get(key string) []Entry {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	return registry[key]
+}
@@ -0,0 +1,30 @@
+package changefeed
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendAndGet(t *testing.T) {
+	Append("ns1/spinnaker", Entry{Time: time.Now(), Actor: "alice", Operation: "CREATE"})
+	Append("ns1/spinnaker", Entry{Time: time.Now(), Actor: "bob", Operation: "UPDATE"})
+
+	entries := Get("ns1/spinnaker")
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "alice", entries[0].Actor)
+		assert.Equal(t, "bob", entries[1].Actor)
+	}
+	assert.Empty(t, Get("ns1/other"))
+}
+
+func TestAppendKeepsMostRecent(t *testing.T) {
+	for i := 0; i < maxEntriesPerService+10; i++ {
+		Append("ns2/spinnaker", Entry{Actor: fmt.Sprintf("user-%d", i)})
+	}
+	entries := Get("ns2/spinnaker")
+	assert.Len(t, entries, maxEntriesPerService)
+	assert.Equal(t, fmt.Sprintf("user-%d", maxEntriesPerService+9), entries[len(entries)-1].Actor)
+}
@@ -0,0 +1,14 @@
+package chaos
+
+// Names of the failure injection points compiled into the operator. They only
+// do anything in builds with the "chaos" tag, see chaos_enabled.go
+const (
+	// PointHalyardGenerate hangs or fails the Halyard manifest generation
+	PointHalyardGenerate = "halyard-generate"
+	// PointApply fails saving a generated manifest, arming it with a skip
+	// count simulates a partial apply
+	PointApply = "apply"
+	// PointStatusUpdate fails the status update closing a deployment with a
+	// conflict, like a concurrent writer would
+	PointStatusUpdate = "status-update"
+)
@@ -0,0 +1,19 @@
+// +build !chaos
+
+package chaos
+
+import (
+	"context"
+	"net/http"
+)
+
+// Enabled reports whether failure injection was compiled in
+const Enabled = false
+
+// Fire is a no-op in release builds, the calls compile away
+func Fire(ctx context.Context, point string) error {
+	return nil
+}
+
+// Register is a no-op in release builds, no chaos endpoints are served
+func Register(mux *http.ServeMux) {}
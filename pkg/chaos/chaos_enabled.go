@@ -0,0 +1,139 @@
+// +build chaos
+
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Enabled reports whether failure injection was compiled in
+const Enabled = true
+
+const (
+	// ModeError makes the point return a plain error
+	ModeError = "error"
+	// ModeConflict makes the point return a Kubernetes conflict error
+	ModeConflict = "conflict"
+	// ModeHang makes the point block until the context is cancelled
+	ModeHang = "hang"
+)
+
+// armedPoint is an injection point primed to fail
+type armedPoint struct {
+	Point string `json:"point"`
+	Mode  string `json:"mode"`
+	// Remaining fires before the point disarms itself, -1 keeps it armed
+	Remaining int `json:"remaining"`
+	// Skip passes this many fires through before failing, simulating a
+	// failure partway through a sequence
+	Skip int `json:"skip,omitempty"`
+	// Fired counts how often the point was hit since it was armed
+	Fired int `json:"fired"`
+}
+
+var (
+	mtx   sync.Mutex
+	armed = map[string]*armedPoint{}
+)
+
+// Fire fails, hangs or passes depending on how the point is armed
+func Fire(ctx context.Context, point string) error {
+	mtx.Lock()
+	a, ok := armed[point]
+	if !ok {
+		mtx.Unlock()
+		return nil
+	}
+	a.Fired++
+	if a.Skip > 0 {
+		a.Skip--
+		mtx.Unlock()
+		return nil
+	}
+	if a.Remaining > 0 {
+		a.Remaining--
+		if a.Remaining == 0 {
+			delete(armed, point)
+		}
+	}
+	mode := a.Mode
+	mtx.Unlock()
+
+	switch mode {
+	case ModeHang:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Minute):
+			return fmt.Errorf("chaos: point %s hung without context cancellation", point)
+		}
+	case ModeConflict:
+		return kerrors.NewConflict(schema.GroupResource{Group: "spinnaker.io", Resource: "spinnakerservices"}, point, fmt.Errorf("chaos: injected conflict"))
+	default:
+		return fmt.Errorf("chaos: injected failure at %s", point)
+	}
+}
+
+// Register serves the chaos API: GET lists armed points, POST arms one with
+// point, mode, count and skip parameters, DELETE disarms
+func Register(mux *http.ServeMux) {
+	mux.HandleFunc("/v1/chaos", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			mtx.Lock()
+			list := make([]*armedPoint, 0, len(armed))
+			for _, a := range armed {
+				list = append(list, a)
+			}
+			mtx.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(list)
+		case http.MethodPost:
+			point := req.URL.Query().Get("point")
+			if point == "" {
+				http.Error(w, "missing point parameter", http.StatusBadRequest)
+				return
+			}
+			a := &armedPoint{Point: point, Mode: ModeError, Remaining: 1}
+			if m := req.URL.Query().Get("mode"); m != "" {
+				a.Mode = m
+			}
+			if c := req.URL.Query().Get("count"); c != "" {
+				n, err := strconv.Atoi(c)
+				if err != nil {
+					http.Error(w, "invalid count parameter", http.StatusBadRequest)
+					return
+				}
+				a.Remaining = n
+			}
+			if s := req.URL.Query().Get("skip"); s != "" {
+				n, err := strconv.Atoi(s)
+				if err != nil {
+					http.Error(w, "invalid skip parameter", http.StatusBadRequest)
+					return
+				}
+				a.Skip = n
+			}
+			mtx.Lock()
+			armed[point] = a
+			mtx.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			mtx.Lock()
+			delete(armed, req.URL.Query().Get("point"))
+			mtx.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
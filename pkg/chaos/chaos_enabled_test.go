@@ -0,0 +1,71 @@
+// +build chaos
+
+package chaos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func arm(t *testing.T, mux *http.ServeMux, query string) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/chaos?"+query, nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestFire_disarmsAfterCount(t *testing.T) {
+	mux := http.NewServeMux()
+	Register(mux)
+	arm(t, mux, "point="+PointApply+"&count=1")
+
+	assert.NotNil(t, Fire(context.TODO(), PointApply))
+	assert.Nil(t, Fire(context.TODO(), PointApply))
+}
+
+func TestFire_skipSimulatesPartialApply(t *testing.T) {
+	mux := http.NewServeMux()
+	Register(mux)
+	arm(t, mux, "point="+PointApply+"&count=1&skip=2")
+
+	assert.Nil(t, Fire(context.TODO(), PointApply))
+	assert.Nil(t, Fire(context.TODO(), PointApply))
+	assert.NotNil(t, Fire(context.TODO(), PointApply))
+	assert.Nil(t, Fire(context.TODO(), PointApply))
+}
+
+func TestFire_conflictMode(t *testing.T) {
+	mux := http.NewServeMux()
+	Register(mux)
+	arm(t, mux, "point="+PointStatusUpdate+"&mode=conflict")
+
+	err := Fire(context.TODO(), PointStatusUpdate)
+	assert.True(t, kerrors.IsConflict(err))
+}
+
+func TestFire_hangHonorsContext(t *testing.T) {
+	mux := http.NewServeMux()
+	Register(mux)
+	arm(t, mux, "point="+PointHalyardGenerate+"&mode=hang")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, Fire(ctx, PointHalyardGenerate))
+}
+
+func TestRegister_disarm(t *testing.T) {
+	mux := http.NewServeMux()
+	Register(mux)
+	arm(t, mux, "point="+PointApply+"&count=-1")
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/chaos?point="+PointApply, nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Nil(t, Fire(context.TODO(), PointApply))
+}
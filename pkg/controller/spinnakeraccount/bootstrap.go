@@ -0,0 +1,152 @@
+package spinnakeraccount
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// BootstrapManagedLabel marks SpinnakerAccounts created from kubeconfig secrets
+	// with the name of the secret they came from
+	BootstrapManagedLabel = "accounts.spinnaker.io/bootstrapped-from"
+	// bootstrapKubeconfigKey is the secret data key read when present, otherwise
+	// the secret must hold a single key
+	bootstrapKubeconfigKey = "kubeconfig"
+)
+
+// addBootstrap watches kubeconfig secrets and turns the ones matching the
+// selector of spec.accounts.bootstrapSelector into Kubernetes SpinnakerAccounts
+func addBootstrap(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("accountbootstrap-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &v1.Secret{}}, &handler.EnqueueRequestForObject{})
+}
+
+var _ reconcile.Reconciler = &ReconcileAccountBootstrap{}
+
+// ReconcileAccountBootstrap reconciles kubeconfig secrets into SpinnakerAccounts
+type ReconcileAccountBootstrap struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+func (r *ReconcileAccountBootstrap) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := context.TODO()
+	secret := &v1.Secret{}
+	if err := r.client.Get(ctx, request.NamespacedName, secret); err != nil {
+		if errors.IsNotFound(err) {
+			// The secret is gone, garbage collect the accounts we created from it
+			return reconcile.Result{}, r.cleanupAccounts(ctx, request.Namespace, request.Name)
+		}
+		return reconcile.Result{}, err
+	}
+
+	spinsvc, err := util.FindSpinnakerService(r.client, request.Namespace, TypesFactory)
+	if err != nil || spinsvc == nil {
+		return reconcile.Result{}, err
+	}
+	selector := spinsvc.GetAccountConfig().BootstrapSelector
+	if len(selector) == 0 || !matchesSelector(secret.Labels, selector) {
+		return reconcile.Result{}, nil
+	}
+	key, err := kubeconfigKey(secret)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, r.upsertAccount(ctx, secret, key)
+}
+
+// matchesSelector returns true when all selector labels are present with the same value
+func matchesSelector(labels map[string]string, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// kubeconfigKey picks the data key holding the kubeconfig
+func kubeconfigKey(secret *v1.Secret) (string, error) {
+	if _, ok := secret.Data[bootstrapKubeconfigKey]; ok {
+		return bootstrapKubeconfigKey, nil
+	}
+	if len(secret.Data) == 1 {
+		for k := range secret.Data {
+			return k, nil
+		}
+	}
+	return "", errors.NewBadRequest("unable to determine kubeconfig key in secret " + secret.Name)
+}
+
+func (r *ReconcileAccountBootstrap) upsertAccount(ctx context.Context, secret *v1.Secret, key string) error {
+	existing := TypesFactory.NewAccount()
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, existing)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		log.Info("bootstrapping Kubernetes account from kubeconfig secret", "metadata.name", secret.Name)
+		return r.client.Create(ctx, newBootstrapAccount(secret, key))
+	}
+	// Never overwrite an account the user defined by hand
+	if existing.GetLabels()[BootstrapManagedLabel] == "" {
+		return nil
+	}
+	desired := newBootstrapAccount(secret, key)
+	if reflect.DeepEqual(existing.GetSpec(), desired.GetSpec()) {
+		return nil
+	}
+	desired.GetSpec().DeepCopyInto(existing.GetSpec())
+	return r.client.Update(ctx, existing)
+}
+
+func newBootstrapAccount(secret *v1.Secret, key string) interfaces.SpinnakerAccount {
+	acc := TypesFactory.NewAccount()
+	acc.SetName(secret.Name)
+	acc.SetNamespace(secret.Namespace)
+	acc.SetLabels(map[string]string{BootstrapManagedLabel: secret.Name})
+	spec := interfaces.SpinnakerAccountSpec{
+		Enabled: true,
+		Type:    interfaces.KubernetesAccountType,
+		Kubernetes: &interfaces.KubernetesAuth{
+			KubeconfigSecret: &interfaces.SecretInNamespaceReference{Name: secret.Name, Key: key},
+		},
+	}
+	spec.DeepCopyInto(acc.GetSpec())
+	return acc
+}
+
+// cleanupAccounts deletes the accounts bootstrapped from a deleted secret, the
+// account controller then removes them from the dynamic config secrets
+func (r *ReconcileAccountBootstrap) cleanupAccounts(ctx context.Context, ns string, name string) error {
+	l := TypesFactory.NewAccountList()
+	if err := r.client.List(ctx, l, client.InNamespace(ns)); err != nil {
+		return err
+	}
+	for _, a := range l.GetItems() {
+		if a.GetLabels()[BootstrapManagedLabel] != name {
+			continue
+		}
+		log.Info("deleting bootstrapped Kubernetes account", "metadata.name", a.GetName())
+		if err := r.client.Delete(ctx, a); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package spinnakeraccount
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	TypesFactory = test.TypesFactory
+	if err := v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+var bootstrapManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      persistentStorage:
+        persistentStoreType: s3
+  accounts:
+    enabled: true
+    dynamic: true
+    bootstrapSelector:
+      spinnaker.io/kubeconfig: "true"
+`
+
+func kubeconfigSecret(name string, labels map[string]string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", Labels: labels},
+		Data:       map[string][]byte{"kubeconfig": []byte("apiVersion: v1\nkind: Config")},
+	}
+}
+
+func TestBootstrap_createsAccount(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(bootstrapManifest, t)
+	sec := kubeconfigSecret("prod-cluster", map[string]string{"spinnaker.io/kubeconfig": "true"})
+	fakeClient := test.FakeClient(t, spinsvc, sec)
+	r := &ReconcileAccountBootstrap{client: fakeClient}
+
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "prod-cluster"}})
+	assert.Nil(t, err)
+
+	acc := TypesFactory.NewAccount()
+	if assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "prod-cluster"}, acc)) {
+		assert.Equal(t, interfaces.KubernetesAccountType, acc.GetSpec().Type)
+		assert.True(t, acc.GetSpec().Enabled)
+		if assert.NotNil(t, acc.GetSpec().Kubernetes) && assert.NotNil(t, acc.GetSpec().Kubernetes.KubeconfigSecret) {
+			assert.Equal(t, "prod-cluster", acc.GetSpec().Kubernetes.KubeconfigSecret.Name)
+			assert.Equal(t, "kubeconfig", acc.GetSpec().Kubernetes.KubeconfigSecret.Key)
+		}
+		assert.Equal(t, "prod-cluster", acc.GetLabels()[BootstrapManagedLabel])
+	}
+}
+
+func TestBootstrap_ignoresUnmatchedSecret(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(bootstrapManifest, t)
+	sec := kubeconfigSecret("random-secret", nil)
+	fakeClient := test.FakeClient(t, spinsvc, sec)
+	r := &ReconcileAccountBootstrap{client: fakeClient}
+
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "random-secret"}})
+	assert.Nil(t, err)
+
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "random-secret"}, TypesFactory.NewAccount())
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestBootstrap_neverOverwritesUserAccount(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(bootstrapManifest, t)
+	sec := kubeconfigSecret("prod-cluster", map[string]string{"spinnaker.io/kubeconfig": "true"})
+	user := TypesFactory.NewAccount()
+	user.SetName("prod-cluster")
+	user.SetNamespace("ns1")
+	user.GetSpec().Type = interfaces.KubernetesAccountType
+	user.GetSpec().Kubernetes = &interfaces.KubernetesAuth{UseServiceAccount: true}
+	fakeClient := test.FakeClient(t, spinsvc, sec, user)
+	r := &ReconcileAccountBootstrap{client: fakeClient}
+
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "prod-cluster"}})
+	assert.Nil(t, err)
+
+	acc := TypesFactory.NewAccount()
+	assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "prod-cluster"}, acc))
+	assert.True(t, acc.GetSpec().Kubernetes.UseServiceAccount)
+	assert.Nil(t, acc.GetSpec().Kubernetes.KubeconfigSecret)
+}
+
+func TestBootstrap_cleansUpDeletedSecret(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(bootstrapManifest, t)
+	sec := kubeconfigSecret("stale-cluster", map[string]string{"spinnaker.io/kubeconfig": "true"})
+	fakeClient := test.FakeClient(t, spinsvc, sec)
+	r := &ReconcileAccountBootstrap{client: fakeClient}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "stale-cluster"}}
+
+	_, err := r.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Nil(t, fakeClient.Delete(context.TODO(), sec))
+
+	_, err = r.Reconcile(req)
+	assert.Nil(t, err)
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "stale-cluster"}, TypesFactory.NewAccount())
+	assert.True(t, kerrors.IsNotFound(err))
+}
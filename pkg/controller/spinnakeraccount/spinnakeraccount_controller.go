@@ -57,7 +57,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 		return err
 	}
-	return nil
+	// Bootstrap Kubernetes accounts from labeled kubeconfig secrets
+	return addBootstrap(mgr, &ReconcileAccountBootstrap{client: mgr.GetClient(), scheme: mgr.GetScheme()})
 }
 
 // blank assignment to verify that ReconcileSpinnakerService implements reconcile.Reconciler
@@ -89,10 +90,9 @@ func (r *ReconcileSpinnakerAccount) Reconcile(request reconcile.Request) (reconc
 	err := r.client.Get(ctx, request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			// Request object not found, could have been deleted after reconcile request.
-			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
-			// Return and don't requeue
-			return reconcile.Result{}, nil
+			// Request object not found, the account was deleted.
+			// Remove it from the dynamic config secrets and don't requeue
+			return reconcile.Result{}, r.undeploy(ctx, request.Namespace, request.Name)
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
@@ -110,8 +110,8 @@ func (r *ReconcileSpinnakerAccount) Reconcile(request reconcile.Request) (reconc
 	return reconcile.Result{}, err
 }
 
-func (r *ReconcileSpinnakerAccount) deploy(ctx context.Context, account interfaces.SpinnakerAccount, accountType account.SpinnakerAccountType) error {
-	spinsvc, err := util.FindSpinnakerService(r.client, account.GetNamespace(), TypesFactory)
+func (r *ReconcileSpinnakerAccount) deploy(ctx context.Context, spinAccount interfaces.SpinnakerAccount, accountType account.SpinnakerAccountType) error {
+	spinsvc, err := util.FindSpinnakerService(r.client, spinAccount.GetNamespace(), TypesFactory)
 	if err != nil {
 		return err
 	}
@@ -127,23 +127,50 @@ func (r *ReconcileSpinnakerAccount) deploy(ctx context.Context, account interfac
 		log.Info("SpinnakerService not accepting dynamic accounts", "metadata.name", spinsvc.GetName())
 	}
 
-	// Get all Spinnaker accounts
-	allAccounts, err := accounts.AllValidCRDAccounts(ctx, r.client, account.GetNamespace())
-	if err != nil {
-		return err
-	}
+	// All Spinnaker accounts, fetched lazily, the incremental path below only
+	// needs the account that changed
+	var allAccounts []account.Account
+	threshold := accounts.IncrementalSyncThreshold()
 
 	// Go through all affected services and update dynamic config secret
 	for _, svc := range accountType.GetServices() {
-		ss, err := accounts.PrepareSettings(ctx, svc, allAccounts)
+		dep, err := util.FindDeployment(r.client, spinsvc, svc)
 		if err != nil {
 			return err
 		}
-		dep, err := util.FindDeployment(r.client, spinsvc, svc)
+		sec, err := util.FindSecretInDeployment(r.client, dep, svc, "/opt/spinnaker/config")
 		if err != nil {
 			return err
 		}
-		sec, err := util.FindSecretInDeployment(r.client, dep, svc, "/opt/spinnaker/config")
+		existing, err := util.GetSecretProfileSettings(sec, svc, accounts.SpringProfile)
+		if err != nil {
+			return err
+		}
+		// Above the threshold, patch only the account that changed instead of
+		// regenerating the complete account list, unchanged accounts skip the
+		// secret write entirely
+		if accounts.CountAccounts(existing, accountType) >= threshold {
+			changed, err := r.applyDelta(ctx, spinAccount, accountType, existing)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+			if err = util.UpdateSecret(sec, svc, existing, accounts.SpringProfile); err != nil {
+				return err
+			}
+			if err = r.client.Update(ctx, sec); err != nil {
+				return err
+			}
+			continue
+		}
+		if allAccounts == nil {
+			if allAccounts, err = accounts.AllValidCRDAccounts(ctx, r.client, spinAccount.GetNamespace()); err != nil {
+				return err
+			}
+		}
+		ss, err := accounts.PrepareSettings(ctx, svc, allAccounts)
 		if err != nil {
 			return err
 		}
@@ -157,3 +184,66 @@ func (r *ReconcileSpinnakerAccount) deploy(ctx context.Context, account interfac
 	}
 	return nil
 }
+
+// applyDelta upserts or removes the single changed account in previously
+// generated settings
+func (r *ReconcileSpinnakerAccount) applyDelta(ctx context.Context, spinAccount interfaces.SpinnakerAccount, accountType account.SpinnakerAccountType, settings map[string]interface{}) (bool, error) {
+	acc, err := accountType.FromCRD(spinAccount)
+	if err != nil {
+		return false, err
+	}
+	if !spinAccount.GetSpec().Enabled {
+		return accounts.RemoveAccountSettings(settings, accountType, acc.GetName())
+	}
+	m, err := acc.ToSpinnakerSettings(ctx)
+	if err != nil {
+		return false, err
+	}
+	return accounts.UpsertAccountSettings(settings, accountType, m, acc.GetName())
+}
+
+// undeploy removes a deleted account from the dynamic config secrets. The
+// account type is no longer known at this point so every registered type is
+// checked.
+func (r *ReconcileSpinnakerAccount) undeploy(ctx context.Context, ns string, name string) error {
+	spinsvc, err := util.FindSpinnakerService(r.client, ns, TypesFactory)
+	if err != nil || spinsvc == nil {
+		return err
+	}
+	for _, svc := range accounts.GetAllServicesWithAccounts() {
+		dep, err := util.FindDeployment(r.client, spinsvc, svc)
+		if err != nil {
+			// Service not deployed, nothing to clean up
+			continue
+		}
+		sec, err := util.FindSecretInDeployment(r.client, dep, svc, "/opt/spinnaker/config")
+		if err != nil {
+			continue
+		}
+		settings, err := util.GetSecretProfileSettings(sec, svc, accounts.SpringProfile)
+		if err != nil {
+			return err
+		}
+		if settings == nil {
+			continue
+		}
+		changed := false
+		for _, t := range accounts.Types {
+			c, err := accounts.RemoveAccountSettings(settings, t, name)
+			if err != nil {
+				return err
+			}
+			changed = changed || c
+		}
+		if !changed {
+			continue
+		}
+		if err = util.UpdateSecret(sec, svc, settings, accounts.SpringProfile); err != nil {
+			return err
+		}
+		if err = r.client.Update(ctx, sec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
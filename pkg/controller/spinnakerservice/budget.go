@@ -0,0 +1,107 @@
+package spinnakerservice
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// ReconcileBudgetEnvVar overrides the busy time a single SpinnakerService may
+	// consume within the fairness window, e.g. "90s"
+	ReconcileBudgetEnvVar = "RECONCILE_TIME_BUDGET"
+	defaultBudget         = 2 * time.Minute
+	budgetWindow          = 5 * time.Minute
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "spinnaker_operator_reconcile_duration_seconds",
+		Help: "Time spent reconciling each SpinnakerService",
+	}, []string{"namespace", "name"})
+	reconcileThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "spinnaker_operator_reconcile_throttled_total",
+		Help: "Reconciles delayed because a SpinnakerService exhausted its time budget",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDuration, reconcileThrottled)
+}
+
+// reconcileBudget tracks the reconcile time consumed per SpinnakerService over a
+// sliding window so a single misbehaving service cannot starve the work queue
+type reconcileBudget struct {
+	mu     sync.Mutex
+	window time.Duration
+	budget time.Duration
+	usage  map[string][]timeSlice
+}
+
+type timeSlice struct {
+	at time.Time
+	d  time.Duration
+}
+
+func newReconcileBudget() *reconcileBudget {
+	budget := defaultBudget
+	if v := os.Getenv(ReconcileBudgetEnvVar); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			budget = d
+		}
+	}
+	return &reconcileBudget{
+		window: budgetWindow,
+		budget: budget,
+		usage:  map[string][]timeSlice{},
+	}
+}
+
+// backoff returns how long the given service should wait before being reconciled
+// again, zero when it still has budget left in the current window
+func (b *reconcileBudget) backoff(key string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	spent := b.pruneAndSum(key)
+	if spent < b.budget {
+		return 0
+	}
+	// Wait until the oldest slice in the window expires
+	slices := b.usage[key]
+	if len(slices) == 0 {
+		return 0
+	}
+	return b.window - time.Since(slices[0].at)
+}
+
+// record accounts reconcile time against the service's budget
+func (b *reconcileBudget) record(key string, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pruneAndSum(key)
+	b.usage[key] = append(b.usage[key], timeSlice{at: time.Now(), d: d})
+}
+
+// pruneAndSum drops slices older than the window and returns the remaining busy time.
+// Callers must hold the lock
+func (b *reconcileBudget) pruneAndSum(key string) time.Duration {
+	slices := b.usage[key]
+	kept := slices[:0]
+	var sum time.Duration
+	for _, s := range slices {
+		if time.Since(s.at) > b.window {
+			continue
+		}
+		kept = append(kept, s)
+		sum += s.d
+	}
+	if len(kept) == 0 {
+		delete(b.usage, key)
+	} else {
+		b.usage[key] = kept
+	}
+	return sum
+}
@@ -0,0 +1,28 @@
+package spinnakerservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget_underBudget(t *testing.T) {
+	b := newReconcileBudget()
+	b.record("ns1/spinnaker", 1*time.Second)
+	assert.Equal(t, time.Duration(0), b.backoff("ns1/spinnaker"))
+}
+
+func TestBudget_exhausted(t *testing.T) {
+	b := newReconcileBudget()
+	b.record("ns1/spinnaker", b.budget+time.Second)
+	assert.True(t, b.backoff("ns1/spinnaker") > 0)
+	// Other services are not affected
+	assert.Equal(t, time.Duration(0), b.backoff("ns1/other"))
+}
+
+func TestBudget_windowExpires(t *testing.T) {
+	b := newReconcileBudget()
+	b.usage["ns1/spinnaker"] = []timeSlice{{at: time.Now().Add(-2 * b.window), d: b.budget * 2}}
+	assert.Equal(t, time.Duration(0), b.backoff("ns1/spinnaker"))
+}
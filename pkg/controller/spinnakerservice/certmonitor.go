@@ -0,0 +1,226 @@
+package spinnakerservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	certificateCheck = "tls-certificate"
+	pemFileCheck     = "pem-file"
+	keystoreCheck    = "keystore"
+
+	// jksMagic starts every Java keystore file
+	jksMagic = 0xfeedfeed
+)
+
+var certificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "spinnaker_operator_certificate_expiry_seconds",
+	Help: "Seconds until a certificate referenced by the deployed config expires",
+}, []string{"namespace", "name", "check", "target"})
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpiry)
+}
+
+// certRef is one certificate found in the resolved configuration
+type certRef struct {
+	check    string
+	target   string
+	notAfter time.Time
+}
+
+// collectCertificates gathers every certificate referenced by the deployed
+// config: TLS secrets in the namespace, and PEM files or Java keystores
+// carried in spec.spinnakerConfig.files
+func (r *configRevalidator) collectCertificates(ctx context.Context, svc interfaces.SpinnakerService) []certRef {
+	var refs []certRef
+	secrets := &v1.SecretList{}
+	if err := r.client.List(ctx, secrets, client.InNamespace(svc.GetNamespace())); err == nil {
+		for i := range secrets.Items {
+			sec := &secrets.Items[i]
+			if sec.Type != v1.SecretTypeTLS {
+				continue
+			}
+			for _, cert := range parsePEMCertificates(sec.Data[v1.TLSCertKey]) {
+				refs = append(refs, certRef{check: certificateCheck, target: sec.Name, notAfter: cert.NotAfter})
+			}
+		}
+	}
+	for name := range svc.GetSpinnakerConfig().Files {
+		content := svc.GetSpinnakerConfig().GetFileContent(name)
+		if len(content) >= 4 && binary.BigEndian.Uint32(content) == jksMagic {
+			certs, err := parseJKSCertificates(content)
+			if err != nil {
+				continue
+			}
+			for _, cert := range certs {
+				refs = append(refs, certRef{check: keystoreCheck, target: name, notAfter: cert.NotAfter})
+			}
+			continue
+		}
+		for _, cert := range parsePEMCertificates(content) {
+			refs = append(refs, certRef{check: pemFileCheck, target: name, notAfter: cert.NotAfter})
+		}
+	}
+	return refs
+}
+
+// certificateFindings raises a finding for every certificate that is expired
+// or inside the renewal window, and keeps the expiry metric current for all
+// of them
+func (r *configRevalidator) certificateFindings(svc interfaces.SpinnakerService, refs []certRef) []interfaces.ConfigFinding {
+	now := r.now()
+	var findings []interfaces.ConfigFinding
+	for _, ref := range refs {
+		certificateExpiry.WithLabelValues(svc.GetNamespace(), svc.GetName(), ref.check, ref.target).
+			Set(ref.notAfter.Sub(now).Seconds())
+		if ref.notAfter.Before(now) {
+			findings = append(findings, interfaces.ConfigFinding{
+				Check:   ref.check,
+				Target:  ref.target,
+				Message: fmt.Sprintf("certificate expired on %s", ref.notAfter.Format(time.RFC3339)),
+			})
+		} else if ref.notAfter.Sub(now) < r.renewalWindow {
+			findings = append(findings, interfaces.ConfigFinding{
+				Check:   ref.check,
+				Target:  ref.target,
+				Message: fmt.Sprintf("certificate expires on %s", ref.notAfter.Format(time.RFC3339)),
+			})
+		}
+	}
+	return findings
+}
+
+// parsePEMCertificates returns every CERTIFICATE block that parses, other
+// blocks and garbage are ignored
+func parsePEMCertificates(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+}
+
+// parseJKSCertificates reads the certificates out of a Java keystore without
+// touching the (password protected) private keys
+func parseJKSCertificates(data []byte) ([]*x509.Certificate, error) {
+	r := bytes.NewReader(data)
+	var magic, version, count uint32
+	if err := binary.Read(r, binary.BigEndian, &magic); err != nil {
+		return nil, err
+	}
+	if magic != jksMagic {
+		return nil, fmt.Errorf("not a Java keystore")
+	}
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != 1 && version != 2 {
+		return nil, fmt.Errorf("unsupported keystore version %d", version)
+	}
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+	var certs []*x509.Certificate
+	for i := uint32(0); i < count; i++ {
+		var tag uint32
+		if err := binary.Read(r, binary.BigEndian, &tag); err != nil {
+			return nil, err
+		}
+		if _, err := readJKSString(r); err != nil { // alias
+			return nil, err
+		}
+		if _, err := r.Seek(8, io.SeekCurrent); err != nil { // timestamp
+			return nil, err
+		}
+		switch tag {
+		case 1: // private key entry with its certificate chain
+			if _, err := readJKSBytes(r); err != nil { // encrypted key, skipped
+				return nil, err
+			}
+			var chainLen uint32
+			if err := binary.Read(r, binary.BigEndian, &chainLen); err != nil {
+				return nil, err
+			}
+			for j := uint32(0); j < chainLen; j++ {
+				cert, err := readJKSCertificate(r, version)
+				if err != nil {
+					return nil, err
+				}
+				certs = append(certs, cert)
+			}
+		case 2: // trusted certificate entry
+			cert, err := readJKSCertificate(r, version)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, cert)
+		default:
+			return nil, fmt.Errorf("unknown keystore entry tag %d", tag)
+		}
+	}
+	return certs, nil
+}
+
+func readJKSCertificate(r *bytes.Reader, version uint32) (*x509.Certificate, error) {
+	if version == 2 {
+		certType, err := readJKSString(r)
+		if err != nil {
+			return nil, err
+		}
+		if certType != "X.509" {
+			return nil, fmt.Errorf("unsupported certificate type %s", certType)
+		}
+	}
+	der, err := readJKSBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+func readJKSString(r *bytes.Reader) (string, error) {
+	var l uint16
+	if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+		return "", err
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func readJKSBytes(r *bytes.Reader) ([]byte, error) {
+	var l uint32
+	if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+		return nil, err
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,116 @@
+package spinnakerservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// selfSignedDER returns the raw DER of a certificate expiring at notAfter
+func selfSignedDER(t *testing.T, notAfter time.Time) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(t, err)
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "saml"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	return der
+}
+
+// jksWithCert builds a minimal version 2 keystore with one trusted cert entry
+func jksWithCert(der []byte) []byte {
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.BigEndian, uint32(jksMagic))
+	_ = binary.Write(buf, binary.BigEndian, uint32(2)) // version
+	_ = binary.Write(buf, binary.BigEndian, uint32(1)) // entry count
+	_ = binary.Write(buf, binary.BigEndian, uint32(2)) // trusted cert tag
+	alias := "saml"
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(alias)))
+	buf.WriteString(alias)
+	_ = binary.Write(buf, binary.BigEndian, uint64(0)) // timestamp
+	certType := "X.509"
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(certType)))
+	buf.WriteString(certType)
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(der)))
+	buf.Write(der)
+	return buf.Bytes()
+}
+
+func TestCertMonitor_expiringKeystore(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	jks := jksWithCert(selfSignedDER(t, time.Now().Add(5*24*time.Hour)))
+	spinSvc.GetSpinnakerConfig().Files = map[string]string{
+		"profiles__gate__saml.jks": base64.StdEncoding.EncodeToString(jks),
+	}
+	r := &configRevalidator{
+		client:        test.FakeClient(t, spinSvc),
+		renewalWindow: defaultRenewalWindow,
+		now:           time.Now,
+		probe:         func(ctx context.Context, url string) error { return nil },
+	}
+
+	findings := r.check(context.TODO(), spinSvc)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, keystoreCheck, findings[0].Check)
+		assert.Equal(t, "profiles__gate__saml.jks", findings[0].Target)
+		assert.Contains(t, findings[0].Message, "certificate expires on")
+	}
+}
+
+func TestCertMonitor_pemFileInConfig(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	der := selfSignedDER(t, time.Now().Add(-time.Hour))
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	spinSvc.GetSpinnakerConfig().Files = map[string]string{
+		"default__ca.crt": string(pemBytes),
+	}
+	r := &configRevalidator{
+		client:        test.FakeClient(t, spinSvc),
+		renewalWindow: defaultRenewalWindow,
+		now:           time.Now,
+		probe:         func(ctx context.Context, url string) error { return nil },
+	}
+
+	findings := r.check(context.TODO(), spinSvc)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, pemFileCheck, findings[0].Check)
+		assert.Contains(t, findings[0].Message, "certificate expired on")
+	}
+}
+
+func TestCertMonitor_healthyCertOutsideWindow(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	der := selfSignedDER(t, time.Now().Add(365*24*time.Hour))
+	spinSvc.GetSpinnakerConfig().Files = map[string]string{
+		"default__ca.crt": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+	r := &configRevalidator{
+		client:        test.FakeClient(t, spinSvc),
+		renewalWindow: defaultRenewalWindow,
+		now:           time.Now,
+		probe:         func(ctx context.Context, url string) error { return nil },
+	}
+
+	assert.Empty(t, r.check(context.TODO(), spinSvc))
+}
+
+func TestParseJKS_rejectsGarbage(t *testing.T) {
+	_, err := parseJKSCertificates([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07})
+	assert.NotNil(t, err)
+}
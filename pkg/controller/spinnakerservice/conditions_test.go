@@ -0,0 +1,34 @@
+package spinnakerservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_setConditions(t *testing.T) {
+	tests := []struct {
+		overall     string
+		available   interfaces.SpinnakerConditionStatus
+		progressing interfaces.SpinnakerConditionStatus
+		failed      interfaces.SpinnakerConditionStatus
+	}{
+		{Ok, interfaces.ConditionTrue, interfaces.ConditionFalse, interfaces.ConditionFalse},
+		{Updating, interfaces.ConditionFalse, interfaces.ConditionTrue, interfaces.ConditionFalse},
+		{Na, interfaces.ConditionFalse, interfaces.ConditionTrue, interfaces.ConditionFalse},
+		{Failure, interfaces.ConditionFalse, interfaces.ConditionFalse, interfaces.ConditionTrue},
+		{Degraded, interfaces.ConditionFalse, interfaces.ConditionFalse, interfaces.ConditionTrue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.overall, func(t *testing.T) {
+			status := &interfaces.SpinnakerServiceStatus{}
+			setConditions(status, tt.overall, time.Now())
+			assert.Equal(t, tt.available, status.GetCondition(interfaces.AvailableCondition).Status)
+			assert.Equal(t, tt.progressing, status.GetCondition(interfaces.ProgressingCondition).Status)
+			assert.Equal(t, tt.failed, status.GetCondition(interfaces.FailedCondition).Status)
+			assert.Equal(t, tt.overall, status.GetCondition(interfaces.AvailableCondition).Reason)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package spinnakerservice
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// failureHint classifies known container failure modes and returns a targeted
+// remediation hint, or an empty string when no classification applies
+func failureHint(cs v1.ContainerStatus) string {
+	if t := cs.State.Terminated; t != nil && isOomKilled(t) {
+		return oomHint(cs)
+	}
+	w := cs.State.Waiting
+	if w == nil {
+		return ""
+	}
+	switch w.Reason {
+	case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+		return fmt.Sprintf("image %s cannot be pulled, check the image name and the pull credentials (imagePullSecrets) of the namespace", cs.Image)
+	case "CrashLoopBackOff":
+		if t := cs.LastTerminationState.Terminated; t != nil && isOomKilled(t) {
+			return oomHint(cs)
+		}
+		return fmt.Sprintf("container %s keeps crashing, check its logs for startup errors", cs.Name)
+	case "CreateContainerConfigError", "CreateContainerError":
+		return fmt.Sprintf("a ConfigMap or Secret used by container %s is missing or invalid: %s", cs.Name, w.Message)
+	}
+	return ""
+}
+
+func isOomKilled(t *v1.ContainerStateTerminated) bool {
+	return t.Reason == "OOMKilled" || t.ExitCode == 137
+}
+
+func oomHint(cs v1.ContainerStatus) string {
+	return fmt.Sprintf("container %s was OOM killed, raise its memory limit with spec.services.%s.resources", cs.Name, cs.Name)
+}
+
+// withHint appends the remediation hint for the container to the event message
+func withHint(msg string, cs v1.ContainerStatus) string {
+	if hint := failureHint(cs); hint != "" {
+		return fmt.Sprintf("%s. Hint: %s", msg, hint)
+	}
+	return msg
+}
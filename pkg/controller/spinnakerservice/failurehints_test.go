@@ -0,0 +1,86 @@
+package spinnakerservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func Test_failureHint(t *testing.T) {
+	tests := []struct {
+		name     string
+		cs       v1.ContainerStatus
+		contains string
+	}{
+		{
+			name: "image pull failure",
+			cs: v1.ContainerStatus{
+				Name:  "gate",
+				Image: "gcr.io/spinnaker/gate:9.9.9",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			},
+			contains: "imagePullSecrets",
+		},
+		{
+			name: "crash loop after OOM",
+			cs: v1.ContainerStatus{
+				Name:  "clouddriver",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: v1.ContainerState{
+					Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+				},
+			},
+			contains: "spec.services.clouddriver.resources",
+		},
+		{
+			name: "crash loop without OOM",
+			cs: v1.ContainerStatus{
+				Name:  "orca",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			},
+			contains: "check its logs",
+		},
+		{
+			name: "config mount failure",
+			cs: v1.ContainerStatus{
+				Name: "gate",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{
+					Reason:  "CreateContainerConfigError",
+					Message: "secret \"spin-gate-files\" not found",
+				}},
+			},
+			contains: "spin-gate-files",
+		},
+		{
+			name: "terminated by OOM",
+			cs: v1.ContainerStatus{
+				Name:  "echo",
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137}},
+			},
+			contains: "OOM killed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hint := failureHint(tt.cs)
+			assert.Contains(t, hint, tt.contains)
+		})
+	}
+}
+
+func Test_failureHint_none(t *testing.T) {
+	assert.Equal(t, "", failureHint(v1.ContainerStatus{Name: "gate", Ready: true}))
+	assert.Equal(t, "", failureHint(v1.ContainerStatus{
+		State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+	}))
+}
+
+func Test_withHint(t *testing.T) {
+	cs := v1.ContainerStatus{
+		Name:  "gate",
+		State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+	}
+	assert.Contains(t, withHint("Pod spin-gate is not ready", cs), "Hint: ")
+	assert.Equal(t, "all good", withHint("all good", v1.ContainerStatus{}))
+}
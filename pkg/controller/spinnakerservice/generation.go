@@ -0,0 +1,47 @@
+package spinnakerservice
+
+import (
+	"sync"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// statusOnlyTracker remembers services whose last write was one of our own
+// status refreshes, so the reconcile that write triggers can skip the deploy
+// path instead of rerunning change detection and Halyard generation.
+// Marks are consumed on read: any event from another source that arrives
+// afterwards goes through the full deploy path again.
+type statusOnlyTracker struct {
+	mtx    sync.Mutex
+	marked map[string]bool
+}
+
+func newStatusOnlyTracker() *statusOnlyTracker {
+	return &statusOnlyTracker{marked: map[string]bool{}}
+}
+
+// mark records that the last write for the service was a status update
+func (t *statusOnlyTracker) mark(key string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.marked[key] = true
+}
+
+// consume returns whether the service was marked and clears the mark
+func (t *statusOnlyTracker) consume(key string) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	b := t.marked[key]
+	delete(t.marked, key)
+	return b
+}
+
+// isStatusOnlyReconcile returns true when the reconcile was triggered by our
+// own status write and the spec generation was already processed, in which
+// case there is nothing new to deploy
+func (r *ReconcileSpinnakerService) isStatusOnlyReconcile(key string, svc interfaces.SpinnakerService) bool {
+	if r.statusOnly == nil || !r.statusOnly.consume(key) {
+		return false
+	}
+	return svc.GetGeneration() == svc.GetStatus().ObservedGeneration
+}
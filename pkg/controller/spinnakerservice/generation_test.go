@@ -0,0 +1,129 @@
+package spinnakerservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestStatusOnlyTracker_consumeIsOneShot(t *testing.T) {
+	tr := newStatusOnlyTracker()
+	tr.mark("ns1/spinnaker")
+	assert.True(t, tr.consume("ns1/spinnaker"))
+	// A later event from another source must go through the full deploy path
+	assert.False(t, tr.consume("ns1/spinnaker"))
+	assert.False(t, tr.consume("ns1/other"))
+}
+
+func TestStatusOnlyReconcile_generationMustMatch(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.SetGeneration(2)
+	spinSvc.GetStatus().ObservedGeneration = 2
+	r := &ReconcileSpinnakerService{statusOnly: newStatusOnlyTracker()}
+
+	r.statusOnly.mark("ns1/spinnaker")
+	assert.True(t, r.isStatusOnlyReconcile("ns1/spinnaker", spinSvc))
+
+	// A spec change bumps the generation: the mark alone is not enough
+	r.statusOnly.mark("ns1/spinnaker")
+	spinSvc.SetGeneration(3)
+	assert.False(t, r.isStatusOnlyReconcile("ns1/spinnaker", spinSvc))
+}
+
+func TestReconcile_skipsDeployOnStatusOnlyUpdate(t *testing.T) {
+	prevFactory := TypesFactory
+	TypesFactory = test.TypesFactory
+	defer func() { TypesFactory = prevFactory }()
+
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.SetGeneration(2)
+	spinSvc.GetStatus().ObservedGeneration = 2
+	dep := &countingDeployer{}
+	r := &ReconcileSpinnakerService{
+		client:      test.FakeClient(t, spinSvc),
+		deployers:   []deploy.Deployer{dep},
+		evtRecorder: record.NewFakeRecorder(10),
+		statusOnly:  newStatusOnlyTracker(),
+		requeue:     requeueIntervalsFromEnv(),
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: spinSvc.GetNamespace(),
+		Name:      spinSvc.GetName(),
+	}}
+
+	// The reconcile triggered by our own status write skips the deployers but
+	// keeps the periodic health loop scheduled
+	r.statusOnly.mark(req.String())
+	res, err := r.Reconcile(req)
+	assert.Nil(t, err)
+	assert.False(t, res.Requeue)
+	assert.True(t, res.RequeueAfter > 0)
+	assert.Equal(t, 0, dep.calls)
+
+	// Any other trigger goes through the deployers
+	res, err = r.Reconcile(req)
+	assert.Nil(t, err)
+	assert.True(t, res.Requeue)
+	assert.Equal(t, 1, dep.calls)
+}
+
+func TestReconcile_freezeKeepsPendingStatus(t *testing.T) {
+	prevFactory := TypesFactory
+	TypesFactory = test.TypesFactory
+	defer func() { TypesFactory = prevFactory }()
+
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	r := &ReconcileSpinnakerService{
+		client:      test.FakeClient(t, spinSvc),
+		deployers:   []deploy.Deployer{&freezingDeployer{}},
+		evtRecorder: record.NewFakeRecorder(10),
+		statusOnly:  newStatusOnlyTracker(),
+		requeue:     requeueIntervalsFromEnv(),
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: spinSvc.GetNamespace(),
+		Name:      spinSvc.GetName(),
+	}}
+
+	// The status checker would recompute the status from pod health and erase
+	// PendingFreeze: the reconcile has to stop before it runs (its lookup is
+	// nil here and would panic) and only schedule a recheck of the window
+	res, err := r.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultDegradedRequeue, res.RequeueAfter)
+}
+
+// countingDeployer records invocations and requeues without touching anything
+type countingDeployer struct {
+	calls int
+}
+
+func (d *countingDeployer) GetName() string {
+	return "counting"
+}
+
+func (d *countingDeployer) Deploy(ctx context.Context, svc interfaces.SpinnakerService, scheme *runtime.Scheme) (bool, error) {
+	d.calls++
+	return true, nil
+}
+
+// freezingDeployer reports a freeze window holding back the rendered changes
+type freezingDeployer struct{}
+
+func (d *freezingDeployer) GetName() string {
+	return "freezing"
+}
+
+func (d *freezingDeployer) Deploy(ctx context.Context, svc interfaces.SpinnakerService, scheme *runtime.Scheme) (bool, error) {
+	svc.GetStatus().Status = spindeploy.StatusPendingFreeze
+	return false, nil
+}
@@ -0,0 +1,78 @@
+package spinnakerservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// namespaceFinalizer ties the lifecycle of an operator-created target namespace
+// to the SpinnakerService that declared it
+const namespaceFinalizer = "spinnaker.io/managed-namespace"
+
+// handleManagedNamespace adds the namespace finalizer when the service manages its
+// target namespace, and cleans the namespace up on deletion. It returns false when
+// the service is being deleted and the reconcile should stop.
+func (r *ReconcileSpinnakerService) handleManagedNamespace(ctx context.Context, svc interfaces.SpinnakerService) (bool, error) {
+	if svc.GetDeletionTimestamp() != nil {
+		if !hasFinalizer(svc, namespaceFinalizer) {
+			return false, nil
+		}
+		if err := r.deleteManagedNamespace(ctx, svc); err != nil {
+			return false, err
+		}
+		svc.SetFinalizers(removeFinalizer(svc.GetFinalizers(), namespaceFinalizer))
+		return false, r.client.Update(ctx, svc)
+	}
+	cfg := svc.GetTargetNamespaceConfig()
+	if cfg.Name == "" || !cfg.Create || !cfg.ManageLifecycle || hasFinalizer(svc, namespaceFinalizer) {
+		return true, nil
+	}
+	svc.SetFinalizers(append(svc.GetFinalizers(), namespaceFinalizer))
+	return true, r.client.Update(ctx, svc)
+}
+
+// deleteManagedNamespace deletes the target namespace if the operator created it for this service
+func (r *ReconcileSpinnakerService) deleteManagedNamespace(ctx context.Context, svc interfaces.SpinnakerService) error {
+	name := svc.GetTargetNamespaceConfig().Name
+	if name == "" {
+		return nil
+	}
+	ns := &corev1.Namespace{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	// Never delete a namespace the operator did not create
+	if ns.Labels[spindeploy.ManagedNamespaceLabel] != spindeploy.ManagedNamespaceValue(svc) {
+		log.Info(fmt.Sprintf("namespace %s is not managed by %s, skipping deletion", name, svc.GetName()))
+		return nil
+	}
+	return r.client.Delete(ctx, ns)
+}
+
+func hasFinalizer(svc interfaces.SpinnakerService, finalizer string) bool {
+	for _, f := range svc.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(finalizers []string, finalizer string) []string {
+	result := make([]string, 0, len(finalizers))
+	for _, f := range finalizers {
+		if f != finalizer {
+			result = append(result, f)
+		}
+	}
+	return result
+}
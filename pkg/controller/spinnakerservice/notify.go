@@ -0,0 +1,149 @@
+package spinnakerservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// eventTemplatesConfigMap customizes operator emitted events per namespace.
+	// Keys matching an event reason hold a go-template for the message, the
+	// "webhookUrl" key adds a webhook notification, and the optional "webhook"
+	// key templates its payload.
+	eventTemplatesConfigMap = "spinnaker-operator-notifications"
+	webhookUrlKey           = "webhookUrl"
+	webhookPayloadKey       = "webhook"
+	notifyTimeout           = 10 * time.Second
+)
+
+// eventData is what the templates are rendered with
+type eventData struct {
+	Namespace string
+	Name      string
+	Type      string
+	Reason    string
+	Message   string
+}
+
+// templatedRecorder wraps an event recorder and rewrites messages through the
+// templates of the namespace's notification ConfigMap, so events can carry
+// org specific runbook links, and optionally posts them to a webhook
+type templatedRecorder struct {
+	base   record.EventRecorder
+	client client.Client
+	// post is replaced in tests
+	post func(url string, body []byte) error
+}
+
+var _ record.EventRecorder = &templatedRecorder{}
+
+func newTemplatedRecorder(base record.EventRecorder, c client.Client) *templatedRecorder {
+	return &templatedRecorder{base: base, client: c, post: postNotification}
+}
+
+func (r *templatedRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.base.Event(object, eventtype, reason, r.render(object, eventtype, reason, message))
+}
+
+func (r *templatedRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *templatedRecorder) PastEventf(object runtime.Object, timestamp metav1.Time, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.base.PastEventf(object, timestamp, eventtype, reason, messageFmt, args...)
+}
+
+func (r *templatedRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.base.AnnotatedEventf(object, annotations, eventtype, reason, r.render(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)))
+}
+
+// render applies the reason's template when one is configured and triggers the
+// webhook notification, falling back to the original message on any error
+func (r *templatedRecorder) render(object runtime.Object, eventtype, reason, message string) string {
+	m, ok := object.(metav1.Object)
+	if !ok {
+		return message
+	}
+	cm := &v1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: m.GetNamespace(), Name: eventTemplatesConfigMap}, cm)
+	if err != nil {
+		return message
+	}
+	data := eventData{
+		Namespace: m.GetNamespace(),
+		Name:      m.GetName(),
+		Type:      eventtype,
+		Reason:    reason,
+		Message:   message,
+	}
+	if tpl, ok := cm.Data[reason]; ok {
+		if rendered, err := renderTemplate(reason, tpl, data); err == nil {
+			message = rendered
+			data.Message = rendered
+		}
+	}
+	if url, ok := cm.Data[webhookUrlKey]; ok && url != "" {
+		r.notify(url, cm.Data[webhookPayloadKey], data)
+	}
+	return message
+}
+
+// notify posts the event to the configured webhook, with a JSON payload
+// unless the ConfigMap templates its own
+func (r *templatedRecorder) notify(url, payloadTpl string, data eventData) {
+	var body []byte
+	if payloadTpl != "" {
+		rendered, err := renderTemplate(webhookPayloadKey, payloadTpl, data)
+		if err != nil {
+			log.Error(err, "unable to render webhook notification payload")
+			return
+		}
+		body = []byte(rendered)
+	} else {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return
+		}
+		body = b
+	}
+	if err := r.post(url, body); err != nil {
+		log.Error(err, "unable to post webhook notification", "url", url)
+	}
+}
+
+func renderTemplate(name, tpl string, data eventData) (string, error) {
+	t, err := template.New(name).Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func postNotification(url string, body []byte) error {
+	c := &http.Client{Timeout: notifyTimeout}
+	resp, err := c.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package spinnakerservice
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTemplatedRecorder_rewritesMessage(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: spinSvc.GetNamespace(), Name: eventTemplatesConfigMap},
+		Data: map[string]string{
+			"DeployFailed": "{{ .Message }} - runbook: https://runbooks.acme.com/spinnaker",
+		},
+	}
+	base := record.NewFakeRecorder(10)
+	r := newTemplatedRecorder(base, test.FakeClient(t, cm))
+
+	r.Eventf(spinSvc, v1.EventTypeWarning, "DeployFailed", "Error deploying spinnaker: %s", "boom")
+
+	evt := <-base.Events
+	assert.Contains(t, evt, "Error deploying spinnaker: boom - runbook: https://runbooks.acme.com/spinnaker")
+}
+
+func TestTemplatedRecorder_postsWebhook(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: spinSvc.GetNamespace(), Name: eventTemplatesConfigMap},
+		Data: map[string]string{
+			webhookUrlKey:     "https://hooks.acme.com/spinnaker",
+			webhookPayloadKey: `{"text":"{{ .Reason }} on {{ .Namespace }}/{{ .Name }}: {{ .Message }}"}`,
+		},
+	}
+	base := record.NewFakeRecorder(10)
+	r := newTemplatedRecorder(base, test.FakeClient(t, cm))
+	var gotUrl, gotBody string
+	r.post = func(url string, body []byte) error {
+		gotUrl, gotBody = url, string(body)
+		return nil
+	}
+
+	r.Eventf(spinSvc, v1.EventTypeNormal, "DeploySuccess", "Spinnaker updated")
+
+	assert.Equal(t, "https://hooks.acme.com/spinnaker", gotUrl)
+	assert.Contains(t, gotBody, "DeploySuccess on "+spinSvc.GetNamespace()+"/"+spinSvc.GetName())
+	// The original event still reaches Kubernetes untouched
+	evt := <-base.Events
+	assert.Contains(t, evt, "Spinnaker updated")
+}
+
+func TestTemplatedRecorder_noConfigMapPassthrough(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	base := record.NewFakeRecorder(10)
+	r := newTemplatedRecorder(base, test.FakeClient(t))
+
+	r.Eventf(spinSvc, v1.EventTypeNormal, "DeployStart", "New configuration detected")
+
+	evt := <-base.Events
+	assert.Contains(t, evt, "New configuration detected")
+}
@@ -0,0 +1,149 @@
+package spinnakerservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// orphanFinalizer guarantees owner references are stripped from the generated
+// objects before the SpinnakerService disappears and garbage collection kicks in
+const orphanFinalizer = "spinnaker.io/orphan-on-delete"
+
+// handleOrphanOnDelete adds the orphan finalizer when spec.apply.orphanOnDelete
+// is set and, on deletion, detaches the generated objects from the service and
+// exports their manifests. It returns false when the reconcile should stop.
+func (r *ReconcileSpinnakerService) handleOrphanOnDelete(ctx context.Context, svc interfaces.SpinnakerService) (bool, error) {
+	if svc.GetDeletionTimestamp() != nil {
+		if !hasFinalizer(svc, orphanFinalizer) {
+			// Let the remaining deletion handlers run
+			return true, nil
+		}
+		if err := r.orphanOwnedObjects(ctx, svc); err != nil {
+			return false, err
+		}
+		svc.SetFinalizers(removeFinalizer(svc.GetFinalizers(), orphanFinalizer))
+		return false, r.client.Update(ctx, svc)
+	}
+	if !svc.GetApplyPolicyConfig().OrphanOnDelete || hasFinalizer(svc, orphanFinalizer) {
+		return true, nil
+	}
+	svc.SetFinalizers(append(svc.GetFinalizers(), orphanFinalizer))
+	return true, r.client.Update(ctx, svc)
+}
+
+// orphanOwnedObjects strips owner references from the deployments and services
+// generated for the SpinnakerService and writes their manifests to a ConfigMap,
+// leaving a running Spinnaker and plain manifests behind
+func (r *ReconcileSpinnakerService) orphanOwnedObjects(ctx context.Context, svc interfaces.SpinnakerService) error {
+	manifests := map[string]string{}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.client.List(ctx, deployments, client.InNamespace(svc.GetNamespace()),
+		client.MatchingLabels{"app.kubernetes.io/managed-by": "spinnaker-operator"}); err != nil {
+		return err
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if err := r.orphanObject(ctx, d, svc); err != nil {
+			return err
+		}
+		b, err := exportManifest(d)
+		if err != nil {
+			return err
+		}
+		manifests[fmt.Sprintf("deployment-%s.yml", d.Name)] = string(b)
+	}
+
+	services := &corev1.ServiceList{}
+	if err := r.client.List(ctx, services, client.InNamespace(svc.GetNamespace()),
+		client.MatchingLabels{"app": "spin"}); err != nil {
+		return err
+	}
+	for i := range services.Items {
+		s := &services.Items[i]
+		if err := r.orphanObject(ctx, s, svc); err != nil {
+			return err
+		}
+		b, err := exportManifest(s)
+		if err != nil {
+			return err
+		}
+		manifests[fmt.Sprintf("service-%s.yml", s.Name)] = string(b)
+	}
+
+	if len(manifests) == 0 {
+		return nil
+	}
+	return r.saveExportedManifests(ctx, svc, manifests)
+}
+
+// orphanObject drops the owner references pointing at the SpinnakerService so
+// the object survives its deletion
+func (r *ReconcileSpinnakerService) orphanObject(ctx context.Context, obj runtime.Object, svc interfaces.SpinnakerService) error {
+	m, ok := obj.(metav1.Object)
+	if !ok {
+		return nil
+	}
+	refs := m.GetOwnerReferences()
+	kept := make([]metav1.OwnerReference, 0, len(refs))
+	for _, ref := range refs {
+		if ref.UID == svc.GetUID() {
+			continue
+		}
+		kept = append(kept, ref)
+	}
+	if len(kept) == len(refs) {
+		return nil
+	}
+	m.SetOwnerReferences(kept)
+	return r.client.Update(ctx, obj)
+}
+
+// exportManifest serializes the object without the cluster-assigned fields so
+// the result can be applied as a plain manifest
+func exportManifest(obj runtime.Object) ([]byte, error) {
+	cp := obj.DeepCopyObject()
+	if m, ok := cp.(metav1.Object); ok {
+		m.SetOwnerReferences(nil)
+		m.SetResourceVersion("")
+		m.SetUID("")
+		m.SetSelfLink("")
+		m.SetCreationTimestamp(metav1.Time{})
+	}
+	return yaml.Marshal(cp)
+}
+
+// saveExportedManifests writes the manifests to the <name>-exported-manifests
+// ConfigMap, creating or updating it
+func (r *ReconcileSpinnakerService) saveExportedManifests(ctx context.Context, svc interfaces.SpinnakerService, manifests map[string]string) error {
+	name := fmt.Sprintf("%s-exported-manifests", svc.GetName())
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: name}, cm)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: svc.GetNamespace()},
+			Data:       manifests,
+		}
+		return r.client.Create(ctx, cm)
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	for k, v := range manifests {
+		cm.Data[k] = v
+	}
+	return r.client.Update(ctx, cm)
+}
@@ -0,0 +1,96 @@
+package spinnakerservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func init() {
+	// The fake client needs to know about SpinnakerService to store it
+	_ = v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+}
+
+func TestOrphanOnDelete_addsFinalizer(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.GetApplyPolicyConfig().OrphanOnDelete = true
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, spinSvc)}
+
+	cont, err := r.handleOrphanOnDelete(context.TODO(), spinSvc)
+	assert.Nil(t, err)
+	assert.True(t, cont)
+	assert.True(t, hasFinalizer(spinSvc, orphanFinalizer))
+}
+
+func TestOrphanOnDelete_detachesAndExports(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.SetUID(types.UID("uid-1"))
+	spinSvc.SetFinalizers([]string{orphanFinalizer})
+	now := metav1.Now()
+	spinSvc.SetDeletionTimestamp(&now)
+
+	ownerRef := metav1.OwnerReference{
+		APIVersion: "spinnaker.io/v1alpha2",
+		Kind:       "SpinnakerService",
+		Name:       spinSvc.GetName(),
+		UID:        spinSvc.GetUID(),
+	}
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       spinSvc.GetNamespace(),
+			Name:            "spin-gate",
+			Labels:          map[string]string{"app.kubernetes.io/managed-by": "spinnaker-operator"},
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+	}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       spinSvc.GetNamespace(),
+			Name:            "spin-gate",
+			Labels:          map[string]string{"app": "spin"},
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+	}
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, spinSvc, dep, svc)}
+
+	cont, err := r.handleOrphanOnDelete(context.TODO(), spinSvc)
+	assert.Nil(t, err)
+	assert.False(t, cont)
+	assert.False(t, hasFinalizer(spinSvc, orphanFinalizer))
+
+	// The generated objects no longer point at the service
+	stored := &appsv1.Deployment{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: spinSvc.GetNamespace(), Name: "spin-gate"}, stored))
+	assert.Empty(t, stored.OwnerReferences)
+
+	// Their manifests are exported without cluster-assigned fields
+	cm := &v1.ConfigMap{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{
+		Namespace: spinSvc.GetNamespace(),
+		Name:      spinSvc.GetName() + "-exported-manifests",
+	}, cm))
+	assert.Contains(t, cm.Data, "deployment-spin-gate.yml")
+	assert.Contains(t, cm.Data, "service-spin-gate.yml")
+	assert.NotContains(t, cm.Data["deployment-spin-gate.yml"], "ownerReferences")
+	assert.NotContains(t, cm.Data["deployment-spin-gate.yml"], "resourceVersion")
+}
+
+func TestOrphanOnDelete_notRequested(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	now := metav1.Now()
+	spinSvc.SetDeletionTimestamp(&now)
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, spinSvc)}
+
+	// Without the finalizer the deletion proceeds untouched
+	cont, err := r.handleOrphanOnDelete(context.TODO(), spinSvc)
+	assert.Nil(t, err)
+	assert.True(t, cont)
+}
@@ -0,0 +1,80 @@
+package spinnakerservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PromoteAnnotation names the SpinnakerService the resolved config is promoted
+// to, as name or namespace/name, e.g. spinnaker.io/promote-to: prod/spinnaker.
+// Only spec.spinnakerConfig moves; the target keeps its own expose, naming and
+// other per environment overlays. The lineage lands in the target's status
+const PromoteAnnotation = "spinnaker.io/promote-to"
+
+// handlePromoteRequest serves a config promotion requested through the promote
+// annotation, then clears the annotation so it fires exactly once.
+// It returns true when the reconcile should stop and requeue
+func (r *ReconcileSpinnakerService) handlePromoteRequest(ctx context.Context, svc interfaces.SpinnakerService, logger logr.Logger) (bool, error) {
+	ref := svc.GetAnnotations()[PromoteAnnotation]
+	if ref == "" {
+		return false, nil
+	}
+	ns, name := parsePromoteTarget(ref, svc.GetNamespace())
+	target := TypesFactory.NewService()
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, target); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.evtRecorder.Eventf(svc, corev1.EventTypeWarning, "PromotionFailed", "No SpinnakerService %s/%s found, ignoring promotion request", ns, name)
+			return false, r.clearPromoteAnnotation(ctx, svc)
+		}
+		return false, err
+	}
+	if target.GetNamespace() == svc.GetNamespace() && target.GetName() == svc.GetName() {
+		r.evtRecorder.Eventf(svc, corev1.EventTypeWarning, "PromotionFailed", "Cannot promote a SpinnakerService onto itself")
+		return false, r.clearPromoteAnnotation(ctx, svc)
+	}
+
+	*target.GetSpinnakerConfig() = *svc.GetSpinnakerConfig().DeepCopy()
+	if err := r.client.Update(ctx, target); err != nil {
+		return false, err
+	}
+	target.GetStatus().LastPromotion = &interfaces.PromotionRecord{
+		From:           fmt.Sprintf("%s/%s", svc.GetNamespace(), svc.GetName()),
+		FromGeneration: svc.GetGeneration(),
+		Time:           metav1.NewTime(time.Now()),
+	}
+	if err := r.client.Status().Update(ctx, target); err != nil {
+		return false, err
+	}
+	logger.Info(fmt.Sprintf("promoted config to %s/%s", ns, name))
+	r.evtRecorder.Eventf(svc, corev1.EventTypeNormal, "PromotionPerformed", "Config promoted to SpinnakerService %s/%s", ns, name)
+	r.evtRecorder.Eventf(target, corev1.EventTypeNormal, "PromotionReceived", "Config promoted from SpinnakerService %s/%s generation %d", svc.GetNamespace(), svc.GetName(), svc.GetGeneration())
+	return true, r.clearPromoteAnnotation(ctx, svc)
+}
+
+func (r *ReconcileSpinnakerService) clearPromoteAnnotation(ctx context.Context, svc interfaces.SpinnakerService) error {
+	a := svc.GetAnnotations()
+	if _, ok := a[PromoteAnnotation]; !ok {
+		return nil
+	}
+	delete(a, PromoteAnnotation)
+	svc.SetAnnotations(a)
+	return r.client.Update(ctx, svc)
+}
+
+// parsePromoteTarget resolves name or namespace/name, defaulting to the
+// namespace of the source
+func parsePromoteTarget(ref, defaultNs string) (string, string) {
+	if i := strings.Index(ref, "/"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return defaultNs, ref
+}
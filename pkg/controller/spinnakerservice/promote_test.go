@@ -0,0 +1,77 @@
+package spinnakerservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestHandlePromoteRequest_promotes(t *testing.T) {
+	prevFactory := TypesFactory
+	TypesFactory = test.TypesFactory
+	defer func() { TypesFactory = prevFactory }()
+	source := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	source.SetAnnotations(map[string]string{PromoteAnnotation: "prod/spinnaker"})
+	assert.Nil(t, source.GetSpinnakerConfig().SetHalConfigProp("version", "1.17.1"))
+
+	target := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	target.SetNamespace("prod")
+	assert.Nil(t, target.GetSpinnakerConfig().SetHalConfigProp("version", "1.16.0"))
+
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, source, target), evtRecorder: &record.FakeRecorder{Events: make(chan string, 10)}}
+
+	handled, err := r.handlePromoteRequest(context.TODO(), source, log.WithName("test"))
+	assert.Nil(t, err)
+	assert.True(t, handled)
+
+	stored := test.TypesFactory.NewService()
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "prod", Name: "spinnaker"}, stored))
+	v, err := stored.GetSpinnakerConfig().GetHalConfigPropString(context.TODO(), "version")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.17.1", v)
+	if assert.NotNil(t, stored.GetStatus().LastPromotion) {
+		assert.Equal(t, "ns1/spinnaker", stored.GetStatus().LastPromotion.From)
+	}
+	assert.NotContains(t, source.GetAnnotations(), PromoteAnnotation)
+}
+
+func TestHandlePromoteRequest_missingTarget(t *testing.T) {
+	prevFactory := TypesFactory
+	TypesFactory = test.TypesFactory
+	defer func() { TypesFactory = prevFactory }()
+	source := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	source.SetAnnotations(map[string]string{PromoteAnnotation: "prod/spinnaker"})
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, source), evtRecorder: &record.FakeRecorder{Events: make(chan string, 10)}}
+
+	handled, err := r.handlePromoteRequest(context.TODO(), source, log.WithName("test"))
+	assert.Nil(t, err)
+	assert.False(t, handled)
+	assert.NotContains(t, source.GetAnnotations(), PromoteAnnotation)
+}
+
+func TestHandlePromoteRequest_selfDenied(t *testing.T) {
+	prevFactory := TypesFactory
+	TypesFactory = test.TypesFactory
+	defer func() { TypesFactory = prevFactory }()
+	source := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	source.SetAnnotations(map[string]string{PromoteAnnotation: "spinnaker"})
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, source), evtRecorder: &record.FakeRecorder{Events: make(chan string, 10)}}
+
+	handled, err := r.handlePromoteRequest(context.TODO(), source, log.WithName("test"))
+	assert.Nil(t, err)
+	assert.False(t, handled)
+	assert.NotContains(t, source.GetAnnotations(), PromoteAnnotation)
+}
+
+func TestParsePromoteTarget(t *testing.T) {
+	ns, name := parsePromoteTarget("prod/spinnaker", "staging")
+	assert.Equal(t, "prod", ns)
+	assert.Equal(t, "spinnaker", name)
+	ns, name = parsePromoteTarget("spinnaker-2", "staging")
+	assert.Equal(t, "staging", ns)
+	assert.Equal(t, "spinnaker-2", name)
+}
@@ -0,0 +1,64 @@
+package spinnakerservice
+
+import (
+	"os"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy"
+)
+
+const (
+	// RequeueHealthyEnvVar sets the reconcile interval while Spinnaker is healthy
+	RequeueHealthyEnvVar = "OPERATOR_REQUEUE_HEALTHY"
+	// RequeueProgressingEnvVar sets the reconcile interval while a rollout is in progress
+	RequeueProgressingEnvVar = "OPERATOR_REQUEUE_PROGRESSING"
+	// RequeueDegradedEnvVar sets the reconcile interval when the deployment failed or is degraded
+	RequeueDegradedEnvVar = "OPERATOR_REQUEUE_DEGRADED"
+
+	defaultHealthyRequeue     = 10 * time.Minute
+	defaultProgressingRequeue = 15 * time.Second
+	defaultDegradedRequeue    = time.Minute
+)
+
+// requeueIntervals balances responsiveness with API load: healthy services are
+// revisited rarely, in-flight rollouts often, degraded ones in between
+type requeueIntervals struct {
+	healthy     time.Duration
+	progressing time.Duration
+	degraded    time.Duration
+}
+
+func requeueIntervalsFromEnv() *requeueIntervals {
+	return &requeueIntervals{
+		healthy:     intervalFromEnv(RequeueHealthyEnvVar, defaultHealthyRequeue),
+		progressing: intervalFromEnv(RequeueProgressingEnvVar, defaultProgressingRequeue),
+		degraded:    intervalFromEnv(RequeueDegradedEnvVar, defaultDegradedRequeue),
+	}
+}
+
+func intervalFromEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Info("ignoring invalid duration in " + name + ": " + v)
+		return def
+	}
+	return d
+}
+
+// forStatus returns the wait before the next reconcile given the overall status
+func (ri *requeueIntervals) forStatus(status string) time.Duration {
+	switch status {
+	case Ok:
+		return ri.healthy
+	case Failure, Degraded:
+		return ri.degraded
+	case spindeploy.StatusPendingFreeze:
+		// Frozen changes only need a recheck when the window may have ended
+		return ri.degraded
+	}
+	return ri.progressing
+}
@@ -0,0 +1,37 @@
+package spinnakerservice
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntervalFromEnv(t *testing.T) {
+	assert.Equal(t, defaultHealthyRequeue, intervalFromEnv(RequeueHealthyEnvVar, defaultHealthyRequeue))
+
+	err := os.Setenv(RequeueHealthyEnvVar, "30m")
+	assert.Nil(t, err)
+	defer os.Unsetenv(RequeueHealthyEnvVar)
+	assert.Equal(t, 30*time.Minute, intervalFromEnv(RequeueHealthyEnvVar, defaultHealthyRequeue))
+
+	err = os.Setenv(RequeueHealthyEnvVar, "not-a-duration")
+	assert.Nil(t, err)
+	assert.Equal(t, defaultHealthyRequeue, intervalFromEnv(RequeueHealthyEnvVar, defaultHealthyRequeue))
+
+	err = os.Setenv(RequeueHealthyEnvVar, "-5s")
+	assert.Nil(t, err)
+	assert.Equal(t, defaultHealthyRequeue, intervalFromEnv(RequeueHealthyEnvVar, defaultHealthyRequeue))
+}
+
+func TestForStatus(t *testing.T) {
+	ri := requeueIntervalsFromEnv()
+	assert.Equal(t, defaultHealthyRequeue, ri.forStatus(Ok))
+	assert.Equal(t, defaultProgressingRequeue, ri.forStatus(Updating))
+	assert.Equal(t, defaultProgressingRequeue, ri.forStatus(Na))
+	assert.Equal(t, defaultDegradedRequeue, ri.forStatus(Failure))
+	assert.Equal(t, defaultDegradedRequeue, ri.forStatus(Degraded))
+	assert.Equal(t, defaultDegradedRequeue, ri.forStatus(spindeploy.StatusPendingFreeze))
+}
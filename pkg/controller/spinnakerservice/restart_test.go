@@ -0,0 +1,79 @@
+package spinnakerservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func restartDeployment(ready bool) *appsv1.Deployment {
+	one := int32(1)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "spin-clouddriver", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: &one},
+	}
+	if ready {
+		dep.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 1, ReadyReplicas: 1}
+	}
+	return dep
+}
+
+func TestHandleRestartRequest_triggers(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.SetAnnotations(map[string]string{RestartAnnotation: "clouddriver"})
+	dep := restartDeployment(true)
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, spinSvc, dep), evtRecorder: &record.FakeRecorder{Events: make(chan string, 10)}}
+
+	handled, err := r.handleRestartRequest(context.TODO(), spinSvc, log.WithName("test"))
+	assert.Nil(t, err)
+	assert.True(t, handled)
+
+	// The pod template got the restart marker and the request annotation is gone
+	stored := &appsv1.Deployment{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "spin-clouddriver"}, stored))
+	assert.NotEmpty(t, stored.Spec.Template.Annotations[restartedAtAnnotation])
+	assert.NotContains(t, spinSvc.GetAnnotations(), RestartAnnotation)
+}
+
+func TestHandleRestartRequest_deferredWhileRolling(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.SetAnnotations(map[string]string{RestartAnnotation: "clouddriver"})
+	dep := restartDeployment(false)
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, spinSvc, dep), evtRecorder: &record.FakeRecorder{Events: make(chan string, 10)}}
+
+	handled, err := r.handleRestartRequest(context.TODO(), spinSvc, log.WithName("test"))
+	assert.Nil(t, err)
+	assert.True(t, handled)
+
+	// Nothing is rolled and the annotation stays for the next reconcile
+	stored := &appsv1.Deployment{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "spin-clouddriver"}, stored))
+	assert.Empty(t, stored.Spec.Template.Annotations)
+	assert.Contains(t, spinSvc.GetAnnotations(), RestartAnnotation)
+}
+
+func TestHandleRestartRequest_unknownService(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.SetAnnotations(map[string]string{RestartAnnotation: "nosuchservice"})
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, spinSvc), evtRecorder: &record.FakeRecorder{Events: make(chan string, 10)}}
+
+	handled, err := r.handleRestartRequest(context.TODO(), spinSvc, log.WithName("test"))
+	assert.Nil(t, err)
+	assert.False(t, handled)
+	assert.NotContains(t, spinSvc.GetAnnotations(), RestartAnnotation)
+}
+
+func TestHandleRestartRequest_noAnnotation(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	r := &ReconcileSpinnakerService{client: test.FakeClient(t, spinSvc)}
+
+	handled, err := r.handleRestartRequest(context.TODO(), spinSvc, log.WithName("test"))
+	assert.Nil(t, err)
+	assert.False(t, handled)
+}
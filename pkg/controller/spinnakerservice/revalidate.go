@@ -0,0 +1,153 @@
+package spinnakerservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RevalidationIntervalEnvVar overrides how often the deployed config is
+	// re-validated in the background, e.g. "30m"
+	RevalidationIntervalEnvVar  = "CONFIG_REVALIDATION_INTERVAL"
+	defaultRevalidationInterval = 1 * time.Hour
+	// CertRenewalWindowEnvVar overrides how long before a certificate expires
+	// that a finding is raised, e.g. "720h"
+	CertRenewalWindowEnvVar = "CERT_RENEWAL_WINDOW"
+	defaultRenewalWindow    = 30 * 24 * time.Hour
+	endpointTimeout         = 10 * time.Second
+
+	jenkinsCheck = "jenkins"
+)
+
+// configRevalidator periodically re-validates the credentials and endpoints
+// referenced by the deployed configuration (expiring certificates, unreachable
+// Jenkins masters) and pushes findings to the SpinnakerService status before
+// they cause an outage
+type configRevalidator struct {
+	client        client.Client
+	interval      time.Duration
+	renewalWindow time.Duration
+	// now and probe are replaced in tests
+	now   func() time.Time
+	probe func(ctx context.Context, url string) error
+}
+
+func newConfigRevalidator(c client.Client) *configRevalidator {
+	interval := defaultRevalidationInterval
+	if v := os.Getenv(RevalidationIntervalEnvVar); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		}
+	}
+	window := defaultRenewalWindow
+	if v := os.Getenv(CertRenewalWindowEnvVar); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			window = d
+		}
+	}
+	return &configRevalidator{
+		client:        c,
+		interval:      interval,
+		renewalWindow: window,
+		now:           time.Now,
+		probe:         probeEndpoint,
+	}
+}
+
+// Start runs the re-validation loop until the manager stops
+func (r *configRevalidator) Start(stop <-chan struct{}) error {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+			r.runOnce(context.TODO())
+		}
+	}
+}
+
+// runOnce re-validates every SpinnakerService and updates its status when the
+// findings changed
+func (r *configRevalidator) runOnce(ctx context.Context) {
+	l := TypesFactory.NewServiceList()
+	if err := r.client.List(ctx, l); err != nil {
+		log.Error(err, "unable to list SpinnakerServices for re-validation")
+		return
+	}
+	for _, svc := range l.GetItems() {
+		findings := r.check(ctx, svc)
+		if reflect.DeepEqual(findings, svc.GetStatus().Findings) {
+			continue
+		}
+		for _, f := range findings {
+			log.Info(fmt.Sprintf("re-validation finding for %s/%s: %s %s: %s", svc.GetNamespace(), svc.GetName(), f.Check, f.Target, f.Message))
+		}
+		svc.GetStatus().Findings = findings
+		if err := r.client.Status().Update(ctx, svc); err != nil {
+			log.Error(err, "unable to update re-validation findings", "metadata.name", svc.GetName())
+		}
+	}
+}
+
+func (r *configRevalidator) check(ctx context.Context, svc interfaces.SpinnakerService) []interfaces.ConfigFinding {
+	var findings []interfaces.ConfigFinding
+	findings = append(findings, r.certificateFindings(svc, r.collectCertificates(ctx, svc))...)
+	findings = append(findings, r.checkJenkinsMasters(ctx, svc)...)
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Check != findings[j].Check {
+			return findings[i].Check < findings[j].Check
+		}
+		return findings[i].Target < findings[j].Target
+	})
+	return findings
+}
+
+// checkJenkinsMasters flags the Jenkins masters of the halconfig that don't
+// answer anymore
+func (r *configRevalidator) checkJenkinsMasters(ctx context.Context, svc interfaces.SpinnakerService) []interfaces.ConfigFinding {
+	masters, err := svc.GetSpinnakerConfig().GetHalConfigObjectArray(ctx, "ci.jenkins.masters")
+	if err != nil {
+		return nil
+	}
+	var findings []interfaces.ConfigFinding
+	for _, m := range masters {
+		name, _ := m["name"].(string)
+		address, _ := m["address"].(string)
+		if address == "" {
+			continue
+		}
+		if err := r.probe(ctx, address); err != nil {
+			findings = append(findings, interfaces.ConfigFinding{
+				Check:   jenkinsCheck,
+				Target:  name,
+				Message: fmt.Sprintf("jenkins master at %s is unreachable: %s", address, err.Error()),
+			})
+		}
+	}
+	return findings
+}
+
+// probeEndpoint considers any HTTP answer as reachable, authentication
+// failures included, only connection level problems are findings
+func probeEndpoint(ctx context.Context, url string) error {
+	c := &http.Client{Timeout: endpointTimeout}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
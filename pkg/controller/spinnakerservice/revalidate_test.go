@@ -0,0 +1,113 @@
+package spinnakerservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func tlsSecret(t *testing.T, name, ns string, notAfter time.Time) *v1.Secret {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(t, err)
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "spin-gate"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Type:       v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		},
+	}
+}
+
+func TestRevalidate_expiringCertificate(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	sec := tlsSecret(t, "gate-tls", spinSvc.GetNamespace(), time.Now().Add(10*24*time.Hour))
+	r := &configRevalidator{
+		client:        test.FakeClient(t, spinSvc, sec),
+		renewalWindow: defaultRenewalWindow,
+		now:           time.Now,
+		probe:         func(ctx context.Context, url string) error { return nil },
+	}
+
+	prevFactory := TypesFactory
+	TypesFactory = test.TypesFactory
+	defer func() { TypesFactory = prevFactory }()
+	r.runOnce(context.TODO())
+
+	stored := test.TypesFactory.NewService()
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: spinSvc.GetNamespace(), Name: spinSvc.GetName()}, stored))
+	if assert.Len(t, stored.GetStatus().Findings, 1) {
+		f := stored.GetStatus().Findings[0]
+		assert.Equal(t, certificateCheck, f.Check)
+		assert.Equal(t, "gate-tls", f.Target)
+		assert.Contains(t, f.Message, "certificate expires on")
+	}
+}
+
+func TestRevalidate_unreachableJenkins(t *testing.T) {
+	spinsvc := interfaces.DefaultTypesFactory.NewService()
+	assert.Nil(t, yaml.Unmarshal([]byte(`
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      ci:
+        jenkins:
+          enabled: true
+          masters:
+          - name: ci-master
+            address: http://jenkins.example.com
+`), spinsvc))
+	r := &configRevalidator{
+		client: test.FakeClient(t, spinsvc),
+		now:    time.Now,
+		probe:  func(ctx context.Context, url string) error { return errors.New("connection refused") },
+	}
+
+	findings := r.check(context.TODO(), spinsvc)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, jenkinsCheck, findings[0].Check)
+		assert.Equal(t, "ci-master", findings[0].Target)
+		assert.Contains(t, findings[0].Message, "connection refused")
+	}
+}
+
+func TestRevalidate_healthyConfigHasNoFindings(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	sec := tlsSecret(t, "gate-tls", spinSvc.GetNamespace(), time.Now().Add(365*24*time.Hour))
+	r := &configRevalidator{
+		client:        test.FakeClient(t, spinSvc, sec),
+		renewalWindow: defaultRenewalWindow,
+		now:           time.Now,
+		probe:         func(ctx context.Context, url string) error { return nil },
+	}
+
+	assert.Empty(t, r.check(context.TODO(), spinSvc))
+}
@@ -0,0 +1,100 @@
+package spinnakerservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxSummarizedEvents bounds how many pod events end up in the status message
+const maxSummarizedEvents = 3
+
+// stuckRollouts returns the deployments that are still not ready
+// spec.apply.progressDeadlineSeconds after the last deployment, with a short
+// summary of the warning events recorded for their pods
+func (s *statusChecker) stuckRollouts(instance interfaces.SpinnakerService, deployments []appsv1.Deployment) []interfaces.RolloutIssue {
+	deadline := instance.GetApplyPolicyConfig().ProgressDeadlineSeconds
+	if deadline <= 0 {
+		return nil
+	}
+	started := lastDeployTime(instance)
+	if started.IsZero() || time.Since(started.Time) < time.Duration(deadline)*time.Second {
+		return nil
+	}
+	var issues []interfaces.RolloutIssue
+	for i := range deployments {
+		d := deployments[i]
+		if d.Status.ReadyReplicas >= expectedReplicas(&d) {
+			continue
+		}
+		issues = append(issues, interfaces.RolloutIssue{
+			Service: d.Name,
+			Message: s.summarizePodEvents(instance, d),
+		})
+	}
+	return issues
+}
+
+// lastDeployTime returns when the current configuration was applied
+func lastDeployTime(instance interfaces.SpinnakerService) metav1.Time {
+	var last metav1.Time
+	for _, h := range instance.GetStatus().LastDeployed {
+		if last.Before(&h.LastUpdatedAt) {
+			last = h.LastUpdatedAt
+		}
+	}
+	return last
+}
+
+func expectedReplicas(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas != nil {
+		return *d.Spec.Replicas
+	}
+	return 1
+}
+
+// summarizePodEvents condenses the warning events of the deployment's pods
+// into a single line for the status, falling back to the replica counts when
+// no event was recorded
+func (s *statusChecker) summarizePodEvents(instance interfaces.SpinnakerService, d appsv1.Deployment) string {
+	fallback := fmt.Sprintf("%d/%d replicas ready", d.Status.ReadyReplicas, expectedReplicas(&d))
+	pods, err := s.k8sLookup.GetPodsByDeployment(instance, d)
+	if err != nil {
+		return fallback
+	}
+	names := map[string]bool{}
+	for _, p := range pods {
+		names[p.Name] = true
+	}
+	events := &v1.EventList{}
+	if err := s.client.List(context.TODO(), events, client.InNamespace(d.Namespace)); err != nil {
+		return fallback
+	}
+	var msgs []string
+	seen := map[string]bool{}
+	for _, e := range events.Items {
+		if e.Type == v1.EventTypeNormal || e.InvolvedObject.Kind != "Pod" || !names[e.InvolvedObject.Name] {
+			continue
+		}
+		m := fmt.Sprintf("%s: %s", e.Reason, e.Message)
+		if seen[m] {
+			continue
+		}
+		seen[m] = true
+		msgs = append(msgs, m)
+		if len(msgs) == maxSummarizedEvents {
+			break
+		}
+	}
+	if len(msgs) == 0 {
+		return fallback
+	}
+	return fmt.Sprintf("%s (%s)", fallback, strings.Join(msgs, "; "))
+}
@@ -0,0 +1,72 @@
+package spinnakerservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStuckRollouts_deadlineExceeded(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.GetApplyPolicyConfig().ProgressDeadlineSeconds = 60
+	spinSvc.GetStatus().LastDeployed = map[string]interfaces.HashStatus{
+		"config": {LastUpdatedAt: metav1.NewTime(time.Now().Add(-2 * time.Minute))},
+	}
+	two := int32(2)
+	dep := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: spinSvc.GetNamespace(), Name: "spin-orca"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &two},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: dep.Namespace, Name: "spin-orca-abc"}}
+	evt := &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: dep.Namespace, Name: "spin-orca-abc.evt"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: dep.Namespace, Name: pod.Name},
+		Type:           v1.EventTypeWarning,
+		Reason:         "Unhealthy",
+		Message:        "Readiness probe failed",
+	}
+
+	ctrl := gomock.NewController(t)
+	mkl := util.NewMockIk8sLookup(ctrl)
+	mkl.EXPECT().GetPodsByDeployment(gomock.Any(), gomock.Any()).Return([]v1.Pod{pod}, nil)
+	s := &statusChecker{client: test.FakeClient(t, evt), k8sLookup: mkl}
+
+	issues := s.stuckRollouts(spinSvc, []appsv1.Deployment{dep})
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, "spin-orca", issues[0].Service)
+		assert.Contains(t, issues[0].Message, "1/2 replicas ready")
+		assert.Contains(t, issues[0].Message, "Unhealthy: Readiness probe failed")
+	}
+}
+
+func TestStuckRollouts_withinDeadline(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.GetApplyPolicyConfig().ProgressDeadlineSeconds = 600
+	spinSvc.GetStatus().LastDeployed = map[string]interfaces.HashStatus{
+		"config": {LastUpdatedAt: metav1.NewTime(time.Now())},
+	}
+	dep := appsv1.Deployment{Status: appsv1.DeploymentStatus{ReadyReplicas: 0}}
+	s := &statusChecker{client: test.FakeClient(t)}
+
+	assert.Empty(t, s.stuckRollouts(spinSvc, []appsv1.Deployment{dep}))
+}
+
+func TestStuckRollouts_disabledByDefault(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc.yml", t)
+	spinSvc.GetStatus().LastDeployed = map[string]interfaces.HashStatus{
+		"config": {LastUpdatedAt: metav1.NewTime(time.Now().Add(-time.Hour))},
+	}
+	dep := appsv1.Deployment{Status: appsv1.DeploymentStatus{ReadyReplicas: 0}}
+	s := &statusChecker{client: test.FakeClient(t)}
+
+	assert.Empty(t, s.stuckRollouts(spinSvc, []appsv1.Deployment{dep}))
+}
@@ -0,0 +1,182 @@
+package spinnakerservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+)
+
+// smokeTestRunner runs endpoint smoke tests against a deployed Spinnaker
+// before the deployment is considered successful
+type smokeTestRunner struct {
+	httpClient *http.Client
+	logger     logr.Logger
+	// Base URLs, overridable in tests. Defaults are built from the
+	// in-cluster service DNS names when left empty.
+	gateUrl        string
+	deckUrl        string
+	clouddriverUrl string
+}
+
+func newSmokeTestRunner(logger logr.Logger) *smokeTestRunner {
+	return &smokeTestRunner{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     logger,
+	}
+}
+
+// run executes all smoke tests, returning the results and whether they all passed
+func (s *smokeTestRunner) run(ctx context.Context, instance interfaces.SpinnakerService) ([]interfaces.SmokeTestResult, bool) {
+	ns := instance.GetNamespace()
+	results := []interfaces.SmokeTestResult{
+		s.gateHealth(ctx, ns),
+		s.deckIndex(ctx, ns),
+		s.clouddriverCredentials(ctx, ns, instance),
+	}
+	passed := true
+	for _, r := range results {
+		passed = passed && r.Passed
+	}
+	return results, passed
+}
+
+// gateHealth checks that Gate reports itself UP
+func (s *smokeTestRunner) gateHealth(ctx context.Context, ns string) interfaces.SmokeTestResult {
+	u := s.gateUrl
+	if u == "" {
+		u = util.BuildUrl("http", fmt.Sprintf("%s.%s", util.GateServiceName, ns), util.GateDefaultPort)
+	}
+	r := interfaces.SmokeTestResult{Name: "gate-health", Url: u + "/health"}
+	body, err := s.get(ctx, r.Url)
+	if err != nil {
+		r.Detail = err.Error()
+		return r
+	}
+	h := struct {
+		Status string `json:"status"`
+	}{}
+	if err := json.Unmarshal(body, &h); err != nil {
+		r.Detail = fmt.Sprintf("unable to parse health response: %s", err.Error())
+		return r
+	}
+	if h.Status != "UP" {
+		r.Detail = fmt.Sprintf("gate reports status %s", h.Status)
+		return r
+	}
+	r.Passed = true
+	return r
+}
+
+// deckIndex checks that Deck serves its index page
+func (s *smokeTestRunner) deckIndex(ctx context.Context, ns string) interfaces.SmokeTestResult {
+	u := s.deckUrl
+	if u == "" {
+		u = util.BuildUrl("http", fmt.Sprintf("%s.%s", util.DeckServiceName, ns), util.DeckDefaultPort)
+	}
+	r := interfaces.SmokeTestResult{Name: "deck-index", Url: u + "/"}
+	body, err := s.get(ctx, r.Url)
+	if err != nil {
+		r.Detail = err.Error()
+		return r
+	}
+	if !strings.Contains(strings.ToLower(string(body)), "<html") {
+		r.Detail = "response does not look like the Deck index page"
+		return r
+	}
+	r.Passed = true
+	return r
+}
+
+// clouddriverCredentials checks that Clouddriver lists every account configured in halconfig
+func (s *smokeTestRunner) clouddriverCredentials(ctx context.Context, ns string, instance interfaces.SpinnakerService) interfaces.SmokeTestResult {
+	u := s.clouddriverUrl
+	if u == "" {
+		u = util.BuildUrl("http", fmt.Sprintf("%s.%s", util.ClouddriverServiceName, ns), util.ClouddriverDefaultPort)
+	}
+	r := interfaces.SmokeTestResult{Name: "clouddriver-credentials", Url: u + "/credentials"}
+	expected := s.expectedAccounts(ctx, instance)
+	body, err := s.get(ctx, r.Url)
+	if err != nil {
+		r.Detail = err.Error()
+		return r
+	}
+	var creds []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &creds); err != nil {
+		r.Detail = fmt.Sprintf("unable to parse credentials response: %s", err.Error())
+		return r
+	}
+	found := map[string]bool{}
+	for _, c := range creds {
+		found[c.Name] = true
+	}
+	var missing []string
+	for _, a := range expected {
+		if !found[a] {
+			missing = append(missing, a)
+		}
+	}
+	if len(missing) > 0 {
+		r.Detail = fmt.Sprintf("accounts not listed by clouddriver: %s", strings.Join(missing, ", "))
+		return r
+	}
+	r.Passed = true
+	return r
+}
+
+// expectedAccounts gathers the names of all provider accounts configured in halconfig
+func (s *smokeTestRunner) expectedAccounts(ctx context.Context, instance interfaces.SpinnakerService) []string {
+	var accounts []string
+	cfg := instance.GetSpinnakerConfig()
+	providers, err := inspect.GetObjectProp(cfg.Config, "providers")
+	if err != nil || providers.Kind() != reflect.Map {
+		return accounts
+	}
+	for _, k := range providers.MapKeys() {
+		name, ok := k.Interface().(string)
+		if !ok {
+			continue
+		}
+		as, err := cfg.GetHalConfigObjectArray(ctx, fmt.Sprintf("providers.%s.accounts", name))
+		if err != nil {
+			continue
+		}
+		for _, a := range as {
+			if n, ok := a["name"].(string); ok {
+				accounts = append(accounts, n)
+			}
+		}
+	}
+	return accounts
+}
+
+func (s *smokeTestRunner) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	return body, nil
+}
@@ -0,0 +1,115 @@
+package spinnakerservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var smokeTestSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        kubernetes:
+          enabled: true
+          accounts:
+          - name: kube-account
+`
+
+func setupSmokeTestRunner(t *testing.T, gate, deck, clouddriver http.HandlerFunc) (*smokeTestRunner, func()) {
+	gs := httptest.NewServer(gate)
+	ds := httptest.NewServer(deck)
+	cs := httptest.NewServer(clouddriver)
+	r := &smokeTestRunner{
+		httpClient:     &http.Client{Timeout: 5 * time.Second},
+		gateUrl:        gs.URL,
+		deckUrl:        ds.URL,
+		clouddriverUrl: cs.URL,
+	}
+	return r, func() {
+		gs.Close()
+		ds.Close()
+		cs.Close()
+	}
+}
+
+func TestSmokeTests_allPass(t *testing.T) {
+	r, cleanup := setupSmokeTestRunner(t,
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"status":"UP"}`))
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`<html><head></head></html>`))
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[{"name":"kube-account","type":"kubernetes"}]`))
+		})
+	defer cleanup()
+	spinsvc := test.ManifestToSpinService(smokeTestSpinsvc, t)
+
+	results, passed := r.run(context.TODO(), spinsvc)
+	assert.True(t, passed)
+	assert.Equal(t, 3, len(results))
+	for _, res := range results {
+		assert.True(t, res.Passed, res.Name)
+	}
+}
+
+func TestSmokeTests_missingAccount(t *testing.T) {
+	r, cleanup := setupSmokeTestRunner(t,
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"status":"UP"}`))
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`<html></html>`))
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[]`))
+		})
+	defer cleanup()
+	spinsvc := test.ManifestToSpinService(smokeTestSpinsvc, t)
+
+	results, passed := r.run(context.TODO(), spinsvc)
+	assert.False(t, passed)
+	for _, res := range results {
+		if res.Name == "clouddriver-credentials" {
+			assert.False(t, res.Passed)
+			assert.Contains(t, res.Detail, "kube-account")
+		} else {
+			assert.True(t, res.Passed, res.Name)
+		}
+	}
+}
+
+func TestSmokeTests_gateDown(t *testing.T) {
+	r, cleanup := setupSmokeTestRunner(t,
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`<html></html>`))
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[{"name":"kube-account"}]`))
+		})
+	defer cleanup()
+	spinsvc := test.ManifestToSpinService(smokeTestSpinsvc, t)
+
+	results, passed := r.run(context.TODO(), spinsvc)
+	assert.False(t, passed)
+	assert.Equal(t, "gate-health", results[0].Name)
+	assert.False(t, results[0].Passed)
+	assert.Contains(t, results[0].Detail, "unexpected status code 500")
+}
@@ -14,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
@@ -24,37 +25,59 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
 )
 
 var (
 	log                = logf.Log.WithName("spinnakerservice")
 	TypesFactory       interfaces.TypesFactory
 	DeployerGenerators = []deployerGenerator{spindeploy.NewDeployer}
+	// ManifestGeneratorFactory builds the manifest generation backend,
+	// swapped out through the manifest-generator operator flag
+	ManifestGeneratorFactory = func() deploy.ManifestGenerator { return halyard.NewService() }
 )
 
 // Add creates a new SpinnakerService Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
 }
 
 type deployerGenerator func(m deploy.ManifestGenerator, mgr manager.Manager, clientset *kubernetes.Clientset, logger logr.Logger) deploy.Deployer
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	h := halyard.NewService()
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	h := ManifestGeneratorFactory()
 	rawClient := kubernetes.NewForConfigOrDie(mgr.GetConfig())
 	deps := make([]deploy.Deployer, 0)
 	for _, g := range DeployerGenerators {
 		deps = append(deps, g(h, mgr, rawClient, log))
 	}
+	// Status checks read through label filtered shared informers so a big
+	// fleet of generated pods does not translate into per-reconcile API calls
+	lookup := util.NewCachedK8sLookup(mgr.GetClient(), rawClient)
+	if err := mgr.Add(lookup); err != nil {
+		return nil, err
+	}
+	// Re-validate deployed credentials and endpoints in the background
+	if err := mgr.Add(newConfigRevalidator(mgr.GetClient())); err != nil {
+		return nil, err
+	}
 	return &ReconcileSpinnakerService{
 		client:      mgr.GetClient(),
 		restConfig:  mgr.GetConfig(),
 		scheme:      mgr.GetScheme(),
 		deployers:   deps,
-		evtRecorder: mgr.GetEventRecorderFor("spinnaker-controller"),
-	}
+		evtRecorder: newTemplatedRecorder(mgr.GetEventRecorderFor("spinnaker-controller"), mgr.GetClient()),
+		budget:      newReconcileBudget(),
+		k8sLookup:   lookup,
+		statusOnly:  newStatusOnlyTracker(),
+		requeue:     requeueIntervalsFromEnv(),
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -79,10 +102,49 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
-	return c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    TypesFactory.NewService(),
 	})
+	if err != nil {
+		return err
+	}
+
+	// Watch TLS secrets so certificate rotations are propagated to the services mounting them
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			sec, ok := o.Object.(*corev1.Secret)
+			if !ok || sec.Type != corev1.SecretTypeTLS {
+				return nil
+			}
+			return requestsForServicesIn(mgr.GetClient(), sec.GetNamespace())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch namespaces so selector based Kubernetes account scopes stay up to date
+	return c.Watch(&source.Kind{Type: &corev1.Namespace{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return requestsForServicesIn(mgr.GetClient(), "")
+		}),
+	})
+}
+
+// requestsForServicesIn returns a reconcile request for every SpinnakerService in the namespace
+func requestsForServicesIn(c client.Client, ns string) []reconcile.Request {
+	l := TypesFactory.NewServiceList()
+	if err := c.List(context.TODO(), l, client.InNamespace(ns)); err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, s := range l.GetItems() {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: s.GetNamespace(), Name: s.GetName()},
+		})
+	}
+	return reqs
 }
 
 // blank assignment to verify that ReconcileSpinnakerService implements reconcile.Reconciler
@@ -97,6 +159,10 @@ type ReconcileSpinnakerService struct {
 	scheme      *runtime.Scheme
 	deployers   []deploy.Deployer
 	evtRecorder record.EventRecorder
+	budget      *reconcileBudget
+	k8sLookup   util.Ik8sLookup
+	statusOnly  *statusOnlyTracker
+	requeue     *requeueIntervals
 }
 
 // Reconcile reads that state of the cluster for a SpinnakerService object and makes changes based on the state read
@@ -108,6 +174,21 @@ func (r *ReconcileSpinnakerService) Reconcile(request reconcile.Request) (reconc
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("reconciling SpinnakerService")
 
+	// Give other services a chance to be processed if this one exhausted its time budget
+	if r.budget != nil {
+		if wait := r.budget.backoff(request.String()); wait > 0 {
+			reqLogger.Info(fmt.Sprintf("reconcile time budget exhausted, requeuing in %s", wait))
+			reconcileThrottled.WithLabelValues(request.Namespace, request.Name).Inc()
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+		start := time.Now()
+		defer func() {
+			d := time.Since(start)
+			r.budget.record(request.String(), d)
+			reconcileDuration.WithLabelValues(request.Namespace, request.Name).Observe(d.Seconds())
+		}()
+	}
+
 	// Fetch the SpinnakerService instance
 	instance := TypesFactory.NewService()
 	ctx := secrets.NewContext(context.TODO(), r.restConfig, request.Namespace)
@@ -125,6 +206,42 @@ func (r *ReconcileSpinnakerService) Reconcile(request reconcile.Request) (reconc
 		return reconcile.Result{}, err
 	}
 
+	// Detach generated objects before deletion when asked to orphan them
+	if cont, err := r.handleOrphanOnDelete(ctx, instance); !cont {
+		return reconcile.Result{}, err
+	}
+
+	// Manage the lifecycle of an operator-created target namespace
+	if cont, err := r.handleManagedNamespace(ctx, instance); !cont {
+		return reconcile.Result{}, err
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Serve an annotation requested config promotion to another instance
+	if handled, err := r.handlePromoteRequest(ctx, instance, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	} else if handled {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// Serve an annotation requested rolling restart of a single service
+	if handled, err := r.handleRestartRequest(ctx, instance, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	} else if handled {
+		return reconcile.Result{RequeueAfter: r.requeue.progressing}, nil
+	}
+
+	// Our own status writes requeue the service; don't rerun the deploy path
+	// when the spec generation was already processed. Still requeue: in steady
+	// state the status write is a no-op that fires no watch event, so this may
+	// be the only queued reconcile and returning without a wait would end the
+	// periodic health loop
+	if r.isStatusOnlyReconcile(request.String(), instance) {
+		reqLogger.Info("only status changed since last deployment, skipping deploy")
+		return reconcile.Result{RequeueAfter: r.requeue.forStatus(instance.GetStatus().Status)}, nil
+	}
+
 	r.evtRecorder.Eventf(instance, corev1.EventTypeNormal, "DeployStart", "New configuration detected")
 
 	// Check if we need to redeploy
@@ -140,11 +257,26 @@ func (r *ReconcileSpinnakerService) Reconcile(request reconcile.Request) (reconc
 			return reconcile.Result{Requeue: true}, nil
 		}
 	}
-	sc := newStatusChecker(r.client, reqLogger, TypesFactory, r.evtRecorder, util.NewK8sLookup(r.client))
-	if err = sc.checks(instance); err != nil {
+	// A freeze window held the rendered changes back and the deployer reported
+	// PendingFreeze. Recomputing the status from pod health would overwrite it,
+	// so leave it in place and come back once the window may have ended
+	if instance.GetStatus().Status == spindeploy.StatusPendingFreeze {
+		wait := r.requeue.forStatus(spindeploy.StatusPendingFreeze)
+		reqLogger.Info(fmt.Sprintf("deployment frozen, next attempt in %s", wait))
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+	sc := newStatusChecker(r.client, reqLogger, TypesFactory, r.evtRecorder, r.k8sLookup)
+	overall, err := sc.checks(instance)
+	if err != nil {
 		r.evtRecorder.Eventf(instance, corev1.EventTypeWarning, "StatusError", "Error updating SpinnakerService status: %s", err.Error())
 		return reconcile.Result{}, err
 	}
+	if r.statusOnly != nil {
+		r.statusOnly.mark(request.String())
+	}
 	r.evtRecorder.Eventf(instance, corev1.EventTypeNormal, "DeploySuccess", "Spinnaker updated")
-	return reconcile.Result{}, nil
+	// Revisit healthy services rarely, rollouts in progress often
+	wait := r.requeue.forStatus(overall)
+	reqLogger.Info(fmt.Sprintf("status is %s, next check in %s", overall, wait))
+	return reconcile.Result{RequeueAfter: wait}, nil
 }
@@ -2,11 +2,14 @@ package spinnakerservice
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"time"
+
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/util"
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -17,6 +20,7 @@ type statusChecker struct {
 	typesFactory interfaces.TypesFactory
 	evtRecorder  record.EventRecorder
 	k8sLookup    util.Ik8sLookup
+	smokeTests   *smokeTestRunner
 }
 
 const (
@@ -25,6 +29,7 @@ const (
 	Unavailable = "Unavailable"
 	Na          = "N/A"
 	Failure     = "Failure"
+	Degraded    = "Degraded"
 )
 
 func newStatusChecker(client client.Client, logger logr.Logger, f interfaces.TypesFactory, evtRecorder record.EventRecorder, k8sLookup util.Ik8sLookup) statusChecker {
@@ -34,17 +39,26 @@ func newStatusChecker(client client.Client, logger logr.Logger, f interfaces.Typ
 		typesFactory: f,
 		evtRecorder:  evtRecorder,
 		k8sLookup:    k8sLookup,
+		smokeTests:   newSmokeTestRunner(logger),
 	}
 }
 
-func (s *statusChecker) checks(instance interfaces.SpinnakerService) error {
+// checks updates the status of the service and returns the overall status,
+// the caller decides how soon to requeue based on it
+func (s *statusChecker) checks(instance interfaces.SpinnakerService) (string, error) {
 	svcs := make([]interfaces.SpinnakerDeploymentStatus, 0)
 	svc := instance.DeepCopyInterface()
 	status := svc.GetStatus()
 	deployments, err := s.k8sLookup.GetSpinnakerDeployments(instance)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	prior := map[string]interfaces.SpinnakerDeploymentStatus{}
+	for _, p := range status.Services {
+		prior[p.Name] = p
 	}
+	now := time.Now()
 
 	var pods []v1.Pod
 
@@ -57,10 +71,16 @@ func (s *statusChecker) checks(instance interfaces.SpinnakerService) error {
 			ReadyReplicas: deployment.Status.ReadyReplicas,
 			Image:         s.k8sLookup.GetSpinnakerServiceImageFromDeployment(deployment.Spec.Template.Spec),
 		}
+		// Keep the transition time of services that did not move
+		if p, ok := prior[st.Name]; ok && p.Image == st.Image && p.Replicas == st.Replicas && p.ReadyReplicas == st.ReadyReplicas {
+			st.LastTransitionTime = p.LastTransitionTime
+		} else {
+			st.LastTransitionTime = metav1.NewTime(now)
+		}
 
 		pd, err := s.k8sLookup.GetPodsByDeployment(instance, deployment)
 		if err != nil {
-			return err
+			return "", err
 		}
 		pods = append(pods, pd...)
 		svcs = append(svcs, st)
@@ -68,23 +88,66 @@ func (s *statusChecker) checks(instance interfaces.SpinnakerService) error {
 
 	spinsvcStatus, err := s.getStatus(instance, pods)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	// Only mark the deployment successful once the endpoint smoke tests pass
+	if spinsvcStatus == Ok && s.smokeTests != nil {
+		results, passed := s.smokeTests.run(context.Background(), instance)
+		status.SmokeTests = results
+		if !passed {
+			for _, r := range results {
+				if !r.Passed {
+					s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "SmokeTestFailed", "Smoke test %s against %s failed: %s", r.Name, r.Url, r.Detail)
+				}
+			}
+			spinsvcStatus = Updating
+		}
+	}
+	// Flag rollouts stuck past the configured progress deadline
+	var stuck []interfaces.RolloutIssue
+	if spinsvcStatus == Updating {
+		stuck = s.stuckRollouts(instance, deployments)
+	}
+	status.StuckRollouts = stuck
+	if len(stuck) > 0 {
+		for _, issue := range stuck {
+			s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "RolloutStuck", "Deployment %s did not become ready within the progress deadline: %s", issue.Service, issue.Message)
+		}
+		spinsvcStatus = Degraded
 	}
+
 	status.Status = spinsvcStatus
 	status.Services = svcs
 	status.ServiceCount = len(status.Services)
+	setConditions(status, spinsvcStatus, now)
 
 	// Go through the list
 	err = s.client.Status().Update(context.Background(), svc)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if Updating == status.Status {
-		return errors.New("spinnaker still updating")
-	}
+	return spinsvcStatus, nil
+}
 
-	return nil
+// setConditions maps the overall status onto the standard conditions
+func setConditions(status *interfaces.SpinnakerServiceStatus, overall string, now time.Time) {
+	available := interfaces.ConditionFalse
+	progressing := interfaces.ConditionFalse
+	failed := interfaces.ConditionFalse
+	switch overall {
+	case Ok:
+		available = interfaces.ConditionTrue
+	case Updating, Na:
+		progressing = interfaces.ConditionTrue
+	case Failure, Degraded:
+		failed = interfaces.ConditionTrue
+	}
+	msg := fmt.Sprintf("overall status is %s", overall)
+	status.SetCondition(interfaces.AvailableCondition, available, overall, msg, now)
+	status.SetCondition(interfaces.ProgressingCondition, progressing, overall, msg, now)
+	status.SetCondition(interfaces.FailedCondition, failed, overall, msg, now)
 }
 
 // getStatus check spinnaker status
@@ -108,11 +171,11 @@ func (s *statusChecker) getStatus(instance interfaces.SpinnakerService, pods []v
 			}
 			for _, cs := range p.Status.ContainerStatuses {
 				if cs.State.Terminated != nil {
-					s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "DeployInProgress", "Pod %s is in Phase: %s. Message: %s", p.Name, p.Status.Phase, cs.State.Terminated.Reason)
+					s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "DeployInProgress", "%s", withHint(fmt.Sprintf("Pod %s is in Phase: %s. Message: %s", p.Name, p.Status.Phase, cs.State.Terminated.Reason), cs))
 					return Updating, nil
 				}
 				if cs.State.Waiting != nil {
-					s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "DeployInProgress", "Pod %s is in Phase: %s. Message: %s", p.Name, p.Status.Phase, cs.State.Waiting.Reason)
+					s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "DeployInProgress", "%s", withHint(fmt.Sprintf("Pod %s is in Phase: %s. Message: %s", p.Name, p.Status.Phase, cs.State.Waiting.Reason), cs))
 					return Updating, nil
 				}
 				if !cs.Ready {
@@ -135,7 +198,7 @@ func (s *statusChecker) getStatus(instance interfaces.SpinnakerService, pods []v
 						s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "DeployInProgress", "Pod %s is in Phase: %s. Message: %s", p.Name, p.Status.Phase, cs.State.Waiting.Reason)
 						return Updating, nil
 					}
-					s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "DeployFailed", "Pod %s has not been able to reach a healthy state is in Phase: %s. Message: %s", p.Name, p.Status.Phase, cs.State.Waiting.Reason)
+					s.evtRecorder.Eventf(instance, v1.EventTypeWarning, "DeployFailed", "%s", withHint(fmt.Sprintf("Pod %s has not been able to reach a healthy state is in Phase: %s. Message: %s", p.Name, p.Status.Phase, cs.State.Waiting.Reason), cs))
 					return Failure, nil
 				}
 				if !cs.Ready {
@@ -93,7 +93,7 @@ func Test_statusChecker_checks(t *testing.T) {
 				mockedDeployments:  []appsv1.Deployment{{}},
 				mockedExceededTime: false,
 			},
-			wantErr: true,
+			wantErr: false,
 			status:  Updating,
 		},
 		{
@@ -165,9 +165,11 @@ func Test_statusChecker_checks(t *testing.T) {
 			}
 
 			// when
-			if err := s.checks(tt.args.instance); (err != nil) != tt.wantErr {
+			overall, err := s.checks(tt.args.instance)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("checks() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			assert.Equal(t, tt.status, overall)
 
 			// then
 			key := client.ObjectKey{Namespace: tt.args.instance.GetNamespace(), Name: tt.args.instance.GetName()}
@@ -22,3 +22,15 @@ func (v *spinnakerValidatingController) getSpinnakerService(req admission.Reques
 	}
 	return nil, nil
 }
+
+// getOldSpinnakerService decodes the object being replaced on updates
+func (v *spinnakerValidatingController) getOldSpinnakerService(req admission.Request) (interfaces.SpinnakerService, error) {
+	if !isSpinnakerRequest(req) || len(req.AdmissionRequest.OldObject.Raw) == 0 {
+		return nil, nil
+	}
+	svc := TypesFactory.NewService()
+	if err := v.decoder.DecodeRaw(req.AdmissionRequest.OldObject, svc); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/changefeed"
 	"github.com/armory/spinnaker-operator/pkg/controller/webhook"
 	"github.com/armory/spinnaker-operator/pkg/halyard"
 	"github.com/armory/spinnaker-operator/pkg/secrets"
@@ -14,6 +15,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"net/http"
+	"time"
+
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -92,10 +95,26 @@ func (v *spinnakerValidatingController) Handle(ctx context.Context, req admissio
 			}
 		}
 	}
+	v.recordChange(req, svc)
 	log.Info("SpinnakerService is valid", "metadata.name", svc.GetName())
 	return admission.ValidationResponse(true, "")
 }
 
+// recordChange appends the admitted change to the in-memory change feed,
+// attributing it to the Kubernetes user that submitted it
+func (v *spinnakerValidatingController) recordChange(req admission.Request, svc interfaces.SpinnakerService) {
+	var diff *interfaces.ConfigDiff
+	if old, err := v.getOldSpinnakerService(req); err == nil && old != nil {
+		diff = interfaces.CompareConfigs(old.GetSpinnakerConfig(), svc.GetSpinnakerConfig())
+	}
+	changefeed.Append(fmt.Sprintf("%s/%s", svc.GetNamespace(), svc.GetName()), changefeed.Entry{
+		Time:      time.Now(),
+		Actor:     req.AdmissionRequest.UserInfo.Username,
+		Operation: string(req.AdmissionRequest.Operation),
+		Diff:      diff,
+	})
+}
+
 // InjectClient injects the client.
 func (v *spinnakerValidatingController) InjectClient(c client.Client) error {
 	v.client = c
@@ -5,12 +5,33 @@ import (
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/generated"
 	"k8s.io/apimachinery/pkg/runtime"
+	"time"
 )
 
 type ManifestGenerator interface {
 	Generate(ctx context.Context, spinConfig *interfaces.SpinnakerConfig) (*generated.SpinnakerGeneratedConfig, error)
 }
 
+// GenerationProgress is a heartbeat emitted while manifests are being generated
+type GenerationProgress struct {
+	Phase   string
+	Elapsed time.Duration
+}
+
+// ProgressAwareGenerator is implemented by manifest generators that can report
+// liveness during long generations and cancel generations that hang
+type ProgressAwareGenerator interface {
+	ManifestGenerator
+	GenerateWithProgress(ctx context.Context, spinConfig *interfaces.SpinnakerConfig, progress func(GenerationProgress)) (*generated.SpinnakerGeneratedConfig, error)
+}
+
+// BomAwareGenerator is implemented by manifest generators that can resolve the
+// bill of materials of a Spinnaker version
+type BomAwareGenerator interface {
+	ManifestGenerator
+	GetBOM(ctx context.Context, version string) (map[string]interface{}, error)
+}
+
 type Deployer interface {
 	GetName() string
 	// Deploy performs an action on the SpinnakerService. When an error is returned processing stops
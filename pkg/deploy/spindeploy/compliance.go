@@ -0,0 +1,58 @@
+package spindeploy
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ChangeTicketAnnotation set on the SpinnakerService is propagated to every
+	// applied object so audit tooling can trace changes back to a ticket
+	ChangeTicketAnnotation = "spinnaker.io/change-ticket"
+	// ConfigHashAnnotation holds the hash of the config that produced the object
+	ConfigHashAnnotation = "spinnaker.io/config-hash"
+	// OperatorVersionAnnotation holds the operator version that applied the object
+	OperatorVersionAnnotation = "spinnaker.io/operator-version"
+	// GenerationAnnotation holds the SpinnakerService generation that produced the object
+	GenerationAnnotation = "spinnaker.io/generation"
+)
+
+// complianceStamper annotates every applied object with audit metadata:
+// change ticket, config hash, operator version and CR generation
+type complianceStamper struct {
+	annotations map[string]string
+}
+
+func newComplianceStamper(svc interfaces.SpinnakerService) (*complianceStamper, error) {
+	data, err := json.Marshal(svc.GetSpinnakerConfig())
+	if err != nil {
+		return nil, err
+	}
+	m := md5.Sum(data)
+	annotations := map[string]string{
+		ConfigHashAnnotation:      hex.EncodeToString(m[:]),
+		OperatorVersionAnnotation: version.GetOperatorVersion(),
+		GenerationAnnotation:      strconv.FormatInt(svc.GetGeneration(), 10),
+	}
+	if ticket := svc.GetAnnotations()[ChangeTicketAnnotation]; ticket != "" {
+		annotations[ChangeTicketAnnotation] = ticket
+	}
+	return &complianceStamper{annotations: annotations}, nil
+}
+
+func (c *complianceStamper) stamp(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range c.annotations {
+		annotations[k] = v
+	}
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,55 @@
+package spindeploy
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComplianceStamp(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(dryRunManifest, t)
+	spinsvc.SetAnnotations(map[string]string{ChangeTicketAnnotation: "CHG-1234"})
+	stamper, err := newComplianceStamper(spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", Namespace: "ns1"}}
+	stamper.stamp(dep)
+
+	assert.Equal(t, "CHG-1234", dep.Annotations[ChangeTicketAnnotation])
+	assert.NotEmpty(t, dep.Annotations[ConfigHashAnnotation])
+	assert.NotEmpty(t, dep.Annotations[OperatorVersionAnnotation])
+	assert.Equal(t, "0", dep.Annotations[GenerationAnnotation])
+}
+
+func TestComplianceStamp_noTicket(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(dryRunManifest, t)
+	stamper, err := newComplianceStamper(spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", Namespace: "ns1"}}
+	stamper.stamp(dep)
+
+	_, ok := dep.Annotations[ChangeTicketAnnotation]
+	assert.False(t, ok)
+	assert.NotEmpty(t, dep.Annotations[ConfigHashAnnotation])
+}
+
+func TestComplianceStamp_hashTracksConfig(t *testing.T) {
+	a := test.ManifestToSpinService(dryRunManifest, t)
+	b := test.ManifestToSpinService(dryRunManifest, t)
+	b.GetSpinnakerConfig().Profiles = map[string]interfaces.FreeForm{"gate": {"default": true}}
+
+	sa, err := newComplianceStamper(a)
+	assert.Nil(t, err)
+	sb, err := newComplianceStamper(b)
+	assert.Nil(t, err)
+	assert.NotEqual(t, sa.annotations[ConfigHashAnnotation], sb.annotations[ConfigHashAnnotation])
+}
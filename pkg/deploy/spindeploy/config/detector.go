@@ -17,6 +17,10 @@ import (
 const SpinnakerConfigHashKey = "config"
 const KustomizeHashKey = "kustomize"
 
+// SpecHashKey covers the whole spec, so changes to transformer inputs
+// (expose, tuning, redis...) trigger a redeploy like halconfig changes do
+const SpecHashKey = "spec"
+
 type changeDetector struct {
 	log         logr.Logger
 	evtRecorder record.EventRecorder
@@ -36,7 +40,30 @@ func (ch *changeDetector) IsSpinnakerUpToDate(ctx context.Context, spinSvc inter
 	}
 
 	kUpd, err := ch.isUpToDate(spinSvc.GetKustomization(), KustomizeHashKey, spinSvc)
-	return upd && kUpd, err
+	if err != nil {
+		return false, err
+	}
+
+	spec, err := specAsMap(spinSvc)
+	if err != nil {
+		return false, err
+	}
+	sUpd, err := ch.isUpToDate(spec, SpecHashKey, spinSvc)
+	return upd && kUpd && sUpd, err
+}
+
+// specAsMap extracts the spec of the service as a plain map, the interface
+// does not expose the typed spec across versions
+func specAsMap(spinSvc interfaces.SpinnakerService) (interface{}, error) {
+	data, err := json.Marshal(spinSvc)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m["spec"], nil
 }
 
 func (ch *changeDetector) isUpToDate(config interface{}, hashKey string, spinSvc interfaces.SpinnakerService) (bool, error) {
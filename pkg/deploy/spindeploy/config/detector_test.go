@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetectortest"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var configManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  orca:
+    queueThreads: 20
+`
+
+func TestIsSpinnakerUpToDate_hashesRecorded(t *testing.T) {
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t)
+	spinSvc := test.ManifestToSpinService(configManifest, t)
+
+	// First pass records the hashes and reports a needed deployment
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+
+	// Nothing changed, the expensive generate path is skipped
+	upToDate, err = ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+	assert.True(t, upToDate)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, spinSvc.GetStatus().LastDeployed[SpinnakerConfigHashKey].Hash)
+	assert.NotEmpty(t, spinSvc.GetStatus().LastDeployed[SpecHashKey].Hash)
+}
+
+func TestIsSpinnakerUpToDate_transformerInputChanged(t *testing.T) {
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t)
+	spinSvc := test.ManifestToSpinService(configManifest, t)
+
+	_, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+	assert.Nil(t, err)
+
+	// A spec change outside spinnakerConfig must trigger a redeploy
+	spinSvc.GetOrcaConfig().QueueThreads = 40
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
+
+func TestIsSpinnakerUpToDate_halConfigChanged(t *testing.T) {
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t)
+	spinSvc := test.ManifestToSpinService(configManifest, t)
+
+	_, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+	assert.Nil(t, err)
+
+	assert.Nil(t, spinSvc.GetSpinnakerConfig().SetHalConfigProp("version", "1.17.2"))
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
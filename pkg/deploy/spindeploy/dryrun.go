@@ -0,0 +1,76 @@
+package spindeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunValidate applies the full generated set with a server-side dry run
+// before anything mutates the cluster, so admission, webhook and validation
+// rejections are all reported at once instead of failing midway through a
+// partial apply
+func (d *Deployer) dryRunValidate(ctx context.Context, gen *generated.SpinnakerGeneratedConfig, logger logr.Logger) error {
+	var failures []error
+	for k := range gen.Config {
+		s := gen.Config[k]
+		if s.Deployment != nil {
+			if err := d.dryRunObject(ctx, s.Deployment); err != nil {
+				failures = append(failures, fmt.Errorf("%s deployment: %s", k, err.Error()))
+			}
+		}
+		if s.Service != nil {
+			if err := d.dryRunObject(ctx, s.Service); err != nil {
+				failures = append(failures, fmt.Errorf("%s service: %s", k, err.Error()))
+			}
+		}
+		for i := range s.Resources {
+			name := fmt.Sprintf("#%d", i)
+			if o, ok := s.Resources[i].(metav1.Object); ok {
+				name = o.GetName()
+			}
+			if err := d.dryRunObject(ctx, s.Resources[i]); err != nil {
+				failures = append(failures, fmt.Errorf("%s resource %s: %s", k, name, err.Error()))
+			}
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("%d manifest(s) failed server-side dry run validation:", len(failures))
+	for _, e := range failures {
+		msg = fmt.Sprintf("%s\n  %s", msg, e.Error())
+	}
+	logger.Info(msg)
+	return errors.New(msg)
+}
+
+// dryRunObject submits the object as a dry run create or update, exercising
+// the same admission chain as the real apply without persisting anything
+func (d *Deployer) dryRunObject(ctx context.Context, obj runtime.Object) error {
+	modified := obj.DeepCopyObject()
+	m, ok := modified.(metav1.Object)
+	if !ok {
+		return nil
+	}
+	existing := obj.DeepCopyObject()
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: m.GetNamespace(), Name: m.GetName()}, existing)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return d.client.Create(ctx, modified, client.DryRunAll)
+		}
+		return err
+	}
+	if e, ok := existing.(metav1.Object); ok {
+		m.SetResourceVersion(e.GetResourceVersion())
+	}
+	return d.client.Update(ctx, modified, client.DryRunAll)
+}
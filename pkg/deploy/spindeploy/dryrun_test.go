@@ -0,0 +1,66 @@
+package spindeploy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestDryRunValidate_validSet(t *testing.T) {
+	d := &Deployer{
+		client: test.FakeClient(t),
+		log:    log.Log.WithName("spinnakerservice"),
+	}
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "transformer/testdata/input_deployment.yml", t)
+	cfg := gen.Config["gate"]
+	cfg.Resources = append(cfg.Resources, &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "spin-gate-files", Namespace: "german"},
+	})
+	gen.Config["gate"] = cfg
+
+	assert.Nil(t, d.dryRunValidate(context.TODO(), gen, d.log))
+}
+
+func TestDryRunValidate_reportsAllFailures(t *testing.T) {
+	d := &Deployer{
+		client: test.FakeClient(t),
+		log:    log.Log.WithName("spinnakerservice"),
+	}
+	// The admission chain rejects both manifests: both failures must show up
+	// in a single error instead of stopping at the first one
+	d.client = &rejectingClient{Client: d.client}
+	gen := &generated.SpinnakerGeneratedConfig{
+		Config: map[string]generated.ServiceConfig{
+			"gate": {Resources: []runtime.Object{
+				&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "first"}},
+				&v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "second"}},
+			}},
+		},
+	}
+
+	err := d.dryRunValidate(context.TODO(), gen, d.log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "2 manifest(s) failed server-side dry run validation")
+		assert.Contains(t, err.Error(), "first")
+		assert.Contains(t, err.Error(), "second")
+	}
+}
+
+// rejectingClient simulates an admission webhook denying every dry run create
+type rejectingClient struct {
+	client.Client
+}
+
+func (c *rejectingClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	return errors.New(`admission webhook "validate.example.com" denied the request`)
+}
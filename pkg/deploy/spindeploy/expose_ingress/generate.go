@@ -0,0 +1,131 @@
+package expose_ingress
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	v1beta12 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const defaultGatePath = "/api"
+
+// generatesIngress returns true when the operator should create the Ingress
+// itself instead of discovering an existing one
+func generatesIngress(t *ingressTransformer) bool {
+	return t.svc.GetExposeConfig().Ingress.Host != ""
+}
+
+// transformConfigForGeneratedIngress derives the override base URLs from the
+// configured host instead of looking them up in the cluster
+func (t *ingressTransformer) transformConfigForGeneratedIngress(ctx context.Context) error {
+	cfg := t.svc.GetExposeConfig().Ingress
+	scheme := "http"
+	if cfg.TlsSecretName != "" {
+		scheme = "https"
+	}
+	gatePath := cfg.GatePath
+	if gatePath == "" {
+		gatePath = defaultGatePath
+	}
+	st := t.svc.GetStatus()
+
+	gateUrl, err := t.getUrlFromConfig(ctx, util.GateOverrideBaseUrlProp)
+	if err != nil {
+		return fmt.Errorf("error checking ingress URL Gate prop: %v", err)
+	}
+	// We only act when the URL has not been explicitly set by the user
+	if gateUrl == nil {
+		gateUrl = &url.URL{Scheme: scheme, Host: cfg.Host, Path: gatePath}
+		t.log.Info(fmt.Sprintf("setting gate overrideBaseUrl to %s", gateUrl.String()))
+		if err = t.svc.GetSpinnakerConfig().SetHalConfigProp(util.GateOverrideBaseUrlProp, gateUrl.String()); err != nil {
+			return err
+		}
+		if gatePath != "" && gatePath != "/" {
+			t.gatePathOverride = gatePath
+			if err := t.setGatePathInConfig(gatePath); err != nil {
+				return err
+			}
+		}
+		st.APIUrl = gateUrl.String()
+	}
+
+	deckUrl, err := t.getUrlFromConfig(ctx, util.DeckOverrideBaseUrlProp)
+	if err != nil {
+		return fmt.Errorf("error checking ingress URL Deck prop: %v", err)
+	}
+	if deckUrl == nil {
+		deckUrl = &url.URL{Scheme: scheme, Host: cfg.Host, Path: "/"}
+		t.log.Info(fmt.Sprintf("setting deck overrideBaseUrl to %s", deckUrl.String()))
+		if err = t.svc.GetSpinnakerConfig().SetHalConfigProp(util.DeckOverrideBaseUrlProp, deckUrl.String()); err != nil {
+			return err
+		}
+		st.UIUrl = deckUrl.String()
+	}
+	return nil
+}
+
+// addGeneratedIngress appends the Ingress routing the host to deck and gate
+func (t *ingressTransformer) addGeneratedIngress(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) {
+	cfg := t.svc.GetExposeConfig().Ingress
+	gatePath := cfg.GatePath
+	if gatePath == "" {
+		gatePath = defaultGatePath
+	}
+	ing := &v1beta12.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "networking.k8s.io/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-spinnaker", t.svc.GetName()),
+			Namespace:   t.svc.GetNamespace(),
+			Annotations: cfg.Annotations,
+		},
+		Spec: v1beta12.IngressSpec{
+			Rules: []v1beta12.IngressRule{
+				{
+					Host: cfg.Host,
+					IngressRuleValue: v1beta12.IngressRuleValue{
+						HTTP: &v1beta12.HTTPIngressRuleValue{
+							Paths: []v1beta12.HTTPIngressPath{
+								{
+									Path: gatePath,
+									Backend: v1beta12.IngressBackend{
+										ServiceName: util.GateServiceName,
+										ServicePort: intstr.FromInt(int(guessGatePort(ctx, t.svc))),
+									},
+								},
+								{
+									Path: "/",
+									Backend: v1beta12.IngressBackend{
+										ServiceName: util.DeckServiceName,
+										ServicePort: intstr.FromInt(int(util.DeckDefaultPort)),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if cfg.TlsSecretName != "" {
+		ing.Spec.TLS = []v1beta12.IngressTLS{
+			{Hosts: []string{cfg.Host}, SecretName: cfg.TlsSecretName},
+		}
+	}
+	// Attach to deck so it gets an owner reference to the deck deployment
+	if gen.Config == nil {
+		gen.Config = make(map[string]generated.ServiceConfig)
+	}
+	config := gen.Config["deck"]
+	config.Resources = append(config.Resources, ing)
+	gen.Config["deck"] = config
+	t.log.Info(fmt.Sprintf("generated ingress for host %s", strings.ToLower(cfg.Host)))
+}
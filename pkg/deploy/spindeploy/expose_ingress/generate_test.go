@@ -0,0 +1,110 @@
+package expose_ingress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformertest"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/stretchr/testify/assert"
+	v1beta12 "k8s.io/api/networking/v1beta1"
+)
+
+var generatedIngressManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.15.1
+  expose:
+    type: ingress
+    ingress:
+      host: spinnaker.acme.com
+      tlsSecretName: spinnaker-tls
+      annotations:
+        kubernetes.io/ingress.class: nginx
+`
+
+func TestGenerateIngress_urlsFromConfiguredHost(t *testing.T) {
+	tr, spinsvc := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, generatedIngressManifest, t)
+
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	url, err := spinsvc.GetSpinnakerConfig().GetHalConfigPropString(context.TODO(), "security.apiSecurity.overrideBaseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://spinnaker.acme.com/api", url)
+	assert.Equal(t, "https://spinnaker.acme.com/api", spinsvc.GetStatus().APIUrl)
+
+	url, err = spinsvc.GetSpinnakerConfig().GetHalConfigPropString(context.TODO(), "security.uiSecurity.overrideBaseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://spinnaker.acme.com/", url)
+	assert.Equal(t, "https://spinnaker.acme.com/", spinsvc.GetStatus().UIUrl)
+}
+
+func TestGenerateIngress_manifestAdded(t *testing.T) {
+	tr, _ := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, generatedIngressManifest, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	resources := gen.Config["deck"].Resources
+	if !assert.Equal(t, 1, len(resources)) {
+		return
+	}
+	ing, ok := resources[0].(*v1beta12.Ingress)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "spinnaker-spinnaker", ing.Name)
+	assert.Equal(t, "nginx", ing.Annotations["kubernetes.io/ingress.class"])
+	if assert.Equal(t, 1, len(ing.Spec.Rules)) {
+		assert.Equal(t, "spinnaker.acme.com", ing.Spec.Rules[0].Host)
+		paths := ing.Spec.Rules[0].HTTP.Paths
+		if assert.Equal(t, 2, len(paths)) {
+			assert.Equal(t, "/api", paths[0].Path)
+			assert.Equal(t, "spin-gate", paths[0].Backend.ServiceName)
+			assert.Equal(t, "/", paths[1].Path)
+			assert.Equal(t, "spin-deck", paths[1].Backend.ServiceName)
+		}
+	}
+	if assert.Equal(t, 1, len(ing.Spec.TLS)) {
+		assert.Equal(t, "spinnaker-tls", ing.Spec.TLS[0].SecretName)
+	}
+}
+
+func TestGenerateIngress_userUrlWins(t *testing.T) {
+	m := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.15.1
+      security:
+        apiSecurity:
+          overrideBaseUrl: https://api.custom.com
+  expose:
+    type: ingress
+    ingress:
+      host: spinnaker.acme.com
+`
+	tr, spinsvc := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, m, t)
+
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	url, err := spinsvc.GetSpinnakerConfig().GetHalConfigPropString(context.TODO(), "security.apiSecurity.overrideBaseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.custom.com", url)
+	// Deck URL still derived from the configured host, without TLS it is http
+	url, err = spinsvc.GetSpinnakerConfig().GetHalConfigPropString(context.TODO(), "security.uiSecurity.overrideBaseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://spinnaker.acme.com/", url)
+}
@@ -44,6 +44,9 @@ func (t *ingressTransformer) TransformManifests(ctx context.Context, gen *genera
 	if !applies(t.svc) {
 		return nil
 	}
+	if generatesIngress(t) {
+		t.addGeneratedIngress(ctx, gen)
+	}
 	// If we need to override gate's path
 	if t.gatePathOverride != "" {
 		if err := t.setGateServerPathInDeployment(
@@ -61,6 +64,10 @@ func (t *ingressTransformer) TransformConfig(ctx context.Context) error {
 	if !applies(t.svc) {
 		return nil
 	}
+	// With a configured host the URLs are known upfront, no cluster lookup needed
+	if generatesIngress(t) {
+		return t.transformConfigForGeneratedIngress(ctx)
+	}
 	st := t.svc.GetStatus()
 	gateUrl, err := t.getUrlFromConfig(ctx, util.GateOverrideBaseUrlProp)
 	if err != nil {
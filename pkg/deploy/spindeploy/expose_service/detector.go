@@ -62,7 +62,9 @@ func (ch *changeDetector) AlwaysRun() bool {
 func (ch *changeDetector) isExposeServiceUpToDate(ctx context.Context, spinSvc interfaces.SpinnakerService, serviceName string, hcSSLEnabled bool) (bool, error) {
 	rLogger := ch.log.WithValues("Service", spinSvc.GetName())
 	ns := spinSvc.GetNamespace()
-	svc, err := util.GetService(serviceName, ns, ch.client)
+	// The actual object name differs from the default when spec.naming is set
+	objectName := util.GeneratedObjectName(spinSvc, util.BaseServiceName(spinSvc, serviceName))
+	svc, err := util.GetService(objectName, ns, ch.client)
 	if err != nil {
 		return false, err
 	}
@@ -82,7 +84,7 @@ func (ch *changeDetector) isExposeServiceUpToDate(ctx context.Context, spinSvc i
 	}
 
 	// annotations are different, redeploy
-	simpleServiceName := serviceName[len("spin-"):]
+	simpleServiceName := util.BaseServiceName(spinSvc, serviceName)
 	exp := spinSvc.GetExposeConfig()
 	expectedAnnotations := exp.GetAggregatedAnnotations(simpleServiceName)
 	if !ch.areAnnotationsEqual(svc.Annotations, expectedAnnotations) {
@@ -94,11 +96,11 @@ func (ch *changeDetector) isExposeServiceUpToDate(ctx context.Context, spinSvc i
 	// status url is available but not set yet, redeploy
 	st := spinSvc.GetStatus()
 	statusUrl := st.APIUrl
-	if serviceName == "spin-deck" {
+	if simpleServiceName == "deck" {
 		statusUrl = st.UIUrl
 	}
 	if statusUrl == "" {
-		lbUrl, err := util.FindLoadBalancerUrl(serviceName, ns, ch.client, hcSSLEnabled)
+		lbUrl, err := util.FindLoadBalancerUrl(objectName, ns, ch.client, hcSSLEnabled)
 		if err != nil {
 			return false, err
 		}
@@ -113,7 +115,7 @@ func (ch *changeDetector) isExposeServiceUpToDate(ctx context.Context, spinSvc i
 
 func (ch *changeDetector) exposeServiceTypeUpToDate(serviceName string, spinSvc interfaces.SpinnakerService, svc *corev1.Service) (bool, error) {
 	rLogger := ch.log.WithValues("Service", spinSvc.GetName())
-	formattedServiceName := serviceName[len("spin-"):]
+	formattedServiceName := util.BaseServiceName(spinSvc, serviceName)
 	exp := spinSvc.GetExposeConfig()
 	if c, ok := exp.Service.Overrides[formattedServiceName]; ok && c.Type != "" {
 		if string(svc.Spec.Type) != c.Type {
@@ -137,7 +139,7 @@ func (ch *changeDetector) exposePortUpToDate(ctx context.Context, serviceName st
 		rLogger.Info(fmt.Sprintf("No exposed port for %s found", serviceName))
 		return false, nil
 	}
-	svcNameWithoutPrefix := serviceName[len("spin-"):]
+	svcNameWithoutPrefix := util.BaseServiceName(spinSvc, serviceName)
 	portName := fmt.Sprintf("%s-tcp", svcNameWithoutPrefix)
 	publicPort, _ := ch.getSvcPorts(portName, svc)
 	desiredPort := util.GetDesiredExposePort(ctx, svcNameWithoutPrefix, int32(80), spinSvc)
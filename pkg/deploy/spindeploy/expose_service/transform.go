@@ -120,7 +120,7 @@ func (t *exposeTransformer) generateOverrideUrl(ctx context.Context, serviceName
 	if err != nil {
 		return "", err
 	}
-	desiredPort := util.GetDesiredExposePort(ctx, serviceName[len("spin-"):], int32(80), t.svc)
+	desiredPort := util.GetDesiredExposePort(ctx, util.BaseServiceName(t.svc, serviceName), int32(80), t.svc)
 	return util.BuildUrl(parsedLbUrl.Scheme, parsedLbUrl.Hostname(), desiredPort), nil
 }
 
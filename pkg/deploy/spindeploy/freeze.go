@@ -0,0 +1,169 @@
+package spindeploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	v1 "k8s.io/api/core/v1"
+)
+
+// StatusPendingFreeze is reported on the status while a freeze window holds back changes
+const StatusPendingFreeze = "PendingFreeze"
+
+// freezeProvider answers whether deployments are currently frozen
+type freezeProvider interface {
+	isFrozen(ctx context.Context, now time.Time) (bool, string, error)
+}
+
+// freezeProvidersFor returns the providers configured on the service,
+// static windows first, the calendar endpoint last
+func freezeProvidersFor(cfg *interfaces.FreezeConfig) []freezeProvider {
+	var providers []freezeProvider
+	if len(cfg.Windows) > 0 {
+		providers = append(providers, &windowFreezeProvider{cfg: cfg})
+	}
+	if cfg.CalendarUrl != "" {
+		providers = append(providers, &calendarFreezeProvider{url: cfg.CalendarUrl})
+	}
+	return providers
+}
+
+// checkFreeze consults every configured provider, the first active freeze wins
+func (d *Deployer) checkFreeze(ctx context.Context, svc interfaces.SpinnakerService) (bool, string, error) {
+	for _, p := range freezeProvidersFor(svc.GetFreezeConfig()) {
+		frozen, reason, err := p.isFrozen(ctx, time.Now())
+		if err != nil {
+			return false, "", err
+		}
+		if frozen {
+			return true, reason, nil
+		}
+	}
+	return false, "", nil
+}
+
+// reportFreeze queues the validated changes on the status instead of applying them
+func (d *Deployer) reportFreeze(ctx context.Context, svc interfaces.SpinnakerService, reason string) error {
+	d.log.Info(fmt.Sprintf("deployment frozen, not applying: %s", reason))
+	if d.evtRecorder != nil {
+		d.evtRecorder.Eventf(svc, v1.EventTypeNormal, "DeployFrozen", "Deployment frozen, %s", reason)
+	}
+	svc.GetStatus().Status = StatusPendingFreeze
+	return d.client.Status().Update(ctx, svc)
+}
+
+// windowFreezeProvider evaluates the static weekly windows of the config
+type windowFreezeProvider struct {
+	cfg *interfaces.FreezeConfig
+}
+
+func (p *windowFreezeProvider) isFrozen(ctx context.Context, now time.Time) (bool, string, error) {
+	loc := time.UTC
+	if p.cfg.TimeZone != "" {
+		l, err := time.LoadLocation(p.cfg.TimeZone)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid freeze time zone %q: %w", p.cfg.TimeZone, err)
+		}
+		loc = l
+	}
+	now = now.In(loc)
+	for i := range p.cfg.Windows {
+		w := &p.cfg.Windows[i]
+		in, err := windowContains(w, now)
+		if err != nil {
+			return false, "", err
+		}
+		if in {
+			reason := w.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("freeze window %s-%s active", w.Start, w.End)
+			}
+			return true, reason, nil
+		}
+	}
+	return false, "", nil
+}
+
+func windowContains(w *interfaces.FreezeWindow, now time.Time) (bool, error) {
+	start, err := minutesOfDay(w.Start)
+	if err != nil {
+		return false, fmt.Errorf("invalid freeze window start %q: %w", w.Start, err)
+	}
+	end, err := minutesOfDay(w.End)
+	if err != nil {
+		return false, fmt.Errorf("invalid freeze window end %q: %w", w.End, err)
+	}
+	cur := now.Hour()*60 + now.Minute()
+	if start <= end {
+		return matchesDay(w.Days, now.Weekday()) && cur >= start && cur < end, nil
+	}
+	// The window spans midnight: the part after Start belongs to the listed
+	// day, the part before End to the following day
+	if cur >= start {
+		return matchesDay(w.Days, now.Weekday()), nil
+	}
+	if cur < end {
+		return matchesDay(w.Days, now.AddDate(0, 0, -1).Weekday()), nil
+	}
+	return false, nil
+}
+
+func minutesOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func matchesDay(days []string, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if strings.EqualFold(d, day.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// calendarFreezeProvider asks an external HTTP endpoint whether changes are frozen
+type calendarFreezeProvider struct {
+	url string
+}
+
+type calendarFreezeResponse struct {
+	Frozen bool   `json:"frozen"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (p *calendarFreezeProvider) isFrozen(ctx context.Context, now time.Time) (bool, string, error) {
+	req, err := http.NewRequest("GET", p.url, nil)
+	if err != nil {
+		return false, "", err
+	}
+	req = req.WithContext(ctx)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, "", fmt.Errorf("error reaching freeze calendar %s: %w", p.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, "", fmt.Errorf("freeze calendar %s answered status %d", p.url, resp.StatusCode)
+	}
+	cal := &calendarFreezeResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(cal); err != nil {
+		return false, "", fmt.Errorf("error parsing freeze calendar %s response: %w", p.url, err)
+	}
+	reason := cal.Reason
+	if cal.Frozen && reason == "" {
+		reason = "freeze calendar reports an active freeze"
+	}
+	return cal.Frozen, reason, nil
+}
@@ -0,0 +1,100 @@
+package spindeploy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowFreeze_active(t *testing.T) {
+	p := &windowFreezeProvider{cfg: &interfaces.FreezeConfig{
+		Windows: []interfaces.FreezeWindow{
+			{Days: []string{"Friday"}, Start: "17:00", End: "23:00", Reason: "weekend freeze"},
+		},
+	}}
+	// Friday 2019-11-01 18:30 UTC
+	now := time.Date(2019, 11, 1, 18, 30, 0, 0, time.UTC)
+
+	frozen, reason, err := p.isFrozen(context.TODO(), now)
+	assert.Nil(t, err)
+	assert.True(t, frozen)
+	assert.Equal(t, "weekend freeze", reason)
+}
+
+func TestWindowFreeze_outsideWindow(t *testing.T) {
+	p := &windowFreezeProvider{cfg: &interfaces.FreezeConfig{
+		Windows: []interfaces.FreezeWindow{
+			{Days: []string{"Friday"}, Start: "17:00", End: "23:00"},
+		},
+	}}
+	// Thursday, same time of day
+	now := time.Date(2019, 10, 31, 18, 30, 0, 0, time.UTC)
+
+	frozen, _, err := p.isFrozen(context.TODO(), now)
+	assert.Nil(t, err)
+	assert.False(t, frozen)
+}
+
+func TestWindowFreeze_spansMidnight(t *testing.T) {
+	p := &windowFreezeProvider{cfg: &interfaces.FreezeConfig{
+		Windows: []interfaces.FreezeWindow{
+			{Days: []string{"Friday"}, Start: "22:00", End: "06:00"},
+		},
+	}}
+	// Saturday 02:00 still belongs to the Friday window
+	now := time.Date(2019, 11, 2, 2, 0, 0, 0, time.UTC)
+
+	frozen, _, err := p.isFrozen(context.TODO(), now)
+	assert.Nil(t, err)
+	assert.True(t, frozen)
+}
+
+func TestWindowFreeze_invalidStart(t *testing.T) {
+	p := &windowFreezeProvider{cfg: &interfaces.FreezeConfig{
+		Windows: []interfaces.FreezeWindow{{Start: "5pm", End: "23:00"}},
+	}}
+	_, _, err := p.isFrozen(context.TODO(), time.Now())
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid freeze window start")
+	}
+}
+
+func TestCalendarFreeze(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"frozen": true, "reason": "CAB-142 change freeze"}`))
+	}))
+	defer srv.Close()
+	p := &calendarFreezeProvider{url: srv.URL}
+
+	frozen, reason, err := p.isFrozen(context.TODO(), time.Now())
+	assert.Nil(t, err)
+	assert.True(t, frozen)
+	assert.Equal(t, "CAB-142 change freeze", reason)
+}
+
+func TestCalendarFreeze_errorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	p := &calendarFreezeProvider{url: srv.URL}
+
+	_, _, err := p.isFrozen(context.TODO(), time.Now())
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "answered status 500")
+	}
+}
+
+func TestFreezeProvidersFor(t *testing.T) {
+	assert.Equal(t, 0, len(freezeProvidersFor(&interfaces.FreezeConfig{})))
+	cfg := &interfaces.FreezeConfig{
+		Windows:     []interfaces.FreezeWindow{{Start: "17:00", End: "23:00"}},
+		CalendarUrl: "http://change-calendar/freeze",
+	}
+	assert.Equal(t, 2, len(freezeProvidersFor(cfg)))
+}
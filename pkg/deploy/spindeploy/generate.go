@@ -0,0 +1,27 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+)
+
+// generateManifests runs the manifest generator, surfacing heartbeats as events
+// when the generator supports progress so long generations stay observable
+func (d *Deployer) generateManifests(ctx context.Context, svc interfaces.SpinnakerService, logger logr.Logger) (*generated.SpinnakerGeneratedConfig, error) {
+	pg, ok := d.m.(deploy.ProgressAwareGenerator)
+	if !ok {
+		return d.m.Generate(ctx, svc.GetSpinnakerConfig())
+	}
+	return pg.GenerateWithProgress(ctx, svc.GetSpinnakerConfig(), func(p deploy.GenerationProgress) {
+		logger.Info(fmt.Sprintf("manifest generation in progress (%s elapsed)", p.Elapsed.Round(0)))
+		if d.evtRecorder != nil {
+			d.evtRecorder.Eventf(svc, v1.EventTypeNormal, "GenerationProgress", "manifest generation %s after %s", p.Phase, p.Elapsed.Round(0))
+		}
+	})
+}
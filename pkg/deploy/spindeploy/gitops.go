@@ -0,0 +1,155 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// defaultGitOpsBranch receives the exported manifests when no branch is configured
+	defaultGitOpsBranch = "master"
+	// defaultGitOpsPath is the directory the manifests are written under
+	defaultGitOpsPath = "spinnaker"
+
+	gitOpsAuthorName  = "Spinnaker Operator"
+	gitOpsAuthorEmail = "spinnaker-operator@localhost"
+)
+
+// exportToGit commits the generated manifests to the configured repository
+// instead of applying them, one directory per service, so a GitOps tool like
+// ArgoCD or Flux owns the actual rollout
+func (d *Deployer) exportToGit(ctx context.Context, scheme *runtime.Scheme, gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService, logger logr.Logger) error {
+	c := svc.GetGitOpsConfig()
+	if c.Repo == "" {
+		return fmt.Errorf("gitops is enabled but no repository is configured, set spec.gitops.repo")
+	}
+	branch := c.Branch
+	if branch == "" {
+		branch = defaultGitOpsBranch
+	}
+	workdir, err := ioutil.TempDir("", "spinnaker-gitops")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(workdir) }()
+
+	if err := runGit(ctx, "", "clone", "--depth", "1", "--branch", branch, c.Repo, workdir); err != nil {
+		// The branch may not exist yet, clone the default branch and create it
+		if err := runGit(ctx, "", "clone", "--depth", "1", c.Repo, workdir); err != nil {
+			return err
+		}
+		if err := runGit(ctx, workdir, "checkout", "-b", branch); err != nil {
+			return err
+		}
+	}
+
+	root := filepath.Join(workdir, gitOpsPath(c))
+	if err := writeManifestTree(scheme, gen, root); err != nil {
+		return err
+	}
+	if err := runGit(ctx, workdir, "add", "-A"); err != nil {
+		return err
+	}
+	// Nothing to commit when the rendered manifests did not change
+	if err := runGit(ctx, workdir, "diff", "--cached", "--quiet"); err == nil {
+		logger.Info("gitops export: manifests unchanged, nothing to commit")
+		return nil
+	}
+	msg := fmt.Sprintf("Update Spinnaker manifests for %s/%s", svc.GetNamespace(), svc.GetName())
+	if err := runGit(ctx, workdir,
+		"-c", fmt.Sprintf("user.name=%s", gitOpsAuthorName),
+		"-c", fmt.Sprintf("user.email=%s", gitOpsAuthorEmail),
+		"commit", "-m", msg); err != nil {
+		return err
+	}
+	if err := runGit(ctx, workdir, "push", "origin", branch); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("gitops export: pushed manifests to %s on branch %s", c.Repo, branch))
+	return nil
+}
+
+// gitOpsPath is the directory inside the repository the operator owns. Paths
+// escaping the repository fall back to the default
+func gitOpsPath(c *interfaces.GitOpsConfig) string {
+	p := filepath.Clean(c.Path)
+	if p == "" || p == "." || p == string(filepath.Separator) || strings.HasPrefix(p, "..") {
+		return defaultGitOpsPath
+	}
+	return strings.TrimPrefix(p, string(filepath.Separator))
+}
+
+// writeManifestTree writes one directory per service with its deployment,
+// service and attached resources. The directory is replaced wholesale so
+// resources of disabled services disappear from the repository
+func writeManifestTree(scheme *runtime.Scheme, gen *generated.SpinnakerGeneratedConfig, root string) error {
+	if err := os.RemoveAll(root); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(gen.Config))
+	for n := range gen.Config {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		cfg := gen.Config[n]
+		dir := filepath.Join(root, n)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if cfg.Deployment != nil {
+			if err := writeManifest(scheme, cfg.Deployment, filepath.Join(dir, "deployment.yml")); err != nil {
+				return err
+			}
+		}
+		if cfg.Service != nil {
+			if err := writeManifest(scheme, cfg.Service, filepath.Join(dir, "service.yml")); err != nil {
+				return err
+			}
+		}
+		for i, o := range cfg.Resources {
+			if err := ensureGVK(scheme, o); err != nil {
+				continue
+			}
+			kind := strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind)
+			if err := writeManifest(scheme, o, filepath.Join(dir, fmt.Sprintf("resource-%02d-%s.yml", i, kind))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeManifest(scheme *runtime.Scheme, o runtime.Object, path string) error {
+	if err := ensureGVK(scheme, o); err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// runGit executes git in dir, surfacing the command output on failure
+func runGit(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s: %s: %s", strings.Join(args, " "), err.Error(), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
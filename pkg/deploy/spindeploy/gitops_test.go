@@ -0,0 +1,110 @@
+package spindeploy
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func gitOpsSpinSvc(t *testing.T, repo string) interfaces.SpinnakerService {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  gitops:
+    enabled: true
+    repo: ` + repo + `
+    branch: env-test
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	return test.ManifestToSpinService(s, t)
+}
+
+func gitOpsGen() *generated.SpinnakerGeneratedConfig {
+	dep := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", Namespace: "ns1"},
+	}
+	svc := &v1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", Namespace: "ns1"},
+	}
+	cm := &v1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "spin-gate-files", Namespace: "ns1"},
+		Data:       map[string]string{"gate.yml": "server:\n  port: 8084\n"},
+	}
+	return &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{
+		"gate": {Deployment: dep, Service: svc, Resources: []runtime.Object{cm}},
+	}}
+}
+
+func initBareRepo(t *testing.T) string {
+	repo := filepath.Join(t.TempDir(), "manifests.git")
+	out, err := exec.Command("git", "init", "--bare", repo).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git init --bare: %s: %s", err, out)
+	}
+	return repo
+}
+
+func TestExportToGit(t *testing.T) {
+	repo := initBareRepo(t)
+	spinsvc := gitOpsSpinSvc(t, repo)
+	d := &Deployer{log: log.Log.WithName("spinnakerservice")}
+
+	err := d.exportToGit(context.TODO(), runtime.NewScheme(), gitOpsGen(), spinsvc, d.log)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Clone the branch back and verify the manifest tree
+	checkout := filepath.Join(t.TempDir(), "checkout")
+	out, err := exec.Command("git", "clone", "--branch", "env-test", repo, checkout).CombinedOutput()
+	if !assert.Nil(t, err, string(out)) {
+		return
+	}
+	assert.FileExists(t, filepath.Join(checkout, "spinnaker", "gate", "deployment.yml"))
+	assert.FileExists(t, filepath.Join(checkout, "spinnaker", "gate", "service.yml"))
+	assert.FileExists(t, filepath.Join(checkout, "spinnaker", "gate", "resource-00-configmap.yml"))
+}
+
+func TestExportToGit_noChangeNoCommit(t *testing.T) {
+	repo := initBareRepo(t)
+	spinsvc := gitOpsSpinSvc(t, repo)
+	d := &Deployer{log: log.Log.WithName("spinnakerservice")}
+
+	assert.Nil(t, d.exportToGit(context.TODO(), runtime.NewScheme(), gitOpsGen(), spinsvc, d.log))
+	assert.Nil(t, d.exportToGit(context.TODO(), runtime.NewScheme(), gitOpsGen(), spinsvc, d.log))
+
+	checkout := filepath.Join(t.TempDir(), "checkout")
+	out, err := exec.Command("git", "clone", "--branch", "env-test", repo, checkout).CombinedOutput()
+	if !assert.Nil(t, err, string(out)) {
+		return
+	}
+	count, err := exec.Command("git", "-C", checkout, "rev-list", "--count", "HEAD").Output()
+	assert.Nil(t, err)
+	assert.Equal(t, "1\n", string(count))
+}
+
+func TestGitOpsPath(t *testing.T) {
+	assert.Equal(t, "spinnaker", gitOpsPath(&interfaces.GitOpsConfig{}))
+	assert.Equal(t, "spinnaker", gitOpsPath(&interfaces.GitOpsConfig{Path: "../escape"}))
+	assert.Equal(t, "envs/prod", gitOpsPath(&interfaces.GitOpsConfig{Path: "envs/prod/"}))
+}
@@ -0,0 +1,148 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	yaml "gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+)
+
+const (
+	// ConfirmDestructiveAnnotation must be set to "true" on the SpinnakerService
+	// for a deployment that removes providers, persistent storage, or authn to proceed
+	ConfirmDestructiveAnnotation = "spinnaker.io/accept-destructive-changes"
+	backupSecretKey              = "config"
+)
+
+// destructiveProps are hal config blocks whose removal we consider destructive
+var destructiveProps = []string{"providers", "persistentStorage", "security.authn"}
+
+// checkDestructiveChanges compares the incoming hal config with the last applied
+// config snapshot. If a destructive block was removed and the user did not confirm
+// the change with an annotation, an error is returned and the deployment stops.
+func (d *Deployer) checkDestructiveChanges(ctx context.Context, svc interfaces.SpinnakerService) error {
+	prior, err := d.getConfigBackup(ctx, svc)
+	if err != nil {
+		return err
+	}
+	if prior == nil {
+		// Nothing to compare against, first deployment
+		return nil
+	}
+	removed := removedDestructiveProps(prior, svc.GetSpinnakerConfig())
+	if len(removed) == 0 {
+		return nil
+	}
+	if svc.GetAnnotations()[ConfirmDestructiveAnnotation] == "true" {
+		d.log.Info(fmt.Sprintf("destructive change to %v confirmed via annotation", removed))
+		return nil
+	}
+	d.evtRecorder.Eventf(svc, v1.EventTypeWarning, "DestructiveChange",
+		"Configuration removes %v, add annotation %s: \"true\" to confirm", removed, ConfirmDestructiveAnnotation)
+	return fmt.Errorf("refusing to apply configuration that removes %v: previous config was saved to secret %s, "+
+		"annotate the SpinnakerService with %s: \"true\" to confirm", removed, getBackupSecretName(svc), ConfirmDestructiveAnnotation)
+}
+
+// removedDestructiveProps returns the destructive hal config blocks present in the prior config but missing from config
+func removedDestructiveProps(prior map[string]interface{}, config *interfaces.SpinnakerConfig) []string {
+	var removed []string
+	for _, p := range destructiveProps {
+		if hasProp(prior, p) && !hasProp(config.Config, p) {
+			removed = append(removed, p)
+		}
+	}
+	return removed
+}
+
+func hasProp(m map[string]interface{}, prop string) bool {
+	k, rest := prop, ""
+	if i := strings.Index(prop, "."); i >= 0 {
+		k, rest = prop[:i], prop[i+1:]
+	}
+	v, ok := m[k]
+	if !ok {
+		return false
+	}
+	if rest == "" {
+		return true
+	}
+	sub, ok := asMap(v)
+	if !ok {
+		return false
+	}
+	return hasProp(sub, rest)
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		r := map[string]interface{}{}
+		for k, val := range m {
+			s, ok := k.(string)
+			if !ok {
+				return nil, false
+			}
+			r[s] = val
+		}
+		return r, true
+	}
+	return nil, false
+}
+
+// getConfigBackup returns the hal config stored in the backup secret, nil if none exists
+func (d *Deployer) getConfigBackup(ctx context.Context, svc interfaces.SpinnakerService) (map[string]interface{}, error) {
+	sec := &v1.Secret{}
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: getBackupSecretName(svc)}, sec)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := yaml.Unmarshal(sec.Data[backupSecretKey], &m); err != nil {
+		return nil, fmt.Errorf("unable to parse config backup secret %s: %w", getBackupSecretName(svc), err)
+	}
+	r, _ := asMap(m)
+	return r, nil
+}
+
+// saveConfigBackup snapshots the hal config being applied so the next deployment can compare against it
+func (d *Deployer) saveConfigBackup(ctx context.Context, svc interfaces.SpinnakerService) error {
+	b, err := yaml.Marshal(map[string]interface{}(svc.GetSpinnakerConfig().Config))
+	if err != nil {
+		return err
+	}
+	sec := &v1.Secret{}
+	err = d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: getBackupSecretName(svc)}, sec)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		sec = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      getBackupSecretName(svc),
+				Namespace: svc.GetNamespace(),
+			},
+			Data: map[string][]byte{backupSecretKey: b},
+		}
+		return d.client.Create(ctx, sec)
+	}
+	if sec.Data == nil {
+		sec.Data = map[string][]byte{}
+	}
+	sec.Data[backupSecretKey] = b
+	return d.client.Update(ctx, sec)
+}
+
+func getBackupSecretName(svc interfaces.SpinnakerService) string {
+	return fmt.Sprintf("%s-halconfig-backup", svc.GetName())
+}
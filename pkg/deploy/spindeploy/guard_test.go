@@ -0,0 +1,93 @@
+package spindeploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func backupSecret(t *testing.T, config string) *v1.Secret {
+	m := map[string]interface{}{}
+	test.ReadYamlString([]byte(config), &m, t)
+	b, err := yaml.Marshal(m)
+	assert.Nil(t, err)
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-halconfig-backup", Namespace: "ns1"},
+		Data:       map[string][]byte{backupSecretKey: b},
+	}
+}
+
+func TestCheckDestructiveChanges_blocked(t *testing.T) {
+	prior := `
+providers:
+  kubernetes:
+    enabled: true
+persistentStorage:
+  persistentStoreType: s3
+`
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t, backupSecret(t, prior)), log: log.Log.WithName("spinnakerservice"), evtRecorder: &record.FakeRecorder{}}
+	err := d.checkDestructiveChanges(context.TODO(), spinsvc)
+	assert.NotNil(t, err)
+}
+
+func TestCheckDestructiveChanges_confirmed(t *testing.T) {
+	prior := `
+providers:
+  kubernetes:
+    enabled: true
+`
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+  annotations:
+    spinnaker.io/accept-destructive-changes: "true"
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t, backupSecret(t, prior)), log: log.Log.WithName("spinnakerservice"), evtRecorder: &record.FakeRecorder{}}
+	err := d.checkDestructiveChanges(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+}
+
+func TestCheckDestructiveChanges_noBackup(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	err := d.checkDestructiveChanges(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+}
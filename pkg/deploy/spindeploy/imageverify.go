@@ -0,0 +1,335 @@
+package spindeploy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// cosignSignatureAnnotation holds the base64 signature on each layer of a
+// cosign signature manifest
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// verifyImages checks every image referenced in generated manifests against
+// the configured trusted digests and cosign public keys. Unverifiable images
+// are recorded as events and block the deployment when enforcement is enabled.
+func (d *Deployer) verifyImages(ctx context.Context, svc interfaces.SpinnakerService, gen *generated.SpinnakerGeneratedConfig) error {
+	cfg := svc.GetImageVerificationConfig()
+	if len(cfg.CosignPublicKeys) == 0 && len(cfg.TrustedDigests) == 0 {
+		return nil
+	}
+	keys, err := parseCosignPublicKeys(cfg.CosignPublicKeys)
+	if err != nil {
+		return err
+	}
+	trusted := make(map[string]bool)
+	for _, dg := range cfg.TrustedDigests {
+		trusted[dg] = true
+	}
+	var failures []string
+	for _, img := range collectImages(gen) {
+		if err := d.verifyImage(ctx, img, trusted, keys); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", img, err.Error()))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	for _, f := range failures {
+		d.evtRecorder.Eventf(svc, v1.EventTypeWarning, "ImageVerificationFailed", "%s", f)
+	}
+	if cfg.Enforce {
+		return fmt.Errorf("deployment blocked, %d image(s) failed verification: %v", len(failures), failures)
+	}
+	d.log.Info(fmt.Sprintf("%d image(s) failed verification, enforcement disabled", len(failures)))
+	return nil
+}
+
+// collectImages returns the deduplicated, sorted images of all generated workloads
+func collectImages(gen *generated.SpinnakerGeneratedConfig) []string {
+	set := make(map[string]bool)
+	addPodSpec := func(spec v1.PodSpec) {
+		for _, c := range spec.InitContainers {
+			set[c.Image] = true
+		}
+		for _, c := range spec.Containers {
+			set[c.Image] = true
+		}
+	}
+	for _, cfg := range gen.Config {
+		if cfg.Deployment != nil {
+			addPodSpec(cfg.Deployment.Spec.Template.Spec)
+		}
+		// Workloads moved to Resources, e.g. services converted to StatefulSets
+		for _, o := range cfg.Resources {
+			switch w := o.(type) {
+			case *appsv1.StatefulSet:
+				addPodSpec(w.Spec.Template.Spec)
+			case *appsv1.DaemonSet:
+				addPodSpec(w.Spec.Template.Spec)
+			case *appsv1.Deployment:
+				addPodSpec(w.Spec.Template.Spec)
+			}
+		}
+	}
+	images := make([]string, 0, len(set))
+	for img := range set {
+		images = append(images, img)
+	}
+	sort.Strings(images)
+	return images
+}
+
+func parseCosignPublicKeys(pems []string) ([]*ecdsa.PublicKey, error) {
+	var keys []*ecdsa.PublicKey
+	for i, p := range pems {
+		block, _ := pem.Decode([]byte(p))
+		if block == nil {
+			return nil, fmt.Errorf("cosign public key %d is not valid PEM", i)
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse cosign public key %d: %w", i, err)
+		}
+		k, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("cosign public key %d is not an ECDSA key", i)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// verifyImage accepts an image when its digest is explicitly trusted or when
+// its cosign signature verifies against one of the configured keys
+func (d *Deployer) verifyImage(ctx context.Context, image string, trusted map[string]bool, keys []*ecdsa.PublicKey) error {
+	reg, err := newRegistryRef(image)
+	if err != nil {
+		return err
+	}
+	if reg.digest != "" && trusted[reg.digest] {
+		return nil
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("digest is not in the trusted list and no cosign keys are configured")
+	}
+	digest := reg.digest
+	if digest == "" {
+		digest, err = reg.resolveDigest(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to resolve digest: %s", err.Error())
+		}
+	}
+	if trusted[digest] {
+		return nil
+	}
+	return reg.verifySignature(ctx, digest, keys)
+}
+
+// registryRef is a parsed image reference with a minimal docker registry v2 client
+type registryRef struct {
+	scheme     string
+	host       string
+	repo       string
+	tag        string
+	digest     string
+	httpClient *http.Client
+}
+
+func newRegistryRef(image string) (*registryRef, error) {
+	r := &registryRef{scheme: "https", tag: "latest", httpClient: &http.Client{Timeout: 30 * time.Second}}
+	rest := image
+	if i := strings.Index(rest, "@"); i >= 0 {
+		r.digest = rest[i+1:]
+		rest = rest[:i]
+	}
+	// The host is the first path element when it looks like a hostname
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		r.host = parts[0]
+		rest = parts[1]
+	} else {
+		r.host = "index.docker.io"
+		if !strings.Contains(rest, "/") {
+			rest = "library/" + rest
+		}
+	}
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		r.tag = rest[i+1:]
+		rest = rest[:i]
+	}
+	if rest == "" {
+		return nil, fmt.Errorf("invalid image reference %s", image)
+	}
+	r.repo = rest
+	if strings.HasPrefix(r.host, "localhost") || strings.HasPrefix(r.host, "127.0.0.1") {
+		r.scheme = "http"
+	}
+	return r, nil
+}
+
+// resolveDigest returns the manifest digest the image tag currently points to
+func (r *registryRef) resolveDigest(ctx context.Context) (string, error) {
+	resp, err := r.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", r.repo, r.tag))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	dg := resp.Header.Get("Docker-Content-Digest")
+	if dg == "" {
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		dg = fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+	}
+	return dg, nil
+}
+
+// verifySignature fetches the cosign signature manifest stored next to the
+// image and checks its signatures against the trusted keys
+func (r *registryRef) verifySignature(ctx context.Context, digest string, keys []*ecdsa.PublicKey) error {
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	resp, err := r.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", r.repo, sigTag))
+	if err != nil {
+		return fmt.Errorf("no cosign signature found for digest %s: %s", digest, err.Error())
+	}
+	defer resp.Body.Close()
+	var manifest struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return fmt.Errorf("unable to parse signature manifest: %s", err.Error())
+	}
+	for _, l := range manifest.Layers {
+		sig, err := base64.StdEncoding.DecodeString(l.Annotations[cosignSignatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+		payload, err := r.blob(ctx, l.Digest)
+		if err != nil {
+			continue
+		}
+		// The signature only vouches for the digest embedded in the payload,
+		// a valid signature copied from another image must not verify
+		if payloadDigest(payload) != digest {
+			continue
+		}
+		h := sha256.Sum256(payload)
+		for _, k := range keys {
+			if ecdsa.VerifyASN1(k, h[:], sig) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no signature matches the configured cosign keys")
+}
+
+// payloadDigest extracts the manifest digest a cosign SimpleSigning payload
+// (https://github.com/containers/image/blob/master/docs/containers-signature.5.md)
+// was issued for
+func payloadDigest(payload []byte) string {
+	var p struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return ""
+	}
+	return p.Critical.Image.DockerManifestDigest
+}
+
+func (r *registryRef) blob(ctx context.Context, digest string) ([]byte, error) {
+	resp, err := r.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", r.repo, digest))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (r *registryRef) get(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s://%s%s", r.scheme, r.host, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.oci.image.manifest.v1+json, application/vnd.docker.distribution.manifest.v2+json")
+	resp, err := r.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, terr := r.anonymousToken(ctx, resp.Header.Get("Www-Authenticate"))
+		resp.Body.Close()
+		if terr != nil {
+			return nil, terr
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err = r.httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry %s returned status %d for %s", r.host, resp.StatusCode, path)
+	}
+	return resp, nil
+}
+
+// anonymousToken implements the token flow from
+// https://docs.docker.com/registry/spec/auth/token/ without credentials
+func (r *registryRef) anonymousToken(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("registry %s requires credentials", r.host)
+	}
+	params := map[string]string{}
+	for _, p := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], "\"")
+		}
+	}
+	u := params["realm"]
+	if u == "" {
+		return "", fmt.Errorf("registry %s returned an invalid auth challenge", r.host)
+	}
+	u = fmt.Sprintf("%s?service=%s&scope=%s", u, params["service"], params["scope"])
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	t := struct {
+		Token string `json:"token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return "", err
+	}
+	return t.Token, nil
+}
@@ -0,0 +1,233 @@
+package spindeploy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func imageVerifySpinSvc(t *testing.T, enforce bool, keyPem, digest string) string {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  imageVerification:
+`
+	if enforce {
+		s += "    enforce: true\n"
+	}
+	if digest != "" {
+		s += fmt.Sprintf("    trustedDigests:\n    - %s\n", digest)
+	}
+	if keyPem != "" {
+		s += "    cosignPublicKeys:\n    - |\n"
+		for _, l := range strings.Split(strings.TrimSpace(keyPem), "\n") {
+			s += "      " + l + "\n"
+		}
+	}
+	s += `  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	return s
+}
+
+func genConfigWithImage(image string) *generated.SpinnakerGeneratedConfig {
+	return &generated.SpinnakerGeneratedConfig{
+		Config: map[string]generated.ServiceConfig{
+			"gate": {
+				Deployment: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "gate", Image: image}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// fakeCosignRegistry serves a manifest for the image tag and a cosign
+// signature manifest with the given payload and signature
+func fakeCosignRegistry(t *testing.T, digest string, payload, sig []byte) *httptest.Server {
+	payloadDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(payload))
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/spinnaker/gate/manifests/1.17.1":
+			w.Header().Set("Docker-Content-Digest", digest)
+			_, _ = w.Write([]byte("{}"))
+		case r.URL.Path == "/v2/spinnaker/gate/manifests/"+sigTag:
+			m := map[string]interface{}{
+				"layers": []map[string]interface{}{{
+					"digest": payloadDigest,
+					"annotations": map[string]string{
+						cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+					},
+				}},
+			}
+			_ = json.NewEncoder(w).Encode(m)
+		case r.URL.Path == "/v2/spinnaker/gate/blobs/"+payloadDigest:
+			_, _ = w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestVerifyImages_trustedDigest(t *testing.T) {
+	digest := "sha256:47bfdb6ac73c1ca34a17cfd0e1ef5d4739ba7a2d1a74e27e64fd5b6f2c30c5b8"
+	spinsvc := test.ManifestToSpinService(imageVerifySpinSvc(t, true, "", digest), t)
+	d := &Deployer{
+		log:         log.Log.WithName("spinnakerservice"),
+		evtRecorder: record.NewFakeRecorder(10),
+	}
+	gen := genConfigWithImage("gcr.io/spinnaker-marketplace/gate@" + digest)
+	assert.Nil(t, d.verifyImages(context.TODO(), spinsvc, gen))
+}
+
+func TestVerifyImages_unknownDigestEnforced(t *testing.T) {
+	digest := "sha256:47bfdb6ac73c1ca34a17cfd0e1ef5d4739ba7a2d1a74e27e64fd5b6f2c30c5b8"
+	spinsvc := test.ManifestToSpinService(imageVerifySpinSvc(t, true, "", digest), t)
+	rec := record.NewFakeRecorder(10)
+	d := &Deployer{
+		log:         log.Log.WithName("spinnakerservice"),
+		evtRecorder: rec,
+	}
+	gen := genConfigWithImage("gcr.io/spinnaker-marketplace/gate@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+	err := d.verifyImages(context.TODO(), spinsvc, gen)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed verification")
+	assert.Equal(t, 1, len(rec.Events))
+}
+
+func TestVerifyImages_cosignSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.Nil(t, err)
+	keyPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	digest := "sha256:47bfdb6ac73c1ca34a17cfd0e1ef5d4739ba7a2d1a74e27e64fd5b6f2c30c5b8"
+	payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + digest + `"}}}`)
+	h := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+	assert.Nil(t, err)
+
+	srv := fakeCosignRegistry(t, digest, payload, sig)
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	spinsvc := test.ManifestToSpinService(imageVerifySpinSvc(t, true, keyPem, ""), t)
+	d := &Deployer{
+		log:         log.Log.WithName("spinnakerservice"),
+		evtRecorder: record.NewFakeRecorder(10),
+	}
+	gen := genConfigWithImage(host + "/spinnaker/gate:1.17.1")
+	assert.Nil(t, d.verifyImages(context.TODO(), spinsvc, gen))
+}
+
+func TestVerifyImages_signatureForOtherImageRejected(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.Nil(t, err)
+	keyPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	// A valid signature, but issued for a different image digest
+	digest := "sha256:47bfdb6ac73c1ca34a17cfd0e1ef5d4739ba7a2d1a74e27e64fd5b6f2c30c5b8"
+	payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"sha256:1111111111111111111111111111111111111111111111111111111111111111"}}}`)
+	h := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+	assert.Nil(t, err)
+
+	srv := fakeCosignRegistry(t, digest, payload, sig)
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	spinsvc := test.ManifestToSpinService(imageVerifySpinSvc(t, true, keyPem, ""), t)
+	d := &Deployer{
+		log:         log.Log.WithName("spinnakerservice"),
+		evtRecorder: record.NewFakeRecorder(10),
+	}
+	gen := genConfigWithImage(host + "/spinnaker/gate:1.17.1")
+	err = d.verifyImages(context.TODO(), spinsvc, gen)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed verification")
+}
+
+func TestCollectImages_statefulSet(t *testing.T) {
+	gen := &generated.SpinnakerGeneratedConfig{
+		Config: map[string]generated.ServiceConfig{
+			"redis": {
+				Resources: []runtime.Object{&appsv1.StatefulSet{
+					Spec: appsv1.StatefulSetSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "redis", Image: "docker.io/library/redis:5"}},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	assert.Equal(t, []string{"docker.io/library/redis:5"}, collectImages(gen))
+}
+
+func TestVerifyImages_wrongKeyReportOnly(t *testing.T) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&otherKey.PublicKey)
+	assert.Nil(t, err)
+	keyPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	digest := "sha256:47bfdb6ac73c1ca34a17cfd0e1ef5d4739ba7a2d1a74e27e64fd5b6f2c30c5b8"
+	payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + digest + `"}}}`)
+	h := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, signingKey, h[:])
+	assert.Nil(t, err)
+
+	srv := fakeCosignRegistry(t, digest, payload, sig)
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	spinsvc := test.ManifestToSpinService(imageVerifySpinSvc(t, false, keyPem, ""), t)
+	rec := record.NewFakeRecorder(10)
+	d := &Deployer{
+		log:         log.Log.WithName("spinnakerservice"),
+		evtRecorder: rec,
+	}
+	gen := genConfigWithImage(host + "/spinnaker/gate:1.17.1")
+	// Enforcement is off, the failure is only reported
+	assert.Nil(t, d.verifyImages(context.TODO(), spinsvc, gen))
+	assert.Equal(t, 1, len(rec.Events))
+}
@@ -0,0 +1,44 @@
+package spindeploy
+
+import (
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// impersonatingDeployer returns a copy of the deployer whose clients act as the
+// deploy service account configured on the SpinnakerService, or the deployer itself
+// when none is configured. A dedicated deploy identity lets cluster audit logs
+// attribute workload changes to it and keeps the RBAC for applying manifests
+// separate from the operator's own permissions.
+func (d *Deployer) impersonatingDeployer(svc interfaces.SpinnakerService) (*Deployer, error) {
+	sa := svc.GetApplyPolicyConfig().ServiceAccount
+	if sa == "" {
+		return d, nil
+	}
+	if d.restConfig == nil {
+		return nil, fmt.Errorf("unable to impersonate service account %s: no rest config available", sa)
+	}
+	cfg := rest.CopyConfig(d.restConfig)
+	cfg.Impersonate = rest.ImpersonationConfig{UserName: serviceAccountUserName(svc.GetNamespace(), sa)}
+	rawClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	nd := *d
+	nd.rawClient = rawClient
+	nd.client = c
+	return &nd, nil
+}
+
+// serviceAccountUserName returns the user name the apiserver assigns to the service account
+func serviceAccountUserName(ns, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", ns, name)
+}
@@ -0,0 +1,54 @@
+package spindeploy
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestImpersonatingDeployer_notConfigured(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	nd, err := d.impersonatingDeployer(spinsvc)
+	assert.Nil(t, err)
+	assert.Equal(t, d, nd)
+}
+
+func TestImpersonatingDeployer_noRestConfig(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  apply:
+    serviceAccount: spinnaker-deployer
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	_, err := d.impersonatingDeployer(spinsvc)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "spinnaker-deployer")
+	}
+}
+
+func TestServiceAccountUserName(t *testing.T) {
+	assert.Equal(t, "system:serviceaccount:ns1:deployer", serviceAccountUserName("ns1", "deployer"))
+}
@@ -0,0 +1,58 @@
+package spindeploy
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	generateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "spinnaker_operator_generate_duration_seconds",
+		Help: "Time spent generating manifests with Halyard",
+	}, []string{"namespace", "name"})
+	applyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "spinnaker_operator_apply_duration_seconds",
+		Help: "Time spent applying the generated manifests",
+	}, []string{"namespace", "name"})
+	deployTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "spinnaker_operator_deploy_total",
+		Help: "Completed deployments per SpinnakerService and outcome",
+	}, []string{"namespace", "name", "outcome"})
+	lastDeployTime = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "spinnaker_operator_last_successful_deploy_timestamp_seconds",
+		Help: "Unix time of the last successful deployment, alert when it stops moving",
+	}, []string{"namespace", "name"})
+	deployedVersion = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "spinnaker_operator_deployed_version",
+		Help: "Deployed Spinnaker version as a label, value is always 1",
+	}, []string{"namespace", "name", "version"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(generateDuration, applyDuration, deployTotal, lastDeployTime, deployedVersion)
+}
+
+// versionGauges remembers the version label last exported per service so a
+// version change does not leave a stale series behind
+var versionGauges = struct {
+	sync.Mutex
+	last map[string]string
+}{last: map[string]string{}}
+
+// setDeployedVersion exports the deployed version, dropping the series of the
+// previously deployed one
+func setDeployedVersion(namespace, name, version string) {
+	if version == "" {
+		return
+	}
+	versionGauges.Lock()
+	defer versionGauges.Unlock()
+	key := namespace + "/" + name
+	if prev, ok := versionGauges.last[key]; ok && prev != version {
+		deployedVersion.DeleteLabelValues(namespace, name, prev)
+	}
+	versionGauges.last[key] = version
+	deployedVersion.WithLabelValues(namespace, name, version).Set(1)
+}
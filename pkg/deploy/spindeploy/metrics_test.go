@@ -0,0 +1,40 @@
+package spindeploy
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+// countSeries returns how many label combinations the vec currently exports
+func countSeries(vec *prometheus.GaugeVec) int {
+	ch := make(chan prometheus.Metric, 10)
+	vec.Collect(ch)
+	close(ch)
+	n := 0
+	for range ch {
+		n++
+	}
+	return n
+}
+
+func TestSetDeployedVersion_replacesStaleSeries(t *testing.T) {
+	deployedVersion.Reset()
+	setDeployedVersion("ns1", "spinnaker", "1.16.0")
+	assert.Equal(t, 1, countSeries(deployedVersion))
+
+	// Upgrading must not leave the old version's series behind
+	setDeployedVersion("ns1", "spinnaker", "1.17.1")
+	assert.Equal(t, 1, countSeries(deployedVersion))
+
+	// Other services keep their own series
+	setDeployedVersion("ns1", "other", "1.17.1")
+	assert.Equal(t, 2, countSeries(deployedVersion))
+}
+
+func TestSetDeployedVersion_emptyVersionIgnored(t *testing.T) {
+	deployedVersion.Reset()
+	setDeployedVersion("ns2", "spinnaker", "")
+	assert.Equal(t, 0, countSeries(deployedVersion))
+}
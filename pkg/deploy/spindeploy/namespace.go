@@ -0,0 +1,80 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ManagedNamespaceLabel marks namespaces created by the operator, namespace
+// cleanup on SpinnakerService deletion only touches labeled namespaces
+const ManagedNamespaceLabel = "spinnaker.io/managed-by"
+
+// ManagedNamespaceValue returns the label value identifying the owning SpinnakerService
+func ManagedNamespaceValue(svc interfaces.SpinnakerService) string {
+	return fmt.Sprintf("%s.%s", svc.GetNamespace(), svc.GetName())
+}
+
+// ensureTargetNamespace creates or updates the namespace declared in spec.targetNamespace
+func (d *Deployer) ensureTargetNamespace(ctx context.Context, svc interfaces.SpinnakerService) error {
+	cfg := svc.GetTargetNamespaceConfig()
+	if cfg.Name == "" {
+		return nil
+	}
+	ns := &v1.Namespace{}
+	err := d.client.Get(ctx, types.NamespacedName{Name: cfg.Name}, ns)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		if !cfg.Create {
+			return fmt.Errorf("target namespace %s does not exist, set spec.targetNamespace.create to create it", cfg.Name)
+		}
+		ns = &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        cfg.Name,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+		}
+		applyNamespaceMetadata(ns, cfg)
+		ns.Labels[ManagedNamespaceLabel] = ManagedNamespaceValue(svc)
+		d.log.Info(fmt.Sprintf("creating target namespace %s", cfg.Name))
+		return d.client.Create(ctx, ns)
+	}
+	// Namespace exists: reconcile the declared labels and annotations
+	if !applyNamespaceMetadata(ns, cfg) {
+		return nil
+	}
+	return d.client.Update(ctx, ns)
+}
+
+// applyNamespaceMetadata sets the declared labels and annotations on the namespace,
+// returning true if anything changed
+func applyNamespaceMetadata(ns *v1.Namespace, cfg *interfaces.TargetNamespaceConfig) bool {
+	changed := false
+	if len(cfg.Labels) > 0 && ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+	for k, v := range cfg.Labels {
+		if ns.Labels[k] != v {
+			ns.Labels[k] = v
+			changed = true
+		}
+	}
+	if len(cfg.Annotations) > 0 && ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+	for k, v := range cfg.Annotations {
+		if ns.Annotations[k] != v {
+			ns.Annotations[k] = v
+			changed = true
+		}
+	}
+	return changed
+}
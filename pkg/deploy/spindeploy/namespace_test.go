@@ -0,0 +1,84 @@
+package spindeploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var targetNsManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  targetNamespace:
+    name: spinnaker-system
+    create: true
+    labels:
+      istio-injection: enabled
+    annotations:
+      team: delivery
+`
+
+func TestEnsureTargetNamespace_created(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(targetNsManifest, t)
+	fakeClient := test.FakeClient(t)
+	d := &Deployer{client: fakeClient, log: log.Log.WithName("spinnakerservice")}
+
+	err := d.ensureTargetNamespace(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	ns := &v1.Namespace{}
+	if assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "spinnaker-system"}, ns)) {
+		assert.Equal(t, "enabled", ns.Labels["istio-injection"])
+		assert.Equal(t, "delivery", ns.Annotations["team"])
+		assert.Equal(t, "ns1.spinnaker", ns.Labels[ManagedNamespaceLabel])
+	}
+}
+
+func TestEnsureTargetNamespace_labelsReconciled(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(targetNsManifest, t)
+	existing := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-system"}}
+	fakeClient := test.FakeClient(t, existing)
+	d := &Deployer{client: fakeClient, log: log.Log.WithName("spinnakerservice")}
+
+	err := d.ensureTargetNamespace(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	ns := &v1.Namespace{}
+	if assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "spinnaker-system"}, ns)) {
+		assert.Equal(t, "enabled", ns.Labels["istio-injection"])
+		// Pre-existing namespaces are never marked as operator managed
+		_, ok := ns.Labels[ManagedNamespaceLabel]
+		assert.False(t, ok)
+	}
+}
+
+func TestEnsureTargetNamespace_missingWithoutCreate(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  targetNamespace:
+    name: spinnaker-system
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	err := d.ensureTargetNamespace(context.TODO(), spinsvc)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,69 @@
+package nsselector
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetector"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const NamespacesHashKey = "kubernetesNamespaces"
+
+// changeDetector detects namespaces coming and going under an account's
+// namespaceSelector so clouddriver's account config is kept up to date
+type changeDetector struct {
+	client      client.Client
+	log         logr.Logger
+	evtRecorder record.EventRecorder
+}
+
+type ChangeDetectorGenerator struct{}
+
+func (g *ChangeDetectorGenerator) NewChangeDetector(client client.Client, log logr.Logger, evtRecorder record.EventRecorder, scheme *runtime.Scheme) (changedetector.ChangeDetector, error) {
+	return &changeDetector{client: client, log: log, evtRecorder: evtRecorder}, nil
+}
+
+// IsSpinnakerUpToDate returns true if the namespaces matching every account
+// selector are unchanged since the last deployment
+func (ch *changeDetector) IsSpinnakerUpToDate(ctx context.Context, svc interfaces.SpinnakerService) (bool, error) {
+	resolved, err := resolveSelectors(ctx, ch.client, svc.GetSpinnakerConfig())
+	if err != nil {
+		return false, err
+	}
+	if len(resolved) == 0 {
+		return true, nil
+	}
+	h := hashResolved(resolved)
+	st := svc.GetStatus()
+	prior := st.UpdateHashIfNotExist(NamespacesHashKey, h, time.Now())
+	return h == prior.Hash, nil
+}
+
+func (ch *changeDetector) AlwaysRun() bool {
+	return true
+}
+
+// hashResolved computes a stable hash over the account to namespaces mapping
+func hashResolved(resolved map[string][]string) string {
+	accounts := make([]string, 0, len(resolved))
+	for a := range resolved {
+		accounts = append(accounts, a)
+	}
+	sort.Strings(accounts)
+	m := md5.New()
+	for _, a := range accounts {
+		m.Write([]byte(a))
+		for _, ns := range resolved[a] {
+			m.Write([]byte(ns))
+		}
+	}
+	return hex.EncodeToString(m.Sum(nil))
+}
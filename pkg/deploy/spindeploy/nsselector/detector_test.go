@@ -0,0 +1,58 @@
+package nsselector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func setupDetector(t *testing.T, objs ...runtime.Object) *changeDetector {
+	g := &ChangeDetectorGenerator{}
+	d, err := g.NewChangeDetector(test.FakeClient(t, objs...), log.Log.WithName("spinnakerservice"), &record.FakeRecorder{}, nil)
+	assert.Nil(t, err)
+	return d.(*changeDetector)
+}
+
+func TestIsSpinnakerUpToDate_noSelectors(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	ch := setupDetector(t)
+	spinsvc := test.ManifestToSpinService(s, t)
+	up, err := ch.IsSpinnakerUpToDate(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.True(t, up)
+}
+
+func TestIsSpinnakerUpToDate_namespaceAdded(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(selectorSpinsvc, t)
+
+	ch := setupDetector(t, selectorNamespaces()...)
+	up, err := ch.IsSpinnakerUpToDate(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	// First run records the hash
+	assert.False(t, up)
+	up, err = ch.IsSpinnakerUpToDate(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.True(t, up)
+
+	// A new matching namespace triggers a redeploy
+	objs := append(selectorNamespaces(), namespace("team-c", map[string]string{"spinnaker.io/managed": "true"}))
+	ch = setupDetector(t, objs...)
+	up, err = ch.IsSpinnakerUpToDate(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.False(t, up)
+}
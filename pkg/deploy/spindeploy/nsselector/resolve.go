@@ -0,0 +1,73 @@
+package nsselector
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	accountsProp = "providers.kubernetes.accounts"
+	// namespaceSelector scopes a Kubernetes account to the namespaces matching
+	// a label selector instead of a static list
+	selectorKey = "namespaceSelector"
+)
+
+// resolveSelectors returns the sorted namespace names matching each account's
+// namespaceSelector, keyed by account name
+func resolveSelectors(ctx context.Context, c client.Client, cfg *interfaces.SpinnakerConfig) (map[string][]string, error) {
+	accounts, err := cfg.GetHalConfigObjectArray(ctx, accountsProp)
+	if err != nil {
+		// No kubernetes provider configured
+		return nil, nil
+	}
+	resolved := map[string][]string{}
+	for _, a := range accounts {
+		sel, ok := a[selectorKey]
+		if !ok {
+			continue
+		}
+		name, _ := a["name"].(string)
+		selector, err := parseSelector(sel)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespaceSelector on account %s: %w", name, err)
+		}
+		nss := &v1.NamespaceList{}
+		if err := c.List(ctx, nss); err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(nss.Items))
+		for _, ns := range nss.Items {
+			if selector.Matches(labels.Set(ns.Labels)) {
+				names = append(names, ns.Name)
+			}
+		}
+		sort.Strings(names)
+		resolved[name] = names
+	}
+	return resolved, nil
+}
+
+// parseSelector accepts a selector string ("env=prod,team!=b") or a map of
+// labels that must all match
+func parseSelector(sel interface{}) (labels.Selector, error) {
+	if s, ok := sel.(string); ok {
+		return labels.Parse(s)
+	}
+	v := reflect.ValueOf(sel)
+	if v.Kind() != reflect.Map {
+		return nil, fmt.Errorf("expected a selector string or a map of labels, got %T", sel)
+	}
+	set := labels.Set{}
+	for _, k := range v.MapKeys() {
+		key := fmt.Sprintf("%v", k.Interface())
+		set[key] = fmt.Sprintf("%v", v.MapIndex(k).Interface())
+	}
+	return labels.SelectorFromValidatedSet(set), nil
+}
@@ -0,0 +1,60 @@
+package nsselector
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformer"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type TransformerGenerator struct{}
+
+func (tg *TransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (transformer.Transformer, error) {
+	base := &transformer.DefaultTransformer{}
+	tr := nsSelectorTransformer{svc: svc, log: log, client: client, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (tg *TransformerGenerator) GetName() string {
+	return "NamespaceSelector"
+}
+
+// nsSelectorTransformer replaces each Kubernetes account's namespaceSelector
+// with the list of namespaces currently matching it
+type nsSelectorTransformer struct {
+	*transformer.DefaultTransformer
+	svc    interfaces.SpinnakerService
+	log    logr.Logger
+	client client.Client
+}
+
+func (t *nsSelectorTransformer) TransformConfig(ctx context.Context) error {
+	cfg := t.svc.GetSpinnakerConfig()
+	resolved, err := resolveSelectors(ctx, t.client, cfg)
+	if err != nil {
+		return err
+	}
+	if len(resolved) == 0 {
+		return nil
+	}
+	accounts, err := cfg.GetHalConfigObjectArray(ctx, accountsProp)
+	if err != nil {
+		return err
+	}
+	for _, a := range accounts {
+		name, _ := a["name"].(string)
+		ns, ok := resolved[name]
+		if !ok {
+			continue
+		}
+		a["namespaces"] = ns
+		// Halyard does not know about the selector
+		delete(a, selectorKey)
+	}
+	return cfg.SetHalConfigProp(accountsProp, accounts)
+}
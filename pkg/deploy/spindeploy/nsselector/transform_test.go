@@ -0,0 +1,100 @@
+package nsselector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformertest"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var selectorSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        kubernetes:
+          enabled: true
+          accounts:
+          - name: teams
+            serviceAccount: true
+            namespaceSelector:
+              spinnaker.io/managed: "true"
+          - name: static
+            serviceAccount: true
+            namespaces:
+            - infra
+`
+
+func namespace(name string, labels map[string]string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func selectorNamespaces() []runtime.Object {
+	return []runtime.Object{
+		namespace("team-a", map[string]string{"spinnaker.io/managed": "true"}),
+		namespace("team-b", map[string]string{"spinnaker.io/managed": "true"}),
+		namespace("other", nil),
+	}
+}
+
+func TestTransformConfig_selectorResolved(t *testing.T) {
+	tr, spinsvc := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, selectorSpinsvc, t, selectorNamespaces()...)
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+
+	accounts, err := spinsvc.GetSpinnakerConfig().GetHalConfigObjectArray(context.TODO(), accountsProp)
+	assert.Nil(t, err)
+	ns, err := inspect.GetStringArray(accounts[0], "namespaces")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, ns)
+	// The selector is stripped so Halyard only sees the resolved list
+	_, ok := accounts[0][selectorKey]
+	assert.False(t, ok)
+	// Accounts with a static list are untouched
+	ns, err = inspect.GetStringArray(accounts[1], "namespaces")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"infra"}, ns)
+}
+
+func TestTransformConfig_stringSelector(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        kubernetes:
+          enabled: true
+          accounts:
+          - name: teams
+            serviceAccount: true
+            namespaceSelector: "spinnaker.io/managed=true"
+`
+	tr, spinsvc := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, s, t, selectorNamespaces()...)
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+
+	accounts, err := spinsvc.GetSpinnakerConfig().GetHalConfigObjectArray(context.TODO(), accountsProp)
+	assert.Nil(t, err)
+	ns, err := inspect.GetStringArray(accounts[0], "namespaces")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, ns)
+}
@@ -0,0 +1,91 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ObserveOnlyAnnotation puts a single SpinnakerService in observer mode: the operator
+// renders, validates and reports what would change but never applies anything
+const ObserveOnlyAnnotation = "spinnaker.io/observe-only"
+
+// ObserverMode puts the whole operator in observer mode, set with --observer-mode
+var ObserverMode = false
+
+const (
+	// DeploymentPlanDryRun renders and diffs the manifests without applying anything
+	DeploymentPlanDryRun = "dryRun"
+	// DeploymentPlanApply is the default plan, deploying the rendered manifests
+	DeploymentPlanApply = "apply"
+)
+
+func (d *Deployer) isObserveOnly(svc interfaces.SpinnakerService) bool {
+	if ObserverMode || svc.GetDeploymentPlan() == DeploymentPlanDryRun {
+		return true
+	}
+	return svc.GetAnnotations()[ObserveOnlyAnnotation] == "true"
+}
+
+// checkDeploymentPlan rejects unknown plan values before anything is rendered
+func (d *Deployer) checkDeploymentPlan(svc interfaces.SpinnakerService) error {
+	switch svc.GetDeploymentPlan() {
+	case "", DeploymentPlanDryRun, DeploymentPlanApply:
+		return nil
+	}
+	return fmt.Errorf("unknown deployment plan %q, expected %q or %q", svc.GetDeploymentPlan(), DeploymentPlanDryRun, DeploymentPlanApply)
+}
+
+// reportDrift compares the generated manifests with the live objects and reports
+// the pending changes as an event instead of applying them
+func (d *Deployer) reportDrift(ctx context.Context, gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService) error {
+	changes := make([]string, 0)
+	for name, cfg := range gen.Config {
+		if cfg.Deployment != nil {
+			c, err := d.objectDrift(ctx, cfg.Deployment.GetNamespace(), cfg.Deployment.GetName(), name, &appsv1.Deployment{}, "deployment")
+			if err != nil {
+				return err
+			}
+			changes = append(changes, c)
+		}
+		if cfg.Service != nil {
+			c, err := d.objectDrift(ctx, cfg.Service.GetNamespace(), cfg.Service.GetName(), name, &v1.Service{}, "service")
+			if err != nil {
+				return err
+			}
+			changes = append(changes, c)
+		}
+	}
+	sort.Strings(changes)
+	msg := "no changes pending"
+	if len(changes) > 0 {
+		msg = fmt.Sprintf("%d pending changes: %s", len(changes), strings.Join(changes, ", "))
+	}
+	d.log.Info(fmt.Sprintf("observer mode, not applying: %s", msg))
+	d.evtRecorder.Eventf(svc, v1.EventTypeNormal, "ObserveOnly", "Observer mode, %s", msg)
+	// Keep the diff queryable on the status as well
+	svc.GetStatus().PendingChanges = changes
+	return d.client.Status().Update(ctx, svc)
+}
+
+// objectDrift reports whether the named object would be created or updated.
+// Without applying there is no deep diff, any existing object counts as an update.
+func (d *Deployer) objectDrift(ctx context.Context, ns, name, svcName string, obj runtime.Object, kind string) (string, error) {
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, obj)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Sprintf("create %s %s (%s)", kind, name, svcName), nil
+		}
+		return "", err
+	}
+	return fmt.Sprintf("update %s %s (%s)", kind, name, svcName), nil
+}
@@ -0,0 +1,89 @@
+package spindeploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func init() {
+	_ = v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+}
+
+var dryRunManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  deploymentPlan: dryRun
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func planManifest(plan string) string {
+	return `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  deploymentPlan: ` + plan + `
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+}
+
+func TestCheckDeploymentPlan(t *testing.T) {
+	d := &Deployer{}
+	assert.Nil(t, d.checkDeploymentPlan(test.ManifestToSpinService(dryRunManifest, t)))
+
+	err := d.checkDeploymentPlan(test.ManifestToSpinService(planManifest("preview"), t))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown deployment plan \"preview\"")
+	}
+}
+
+func TestIsObserveOnly_dryRunPlan(t *testing.T) {
+	d := &Deployer{}
+	assert.True(t, d.isObserveOnly(test.ManifestToSpinService(dryRunManifest, t)))
+	assert.False(t, d.isObserveOnly(test.ManifestToSpinService(planManifest(DeploymentPlanApply), t)))
+}
+
+func TestReportDrift_writesStatusAndEvent(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(dryRunManifest, t)
+	fakeClient := test.FakeClient(t, spinsvc)
+	rec := record.NewFakeRecorder(10)
+	d := &Deployer{client: fakeClient, evtRecorder: rec, log: log.Log.WithName("spinnakerservice")}
+
+	gen := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{
+		"gate": {Deployment: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", Namespace: "ns1"},
+		}},
+	}}
+	assert.Nil(t, d.reportDrift(context.TODO(), gen, spinsvc))
+
+	if assert.Len(t, spinsvc.GetStatus().PendingChanges, 1) {
+		assert.Equal(t, "create deployment spin-gate (gate)", spinsvc.GetStatus().PendingChanges[0])
+	}
+	select {
+	case e := <-rec.Events:
+		assert.Contains(t, e, "ObserveOnly")
+		assert.Contains(t, e, "1 pending changes")
+	default:
+		t.Fatal("expected an ObserveOnly event")
+	}
+}
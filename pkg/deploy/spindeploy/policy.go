@@ -0,0 +1,89 @@
+package spindeploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/open-policy-agent/opa/rego"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// policyQuery collects violation messages from any package under data.spinnaker
+const policyQuery = "data.spinnaker[_].deny[x]"
+
+// evaluatePolicies runs the Rego policies stored in the ConfigMap referenced by
+// spec.policies against the hal config and generated manifests. Violations are
+// recorded as events and block the deployment when enforcement is enabled.
+func (d *Deployer) evaluatePolicies(ctx context.Context, svc interfaces.SpinnakerService, gen *generated.SpinnakerGeneratedConfig) error {
+	p := svc.GetPolicyConfig()
+	if p.ConfigMap == "" {
+		return nil
+	}
+	cm := &v1.ConfigMap{}
+	if err := d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: p.ConfigMap}, cm); err != nil {
+		return fmt.Errorf("unable to read policy ConfigMap %s: %w", p.ConfigMap, err)
+	}
+	if len(cm.Data) == 0 {
+		return nil
+	}
+	violations, err := evalRegoModules(ctx, cm.Data, policyInput(svc, gen))
+	if err != nil {
+		return fmt.Errorf("error evaluating policies from ConfigMap %s: %w", p.ConfigMap, err)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	for _, v := range violations {
+		d.evtRecorder.Eventf(svc, v1.EventTypeWarning, "PolicyViolation", "%s", v)
+	}
+	if p.Enforce {
+		return fmt.Errorf("deployment blocked by %d policy violation(s): %v", len(violations), violations)
+	}
+	d.log.Info(fmt.Sprintf("%d policy violation(s) reported, enforcement disabled", len(violations)))
+	return nil
+}
+
+// policyInput builds the input document passed to the policies
+func policyInput(svc interfaces.SpinnakerService, gen *generated.SpinnakerGeneratedConfig) interface{} {
+	in := map[string]interface{}{
+		"name":      svc.GetName(),
+		"namespace": svc.GetNamespace(),
+		"config":    svc.GetSpinnakerConfig(),
+		"manifests": gen,
+	}
+	// Round-trip through JSON so OPA only sees native types
+	b, err := json.Marshal(in)
+	if err != nil {
+		return in
+	}
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return in
+	}
+	return out
+}
+
+// evalRegoModules evaluates all modules and returns the deny messages
+func evalRegoModules(ctx context.Context, modules map[string]string, input interface{}) ([]string, error) {
+	opts := []func(*rego.Rego){rego.Query(policyQuery), rego.Input(input)}
+	for name, mod := range modules {
+		opts = append(opts, rego.Module(name, mod))
+	}
+	rs, err := rego.New(opts...).Eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var violations []string
+	for _, r := range rs {
+		for _, b := range r.Bindings {
+			violations = append(violations, fmt.Sprintf("%v", b))
+		}
+	}
+	sort.Strings(violations)
+	return violations, nil
+}
@@ -0,0 +1,76 @@
+package spindeploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+const denyDebugPolicy = `
+package spinnaker.config
+
+deny[msg] {
+  input.config.config.debug == true
+  msg := "debug mode is not allowed"
+}
+`
+
+func policyConfigMap() *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-policies", Namespace: "ns1"},
+		Data:       map[string]string{"config.rego": denyDebugPolicy},
+	}
+}
+
+func policySpinSvc(enforce bool, t *testing.T) string {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  policies:
+    configMap: spinnaker-policies
+`
+	if enforce {
+		s += "    enforce: true\n"
+	}
+	s += `  spinnakerConfig:
+    config:
+      debug: true
+`
+	return s
+}
+
+func TestEvaluatePolicies_enforced(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(policySpinSvc(true, t), t)
+	d := &Deployer{
+		client:      test.FakeClient(t, policyConfigMap()),
+		log:         log.Log.WithName("spinnakerservice"),
+		evtRecorder: record.NewFakeRecorder(10),
+	}
+	err := d.evaluatePolicies(context.TODO(), spinsvc, &generated.SpinnakerGeneratedConfig{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "debug mode is not allowed")
+}
+
+func TestEvaluatePolicies_reportOnly(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(policySpinSvc(false, t), t)
+	rec := record.NewFakeRecorder(10)
+	d := &Deployer{
+		client:      test.FakeClient(t, policyConfigMap()),
+		log:         log.Log.WithName("spinnakerservice"),
+		evtRecorder: rec,
+	}
+	err := d.evaluatePolicies(context.TODO(), spinsvc, &generated.SpinnakerGeneratedConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rec.Events))
+}
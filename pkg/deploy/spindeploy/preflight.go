@@ -0,0 +1,241 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"google.golang.org/api/option"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SkipPreflightAnnotation set to "true" skips the provider connectivity
+	// checks, e.g. when the operator cannot reach what Spinnaker can
+	SkipPreflightAnnotation = "spinnaker.io/skip-preflight"
+	// preflightTimeout caps every single connectivity probe
+	preflightTimeout = 15 * time.Second
+	// preflightProbeKey is the object written and removed by the bucket write test
+	preflightProbeKey = ".spinnaker-operator-preflight"
+
+	kubernetesAccountsProp = "providers.kubernetes.accounts"
+	dockerAccountsProp     = "providers.dockerRegistry.accounts"
+	storageTypeProp        = "persistentStorage.persistentStoreType"
+)
+
+// preflightCheck is one provider connectivity probe
+type preflightCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// runPreflightChecks probes the configured providers before anything is
+// generated: Kubernetes accounts, docker registries and the persistent
+// storage bucket. Failures surface as a status condition and events instead
+// of a broken Spinnaker deployed with unreachable providers
+func (d *Deployer) runPreflightChecks(ctx context.Context, svc interfaces.SpinnakerService, nSvc interfaces.SpinnakerService) error {
+	checks := d.preflightChecks(ctx, nSvc)
+	var failures []string
+	for _, c := range checks {
+		cctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+		err := c.run(cctx)
+		cancel()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", c.name, err.Error()))
+			d.evtRecorder.Eventf(svc, v1.EventTypeWarning, "PreflightFailed", "%s: %s", c.name, err.Error())
+		}
+	}
+	now := time.Now()
+	if len(failures) == 0 {
+		nSvc.GetStatus().SetCondition(interfaces.PreflightCondition, interfaces.ConditionTrue, "PreflightPassed",
+			fmt.Sprintf("%d provider check(s) passed", len(checks)), now)
+		return nil
+	}
+	msg := strings.Join(failures, "; ")
+	// The deploy stops here, persist the condition ourselves
+	svc.GetStatus().SetCondition(interfaces.PreflightCondition, interfaces.ConditionFalse, "PreflightFailed", msg, now)
+	if err := d.client.Status().Update(ctx, svc); err != nil {
+		d.log.Error(err, "unable to record preflight condition")
+	}
+	return fmt.Errorf("preflight checks failed: %s", msg)
+}
+
+// preflightChecks builds the probes for the providers found in the resolved config
+func (d *Deployer) preflightChecks(ctx context.Context, svc interfaces.SpinnakerService) []preflightCheck {
+	var checks []preflightCheck
+	cfg := svc.GetSpinnakerConfig()
+
+	if accounts, err := cfg.GetHalConfigObjectArray(ctx, kubernetesAccountsProp); err == nil {
+		for i := range accounts {
+			a := accounts[i]
+			name, _ := a["name"].(string)
+			file, _ := a["kubeconfigFile"].(string)
+			data := cfg.GetFileContent(file)
+			if file == "" || len(data) == 0 {
+				// Account uses the service account or a secret reference the
+				// operator cannot resolve here, nothing to probe
+				continue
+			}
+			ctxName, _ := a["context"].(string)
+			checks = append(checks, preflightCheck{
+				name: fmt.Sprintf("kubernetes account %s", name),
+				run: func(cctx context.Context) error {
+					return probeKubeconfig(cctx, data, ctxName)
+				},
+			})
+		}
+	}
+
+	if registries, err := cfg.GetHalConfigObjectArray(ctx, dockerAccountsProp); err == nil {
+		for i := range registries {
+			r := registries[i]
+			name, _ := r["name"].(string)
+			address, _ := r["address"].(string)
+			if address == "" {
+				continue
+			}
+			checks = append(checks, preflightCheck{
+				name: fmt.Sprintf("docker registry %s", name),
+				run: func(cctx context.Context) error {
+					return probeDockerRegistry(cctx, address)
+				},
+			})
+		}
+	}
+
+	switch storeType, _ := cfg.GetHalConfigPropString(ctx, storageTypeProp); storeType {
+	case "s3":
+		checks = append(checks, preflightCheck{
+			name: "s3 persistent storage",
+			run: func(cctx context.Context) error {
+				return d.probeS3(cctx, svc)
+			},
+		})
+	case "gcs":
+		checks = append(checks, preflightCheck{
+			name: "gcs persistent storage",
+			run: func(cctx context.Context) error {
+				return d.probeGcs(cctx, svc)
+			},
+		})
+	}
+	return checks
+}
+
+// probeKubeconfig verifies the kubeconfig builds a working client and the
+// cluster answers a namespace list
+func probeKubeconfig(ctx context.Context, data []byte, contextName string) error {
+	restConfig, err := restConfigFromKubeconfig(data, contextName)
+	if err != nil {
+		return err
+	}
+	restConfig.Timeout = preflightTimeout
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	if _, err := clientset.CoreV1().Namespaces().List(metav1.ListOptions{Limit: 1}); err != nil {
+		return fmt.Errorf("unable to list namespaces: %s", err.Error())
+	}
+	return nil
+}
+
+// probeDockerRegistry verifies the registry answers on its catalog endpoint,
+// an auth challenge counts as reachable
+func probeDockerRegistry(ctx context.Context, address string) error {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "https://" + address
+	}
+	req, err := http.NewRequest("GET", strings.TrimSuffix(address, "/")+"/v2/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("registry unreachable: %s", err.Error())
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("registry answered %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// probeS3 writes and removes a probe object in the configured bucket
+func (d *Deployer) probeS3(ctx context.Context, svc interfaces.SpinnakerService) error {
+	cfg := svc.GetSpinnakerConfig()
+	bucket, _ := cfg.GetHalConfigPropString(ctx, "persistentStorage.s3.bucket")
+	if bucket == "" {
+		return fmt.Errorf("no bucket configured")
+	}
+	region, _ := cfg.GetHalConfigPropString(ctx, "persistentStorage.s3.region")
+	endpoint, _ := cfg.GetHalConfigPropString(ctx, "persistentStorage.s3.endpoint")
+	awsConfig := aws.NewConfig()
+	if region != "" {
+		awsConfig = awsConfig.WithRegion(region)
+	}
+	if endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+	accessKey, _ := cfg.GetHalConfigPropString(ctx, "persistentStorage.s3.accessKeyId")
+	secretKey, _ := cfg.GetHalConfigPropString(ctx, "persistentStorage.s3.secretAccessKey")
+	if accessKey != "" && secretKey != "" {
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, ""))
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return err
+	}
+	client := s3.New(sess)
+	if _, err := client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(preflightProbeKey),
+		Body:   strings.NewReader("ok"),
+	}); err != nil {
+		return fmt.Errorf("bucket %s not writable: %s", bucket, err.Error())
+	}
+	_, _ = client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(preflightProbeKey),
+	})
+	return nil
+}
+
+// probeGcs writes and removes a probe object in the configured bucket
+func (d *Deployer) probeGcs(ctx context.Context, svc interfaces.SpinnakerService) error {
+	cfg := svc.GetSpinnakerConfig()
+	bucket, _ := cfg.GetHalConfigPropString(ctx, "persistentStorage.gcs.bucket")
+	if bucket == "" {
+		return fmt.Errorf("no bucket configured")
+	}
+	var opts []option.ClientOption
+	jsonPath, _ := cfg.GetHalConfigPropString(ctx, "persistentStorage.gcs.jsonPath")
+	if creds := cfg.GetFileContent(jsonPath); len(creds) > 0 {
+		opts = append(opts, option.WithCredentialsJSON(creds))
+	}
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+	obj := client.Bucket(bucket).Object(preflightProbeKey)
+	w := obj.NewWriter(ctx)
+	if _, err := w.Write([]byte("ok")); err != nil {
+		return fmt.Errorf("bucket %s not writable: %s", bucket, err.Error())
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("bucket %s not writable: %s", bucket, err.Error())
+	}
+	_ = obj.Delete(ctx)
+	return nil
+}
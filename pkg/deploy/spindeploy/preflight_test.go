@@ -0,0 +1,157 @@
+package spindeploy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func preflightSpinSvc(t *testing.T, registryAddress string) interfaces.SpinnakerService {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        dockerRegistry:
+          enabled: true
+          accounts:
+          - name: dockerhub
+            address: ` + registryAddress + `
+      persistentStorage:
+        persistentStoreType: s3
+        s3:
+          bucket: spin-bucket
+`
+	return test.ManifestToSpinService(s, t)
+}
+
+func TestPreflightChecks_built(t *testing.T) {
+	svc := preflightSpinSvc(t, "https://index.docker.io")
+	d := &Deployer{log: log.Log.WithName("spinnakerservice")}
+
+	checks := d.preflightChecks(context.TODO(), svc)
+	if assert.Equal(t, 2, len(checks)) {
+		assert.Equal(t, "docker registry dockerhub", checks[0].name)
+		assert.Equal(t, "s3 persistent storage", checks[1].name)
+	}
+}
+
+func TestPreflightChecks_noProviders(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	svc := test.ManifestToSpinService(s, t)
+	d := &Deployer{log: log.Log.WithName("spinnakerservice")}
+	assert.Empty(t, d.preflightChecks(context.TODO(), svc))
+}
+
+func TestProbeDockerRegistry(t *testing.T) {
+	// An auth challenge still proves the registry is reachable
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/", r.URL.Path)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+	assert.Nil(t, probeDockerRegistry(context.TODO(), ts.URL))
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	err := probeDockerRegistry(context.TODO(), broken.URL)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "500")
+	}
+}
+
+func TestRunPreflightChecks_failureSetsCondition(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        dockerRegistry:
+          enabled: true
+          accounts:
+          - name: dockerhub
+            address: ` + broken.URL + `
+`
+	svc := test.ManifestToSpinService(s, t)
+	rec := &record.FakeRecorder{Events: make(chan string, 10)}
+	d := &Deployer{client: test.FakeClient(t, svc), log: log.Log.WithName("spinnakerservice"), evtRecorder: rec}
+
+	err := d.runPreflightChecks(context.TODO(), svc, svc.DeepCopyInterface())
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "docker registry dockerhub")
+	}
+	c := svc.GetStatus().GetCondition(interfaces.PreflightCondition)
+	if assert.NotNil(t, c) {
+		assert.Equal(t, interfaces.ConditionFalse, c.Status)
+	}
+	assert.Contains(t, <-rec.Events, "PreflightFailed")
+}
+
+func TestRunPreflightChecks_successSetsCondition(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        dockerRegistry:
+          enabled: true
+          accounts:
+          - name: dockerhub
+            address: ` + ok.URL + `
+`
+	svc := test.ManifestToSpinService(s, t)
+	nSvc := svc.DeepCopyInterface()
+	d := &Deployer{client: test.FakeClient(t, svc), log: log.Log.WithName("spinnakerservice"), evtRecorder: &record.FakeRecorder{}}
+
+	assert.Nil(t, d.runPreflightChecks(context.TODO(), svc, nSvc))
+	c := nSvc.GetStatus().GetCondition(interfaces.PreflightCondition)
+	if assert.NotNil(t, c) {
+		assert.Equal(t, interfaces.ConditionTrue, c.Status)
+	}
+}
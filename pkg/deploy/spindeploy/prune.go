@@ -0,0 +1,293 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	yaml "gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagedResourceLabel marks every generated resource with the SpinnakerService
+// that produced it, namespace.name, so resources of services removed from the
+// config can be garbage collected
+const ManagedResourceLabel = "spinnaker.io/generated-by"
+
+// PruneDrainGraceEnvVar overrides how long to wait between deleting the
+// Service and the Deployment of a disabled service so in-flight connections
+// can drain. Accepts a Go duration, 0 disables the wait
+const PruneDrainGraceEnvVar = "PRUNE_DRAIN_GRACE"
+
+const defaultPruneDrainGrace = 5 * time.Second
+
+// integrationOverride is a config property of a dependent service that must be
+// switched off when the service it points at is disabled
+type integrationOverride struct {
+	service string
+	prop    string
+	value   interface{}
+}
+
+// disabledIntegrations lists, per disableable service, the dependent config
+// that references it. Without these the dependents keep calling a service
+// that no longer exists
+var disabledIntegrations = map[string][]integrationOverride{
+	"kayenta": {
+		{service: "orca", prop: "canary.enabled", value: false},
+	},
+	"fiat": {
+		{service: "gate", prop: "services.fiat.enabled", value: false},
+		{service: "orca", prop: "services.fiat.enabled", value: false},
+		{service: "clouddriver", prop: "services.fiat.enabled", value: false},
+		{service: "echo", prop: "services.fiat.enabled", value: false},
+		{service: "igor", prop: "services.fiat.enabled", value: false},
+	},
+	"igor": {
+		{service: "echo", prop: "services.igor.enabled", value: false},
+		{service: "orca", prop: "services.igor.enabled", value: false},
+	},
+}
+
+// markManaged labels a generated object as owned by the SpinnakerService
+func markManaged(obj metav1.Object, svc interfaces.SpinnakerService) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ManagedResourceLabel] = ManagedNamespaceValue(svc)
+	obj.SetLabels(labels)
+}
+
+// orphanSet holds resources labeled for a SpinnakerService that are no longer
+// present in the generated config, e.g. after a service was disabled in the
+// halconfig
+type orphanSet struct {
+	deployments  []*appsv1.Deployment
+	statefulSets []*appsv1.StatefulSet
+	services     []*v1.Service
+}
+
+// workloads returns the number of orphaned pod-owning resources
+func (o *orphanSet) workloads() int {
+	return len(o.deployments) + len(o.statefulSets)
+}
+
+// disabledServices returns the Spinnaker service names behind the orphaned
+// workloads, e.g. kayenta for spin-kayenta, honoring spec.naming
+func (o *orphanSet) disabledServices(svc interfaces.SpinnakerService) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(objectName string) {
+		name := util.BaseServiceName(svc, objectName)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, dep := range o.deployments {
+		add(dep.GetName())
+	}
+	for _, sts := range o.statefulSets {
+		add(sts.GetName())
+	}
+	return names
+}
+
+// findOrphans lists deployments, stateful sets and services labeled for this
+// SpinnakerService that the generated config no longer contains
+func (d *Deployer) findOrphans(ctx context.Context, gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService) (*orphanSet, error) {
+	expected := map[string]bool{}
+	namespaces := map[string]bool{svc.GetNamespace(): true}
+	for _, cfg := range gen.Config {
+		if cfg.Deployment != nil {
+			expected[fmt.Sprintf("deployment/%s", cfg.Deployment.GetName())] = true
+			namespaces[cfg.Deployment.GetNamespace()] = true
+		}
+		if cfg.Service != nil {
+			expected[fmt.Sprintf("service/%s", cfg.Service.GetName())] = true
+			namespaces[cfg.Service.GetNamespace()] = true
+		}
+		// Workloads moved to Resources, e.g. services converted to StatefulSets
+		for _, o := range cfg.Resources {
+			if sts, ok := o.(*appsv1.StatefulSet); ok {
+				expected[fmt.Sprintf("statefulset/%s", sts.GetName())] = true
+				namespaces[sts.GetNamespace()] = true
+			}
+		}
+	}
+
+	orphans := &orphanSet{}
+	sel := client.MatchingLabels{ManagedResourceLabel: ManagedNamespaceValue(svc)}
+	for ns := range namespaces {
+		if ns == "" {
+			continue
+		}
+		deps := &appsv1.DeploymentList{}
+		if err := d.client.List(ctx, deps, client.InNamespace(ns), sel); err != nil {
+			return nil, err
+		}
+		for i := range deps.Items {
+			if !expected[fmt.Sprintf("deployment/%s", deps.Items[i].GetName())] {
+				orphans.deployments = append(orphans.deployments, &deps.Items[i])
+			}
+		}
+		stss := &appsv1.StatefulSetList{}
+		if err := d.client.List(ctx, stss, client.InNamespace(ns), sel); err != nil {
+			return nil, err
+		}
+		for i := range stss.Items {
+			if !expected[fmt.Sprintf("statefulset/%s", stss.Items[i].GetName())] {
+				orphans.statefulSets = append(orphans.statefulSets, &stss.Items[i])
+			}
+		}
+		svcs := &v1.ServiceList{}
+		if err := d.client.List(ctx, svcs, client.InNamespace(ns), sel); err != nil {
+			return nil, err
+		}
+		for i := range svcs.Items {
+			if !expected[fmt.Sprintf("service/%s", svcs.Items[i].GetName())] {
+				orphans.services = append(orphans.services, &svcs.Items[i])
+			}
+		}
+	}
+	return orphans, nil
+}
+
+// deleteOrphans removes resources of disabled services in safe order: the
+// Service first so nothing new is routed to pods about to disappear, a drain
+// grace for in-flight connections, then the Deployment or StatefulSet
+func (d *Deployer) deleteOrphans(ctx context.Context, orphans *orphanSet, logger logr.Logger) error {
+	for _, s := range orphans.services {
+		logger.Info(fmt.Sprintf("pruning orphaned service %s", s.GetName()))
+		if err := d.client.Delete(ctx, s); err != nil {
+			return err
+		}
+	}
+	if len(orphans.services) > 0 && orphans.workloads() > 0 {
+		if err := waitForDrain(ctx, pruneDrainGraceFromEnv(), logger); err != nil {
+			return err
+		}
+	}
+	for _, dep := range orphans.deployments {
+		logger.Info(fmt.Sprintf("pruning orphaned deployment %s", dep.GetName()))
+		if err := d.client.Delete(ctx, dep); err != nil {
+			return err
+		}
+	}
+	for _, sts := range orphans.statefulSets {
+		logger.Info(fmt.Sprintf("pruning orphaned statefulset %s", sts.GetName()))
+		if err := d.client.Delete(ctx, sts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneOrphans deletes workloads and services labeled for this
+// SpinnakerService that are no longer present in the generated config
+func (d *Deployer) pruneOrphans(ctx context.Context, gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService, logger logr.Logger) error {
+	orphans, err := d.findOrphans(ctx, gen, svc)
+	if err != nil {
+		return err
+	}
+	return d.deleteOrphans(ctx, orphans, logger)
+}
+
+func waitForDrain(ctx context.Context, grace time.Duration, logger logr.Logger) error {
+	if grace <= 0 {
+		return nil
+	}
+	logger.Info(fmt.Sprintf("waiting %s for connections to drain", grace))
+	select {
+	case <-time.After(grace):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func pruneDrainGraceFromEnv() time.Duration {
+	if v := os.Getenv(PruneDrainGraceEnvVar); v != "" {
+		if grace, err := time.ParseDuration(v); err == nil {
+			return grace
+		}
+	}
+	return defaultPruneDrainGrace
+}
+
+// scrubDisabledIntegrations switches off config of dependent services that
+// points at services about to be pruned, e.g. Orca's canary stages when
+// Kayenta is disabled, so dependents pick up the change in the same reconcile.
+// Overrides land in the local profile which Spring merges over the base config
+func (d *Deployer) scrubDisabledIntegrations(gen *generated.SpinnakerGeneratedConfig, disabled []string, logger logr.Logger) {
+	for _, name := range disabled {
+		for _, ov := range disabledIntegrations[name] {
+			cfg, ok := gen.Config[ov.service]
+			if !ok {
+				continue
+			}
+			cm := firstConfigMap(cfg.Resources)
+			if cm == nil {
+				continue
+			}
+			key := fmt.Sprintf("%s-local.yml", ov.service)
+			merged, err := setYamlProp(cm.Data[key], ov.prop, ov.value)
+			if err != nil {
+				logger.Info(fmt.Sprintf("unable to disable %s in %s config: %s", name, ov.service, err.Error()))
+				continue
+			}
+			if cm.Data == nil {
+				cm.Data = map[string]string{}
+			}
+			cm.Data[key] = merged
+			logger.Info(fmt.Sprintf("disabled the %s integration in %s config", name, ov.service))
+		}
+	}
+}
+
+func firstConfigMap(resources []runtime.Object) *v1.ConfigMap {
+	for i := range resources {
+		if cm, ok := resources[i].(*v1.ConfigMap); ok {
+			return cm
+		}
+	}
+	return nil
+}
+
+// setYamlProp sets a dot delimited property in a yaml document, creating
+// intermediate maps as needed
+func setYamlProp(doc string, prop string, value interface{}) (string, error) {
+	root := map[interface{}]interface{}{}
+	if doc != "" {
+		if err := yaml.Unmarshal([]byte(doc), &root); err != nil {
+			return "", err
+		}
+	}
+	node := root
+	parts := strings.Split(prop, ".")
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := node[p].(map[interface{}]interface{})
+		if !ok {
+			child = map[interface{}]interface{}{}
+			node[p] = child
+		}
+		node = child
+	}
+	node[parts[len(parts)-1]] = value
+	b, err := yaml.Marshal(root)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
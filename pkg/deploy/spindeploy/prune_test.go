@@ -0,0 +1,98 @@
+package spindeploy
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestPruneOrphans(t *testing.T) {
+	assert.Nil(t, os.Setenv(PruneDrainGraceEnvVar, "0"))
+	defer func() { assert.Nil(t, os.Unsetenv(PruneDrainGraceEnvVar)) }()
+	spinsvc := test.ManifestToSpinService(dryRunManifest, t)
+	labeled := map[string]string{ManagedResourceLabel: "ns1.spinnaker"}
+	gate := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", Namespace: "ns1", Labels: labeled}}
+	kayenta := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spin-kayenta", Namespace: "ns1", Labels: labeled}}
+	kayentaSvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "spin-kayenta", Namespace: "ns1", Labels: labeled}}
+	// Converted services run as StatefulSets and are pruned the same way
+	redis := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "spin-redis", Namespace: "ns1", Labels: labeled}}
+	keptSts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "spin-minio", Namespace: "ns1", Labels: labeled}}
+	// Not labeled for this service, never touched
+	other := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "some-app", Namespace: "ns1"}}
+	fakeClient := test.FakeClient(t, gate, kayenta, kayentaSvc, redis, keptSts, other)
+	d := &Deployer{client: fakeClient, log: log.Log.WithName("spinnakerservice")}
+
+	gen := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{
+		"gate":  {Deployment: gate.DeepCopy()},
+		"minio": {Resources: []runtime.Object{keptSts.DeepCopy()}},
+	}}
+	assert.Nil(t, d.pruneOrphans(context.TODO(), gen, spinsvc, d.log))
+
+	err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "spin-kayenta"}, &appsv1.Deployment{})
+	assert.True(t, kerrors.IsNotFound(err))
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "spin-kayenta"}, &v1.Service{})
+	assert.True(t, kerrors.IsNotFound(err))
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "spin-redis"}, &appsv1.StatefulSet{})
+	assert.True(t, kerrors.IsNotFound(err))
+	assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "spin-gate"}, &appsv1.Deployment{}))
+	assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "spin-minio"}, &appsv1.StatefulSet{}))
+	assert.Nil(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "some-app"}, &appsv1.Deployment{}))
+}
+
+func TestDisabledServices_statefulSet(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(dryRunManifest, t)
+	o := &orphanSet{
+		deployments:  []*appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "spin-kayenta"}}},
+		statefulSets: []*appsv1.StatefulSet{{ObjectMeta: metav1.ObjectMeta{Name: "spin-redis"}}},
+	}
+	assert.Equal(t, []string{"kayenta", "redis"}, o.disabledServices(spinsvc))
+}
+
+func TestScrubDisabledIntegrations(t *testing.T) {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "spin-orca-files", Namespace: "ns1"},
+		Data:       map[string]string{"orca-local.yml": "tasks:\n  daysOfExecutionHistory: 90\n"},
+	}
+	gen := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{
+		"orca": {Resources: []runtime.Object{cm.DeepCopy()}},
+	}}
+	d := &Deployer{log: log.Log.WithName("spinnakerservice")}
+
+	d.scrubDisabledIntegrations(gen, []string{"kayenta"}, d.log)
+
+	scrubbed, ok := gen.Config["orca"].Resources[0].(*v1.ConfigMap)
+	if assert.True(t, ok) {
+		assert.Contains(t, scrubbed.Data["orca-local.yml"], "enabled: false")
+		// Unrelated profile content is preserved
+		assert.Contains(t, scrubbed.Data["orca-local.yml"], "daysOfExecutionHistory: 90")
+	}
+}
+
+func TestSetYamlProp(t *testing.T) {
+	out, err := setYamlProp("", "services.fiat.enabled", false)
+	assert.Nil(t, err)
+	assert.Contains(t, out, "enabled: false")
+
+	out, err = setYamlProp("canary:\n  stages: 3\n", "canary.enabled", false)
+	assert.Nil(t, err)
+	assert.Contains(t, out, "stages: 3")
+	assert.Contains(t, out, "enabled: false")
+}
+
+func TestMarkManaged(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(dryRunManifest, t)
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", Namespace: "ns1"}}
+	markManaged(dep, spinsvc)
+	assert.Equal(t, "ns1.spinnaker", dep.Labels[ManagedResourceLabel])
+}
@@ -0,0 +1,123 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultKubeconfigSecretKey = "kubeconfig"
+
+// targetClusterDeployer returns a copy of the deployer whose clients point at
+// the remote cluster configured through spec.targetCluster.kubeconfigSecret,
+// or the deployer itself when none is configured. Teams running the operator
+// in an ops cluster use this to apply the generated manifests to the cluster
+// that actually runs Spinnaker, while the CR and status stay local.
+func (d *Deployer) targetClusterDeployer(ctx context.Context, svc interfaces.SpinnakerService) (*Deployer, error) {
+	tc := svc.GetTargetClusterConfig()
+	if tc.KubeconfigSecret == "" {
+		return d, nil
+	}
+	sec := &corev1.Secret{}
+	if err := d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: tc.KubeconfigSecret}, sec); err != nil {
+		return nil, fmt.Errorf("unable to read target cluster kubeconfig secret %s: %v", tc.KubeconfigSecret, err)
+	}
+	key := tc.KubeconfigSecretKey
+	if key == "" {
+		key = defaultKubeconfigSecretKey
+	}
+	data, ok := sec.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("target cluster kubeconfig secret %s has no %s key", tc.KubeconfigSecret, key)
+	}
+	cfg, err := restConfigFromKubeconfig(data, tc.Context)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in secret %s: %v", tc.KubeconfigSecret, err)
+	}
+	rawClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	nd := *d
+	nd.rawClient = rawClient
+	nd.client = c
+	// Impersonation stacks on top of the remote cluster credentials
+	nd.restConfig = cfg
+	return &nd, nil
+}
+
+// applyDeployer resolves the deployer the manifests are applied through:
+// remote target cluster first, then the deploy service account identity
+func (d *Deployer) applyDeployer(ctx context.Context, svc interfaces.SpinnakerService) (*Deployer, error) {
+	nd, err := d.targetClusterDeployer(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	return nd.impersonatingDeployer(svc)
+}
+
+// restConfigFromKubeconfig builds a rest config from raw kubeconfig bytes,
+// optionally selecting a context other than the current one
+func restConfigFromKubeconfig(data []byte, contextName string) (*rest.Config, error) {
+	cc, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, err
+	}
+	overrides := &clientcmd.ConfigOverrides{}
+	if contextName != "" {
+		overrides.CurrentContext = contextName
+	}
+	return clientcmd.NewDefaultClientConfig(*cc, overrides).ClientConfig()
+}
+
+// stripOwnerReferences removes the owner references pointing at the
+// SpinnakerService from the manifest set. The CR only exists in the management
+// cluster, a dangling reference would make the remote garbage collector delete
+// the workloads right after they are applied
+func stripOwnerReferences(gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService) {
+	for _, cfg := range gen.Config {
+		if cfg.Deployment != nil {
+			dropOwnerRef(&cfg.Deployment.ObjectMeta, svc)
+		}
+		if cfg.Service != nil {
+			dropOwnerRef(&cfg.Service.ObjectMeta, svc)
+		}
+		for i := range cfg.Resources {
+			if m, ok := cfg.Resources[i].(metav1.Object); ok {
+				refs := m.GetOwnerReferences()
+				m.SetOwnerReferences(filterOwnerRefs(refs, svc))
+			}
+		}
+	}
+}
+
+func dropOwnerRef(m *metav1.ObjectMeta, svc interfaces.SpinnakerService) {
+	m.OwnerReferences = filterOwnerRefs(m.OwnerReferences, svc)
+}
+
+func filterOwnerRefs(refs []metav1.OwnerReference, svc interfaces.SpinnakerService) []metav1.OwnerReference {
+	if len(refs) == 0 {
+		return refs
+	}
+	kept := make([]metav1.OwnerReference, 0, len(refs))
+	for _, r := range refs {
+		if r.Kind == "SpinnakerService" && r.Name == svc.GetName() {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
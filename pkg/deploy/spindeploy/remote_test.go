@@ -0,0 +1,139 @@
+package spindeploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var remoteManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  targetCluster:
+    kubeconfigSecret: target-kubeconfig
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+var kubeconfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- name: target
+  cluster:
+    server: https://target.example.com
+- name: other
+  cluster:
+    server: https://other.example.com
+contexts:
+- name: target
+  context:
+    cluster: target
+    user: admin
+- name: other
+  context:
+    cluster: other
+    user: admin
+current-context: target
+users:
+- name: admin
+  user:
+    token: abc
+`
+
+func TestTargetClusterDeployer_notConfigured(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	nd, err := d.targetClusterDeployer(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.Equal(t, d, nd)
+}
+
+func TestTargetClusterDeployer_missingSecret(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(remoteManifest, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	_, err := d.targetClusterDeployer(context.TODO(), spinsvc)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "target-kubeconfig")
+	}
+}
+
+func TestTargetClusterDeployer_missingKey(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(remoteManifest, t)
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "target-kubeconfig"},
+		Data:       map[string][]byte{"config": []byte(kubeconfig)},
+	}
+	d := &Deployer{client: test.FakeClient(t, sec), log: log.Log.WithName("spinnakerservice")}
+	_, err := d.targetClusterDeployer(context.TODO(), spinsvc)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "no kubeconfig key")
+	}
+}
+
+func TestTargetClusterDeployer_badKubeconfig(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(remoteManifest, t)
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "target-kubeconfig"},
+		Data:       map[string][]byte{"kubeconfig": []byte("not a kubeconfig")},
+	}
+	d := &Deployer{client: test.FakeClient(t, sec), log: log.Log.WithName("spinnakerservice")}
+	_, err := d.targetClusterDeployer(context.TODO(), spinsvc)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid kubeconfig")
+	}
+}
+
+func TestRestConfigFromKubeconfig(t *testing.T) {
+	cfg, err := restConfigFromKubeconfig([]byte(kubeconfig), "")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "https://target.example.com", cfg.Host)
+	}
+	cfg, err = restConfigFromKubeconfig([]byte(kubeconfig), "other")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "https://other.example.com", cfg.Host)
+	}
+}
+
+func TestStripOwnerReferences(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(remoteManifest, t)
+	refs := []metav1.OwnerReference{
+		{Kind: "SpinnakerService", Name: "spinnaker"},
+		{Kind: "Deployment", Name: "spin-gate"},
+	}
+	gen := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{
+		"gate": {
+			Deployment: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", OwnerReferences: refs}},
+			Service:    &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate", OwnerReferences: refs}},
+		},
+	}}
+	stripOwnerReferences(gen, spinsvc)
+	dep := gen.Config["gate"].Deployment
+	if assert.Equal(t, 1, len(dep.OwnerReferences)) {
+		assert.Equal(t, "Deployment", dep.OwnerReferences[0].Kind)
+	}
+	assert.Equal(t, 1, len(gen.Config["gate"].Service.OwnerReferences))
+}
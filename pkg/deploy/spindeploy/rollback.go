@@ -0,0 +1,243 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	yamlv2 "gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	lastGoodSecretKey = "manifests"
+	// candidateSecretKey holds the manifest set of the most recent successful
+	// apply until the status checker reports the services healthy, only then
+	// does it become the rollback target
+	candidateSecretKey = "candidateManifests"
+	// candidateGenerationKey records the spec generation the candidate was
+	// generated from so a stale candidate is never promoted
+	candidateGenerationKey = "candidateGeneration"
+	// rolledBackGenerationKey records the spec generation that was already rolled
+	// back so a failing deployment is only rolled back once
+	rolledBackGenerationKey = "rolledBackGeneration"
+	// statusFailure mirrors the Failure status written by the status checker once
+	// pods exceed their maximum waiting time without becoming ready
+	statusFailure = "Failure"
+	// statusOk mirrors the OK status written by the status checker when all
+	// deployed services are healthy
+	statusOk = "OK"
+	// statusUpdating mirrors the Updating status of the status checker, used to
+	// hand a previously frozen change over to the regular rollout tracking
+	statusUpdating = "Updating"
+)
+
+// saveCandidateManifests snapshots the manifest set that was just applied
+// successfully. Applying cleanly says nothing about the rollout's health, so
+// the snapshot is only a candidate: promoteLastGoodManifests turns it into the
+// rollback target once the status checker reports the services healthy. Until
+// then the previous healthy snapshot stays in place.
+func (d *Deployer) saveCandidateManifests(ctx context.Context, scheme *runtime.Scheme, svc interfaces.SpinnakerService, gen *generated.SpinnakerGeneratedConfig) error {
+	b, err := marshalGeneratedConfig(scheme, gen)
+	if err != nil {
+		return err
+	}
+	generation := []byte(strconv.FormatInt(svc.GetGeneration(), 10))
+	name := getLastGoodSecretName(svc)
+	sec := &v1.Secret{}
+	err = d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: name}, sec)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		sec = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: svc.GetNamespace()},
+			Data:       map[string][]byte{candidateSecretKey: b, candidateGenerationKey: generation},
+		}
+		return d.client.Create(ctx, sec)
+	}
+	if sec.Data == nil {
+		sec.Data = map[string][]byte{}
+	}
+	sec.Data[candidateSecretKey] = b
+	sec.Data[candidateGenerationKey] = generation
+	return d.client.Update(ctx, sec)
+}
+
+// promoteLastGoodManifests makes the candidate snapshot the rollback target
+// once the deployed generation is reported healthy. A healthy deployment may
+// also be rolled back again should a future one fail.
+func (d *Deployer) promoteLastGoodManifests(ctx context.Context, svc interfaces.SpinnakerService) error {
+	st := svc.GetStatus()
+	if st.Status != statusOk || st.ObservedGeneration != svc.GetGeneration() {
+		return nil
+	}
+	sec := &v1.Secret{}
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: getLastGoodSecretName(svc)}, sec)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if len(sec.Data[candidateSecretKey]) == 0 {
+		return nil
+	}
+	if gen, _ := strconv.ParseInt(string(sec.Data[candidateGenerationKey]), 10, 64); gen != svc.GetGeneration() {
+		return nil
+	}
+	sec.Data[lastGoodSecretKey] = sec.Data[candidateSecretKey]
+	delete(sec.Data, candidateSecretKey)
+	delete(sec.Data, candidateGenerationKey)
+	delete(sec.Data, rolledBackGenerationKey)
+	return d.client.Update(ctx, sec)
+}
+
+// marshalGeneratedConfig serializes the manifest set with apiVersion and kind on every
+// object so the generated config parser can read it back. Objects of types not
+// registered in the scheme (e.g. monitoring CRs attached by transformers) are left out,
+// the parser cannot decode them.
+func marshalGeneratedConfig(scheme *runtime.Scheme, gen *generated.SpinnakerGeneratedConfig) ([]byte, error) {
+	out := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{}}
+	for n, cfg := range gen.Config {
+		c := generated.ServiceConfig{Deployment: cfg.Deployment, Service: cfg.Service}
+		if c.Deployment != nil {
+			if err := ensureGVK(scheme, c.Deployment); err != nil {
+				return nil, err
+			}
+		}
+		if c.Service != nil {
+			if err := ensureGVK(scheme, c.Service); err != nil {
+				return nil, err
+			}
+		}
+		for _, o := range cfg.Resources {
+			if err := ensureGVK(scheme, o); err != nil {
+				continue
+			}
+			if !scheme.Recognizes(o.GetObjectKind().GroupVersionKind()) {
+				continue
+			}
+			c.Resources = append(c.Resources, o)
+		}
+		out.Config[n] = c
+	}
+	return yaml.Marshal(out)
+}
+
+// ensureGVK fills in the type metadata from the scheme when the object does not carry it
+func ensureGVK(scheme *runtime.Scheme, o runtime.Object) error {
+	if !o.GetObjectKind().GroupVersionKind().Empty() {
+		return nil
+	}
+	gvks, _, err := scheme.ObjectKinds(o)
+	if err != nil {
+		return err
+	}
+	if len(gvks) == 0 {
+		return fmt.Errorf("unable to determine the kind of %T", o)
+	}
+	o.GetObjectKind().SetGroupVersionKind(gvks[0])
+	return nil
+}
+
+// getLastGoodManifests returns the saved manifest set and the generation already rolled
+// back, nil if no snapshot exists
+func (d *Deployer) getLastGoodManifests(ctx context.Context, svc interfaces.SpinnakerService) (*generated.SpinnakerGeneratedConfig, int64, error) {
+	sec := &v1.Secret{}
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: getLastGoodSecretName(svc)}, sec)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	rolledBack, _ := strconv.ParseInt(string(sec.Data[rolledBackGenerationKey]), 10, 64)
+	// The secret may only hold an unpromoted candidate yet
+	if len(sec.Data[lastGoodSecretKey]) == 0 {
+		return nil, rolledBack, nil
+	}
+	gen := &generated.SpinnakerGeneratedConfig{}
+	if err := yamlv2.Unmarshal(sec.Data[lastGoodSecretKey], gen); err != nil {
+		return nil, 0, fmt.Errorf("unable to parse last known good manifests from secret %s: %w", getLastGoodSecretName(svc), err)
+	}
+	return gen, rolledBack, nil
+}
+
+// rollbackOnFailure re-applies the last known good manifest set after a failed apply so
+// a partially written configuration does not linger. Best effort, the original error is
+// always returned.
+func (d *Deployer) rollbackOnFailure(ctx context.Context, scheme *runtime.Scheme, svc interfaces.SpinnakerService, logger logr.Logger, cause error) error {
+	gen, _, err := d.getLastGoodManifests(ctx, svc)
+	if err != nil {
+		d.log.Error(err, "unable to load the last known good manifests, skipping rollback")
+		return cause
+	}
+	if gen == nil {
+		return cause
+	}
+	if err := d.deployConfig(ctx, scheme, gen, svc, logger); err != nil {
+		d.evtRecorder.Eventf(svc, v1.EventTypeWarning, "RollbackFailed", "Unable to roll back to the last known good manifests: %s", err.Error())
+		return cause
+	}
+	d.evtRecorder.Eventf(svc, v1.EventTypeWarning, "RollbackPerformed", "Deployment failed (%s), rolled back to the last known good manifests", cause.Error())
+	return cause
+}
+
+// rollbackIfStuck re-applies the last known good manifest set when the current
+// deployment never became healthy: the status checker marks the service Failure once
+// pods exceed their maximum waiting time. Each spec generation is rolled back at most
+// once so the user can still push a fix.
+func (d *Deployer) rollbackIfStuck(ctx context.Context, scheme *runtime.Scheme, svc interfaces.SpinnakerService) (bool, error) {
+	st := svc.GetStatus()
+	if st.Status != statusFailure || st.ObservedGeneration != svc.GetGeneration() {
+		return false, nil
+	}
+	gen, rolledBack, err := d.getLastGoodManifests(ctx, svc)
+	if err != nil || gen == nil {
+		return false, err
+	}
+	if rolledBack == svc.GetGeneration() {
+		return false, nil
+	}
+	rLogger := d.log.WithValues("Service", svc.GetName())
+	rLogger.Info("deployment never became healthy, rolling back to the last known good manifests")
+	if err := d.deployConfig(ctx, scheme, gen, svc, rLogger); err != nil {
+		d.evtRecorder.Eventf(svc, v1.EventTypeWarning, "RollbackFailed", "Unable to roll back to the last known good manifests: %s", err.Error())
+		return false, err
+	}
+	if err := d.markRolledBack(ctx, svc); err != nil {
+		return false, err
+	}
+	d.evtRecorder.Eventf(svc, v1.EventTypeWarning, "RollbackPerformed", "Services never became healthy, rolled back to the last known good manifests")
+	return true, nil
+}
+
+// markRolledBack records the generation just rolled back on the snapshot secret
+func (d *Deployer) markRolledBack(ctx context.Context, svc interfaces.SpinnakerService) error {
+	sec := &v1.Secret{}
+	if err := d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: getLastGoodSecretName(svc)}, sec); err != nil {
+		return err
+	}
+	if sec.Data == nil {
+		sec.Data = map[string][]byte{}
+	}
+	sec.Data[rolledBackGenerationKey] = []byte(strconv.FormatInt(svc.GetGeneration(), 10))
+	// The candidate of this generation never became healthy, it must not be
+	// promoted once the rolled back manifests report healthy again
+	delete(sec.Data, candidateSecretKey)
+	delete(sec.Data, candidateGenerationKey)
+	return d.client.Update(ctx, sec)
+}
+
+func getLastGoodSecretName(svc interfaces.SpinnakerService) string {
+	return fmt.Sprintf("%s-last-good-manifests", svc.GetName())
+}
@@ -0,0 +1,133 @@
+package spindeploy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var rollbackManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+  generation: 2
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func rollbackGenConfig(t *testing.T) *generated.SpinnakerGeneratedConfig {
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "transformer/testdata/input_deployment.yml", t)
+	// An unregistered CR cannot be read back and must be left out of the snapshot
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion("monitoring.coreos.com/v1")
+	cr.SetKind("PrometheusRule")
+	cr.SetName("spinnaker-slo-rules")
+	cfg := gen.Config["gate"]
+	cfg.Resources = append(cfg.Resources, cr)
+	gen.Config["gate"] = cfg
+	return gen
+}
+
+func TestSaveAndPromoteLastGoodManifests(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(rollbackManifest, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	gen := rollbackGenConfig(t)
+
+	if !assert.Nil(t, d.saveCandidateManifests(context.TODO(), scheme.Scheme, spinsvc, gen)) {
+		return
+	}
+	// Applying cleanly is not enough, the snapshot is not a rollback target yet
+	loaded, _, err := d.getLastGoodManifests(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.Nil(t, loaded)
+
+	// Not promoted while the rollout is not reported healthy
+	spinsvc.GetStatus().Status = statusFailure
+	spinsvc.GetStatus().ObservedGeneration = spinsvc.GetGeneration()
+	assert.Nil(t, d.promoteLastGoodManifests(context.TODO(), spinsvc))
+	loaded, _, err = d.getLastGoodManifests(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.Nil(t, loaded)
+
+	spinsvc.GetStatus().Status = statusOk
+	assert.Nil(t, d.promoteLastGoodManifests(context.TODO(), spinsvc))
+	loaded, rolledBack, err := d.getLastGoodManifests(context.TODO(), spinsvc)
+	if !assert.Nil(t, err) || !assert.NotNil(t, loaded) {
+		return
+	}
+	assert.Equal(t, int64(0), rolledBack)
+	if assert.NotNil(t, loaded.Config["gate"].Deployment) {
+		assert.Equal(t, "spin-gate", loaded.Config["gate"].Deployment.GetName())
+	}
+	assert.Equal(t, 0, len(loaded.Config["gate"].Resources))
+}
+
+func TestPromoteLastGoodManifests_staleCandidateAfterRollback(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(rollbackManifest, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	assert.Nil(t, d.saveCandidateManifests(context.TODO(), scheme.Scheme, spinsvc, rollbackGenConfig(t)))
+	// The generation was rolled back, its candidate is dropped and must not be
+	// promoted when the rolled back manifests become healthy again
+	assert.Nil(t, d.markRolledBack(context.TODO(), spinsvc))
+
+	spinsvc.GetStatus().Status = statusOk
+	spinsvc.GetStatus().ObservedGeneration = spinsvc.GetGeneration()
+	assert.Nil(t, d.promoteLastGoodManifests(context.TODO(), spinsvc))
+	loaded, rolledBack, err := d.getLastGoodManifests(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.Nil(t, loaded)
+	assert.Equal(t, spinsvc.GetGeneration(), rolledBack)
+}
+
+func TestGetLastGoodManifests_none(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(rollbackManifest, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	gen, _, err := d.getLastGoodManifests(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.Nil(t, gen)
+}
+
+func TestRollbackIfStuck_healthyService(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(rollbackManifest, t)
+	spinsvc.GetStatus().Status = "OK"
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	rolled, err := d.rollbackIfStuck(context.TODO(), scheme.Scheme, spinsvc)
+	assert.Nil(t, err)
+	assert.False(t, rolled)
+}
+
+func TestRollbackIfStuck_alreadyRolledBack(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(rollbackManifest, t)
+	spinsvc.GetStatus().Status = statusOk
+	spinsvc.GetStatus().ObservedGeneration = spinsvc.GetGeneration()
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice"), evtRecorder: &record.FakeRecorder{}}
+	assert.Nil(t, d.saveCandidateManifests(context.TODO(), scheme.Scheme, spinsvc, rollbackGenConfig(t)))
+	assert.Nil(t, d.promoteLastGoodManifests(context.TODO(), spinsvc))
+	assert.Nil(t, d.markRolledBack(context.TODO(), spinsvc))
+	spinsvc.GetStatus().Status = statusFailure
+
+	rolled, err := d.rollbackIfStuck(context.TODO(), scheme.Scheme, spinsvc)
+	assert.Nil(t, err)
+	assert.False(t, rolled)
+}
+
+func TestRollbackOnFailure_noSnapshot(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(rollbackManifest, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice"), evtRecorder: &record.FakeRecorder{}}
+	cause := errors.New("apply failed")
+	err := d.rollbackOnFailure(context.TODO(), scheme.Scheme, spinsvc, d.log, cause)
+	assert.Equal(t, cause, err)
+}
@@ -3,10 +3,13 @@ package spindeploy
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
 
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/chaos"
 	"github.com/armory/spinnaker-operator/pkg/generated"
 	"github.com/go-logr/logr"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -17,8 +20,80 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-// TransformManifests adjusts settings to the configuration
-func (d *Deployer) deployConfig(ctx context.Context, scheme *runtime.Scheme, gen *generated.SpinnakerGeneratedConfig, logger logr.Logger) error {
+// applyFailureCollector accumulates apply errors when the failure policy tolerates them
+type applyFailureCollector struct {
+	tolerate    bool
+	maxFailures int
+	failures    []error
+}
+
+func newApplyFailureCollector(p *interfaces.ApplyPolicyConfig) *applyFailureCollector {
+	return &applyFailureCollector{tolerate: p.FailurePolicy == interfaces.ContinuePolicy, maxFailures: p.MaxFailures}
+}
+
+// handle returns a non-nil error when the apply should stop
+func (c *applyFailureCollector) handle(err error) error {
+	if err == nil {
+		return nil
+	}
+	if !c.tolerate {
+		return err
+	}
+	c.failures = append(c.failures, err)
+	if c.maxFailures > 0 && len(c.failures) >= c.maxFailures {
+		return c.aggregate()
+	}
+	return nil
+}
+
+func (c *applyFailureCollector) aggregate() error {
+	if len(c.failures) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("%d manifest(s) failed to apply:", len(c.failures))
+	for _, e := range c.failures {
+		msg = fmt.Sprintf("%s\n  %s", msg, e.Error())
+	}
+	return errors.New(msg)
+}
+
+// deployConfig applies the full manifest set and cleans up orphaned resources
+func (d *Deployer) deployConfig(ctx context.Context, scheme *runtime.Scheme, gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService, logger logr.Logger) error {
+	// GitOps mode: commit the manifests to the configured repository and let
+	// the GitOps tool own the rollout
+	if c := svc.GetGitOpsConfig(); c.Enabled {
+		return d.exportToGit(ctx, scheme, gen, svc, logger)
+	}
+	// Apply to the remote target cluster and through the dedicated deploy
+	// identity when they are configured
+	d, err := d.applyDeployer(ctx, svc)
+	if err != nil {
+		return err
+	}
+	if svc.GetTargetClusterConfig().KubeconfigSecret != "" {
+		stripOwnerReferences(gen, svc)
+	}
+	// Services removed from the config are cleaned up after the apply, but
+	// their dependents must stop referencing them before they go away
+	orphans, err := d.findOrphans(ctx, gen, svc)
+	if err != nil {
+		return err
+	}
+	d.scrubDisabledIntegrations(gen, orphans.disabledServices(svc), logger)
+	if err := d.applyConfig(ctx, scheme, gen, svc, logger); err != nil {
+		return err
+	}
+	return d.deleteOrphans(ctx, orphans, logger)
+}
+
+// applyConfig saves the given manifests without pruning anything, so a subset
+// (e.g. an upgrade stage) can be applied on its own
+func (d *Deployer) applyConfig(ctx context.Context, scheme *runtime.Scheme, gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService, logger logr.Logger) error {
+	collector := newApplyFailureCollector(svc.GetApplyPolicyConfig())
+	stamper, err := newComplianceStamper(svc)
+	if err != nil {
+		return err
+	}
 	// Set SpinnakerService instance as the owner and controller
 	count := 0
 	for _, v := range gen.Config {
@@ -39,19 +114,25 @@ func (d *Deployer) deployConfig(ctx context.Context, scheme *runtime.Scheme, gen
 		s := gen.Config[k]
 		if s.Deployment != nil {
 			logger.Info(fmt.Sprintf("saving deployment manifest for %s", k))
-			if err := d.saveObject(ctx, s.Deployment, logger); err != nil {
+			markManaged(s.Deployment, svc)
+			stamper.stamp(s.Deployment)
+			if err := collector.handle(d.saveObject(ctx, s.Deployment, logger)); err != nil {
 				return err
 			}
 		}
 		if s.Service != nil {
 			logger.Info(fmt.Sprintf("saving service manifest for %s", k))
-			if err := d.saveObject(ctx, s.Service, logger); err != nil {
+			markManaged(s.Service, svc)
+			stamper.stamp(s.Service)
+			if err := collector.handle(d.saveObject(ctx, s.Service, logger)); err != nil {
 				return err
 			}
 		}
 		for i := range s.Resources {
 			o, ok := s.Resources[i].(metav1.Object)
 			if ok {
+				markManaged(o, svc)
+				stamper.stamp(o)
 				logger.Info(fmt.Sprintf("saving resource manifest %s for %s", o.GetName(), k))
 				// Set SpinnakerService instance as the owner and controller
 				if s.Deployment != nil {
@@ -60,21 +141,24 @@ func (d *Deployer) deployConfig(ctx context.Context, scheme *runtime.Scheme, gen
 					}
 				}
 			}
-			if err := d.saveObject(ctx, s.Resources[i], logger); err != nil {
+			if err := collector.handle(d.saveObject(ctx, s.Resources[i], logger)); err != nil {
 				return err
 			}
 		}
 		for _, o := range s.ToDelete {
 			logger.Info(fmt.Sprintf("deleting resource manifest for %s", k))
-			if err := d.deleteObject(ctx, o); err != nil {
+			if err := collector.handle(d.deleteObject(ctx, o)); err != nil {
 				return err
 			}
 		}
 	}
-	return nil
+	return collector.aggregate()
 }
 
 func (d *Deployer) saveObject(ctx context.Context, obj runtime.Object, logger logr.Logger) error {
+	if err := chaos.Fire(ctx, chaos.PointApply); err != nil {
+		return err
+	}
 	// Check if it exists
 	if err := d.patch(ctx, obj); err != nil {
 		logger.Error(err, fmt.Sprintf("Unable to save object: %v", obj))
@@ -112,12 +196,17 @@ func (d *Deployer) patch(ctx context.Context, modifiedRaw runtime.Object) error
 		i = d.rawClient.AppsV1beta1().RESTClient()
 	case "networking.k8s.io/v1":
 		i = d.rawClient.NetworkingV1().RESTClient()
+	case "networking.k8s.io/v1beta1":
+		i = d.rawClient.NetworkingV1beta1().RESTClient()
 	case "storage.k8s.io/v1":
 		i = d.rawClient.StorageV1().RESTClient()
 	case "authorization.k8s.io/v":
 		i = d.rawClient.AuthorizationV1().RESTClient()
 	case "authentication.k8s.io/v1":
 		i = d.rawClient.AuthenticationV1().RESTClient()
+	case "monitoring.coreos.com/v1":
+		// CRD group without a typed client, apply with the generic client
+		return d.applyWithClient(ctx, modifiedRaw, modified)
 	default:
 		return fmt.Errorf("Unable to find a REST interface for %s", gvk.String())
 	}
@@ -157,6 +246,25 @@ func (d *Deployer) patch(ctx context.Context, modifiedRaw runtime.Object) error
 		Into(modifiedRaw)
 }
 
+// applyWithClient creates or updates an object of a CRD group through the
+// controller-runtime client instead of the typed REST clients
+func (d *Deployer) applyWithClient(ctx context.Context, modifiedRaw runtime.Object, modified metav1.Object) error {
+	originalRaw := modifiedRaw.DeepCopyObject()
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: modified.GetNamespace(), Name: modified.GetName()}, originalRaw)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return d.client.Create(ctx, modifiedRaw)
+		}
+		return err
+	}
+	original, ok := originalRaw.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("unable to cast %s to metav1.Object", originalRaw.GetObjectKind().GroupVersionKind().String())
+	}
+	modified.SetResourceVersion(original.GetResourceVersion())
+	return d.client.Update(ctx, modifiedRaw)
+}
+
 // createDeleteJson creates a json with potential fields to be removed from the original object
 func (d *Deployer) createDeleteJson(modifiedRaw runtime.Object, originalRaw runtime.Object) ([]byte, error) {
 	original, ok := originalRaw.(metav1.Object)
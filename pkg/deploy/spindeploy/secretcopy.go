@@ -0,0 +1,202 @@
+package spindeploy
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SecretCopyLabel marks secrets copied into the target namespace with the
+	// SpinnakerService that needed them, namespace.name, so stale copies can be
+	// garbage collected
+	SecretCopyLabel = "spinnaker.io/secret-copy"
+	// SecretCopySourceAnnotation records the name of the source secret
+	SecretCopySourceAnnotation = "spinnaker.io/copied-from"
+	secretCopyHashLen          = 8
+)
+
+// propagateSecrets copies the secrets referenced by the generated deployments
+// into the target namespace, so users don't have to pre-copy credentials when
+// deploying outside the SpinnakerService's namespace. Copies get a content
+// hashed name and references are rewritten; copies no longer referenced are
+// garbage collected.
+func (d *Deployer) propagateSecrets(ctx context.Context, gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService, logger logr.Logger) error {
+	targetNs := svc.GetTargetNamespaceConfig().Name
+	if targetNs == "" || targetNs == svc.GetNamespace() {
+		return nil
+	}
+	names := collectSecretNames(gen)
+	keep := map[string]bool{}
+	for _, name := range names {
+		sec := &v1.Secret{}
+		if err := d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: name}, sec); err != nil {
+			if kerrors.IsNotFound(err) {
+				return fmt.Errorf("secret %s referenced by the generated config does not exist in namespace %s", name, svc.GetNamespace())
+			}
+			return err
+		}
+		copied := copiedSecretName(name, sec)
+		keep[copied] = true
+		if err := d.ensureSecretCopy(ctx, sec, copied, targetNs, svc, logger); err != nil {
+			return err
+		}
+		renameSecretRefs(gen, name, copied)
+	}
+	return d.gcSecretCopies(ctx, targetNs, keep, svc, logger)
+}
+
+// collectSecretNames returns the secrets referenced by the generated
+// deployments, through volumes, env vars or envFrom, sorted for determinism.
+// Secrets that are themselves part of the generated config are excluded, they
+// are applied to the target namespace alongside the deployments.
+func collectSecretNames(gen *generated.SpinnakerGeneratedConfig) []string {
+	generatedSecrets := map[string]bool{}
+	for _, cfg := range gen.Config {
+		for k := range cfg.Resources {
+			if sec, ok := cfg.Resources[k].(*v1.Secret); ok {
+				generatedSecrets[sec.GetName()] = true
+			}
+		}
+	}
+	found := map[string]bool{}
+	for _, cfg := range gen.Config {
+		if cfg.Deployment == nil {
+			continue
+		}
+		spec := cfg.Deployment.Spec.Template.Spec
+		for _, v := range spec.Volumes {
+			if v.Secret != nil {
+				found[v.Secret.SecretName] = true
+			}
+		}
+		for _, c := range spec.Containers {
+			for _, e := range c.Env {
+				if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+					found[e.ValueFrom.SecretKeyRef.Name] = true
+				}
+			}
+			for _, e := range c.EnvFrom {
+				if e.SecretRef != nil {
+					found[e.SecretRef.Name] = true
+				}
+			}
+		}
+	}
+	names := make([]string, 0, len(found))
+	for n := range found {
+		if !generatedSecrets[n] {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// copiedSecretName derives the name of the copy from the content of the
+// source, so a credential rotation produces a new copy and a rolling update
+func copiedSecretName(name string, sec *v1.Secret) string {
+	keys := make([]string, 0, len(sec.Data))
+	for k := range sec.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := md5.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write(sec.Data[k])
+	}
+	return fmt.Sprintf("%s-%x", name, h.Sum(nil))[:len(name)+1+secretCopyHashLen]
+}
+
+// ensureSecretCopy creates the copy in the target namespace if missing, the
+// content hash in the name makes an existing copy up to date by construction
+func (d *Deployer) ensureSecretCopy(ctx context.Context, src *v1.Secret, copied, targetNs string, svc interfaces.SpinnakerService, logger logr.Logger) error {
+	existing := &v1.Secret{}
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: targetNs, Name: copied}, existing)
+	if err == nil {
+		return nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return err
+	}
+	cp := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      copied,
+			Namespace: targetNs,
+			Labels:    map[string]string{SecretCopyLabel: ManagedNamespaceValue(svc)},
+			Annotations: map[string]string{
+				SecretCopySourceAnnotation: fmt.Sprintf("%s/%s", src.GetNamespace(), src.GetName()),
+			},
+		},
+		Type: src.Type,
+		Data: src.Data,
+	}
+	logger.Info(fmt.Sprintf("copying secret %s to namespace %s as %s", src.GetName(), targetNs, copied))
+	return d.client.Create(ctx, cp)
+}
+
+// renameSecretRefs points every reference to the source secret at its copy
+func renameSecretRefs(gen *generated.SpinnakerGeneratedConfig, name, copied string) {
+	for _, cfg := range gen.Config {
+		if cfg.Deployment == nil {
+			continue
+		}
+		renameSecretRefsInDeployment(cfg.Deployment, name, copied)
+	}
+}
+
+func renameSecretRefsInDeployment(dep *appsv1.Deployment, name, copied string) {
+	spec := &dep.Spec.Template.Spec
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Secret != nil && spec.Volumes[i].Secret.SecretName == name {
+			spec.Volumes[i].Secret.SecretName = copied
+		}
+	}
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		for j := range c.Env {
+			e := c.Env[j]
+			if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil && e.ValueFrom.SecretKeyRef.Name == name {
+				c.Env[j].ValueFrom.SecretKeyRef.Name = copied
+			}
+		}
+		for j := range c.EnvFrom {
+			if c.EnvFrom[j].SecretRef != nil && c.EnvFrom[j].SecretRef.Name == name {
+				c.EnvFrom[j].SecretRef.Name = copied
+			}
+		}
+	}
+}
+
+// gcSecretCopies deletes copies made for this SpinnakerService that are no
+// longer referenced, e.g. after a credential rotation or a removed account
+func (d *Deployer) gcSecretCopies(ctx context.Context, targetNs string, keep map[string]bool, svc interfaces.SpinnakerService, logger logr.Logger) error {
+	list := &v1.SecretList{}
+	sel := client.MatchingLabels{SecretCopyLabel: ManagedNamespaceValue(svc)}
+	if err := d.client.List(ctx, list, client.InNamespace(targetNs), sel); err != nil {
+		return err
+	}
+	for i := range list.Items {
+		sec := &list.Items[i]
+		if keep[sec.GetName()] {
+			continue
+		}
+		logger.Info(fmt.Sprintf("garbage collecting secret copy %s", sec.GetName()))
+		if err := d.client.Delete(ctx, sec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
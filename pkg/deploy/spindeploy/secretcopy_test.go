@@ -0,0 +1,142 @@
+package spindeploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var secretCopyManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  targetNamespace:
+    name: spinnaker-system
+    create: true
+`
+
+func genConfigWithSecretRefs(t *testing.T) *generated.SpinnakerGeneratedConfig {
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "clouddriver", "transformer/testdata/input_deployment.yml", t)
+	// Halyard generates this secret alongside the deployment, it must not be copied
+	cfg := gen.Config["clouddriver"]
+	cfg.Resources = append(cfg.Resources,
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate-files-1330373132", Namespace: "ns1"}},
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate-files-2090535411", Namespace: "ns1"}},
+	)
+	gen.Config["clouddriver"] = cfg
+	dep := gen.Config["clouddriver"].Deployment
+	spec := &dep.Spec.Template.Spec
+	spec.Containers[0].Env = append(spec.Containers[0].Env, v1.EnvVar{
+		Name: "AWS_SECRET_ACCESS_KEY",
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "aws-creds"},
+				Key:                  "secretKey",
+			},
+		},
+	})
+	spec.Volumes = append(spec.Volumes, v1.Volume{
+		Name: "volume-aws-creds",
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: "aws-creds"},
+		},
+	})
+	return gen
+}
+
+func TestPropagateSecrets_copiesAndRenames(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(secretCopyManifest, t)
+	src := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws-creds", Namespace: "ns1"},
+		Data:       map[string][]byte{"secretKey": []byte("s3cr3t")},
+	}
+	d := &Deployer{client: test.FakeClient(t, src), log: log.Log.WithName("spinnakerservice")}
+	gen := genConfigWithSecretRefs(t)
+
+	if !assert.Nil(t, d.propagateSecrets(context.TODO(), gen, spinsvc, d.log)) {
+		return
+	}
+
+	copied := copiedSecretName("aws-creds", src)
+	sec := &v1.Secret{}
+	if assert.Nil(t, d.client.Get(context.TODO(), types.NamespacedName{Namespace: "spinnaker-system", Name: copied}, sec)) {
+		assert.Equal(t, []byte("s3cr3t"), sec.Data["secretKey"])
+		assert.Equal(t, "ns1.spinnaker", sec.Labels[SecretCopyLabel])
+		assert.Equal(t, "ns1/aws-creds", sec.Annotations[SecretCopySourceAnnotation])
+	}
+
+	spec := gen.Config["clouddriver"].Deployment.Spec.Template.Spec
+	assert.Equal(t, copied, spec.Containers[0].Env[len(spec.Containers[0].Env)-1].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, copied, spec.Volumes[len(spec.Volumes)-1].Secret.SecretName)
+}
+
+func TestPropagateSecrets_gcStaleCopies(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(secretCopyManifest, t)
+	src := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws-creds", Namespace: "ns1"},
+		Data:       map[string][]byte{"secretKey": []byte("rotated")},
+	}
+	stale := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "aws-creds-0ld0ld0l",
+			Namespace: "spinnaker-system",
+			Labels:    map[string]string{SecretCopyLabel: "ns1.spinnaker"},
+		},
+	}
+	d := &Deployer{client: test.FakeClient(t, src, stale), log: log.Log.WithName("spinnakerservice")}
+	gen := genConfigWithSecretRefs(t)
+
+	if !assert.Nil(t, d.propagateSecrets(context.TODO(), gen, spinsvc, d.log)) {
+		return
+	}
+	err := d.client.Get(context.TODO(), types.NamespacedName{Namespace: "spinnaker-system", Name: "aws-creds-0ld0ld0l"}, &v1.Secret{})
+	assert.NotNil(t, err)
+	assert.Nil(t, d.client.Get(context.TODO(), types.NamespacedName{Namespace: "spinnaker-system", Name: copiedSecretName("aws-creds", src)}, &v1.Secret{}))
+}
+
+func TestPropagateSecrets_sameNamespaceNoop(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	gen := genConfigWithSecretRefs(t)
+
+	assert.Nil(t, d.propagateSecrets(context.TODO(), gen, spinsvc, d.log))
+	spec := gen.Config["clouddriver"].Deployment.Spec.Template.Spec
+	assert.Equal(t, "aws-creds", spec.Volumes[len(spec.Volumes)-1].Secret.SecretName)
+}
+
+func TestPropagateSecrets_missingSourceSecret(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(secretCopyManifest, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	gen := genConfigWithSecretRefs(t)
+
+	err := d.propagateSecrets(context.TODO(), gen, spinsvc, d.log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "aws-creds")
+		assert.Contains(t, err.Error(), "does not exist in namespace ns1")
+	}
+}
@@ -4,19 +4,27 @@ import (
 	"context"
 	"fmt"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/chaos"
 	"github.com/armory/spinnaker-operator/pkg/deploy"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetector"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/config"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/expose_ingress"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/expose_service"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/nsselector"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/tlssecret"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformer"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/x509"
+	"github.com/armory/spinnaker-operator/pkg/kleat"
+	"github.com/armory/spinnaker-operator/pkg/provenance"
+	"github.com/armory/spinnaker-operator/pkg/topology"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"time"
 )
 
 var DetectorGenerators = []changedetector.DetectorGenerator{
@@ -24,20 +32,68 @@ var DetectorGenerators = []changedetector.DetectorGenerator{
 	&expose_service.ChangeDetectorGenerator{},
 	&expose_ingress.ChangeDetectorGenerator{},
 	&x509.ChangeDetectorGenerator{},
+	&tlssecret.ChangeDetectorGenerator{},
+	&nsselector.ChangeDetectorGenerator{},
 }
 
 var TransformerGenerators = []transformer.Generator{
+	&kleat.TransformerGenerator{},
+	// Manifest transformers run in reverse registration order: keeping the patch
+	// transformer at the front gives user supplied kustomize patches the final
+	// word over everything the other transformers set on the generated manifests
+	&transformer.PatchTransformerGenerator{},
+	// Runs right before the patch transformer on manifests so no later transformer
+	// sees the plaintext secret values
+	&transformer.SecretEncryptionTransformerGenerator{},
+	// Converts deployments to StatefulSets only after every deployment-mutating
+	// transformer has run, otherwise they would skip the converted services
+	&transformer.StatefulSetTransformerGenerator{},
+	// Runs late on manifests so images of injected sidecars are mirrored too
+	&transformer.RegistryTransformerGenerator{},
+	&transformer.ClusterFactsTransformerGenerator{},
+	&transformer.PresetsTransformerGenerator{},
+	&transformer.HaTransformerGenerator{},
 	&transformer.OwnerTransformerGenerator{},
 	&transformer.NamedPortsTransformerGenerator{},
 	&transformer.TargetTransformerGenerator{},
+	&nsselector.TransformerGenerator{},
+	&transformer.RedisMigrationTransformerGenerator{},
 	&expose_service.TransformerGenerator{},
 	&expose_ingress.TransformerGenerator{},
+	&transformer.SessionAffinityTransformerGenerator{},
 	&transformer.ServerPortTransformerGenerator{},
 	&x509.X509TransformerGenerator{},
 	&transformer.AccountsTransformerGenerator{},
+	&transformer.TlsRotationTransformerGenerator{},
 	&transformer.SecretsTransformerGenerator{},
+	&transformer.SecretFilesTransformerGenerator{},
 	&transformer.StatsTransformerGenerator{},
-	&transformer.PatchTransformerGenerator{},
+	&transformer.ArchTransformerGenerator{},
+	&transformer.ArtifactsTransformerGenerator{},
+	&transformer.NetworkPolicyTransformerGenerator{},
+	&transformer.NodeOsTransformerGenerator{},
+	&transformer.SchedulingTransformerGenerator{},
+	&transformer.ProxyTransformerGenerator{},
+	&transformer.DrainTransformerGenerator{},
+	&transformer.QosTransformerGenerator{},
+	&transformer.SecretAgentTransformerGenerator{},
+	&transformer.CacheWarmingTransformerGenerator{},
+	&transformer.TuningTransformerGenerator{},
+	&transformer.ResourcesTransformerGenerator{},
+	&transformer.SidecarsTransformerGenerator{},
+	&transformer.MonitoringTransformerGenerator{},
+	&transformer.MetadataTransformerGenerator{},
+	&transformer.GateProbesTransformerGenerator{},
+	&transformer.ManagementPortsTransformerGenerator{},
+	&transformer.Front50VersioningTransformerGenerator{},
+	&transformer.RedisAuthTransformerGenerator{},
+	&transformer.OrcaTuningTransformerGenerator{},
+	&transformer.DeckAssetsTransformerGenerator{},
+	&transformer.CorsTransformerGenerator{},
+	&transformer.SloRulesTransformerGenerator{},
+	&transformer.ClouddriverRbacTransformerGenerator{},
+	&transformer.NamingTransformerGenerator{},
+	&transformer.ServiceOverridesTransformerGenerator{},
 	&transformer.DefaultsTransformerGenerator{},
 	&transformer.SpinSvcSettingsTransformerGenerator{},
 }
@@ -50,6 +106,7 @@ type Deployer struct {
 	changeDetectorGenerator changedetector.DetectorGenerator
 	log                     logr.Logger
 	rawClient               *kubernetes.Clientset
+	restConfig              *rest.Config
 	evtRecorder             record.EventRecorder
 }
 
@@ -61,6 +118,7 @@ func NewDeployer(m deploy.ManifestGenerator, mgr manager.Manager, c *kubernetes.
 		transformerGenerators:   TransformerGenerators,
 		changeDetectorGenerator: &changedetector.CompositeChangeDetectorGenerator{Generators: DetectorGenerators},
 		rawClient:               c,
+		restConfig:              mgr.GetConfig(),
 		evtRecorder:             evtRecorder,
 		log:                     log,
 	}
@@ -77,6 +135,26 @@ func (d *Deployer) GetName() string {
 func (d *Deployer) Deploy(ctx context.Context, svc interfaces.SpinnakerService, scheme *runtime.Scheme) (bool, error) {
 	rLogger := d.log.WithValues("Service", svc.GetName())
 
+	if err := d.checkDeploymentPlan(svc); err != nil {
+		return false, err
+	}
+
+	// Restore an exported deploy state before checking for changes
+	if err := d.importDeployState(ctx, svc); err != nil {
+		return false, err
+	}
+
+	// Promote the last applied manifest set to rollback target once its
+	// services are reported healthy
+	if err := d.promoteLastGoodManifests(ctx, svc); err != nil {
+		return false, err
+	}
+
+	// Re-apply the last known good manifests when the current deployment never became healthy
+	if rolled, err := d.rollbackIfStuck(ctx, scheme, svc); rolled || err != nil {
+		return false, err
+	}
+
 	ch, err := d.changeDetectorGenerator.NewChangeDetector(d.client, d.log, d.evtRecorder, scheme)
 	if err != nil {
 		return false, err
@@ -87,6 +165,14 @@ func (d *Deployer) Deploy(ctx context.Context, svc interfaces.SpinnakerService,
 		return !up, err
 	}
 
+	if err := d.checkDestructiveChanges(ctx, svc); err != nil {
+		return false, err
+	}
+
+	if err := d.ensureTargetNamespace(ctx, svc); err != nil {
+		return false, err
+	}
+
 	rLogger.Info("retrieving complete Spinnaker configuration")
 	v, err := svc.GetSpinnakerConfig().GetHalConfigPropString(ctx, "version")
 	if err != nil {
@@ -97,6 +183,7 @@ func (d *Deployer) Deploy(ctx context.Context, svc interfaces.SpinnakerService,
 
 	rLogger.Info(fmt.Sprintf("applying options to Spinnaker config with %d generators", len(d.transformerGenerators)))
 	nSvc := svc.DeepCopyInterface()
+	prov := provenance.NewTracker(nSvc.GetSpinnakerConfig())
 	for _, t := range d.transformerGenerators {
 		tr, err := t.NewTransformer(nSvc, d.client, d.log, scheme)
 		if err != nil {
@@ -106,13 +193,26 @@ func (d *Deployer) Deploy(ctx context.Context, svc interfaces.SpinnakerService,
 		if err = tr.TransformConfig(ctx); err != nil {
 			return true, err
 		}
+		prov.Observe(t.GetName(), nSvc.GetSpinnakerConfig())
+	}
+	// Keep the attribution of every effective value for the admin provenance API
+	provenance.Set(fmt.Sprintf("%s/%s", svc.GetNamespace(), svc.GetName()), prov.Records())
+
+	// Probe provider connectivity before spending a generation on a config
+	// that cannot work
+	if svc.GetAnnotations()[SkipPreflightAnnotation] != "true" {
+		if err := d.runPreflightChecks(ctx, svc, nSvc); err != nil {
+			return true, err
+		}
 	}
 
 	rLogger.Info("generating manifests with Halyard")
-	l, err := d.m.Generate(ctx, nSvc.GetSpinnakerConfig())
+	genStart := time.Now()
+	l, err := d.generateManifests(ctx, nSvc, rLogger)
 	if err != nil {
 		return true, err
 	}
+	generateDuration.WithLabelValues(svc.GetNamespace(), svc.GetName()).Observe(time.Since(genStart).Seconds())
 
 	rLogger.Info("applying options to generated manifests")
 	// Traverse transformers in reverse order
@@ -122,7 +222,71 @@ func (d *Deployer) Deploy(ctx context.Context, svc interfaces.SpinnakerService,
 		}
 	}
 
-	if err = d.deployConfig(ctx, scheme, l, rLogger); err != nil {
+	if err = d.validateWithWebhook(ctx, nSvc, l); err != nil {
+		return true, err
+	}
+
+	if err = d.evaluatePolicies(ctx, nSvc, l); err != nil {
+		return true, err
+	}
+
+	if err = d.verifyImages(ctx, nSvc, l); err != nil {
+		return true, err
+	}
+
+	// In dry run or observer mode report what would change and stop, nothing is applied
+	if d.isObserveOnly(svc) {
+		return false, d.reportDrift(ctx, l, svc)
+	}
+
+	// Make the referenced credentials available in the target namespace
+	if err = d.propagateSecrets(ctx, l, nSvc, rLogger); err != nil {
+		return true, err
+	}
+
+	// Surface every admission and validation rejection before mutating the cluster
+	if err = d.dryRunValidate(ctx, l, rLogger); err != nil {
+		return true, err
+	}
+
+	// Everything is rendered and validated, hold it back during a freeze window
+	frozen, reason, err := d.checkFreeze(ctx, nSvc)
+	if err != nil {
+		return true, err
+	}
+	if frozen {
+		return false, d.reportFreeze(ctx, svc, reason)
+	}
+
+	// Version changes roll out in stages: stateful services first, the rest
+	// once they are ready
+	if up, current := d.versionUpgrade(v, svc); up {
+		rLogger.Info(fmt.Sprintf("upgrading Spinnaker from %s to %s", current, v))
+		done, err := d.deployUpgradeStage(ctx, scheme, l, nSvc, v, rLogger)
+		if err != nil {
+			return true, err
+		}
+		if !done {
+			return true, nil
+		}
+	}
+
+	applyStart := time.Now()
+	if err = d.deployConfig(ctx, scheme, l, nSvc, rLogger); err != nil {
+		deployTotal.WithLabelValues(svc.GetNamespace(), svc.GetName(), "failure").Inc()
+		// A partial apply may leave a mix of old and new manifests behind
+		return true, d.rollbackOnFailure(ctx, scheme, nSvc, rLogger, err)
+	}
+	applyDuration.WithLabelValues(svc.GetNamespace(), svc.GetName()).Observe(time.Since(applyStart).Seconds())
+
+	// Snapshot the config we just applied so destructive changes can be detected on the next deployment
+	if err = d.saveConfigBackup(ctx, svc); err != nil {
+		return true, err
+	}
+
+	// Keep the manifest set for a later rollback, it is only promoted to the
+	// rollback target once the rollout is reported healthy
+	if err = d.saveCandidateManifests(ctx, scheme, svc, l); err != nil {
 		return true, err
 	}
 
@@ -130,10 +294,33 @@ func (d *Deployer) Deploy(ctx context.Context, svc interfaces.SpinnakerService,
 	// otherwise we'll have updated the instance
 	newStatus := nSvc.GetStatus()
 	newStatus.Version = v
+	// Record the generation we just deployed so status-only updates can be told apart
+	newStatus.ObservedGeneration = svc.GetGeneration()
+	// Anything pending from an earlier dry run was just applied
+	newStatus.PendingChanges = nil
+	// A change held back by a freeze window was just applied, the rollout
+	// drives the status from here on
+	if newStatus.Status == StatusPendingFreeze {
+		newStatus.Status = statusUpdating
+	}
 	newStatus.DeepCopyInto(svc.GetStatus())
 
+	// Keep the resource graph of what was just applied for the admin topology API
+	topology.Set(fmt.Sprintf("%s/%s", svc.GetNamespace(), svc.GetName()), topology.Build(l))
+
+	deployTotal.WithLabelValues(svc.GetNamespace(), svc.GetName(), "success").Inc()
+	lastDeployTime.WithLabelValues(svc.GetNamespace(), svc.GetName()).SetToCurrentTime()
+	setDeployedVersion(svc.GetNamespace(), svc.GetName(), v)
+
 	rLogger.Info(fmt.Sprintf("deployed version %s, setting status", v))
+	if err = chaos.Fire(ctx, chaos.PointStatusUpdate); err != nil {
+		return true, err
+	}
 	// We're updating with svc not nSvc
-	err = d.client.Status().Update(ctx, svc)
-	return true, err
+	if err = d.client.Status().Update(ctx, svc); err != nil {
+		return true, err
+	}
+
+	// Export the deploy state for disaster recovery
+	return true, d.exportDeployState(ctx, svc)
 }
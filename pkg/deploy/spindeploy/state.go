@@ -0,0 +1,90 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/storage"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ImportStateAnnotation references a Secret holding an exported deploy state.
+	// Set to "true" to use the default secret name
+	ImportStateAnnotation = "spinnaker.io/import-deploy-state"
+	deployStateSecretKey  = "status"
+)
+
+// exportDeployState saves the deploy state (deployed hashes and version) to a Secret
+// so it can be restored in another cluster for disaster recovery
+func (d *Deployer) exportDeployState(ctx context.Context, svc interfaces.SpinnakerService) error {
+	b, err := yaml.Marshal(svc.GetStatus())
+	if err != nil {
+		return err
+	}
+	// Keep an off cluster copy when an operator level object store is configured
+	if store, err := storage.FromEnv(); err != nil {
+		return err
+	} else if store != nil {
+		key := fmt.Sprintf("deploy-state/%s/%s.yml", svc.GetNamespace(), svc.GetName())
+		if err := store.Put(ctx, key, b); err != nil {
+			d.log.Error(err, "unable to export deploy state to object storage")
+		}
+	}
+	name := getDeployStateSecretName(svc)
+	sec := &v1.Secret{}
+	err = d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: name}, sec)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		sec = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: svc.GetNamespace()},
+			Data:       map[string][]byte{deployStateSecretKey: b},
+		}
+		return d.client.Create(ctx, sec)
+	}
+	if sec.Data == nil {
+		sec.Data = map[string][]byte{}
+	}
+	sec.Data[deployStateSecretKey] = b
+	return d.client.Update(ctx, sec)
+}
+
+// importDeployState restores a previously exported deploy state when the service has
+// never been deployed in this cluster and asks for it with an annotation
+func (d *Deployer) importDeployState(ctx context.Context, svc interfaces.SpinnakerService) error {
+	ref, ok := svc.GetAnnotations()[ImportStateAnnotation]
+	if !ok || ref == "" {
+		return nil
+	}
+	if len(svc.GetStatus().LastDeployed) > 0 {
+		// Already deployed here, nothing to import
+		return nil
+	}
+	name := ref
+	if ref == "true" {
+		name = getDeployStateSecretName(svc)
+	}
+	sec := &v1.Secret{}
+	if err := d.client.Get(ctx, types.NamespacedName{Namespace: svc.GetNamespace(), Name: name}, sec); err != nil {
+		return fmt.Errorf("unable to read deploy state secret %s: %w", name, err)
+	}
+	st := &interfaces.SpinnakerServiceStatus{}
+	if err := yaml.Unmarshal(sec.Data[deployStateSecretKey], st); err != nil {
+		return fmt.Errorf("unable to parse deploy state secret %s: %w", name, err)
+	}
+	d.log.Info(fmt.Sprintf("imported deploy state from secret %s", name))
+	// The status checker persists the status at the end of the reconcile
+	st.DeepCopyInto(svc.GetStatus())
+	return nil
+}
+
+func getDeployStateSecretName(svc interfaces.SpinnakerService) string {
+	return fmt.Sprintf("%s-deploy-state", svc.GetName())
+}
@@ -0,0 +1,69 @@
+package spindeploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestImportDeployState(t *testing.T) {
+	state := `
+version: 1.17.1
+lastDeployed:
+  config:
+    hash: abcdef
+`
+	sec := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-deploy-state", Namespace: "ns1"},
+		Data:       map[string][]byte{deployStateSecretKey: []byte(state)},
+	}
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+  annotations:
+    spinnaker.io/import-deploy-state: "true"
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t, sec), log: log.Log.WithName("spinnakerservice")}
+	err := d.importDeployState(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.17.1", spinsvc.GetStatus().Version)
+	assert.Equal(t, "abcdef", spinsvc.GetStatus().LastDeployed["config"].Hash)
+}
+
+func TestImportDeployState_alreadyDeployed(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+  annotations:
+    spinnaker.io/import-deploy-state: "true"
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+status:
+  lastDeployed:
+    config:
+      hash: current
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	err := d.importDeployState(context.TODO(), spinsvc)
+	assert.Nil(t, err)
+	assert.Equal(t, "current", spinsvc.GetStatus().LastDeployed["config"].Hash)
+}
@@ -0,0 +1,81 @@
+package tlssecret
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetector"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const TlsSecretsHashKey = "tlsSecrets"
+
+// changeDetector detects rotations of TLS secrets in the Spinnaker namespace so
+// certificates are propagated to the services that mount them
+type changeDetector struct {
+	client      client.Client
+	log         logr.Logger
+	evtRecorder record.EventRecorder
+}
+
+type ChangeDetectorGenerator struct{}
+
+func (g *ChangeDetectorGenerator) NewChangeDetector(client client.Client, log logr.Logger, evtRecorder record.EventRecorder, scheme *runtime.Scheme) (changedetector.ChangeDetector, error) {
+	return &changeDetector{client: client, log: log, evtRecorder: evtRecorder}, nil
+}
+
+// IsSpinnakerUpToDate returns true if no TLS secret changed since the last deployment
+func (ch *changeDetector) IsSpinnakerUpToDate(ctx context.Context, svc interfaces.SpinnakerService) (bool, error) {
+	h, err := HashTlsSecrets(ctx, ch.client, svc.GetNamespace())
+	if err != nil {
+		return false, err
+	}
+	st := svc.GetStatus()
+	prior := st.UpdateHashIfNotExist(TlsSecretsHashKey, h, time.Now())
+	return h == prior.Hash, nil
+}
+
+func (ch *changeDetector) AlwaysRun() bool {
+	return true
+}
+
+// HashTlsSecrets computes a stable hash over the data of all TLS secrets in the namespace
+func HashTlsSecrets(ctx context.Context, c client.Client, ns string) (string, error) {
+	secrets := &v1.SecretList{}
+	if err := c.List(ctx, secrets, client.InNamespace(ns)); err != nil {
+		return "", err
+	}
+	names := make([]string, 0)
+	byName := map[string]v1.Secret{}
+	for _, s := range secrets.Items {
+		if s.Type != v1.SecretTypeTLS {
+			continue
+		}
+		names = append(names, s.Name)
+		byName[s.Name] = s
+	}
+	sort.Strings(names)
+	m := md5.New()
+	for _, n := range names {
+		s := byName[n]
+		m.Write([]byte(n))
+		keys := make([]string, 0, len(s.Data))
+		for k := range s.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			m.Write([]byte(k))
+			m.Write(s.Data[k])
+		}
+	}
+	return hex.EncodeToString(m.Sum(nil)), nil
+}
@@ -0,0 +1,44 @@
+package tlssecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHashTlsSecrets_ignoresOpaqueSecrets(t *testing.T) {
+	tls := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gate-tls", Namespace: "ns1"},
+		Type:       v1.SecretTypeTLS,
+		Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}
+	opaque := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns1"},
+		Type:       v1.SecretTypeOpaque,
+		Data:       map[string][]byte{"k": []byte("v")},
+	}
+	h1, err := HashTlsSecrets(context.TODO(), test.FakeClient(t, tls), "ns1")
+	assert.Nil(t, err)
+	h2, err := HashTlsSecrets(context.TODO(), test.FakeClient(t, tls, opaque), "ns1")
+	assert.Nil(t, err)
+	assert.Equal(t, h1, h2)
+}
+
+func TestHashTlsSecrets_changesOnRotation(t *testing.T) {
+	tls := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gate-tls", Namespace: "ns1"},
+		Type:       v1.SecretTypeTLS,
+		Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}
+	h1, err := HashTlsSecrets(context.TODO(), test.FakeClient(t, tls), "ns1")
+	assert.Nil(t, err)
+	rotated := tls.DeepCopy()
+	rotated.Data["tls.crt"] = []byte("newcert")
+	h2, err := HashTlsSecrets(context.TODO(), test.FakeClient(t, rotated), "ns1")
+	assert.Nil(t, err)
+	assert.NotEqual(t, h1, h2)
+}
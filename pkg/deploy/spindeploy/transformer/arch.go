@@ -0,0 +1,152 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	archLabel     = "kubernetes.io/arch"
+	archLabelBeta = "beta.kubernetes.io/arch"
+	archAmd64     = "amd64"
+)
+
+// archTransformer validates that images exist for the target node architectures,
+// applies per-arch image overrides and pins amd64-only images to amd64 nodes
+type archTransformer struct {
+	*DefaultTransformer
+	svc    interfaces.SpinnakerService
+	log    logr.Logger
+	client client.Client
+	// architectures targeted by this deployment
+	archs []string
+}
+
+type ArchTransformerGenerator struct{}
+
+func (g *ArchTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := archTransformer{svc: svc, log: log, client: client, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *ArchTransformerGenerator) GetName() string {
+	return "Architecture"
+}
+
+func (t *archTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *archTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	archs, err := t.targetArchitectures(ctx)
+	if err != nil {
+		return err
+	}
+	if len(archs) == 0 {
+		// No arch information available, nothing to do
+		return nil
+	}
+	overrides := t.svc.GetArchitectureConfig().ImageOverrides[deploymentName]
+	if len(archs) == 1 {
+		arch := archs[0]
+		if img, ok := overrides[arch]; ok {
+			return setContainerImage(deployment, deploymentName, img)
+		}
+		if arch != archAmd64 {
+			return fmt.Errorf("all target nodes are %s but service %s has no image override for that architecture, "+
+				"add one under spec.architecture.imageOverrides", arch, deploymentName)
+		}
+		return nil
+	}
+	// Mixed architecture cluster: without an override for every non amd64 arch,
+	// keep the default image and pin the pods to amd64 nodes
+	for _, arch := range archs {
+		if arch == archAmd64 {
+			continue
+		}
+		if _, ok := overrides[arch]; !ok {
+			t.log.Info(fmt.Sprintf("service %s has no %s image, pinning to %s nodes", deploymentName, arch, archAmd64))
+			addArchNodeAffinity(deployment, archAmd64)
+			return nil
+		}
+	}
+	return nil
+}
+
+// targetArchitectures returns the architectures from the spec, falling back to the node labels
+func (t *archTransformer) targetArchitectures(ctx context.Context) ([]string, error) {
+	if t.archs != nil {
+		return t.archs, nil
+	}
+	cfg := t.svc.GetArchitectureConfig()
+	if len(cfg.Architectures) > 0 {
+		t.archs = cfg.Architectures
+		return t.archs, nil
+	}
+	nodes := &corev1.NodeList{}
+	if err := t.client.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	archs := make([]string, 0)
+	for _, n := range nodes.Items {
+		a := n.Labels[archLabel]
+		if a == "" {
+			a = n.Labels[archLabelBeta]
+		}
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		archs = append(archs, a)
+	}
+	t.archs = archs
+	return t.archs, nil
+}
+
+func setContainerImage(deployment *v1.Deployment, containerName, image string) error {
+	for i := range deployment.Spec.Template.Spec.Containers {
+		c := &deployment.Spec.Template.Spec.Containers[i]
+		if c.Name == containerName {
+			c.Image = image
+			return nil
+		}
+	}
+	return fmt.Errorf("unable to find container %s in deployment, cannot override image", containerName)
+}
+
+// addArchNodeAffinity adds a required node affinity on the given architecture
+func addArchNodeAffinity(deployment *v1.Deployment, arch string) {
+	spec := &deployment.Spec.Template.Spec
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	na := spec.Affinity.NodeAffinity
+	if na.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		na.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	term := corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{
+				Key:      archLabel,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{arch},
+			},
+		},
+	}
+	sel := na.RequiredDuringSchedulingIgnoredDuringExecution
+	sel.NodeSelectorTerms = append(sel.NodeSelectorTerms, term)
+}
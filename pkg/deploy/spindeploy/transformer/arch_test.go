@@ -0,0 +1,71 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func archSpinSvc(extra string) string {
+	return `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+` + extra + `
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+}
+
+func TestArch_imageOverrideApplied(t *testing.T) {
+	s := archSpinSvc(`  architecture:
+    architectures: ["arm64"]
+    imageOverrides:
+      gate:
+        arm64: myrepo/gate:arm64
+`)
+	tr, _ := th.SetupTransformerFromSpinText(&ArchTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	assert.Equal(t, "myrepo/gate:arm64", gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestArch_armOnlyWithoutOverride(t *testing.T) {
+	s := archSpinSvc(`  architecture:
+    architectures: ["arm64"]
+`)
+	tr, _ := th.SetupTransformerFromSpinText(&ArchTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.NotNil(t, err)
+}
+
+func TestArch_mixedClusterPinsToAmd64(t *testing.T) {
+	s := archSpinSvc(`  architecture:
+    architectures: ["amd64", "arm64"]
+`)
+	tr, _ := th.SetupTransformerFromSpinText(&ArchTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	affinity := gen.Config["gate"].Deployment.Spec.Template.Spec.Affinity
+	if assert.NotNil(t, affinity) {
+		terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		assert.Equal(t, 1, len(terms))
+		assert.Equal(t, archLabel, terms[0].MatchExpressions[0].Key)
+		assert.Equal(t, []string{"amd64"}, terms[0].MatchExpressions[0].Values)
+	}
+}
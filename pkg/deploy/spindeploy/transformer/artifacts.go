@@ -0,0 +1,113 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	yaml "gopkg.in/yaml.v2"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// bomFileKey is where a custom BOM is expected under spec.spinnakerConfig.files
+const bomFileKey = "bom"
+
+// customBom is the subset of a Halyard bill of materials the operator reads:
+// the docker registry the images come from and the version of every service
+type customBom struct {
+	ArtifactSources struct {
+		DockerRegistry string `yaml:"dockerRegistry"`
+	} `yaml:"artifactSources"`
+	Services map[string]struct {
+		Version string `yaml:"version"`
+	} `yaml:"services"`
+}
+
+// artifactsTransformer rewrites the images Halyard produced with pinned
+// service builds: per-service overrides from spec.artifacts win over a custom
+// BOM supplied in spec.spinnakerConfig.files.bom
+type artifactsTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+	bom *customBom
+}
+
+type ArtifactsTransformerGenerator struct{}
+
+func (g *ArtifactsTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := artifactsTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	if b := svc.GetSpinnakerConfig().GetFileContent(bomFileKey); len(b) > 0 {
+		tr.bom = &customBom{}
+		if err := yaml.Unmarshal(b, tr.bom); err != nil {
+			return nil, fmt.Errorf("unable to parse the custom BOM in files.%s: %s", bomFileKey, err.Error())
+		}
+	}
+	return &tr, nil
+}
+
+func (g *ArtifactsTransformerGenerator) GetName() string {
+	return "Artifacts"
+}
+
+func (t *artifactsTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *artifactsTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	if image := t.imageFor(deploymentName, deployment); image != "" {
+		t.log.Info(fmt.Sprintf("pinning %s to %s", deploymentName, image))
+		return setContainerImage(deployment, deploymentName, image)
+	}
+	return nil
+}
+
+// imageFor resolves the pinned image of a service, empty when it is not pinned
+func (t *artifactsTransformer) imageFor(svcName string, deployment *v1.Deployment) string {
+	if ov, ok := t.svc.GetArtifacts()[svcName]; ok {
+		if ov.Image != "" {
+			return ov.Image
+		}
+		if ov.Version != "" {
+			return retagImage(currentImage(deployment, svcName), ov.Version)
+		}
+	}
+	if t.bom == nil {
+		return ""
+	}
+	entry, ok := t.bom.Services[svcName]
+	if !ok || entry.Version == "" {
+		return ""
+	}
+	if reg := t.bom.ArtifactSources.DockerRegistry; reg != "" {
+		return fmt.Sprintf("%s/%s:%s", reg, svcName, entry.Version)
+	}
+	return retagImage(currentImage(deployment, svcName), entry.Version)
+}
+
+func currentImage(deployment *v1.Deployment, containerName string) string {
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		if c.Name == containerName {
+			return c.Image
+		}
+	}
+	return ""
+}
+
+// retagImage swaps the tag of an image for the given version, careful not to
+// mistake a registry port for a tag
+func retagImage(image, version string) string {
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		image = image[:colon]
+	}
+	return fmt.Sprintf("%s:%s", image, version)
+}
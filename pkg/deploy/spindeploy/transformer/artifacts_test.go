@@ -0,0 +1,90 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifacts_versionOverride(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  artifacts:
+    gate:
+      version: 1.9.1-patched
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ArtifactsTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, "docker.io/armory/gate:1.9.1-patched", gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestArtifacts_imageOverrideWins(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  artifacts:
+    gate:
+      version: 1.9.1-patched
+      image: internal.registry:5000/gate:custom
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ArtifactsTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, "internal.registry:5000/gate:custom", gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestArtifacts_customBom(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    files:
+      bom: |
+        version: 1.17.1
+        artifactSources:
+          dockerRegistry: internal.registry/spinnaker
+        services:
+          gate:
+            version: 1.9.2-internal
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ArtifactsTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	test.AddDeploymentToGenConfig(gen, "echo", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, "internal.registry/spinnaker/gate:1.9.2-internal", gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Image)
+	// Services not listed in the BOM keep the image Halyard produced
+	assert.Equal(t, "docker.io/armory/gate:1.9.0-83b6e52-193c7b9-edge3", gen.Config["echo"].Deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestRetagImage(t *testing.T) {
+	assert.Equal(t, "docker.io/armory/gate:1.9.1", retagImage("docker.io/armory/gate:1.9.0", "1.9.1"))
+	assert.Equal(t, "reg:5000/gate:1.9.1", retagImage("reg:5000/gate", "1.9.1"))
+}
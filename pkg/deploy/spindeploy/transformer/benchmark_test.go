@@ -0,0 +1,36 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// benchmarkTransformConfig runs a transformer's TransformConfig against a
+// synthesized hal config with many accounts, so config traversal costs show
+// up at a realistic scale.
+func benchmarkTransformConfig(b *testing.B, gen Generator, k8sAccounts int, dockerRegistries int) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		svc := test.ScaleSpinService("ns1", "spinnaker", k8sAccounts, dockerRegistries)
+		tr, err := gen.NewTransformer(svc, nil, log.Log.WithName("spinnakerservice"), runtime.NewScheme())
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+		if err := tr.TransformConfig(context.TODO()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSecretsTransformConfigAtScale(b *testing.B) {
+	benchmarkTransformConfig(b, &SecretsTransformerGenerator{}, 500, 100)
+}
+
+func BenchmarkDefaultsTransformConfigAtScale(b *testing.B) {
+	benchmarkTransformConfig(b, &DefaultsTransformerGenerator{}, 500, 100)
+}
@@ -0,0 +1,152 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultMinWarmupSeconds         = 60
+	defaultMaxWarmupSeconds         = 600
+	defaultMinCachedAccountsPercent = 90
+)
+
+// cacheWarmingTransformer staggers Clouddriver replica startup and ties its
+// readiness to cache population, so upgrades of installs with many accounts
+// don't route pipeline stages to replicas with cold caches
+type cacheWarmingTransformer struct {
+	*DefaultTransformer
+	svc       interfaces.SpinnakerService
+	log       logr.Logger
+	minWarmup int32
+	maxWarmup int32
+}
+
+type CacheWarmingTransformerGenerator struct{}
+
+func (g *CacheWarmingTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := cacheWarmingTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *CacheWarmingTransformerGenerator) GetName() string {
+	return "CacheWarming"
+}
+
+func (t *cacheWarmingTransformer) TransformConfig(ctx context.Context) error {
+	c := t.svc.GetCacheWarmingConfig()
+	if !c.Enabled {
+		return nil
+	}
+	cfg := t.svc.GetSpinnakerConfig()
+
+	t.minWarmup = c.MinWarmupSeconds
+	if t.minWarmup == 0 {
+		// Scale the default warmup with the number of configured accounts
+		t.minWarmup = defaultMinWarmupSeconds + int32(countProviderAccounts(ctx, cfg))
+	}
+	t.maxWarmup = c.MaxWarmupSeconds
+	if t.maxWarmup == 0 {
+		t.maxWarmup = defaultMaxWarmupSeconds
+	}
+	if t.maxWarmup < t.minWarmup {
+		t.maxWarmup = t.minWarmup
+	}
+	pct := c.MinCachedAccountsPercent
+	if pct == 0 {
+		pct = defaultMinCachedAccountsPercent
+	}
+
+	// Readiness thresholds are profile settings, existing profile entries win
+	defaults := map[string]interface{}{
+		"caching": map[string]interface{}{
+			"readiness": map[string]interface{}{
+				"enabled":                  true,
+				"minCachedAccountsPercent": pct,
+			},
+		},
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	cfg.Profiles[util.ClouddriverName] = inspect.Overlay(defaults, cfg.Profiles[util.ClouddriverName])
+	return nil
+}
+
+// countProviderAccounts returns the total number of accounts configured
+// across all cloud providers
+func countProviderAccounts(ctx context.Context, cfg *interfaces.SpinnakerConfig) int {
+	count := 0
+	providers, err := inspect.GetObjectProp(cfg.Config, "providers")
+	if err != nil {
+		return count
+	}
+	for _, k := range providers.MapKeys() {
+		accounts, err := cfg.GetHalConfigObjectArray(ctx, "providers."+k.String()+".accounts")
+		if err != nil {
+			continue
+		}
+		count += len(accounts)
+	}
+	return count
+}
+
+func (t *cacheWarmingTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *cacheWarmingTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	if !t.svc.GetCacheWarmingConfig().Enabled || deploymentName != util.ClouddriverName {
+		return nil
+	}
+	// Surge one replica at a time so only a single cold cache warms up at once
+	if deployment.Spec.Strategy.Type == "" {
+		maxUnavailable := intstr.FromInt(0)
+		maxSurge := intstr.FromInt(1)
+		deployment.Spec.Strategy = v1.DeploymentStrategy{
+			Type: v1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &v1.RollingUpdateDeployment{
+				MaxUnavailable: &maxUnavailable,
+				MaxSurge:       &maxSurge,
+			},
+		}
+	}
+	container := util.GetContainerInDeployment(deployment, util.ClouddriverName)
+	if container == nil {
+		return nil
+	}
+	probe := container.ReadinessProbe
+	if probe == nil {
+		probe = &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/health",
+					Port: intstr.FromInt(int(util.ClouddriverDefaultPort)),
+				},
+			},
+		}
+		container.ReadinessProbe = probe
+	}
+	probe.InitialDelaySeconds = t.minWarmup
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	// Keep probing until the maximum warmup time has elapsed
+	failures := (t.maxWarmup - t.minWarmup) / probe.PeriodSeconds
+	if failures > probe.FailureThreshold {
+		probe.FailureThreshold = failures
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+var cacheWarmingSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  cacheWarming:
+    enabled: true
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        kubernetes:
+          enabled: true
+          accounts:
+          - name: acc1
+          - name: acc2
+        aws:
+          enabled: true
+          accounts:
+          - name: prod
+`
+
+func TestCacheWarming_profileAndProbe(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&CacheWarmingTransformerGenerator{}, cacheWarmingSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	p := spinSvc.GetSpinnakerConfig().Profiles["clouddriver"]
+	enabled, err := inspect.GetObjectPropBool(p, "caching.readiness.enabled", false)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "clouddriver", "testdata/input_deployment_clouddriver.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	dep := gen.Config["clouddriver"].Deployment
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, dep.Spec.Strategy.Type)
+	assert.Equal(t, 0, dep.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue())
+	assert.Equal(t, 1, dep.Spec.Strategy.RollingUpdate.MaxSurge.IntValue())
+
+	probe := dep.Spec.Template.Spec.Containers[0].ReadinessProbe
+	if assert.NotNil(t, probe) {
+		// 60s baseline plus one second per account (3 accounts in 2 providers)
+		assert.Equal(t, int32(63), probe.InitialDelaySeconds)
+		assert.Equal(t, "/health", probe.Handler.HTTPGet.Path)
+		assert.Equal(t, 7002, probe.Handler.HTTPGet.Port.IntValue())
+		// Failures are tolerated until maxWarmupSeconds have elapsed
+		assert.Equal(t, int32((600-63)/10), probe.FailureThreshold)
+	}
+}
+
+func TestCacheWarming_userProfileWins(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  cacheWarming:
+    enabled: true
+    minWarmupSeconds: 120
+    maxWarmupSeconds: 300
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    profiles:
+      clouddriver:
+        caching:
+          readiness:
+            minCachedAccountsPercent: 50
+`
+	tr, spinSvc := th.SetupTransformerFromSpinText(&CacheWarmingTransformerGenerator{}, s, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	p := spinSvc.GetSpinnakerConfig().Profiles["clouddriver"]
+	pct, err := inspect.GetRawObjectPropString(p, "caching.readiness.minCachedAccountsPercent")
+	assert.Nil(t, err)
+	assert.Equal(t, "50", pct)
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "clouddriver", "testdata/input_deployment_clouddriver.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	probe := gen.Config["clouddriver"].Deployment.Spec.Template.Spec.Containers[0].ReadinessProbe
+	if assert.NotNil(t, probe) {
+		assert.Equal(t, int32(120), probe.InitialDelaySeconds)
+		assert.Equal(t, int32((300-120)/10), probe.FailureThreshold)
+	}
+}
+
+func TestCacheWarming_disabled(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, spinSvc := th.SetupTransformerFromSpinText(&CacheWarmingTransformerGenerator{}, s, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+	assert.Nil(t, spinSvc.GetSpinnakerConfig().Profiles["clouddriver"])
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "clouddriver", "testdata/input_deployment_clouddriver.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Nil(t, gen.Config["clouddriver"].Deployment.Spec.Template.Spec.Containers[0].ReadinessProbe)
+}
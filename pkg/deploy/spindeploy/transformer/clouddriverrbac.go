@@ -0,0 +1,152 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clouddriverRbacRules is the access Clouddriver needs to manage and cache
+// workloads with the Kubernetes V2 provider
+var clouddriverRbacRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods", "pods/log", "services", "endpoints", "configmaps", "secrets", "namespaces", "events", "serviceaccounts"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{"apps", "extensions"},
+		Resources: []string{"deployments", "replicasets", "statefulsets", "daemonsets", "ingresses"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{"batch"},
+		Resources: []string{"jobs", "cronjobs"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{"networking.k8s.io"},
+		Resources: []string{"ingresses", "networkpolicies"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+	},
+}
+
+// clouddriverRbacTransformer generates the (Cluster)Role and bindings
+// Clouddriver needs when a Kubernetes account targets the local cluster via
+// service account, scoped to the account's namespaces
+type clouddriverRbacTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type ClouddriverRbacTransformerGenerator struct{}
+
+func (g *ClouddriverRbacTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := clouddriverRbacTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *ClouddriverRbacTransformerGenerator) GetName() string {
+	return "ClouddriverRbac"
+}
+
+func (t *clouddriverRbacTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	cc, ok := gen.Config[util.ClouddriverName]
+	if !ok || cc.Deployment == nil {
+		return nil
+	}
+	cfg := t.svc.GetSpinnakerConfig()
+	enabled, err := cfg.GetHalConfigPropBool("providers.kubernetes.enabled", false)
+	if err != nil || !enabled {
+		return nil
+	}
+	accounts, err := cfg.GetHalConfigObjectArray(ctx, "providers.kubernetes.accounts")
+	if err != nil {
+		return nil
+	}
+	saName := cc.Deployment.Spec.Template.Spec.ServiceAccountName
+	if saName == "" {
+		saName = "default"
+	}
+	saNamespace := cc.Deployment.Namespace
+	if saNamespace == "" {
+		saNamespace = t.svc.GetNamespace()
+	}
+	for _, a := range accounts {
+		sa, ok := a["serviceAccount"].(bool)
+		if !ok || !sa {
+			continue
+		}
+		name, ok := a["name"].(string)
+		if !ok {
+			continue
+		}
+		namespaces, _ := inspect.GetStringArray(a, "namespaces")
+		cc.Resources = append(cc.Resources, t.rbacForAccount(name, saName, saNamespace, namespaces)...)
+	}
+	gen.Config[util.ClouddriverName] = cc
+	return nil
+}
+
+// rbacForAccount generates Roles scoped to the account namespaces, or a
+// ClusterRole when the account has access to all namespaces
+func (t *clouddriverRbacTransformer) rbacForAccount(account, saName, saNamespace string, namespaces []string) []runtime.Object {
+	objectName := fmt.Sprintf("spin-clouddriver-%s", account)
+	subjects := []rbacv1.Subject{{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      saName,
+		Namespace: saNamespace,
+	}}
+	if len(namespaces) == 0 {
+		return []runtime.Object{
+			&rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: objectName},
+				Rules:      clouddriverRbacRules,
+			},
+			&rbacv1.ClusterRoleBinding{
+				TypeMeta:   metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: objectName},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "ClusterRole",
+					Name:     objectName,
+				},
+				Subjects: subjects,
+			},
+		}
+	}
+	var objs []runtime.Object
+	for _, ns := range namespaces {
+		objs = append(objs,
+			&rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{Kind: "Role", APIVersion: "rbac.authorization.k8s.io/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: objectName, Namespace: ns},
+				Rules:      clouddriverRbacRules,
+			},
+			&rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{Kind: "RoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: objectName, Namespace: ns},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "Role",
+					Name:     objectName,
+				},
+				Subjects: subjects,
+			})
+	}
+	return objs
+}
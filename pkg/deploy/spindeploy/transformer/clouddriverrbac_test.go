@@ -0,0 +1,107 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func clouddriverRbacSpinsvc(namespaces string) string {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        kubernetes:
+          enabled: true
+          accounts:
+          - name: local
+            serviceAccount: true
+`
+	if namespaces != "" {
+		s += namespaces
+	}
+	return s
+}
+
+func TestClouddriverRbac_scopedToNamespaces(t *testing.T) {
+	s := clouddriverRbacSpinsvc(`            namespaces:
+            - ns-a
+            - ns-b
+`)
+	tr, _ := th.SetupTransformerFromSpinText(&ClouddriverRbacTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "clouddriver", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	res := gen.Config["clouddriver"].Resources
+	assert.Equal(t, 4, len(res))
+	role, ok := res[0].(*rbacv1.Role)
+	if assert.True(t, ok) {
+		assert.Equal(t, "spin-clouddriver-local", role.Name)
+		assert.Equal(t, "ns-a", role.Namespace)
+	}
+	binding, ok := res[1].(*rbacv1.RoleBinding)
+	if assert.True(t, ok) {
+		assert.Equal(t, "ns-a", binding.Namespace)
+		assert.Equal(t, "Role", binding.RoleRef.Kind)
+		assert.Equal(t, "default", binding.Subjects[0].Name)
+		// The binding points at the service account in the deployment's namespace
+		assert.Equal(t, gen.Config["clouddriver"].Deployment.Namespace, binding.Subjects[0].Namespace)
+	}
+}
+
+func TestClouddriverRbac_clusterWideWithoutNamespaces(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&ClouddriverRbacTransformerGenerator{}, clouddriverRbacSpinsvc(""), t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "clouddriver", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	res := gen.Config["clouddriver"].Resources
+	assert.Equal(t, 2, len(res))
+	_, ok := res[0].(*rbacv1.ClusterRole)
+	assert.True(t, ok)
+	binding, ok := res[1].(*rbacv1.ClusterRoleBinding)
+	if assert.True(t, ok) {
+		assert.Equal(t, "ClusterRole", binding.RoleRef.Kind)
+	}
+}
+
+func TestClouddriverRbac_ignoresKubeconfigAccounts(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        kubernetes:
+          enabled: true
+          accounts:
+          - name: remote
+            kubeconfigFile: encrypted:k8s!n:creds!k:kubeconfig
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ClouddriverRbacTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "clouddriver", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(gen.Config["clouddriver"].Resources))
+}
@@ -0,0 +1,138 @@
+package transformer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	clusterVarPrefix   = "${cluster."
+	regionLabel        = "topology.kubernetes.io/region"
+	regionLabelBeta    = "failure-domain.beta.kubernetes.io/region"
+	ingressClassKey    = "kubernetes.io/ingress.class"
+	defaultClusterName = "cluster.local"
+)
+
+// clusterFactsTransformer substitutes ${cluster.*} variables in the hal config, profiles
+// and service settings with facts resolved from the target cluster, so the same config
+// source can be deployed to heterogeneous clusters
+type clusterFactsTransformer struct {
+	*DefaultTransformer
+	svc    interfaces.SpinnakerService
+	log    logr.Logger
+	client client.Client
+}
+
+type ClusterFactsTransformerGenerator struct{}
+
+func (g *ClusterFactsTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := clusterFactsTransformer{svc: svc, log: log, client: client, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *ClusterFactsTransformerGenerator) GetName() string {
+	return "ClusterFacts"
+}
+
+func (t *clusterFactsTransformer) TransformConfig(ctx context.Context) error {
+	cfg := t.svc.GetSpinnakerConfig()
+	if !configReferencesClusterVars(cfg) {
+		return nil
+	}
+	facts, err := t.resolveClusterFacts(ctx)
+	if err != nil {
+		return err
+	}
+	h := func(val string) (string, error) {
+		if !strings.Contains(val, clusterVarPrefix) {
+			return val, nil
+		}
+		for k, v := range facts {
+			val = strings.ReplaceAll(val, "${cluster."+k+"}", v)
+		}
+		return val, nil
+	}
+	c, err := inspect.InspectStrings(cfg.Config, h)
+	if err != nil {
+		return err
+	}
+	cfg.Config = c.(interfaces.FreeForm)
+	for name, p := range cfg.Profiles {
+		np, err := inspect.InspectStrings(p, h)
+		if err != nil {
+			return err
+		}
+		cfg.Profiles[name] = np.(interfaces.FreeForm)
+	}
+	for name, s := range cfg.ServiceSettings {
+		ns, err := inspect.InspectStrings(s, h)
+		if err != nil {
+			return err
+		}
+		cfg.ServiceSettings[name] = ns.(interfaces.FreeForm)
+	}
+	return nil
+}
+
+// resolveClusterFacts gathers the supported substitution variables from the cluster
+func (t *clusterFactsTransformer) resolveClusterFacts(ctx context.Context) (map[string]string, error) {
+	facts := map[string]string{
+		"domain":        defaultClusterName,
+		"cloudProvider": "",
+		"region":        "",
+		"ingressClass":  t.svc.GetExposeConfig().Service.Annotations[ingressClassKey],
+	}
+	nodes := &corev1.NodeList{}
+	if err := t.client.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	for _, n := range nodes.Items {
+		if p := providerFromID(n.Spec.ProviderID); p != "" {
+			facts["cloudProvider"] = p
+		}
+		if r, ok := n.Labels[regionLabel]; ok {
+			facts["region"] = r
+		} else if r, ok := n.Labels[regionLabelBeta]; ok {
+			facts["region"] = r
+		}
+	}
+	return facts, nil
+}
+
+// providerFromID extracts the cloud provider from a node providerID, e.g. "aws:///us-west-2a/i-0123" -> "aws"
+func providerFromID(id string) string {
+	p := strings.Index(id, "://")
+	if p <= 0 {
+		return ""
+	}
+	return id[:p]
+}
+
+// configReferencesClusterVars returns true if any string in the config mentions a ${cluster.*} variable
+func configReferencesClusterVars(cfg *interfaces.SpinnakerConfig) bool {
+	found := false
+	h := func(val string) (string, error) {
+		if strings.Contains(val, clusterVarPrefix) {
+			found = true
+		}
+		return val, nil
+	}
+	_, _ = inspect.InspectStrings(cfg.Config, h)
+	for _, p := range cfg.Profiles {
+		_, _ = inspect.InspectStrings(p, h)
+	}
+	for _, s := range cfg.ServiceSettings {
+		_, _ = inspect.InspectStrings(s, h)
+	}
+	return found
+}
@@ -0,0 +1,69 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterFacts_substitution(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      persistentStorage:
+        s3:
+          region: ${cluster.region}
+    profiles:
+      clouddriver:
+        provider: ${cluster.cloudProvider}
+`
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node1",
+			Labels: map[string]string{"topology.kubernetes.io/region": "us-west-2"},
+		},
+		Spec: corev1.NodeSpec{ProviderID: "aws:///us-west-2a/i-0123"},
+	}
+	tr, spinsvc := th.SetupTransformerFromSpinText(&ClusterFactsTransformerGenerator{}, s, t, node)
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	r, err := spinsvc.GetSpinnakerConfig().GetRawHalConfigPropString("persistentStorage.s3.region")
+	assert.Nil(t, err)
+	assert.Equal(t, "us-west-2", r)
+	p, err := spinsvc.GetSpinnakerConfig().GetRawServiceConfigPropString("clouddriver", "provider")
+	assert.Nil(t, err)
+	assert.Equal(t, "aws", p)
+}
+
+func TestClusterFacts_noReference(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	// No node in the fake client: resolution would fail if it ran
+	tr, _ := th.SetupTransformerFromSpinText(&ClusterFactsTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+}
+
+func TestProviderFromID(t *testing.T) {
+	assert.Equal(t, "aws", providerFromID("aws:///us-west-2a/i-0123"))
+	assert.Equal(t, "gce", providerFromID("gce://project/us-central1-a/node"))
+	assert.Equal(t, "", providerFromID(""))
+}
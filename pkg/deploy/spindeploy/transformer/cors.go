@@ -0,0 +1,112 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	uiBaseUrlProp      = "security.uiSecurity.overrideBaseUrl"
+	apiBaseUrlProp     = "security.apiSecurity.overrideBaseUrl"
+	deckCspEnvVar      = "CONTENT_SECURITY_POLICY"
+	deckDeploymentName = "deck"
+)
+
+// corsTransformer maps spec.security.cors onto Gate's allowed origins pattern
+// and Deck's CSP header. The expose base URLs are always part of the allowed
+// origins so a proxied UI does not break the moment extra origins are listed
+type corsTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type CorsTransformerGenerator struct{}
+
+func (g *CorsTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := corsTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *CorsTransformerGenerator) GetName() string {
+	return "Cors"
+}
+
+func (t *corsTransformer) TransformConfig(ctx context.Context) error {
+	cors := t.svc.GetSecurityConfig().Cors
+	if len(cors.AllowedOrigins) == 0 {
+		return nil
+	}
+	cfg := t.svc.GetSpinnakerConfig()
+	// An explicit CORS pattern wins
+	if v, _ := cfg.GetServiceConfigPropString(ctx, "gate", gateCorsPatternProp); v != "" {
+		return nil
+	}
+	hosts := make([]string, 0, len(cors.AllowedOrigins)+2)
+	// The hostnames the UI and the API are exposed on are always allowed
+	for _, prop := range []string{uiBaseUrlProp, apiBaseUrlProp} {
+		if v, _ := cfg.GetHalConfigPropString(ctx, prop); v != "" {
+			if u, err := url.Parse(v); err == nil && u.Hostname() != "" {
+				hosts = appendIfMissing(hosts, u.Hostname())
+			}
+		}
+	}
+	for _, o := range cors.AllowedOrigins {
+		h := o
+		if u, err := url.Parse(o); err == nil && u.Hostname() != "" {
+			h = u.Hostname()
+		}
+		hosts = appendIfMissing(hosts, h)
+	}
+	pattern := corsPattern(hosts)
+	t.log.Info(fmt.Sprintf("allowing origins %s through gate CORS", strings.Join(hosts, ", ")))
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	if cfg.Profiles["gate"] == nil {
+		cfg.Profiles["gate"] = interfaces.FreeForm{}
+	}
+	return inspect.SetObjectProp(cfg.Profiles["gate"], gateCorsPatternProp, pattern)
+}
+
+func (t *corsTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	csp := t.svc.GetSecurityConfig().Cors.ContentSecurityPolicy
+	if csp == "" || deploymentName != deckDeploymentName {
+		return nil
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		appendOrMergeEnvVar(&deployment.Spec.Template.Spec.Containers[i], corev1.EnvVar{Name: deckCspEnvVar, Value: csp})
+	}
+	return nil
+}
+
+// corsPattern builds the origin regex Gate matches request origins against
+func corsPattern(hosts []string) string {
+	escaped := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		escaped = append(escaped, strings.Replace(h, ".", "\\.", -1))
+	}
+	return fmt.Sprintf("^https?://(%s)(:[0-9]+)?/?.*$", strings.Join(escaped, "|"))
+}
+
+func appendIfMissing(hosts []string, h string) []string {
+	for _, e := range hosts {
+		if e == h {
+			return hosts
+		}
+	}
+	return append(hosts, h)
+}
@@ -0,0 +1,121 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCors_patternFromOriginsAndExposeUrls(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  security:
+    cors:
+      allowedOrigins:
+      - https://tools.acme.com
+      - backstage.acme.com
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      security:
+        uiSecurity:
+          overrideBaseUrl: https://spinnaker.acme.com
+        apiSecurity:
+          overrideBaseUrl: https://spinnaker-api.acme.com
+`
+	tr, spinSvc := th.SetupTransformerFromSpinText(&CorsTransformerGenerator{}, s, t)
+	if !assert.Nil(t, tr.TransformConfig(context.TODO())) {
+		return
+	}
+	p, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "gate", gateCorsPatternProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "^https?://(spinnaker\\.acme\\.com|spinnaker-api\\.acme\\.com|tools\\.acme\\.com|backstage\\.acme\\.com)(:[0-9]+)?/?.*$", p)
+}
+
+func TestCors_explicitPatternWins(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  security:
+    cors:
+      allowedOrigins:
+      - https://tools.acme.com
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    profiles:
+      gate:
+        cors:
+          allowedOriginsPattern: ^https://custom$
+`
+	tr, spinSvc := th.SetupTransformerFromSpinText(&CorsTransformerGenerator{}, s, t)
+	if !assert.Nil(t, tr.TransformConfig(context.TODO())) {
+		return
+	}
+	p, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "gate", gateCorsPatternProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "^https://custom$", p)
+}
+
+func TestCors_cspEnvOnDeck(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  security:
+    cors:
+      contentSecurityPolicy: "default-src 'self'"
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&CorsTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "deck", "testdata/input_deployment.yml", t)
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	deck := gen.Config["deck"].Deployment.Spec.Template.Spec.Containers[0]
+	found := false
+	for _, e := range deck.Env {
+		if e.Name == deckCspEnvVar {
+			found = true
+			assert.Equal(t, "default-src 'self'", e.Value)
+		}
+	}
+	assert.True(t, found)
+	// Other services are left alone
+	for _, e := range gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Env {
+		assert.NotEqual(t, deckCspEnvVar, e.Name)
+	}
+}
+
+func TestCors_disabledByDefault(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, spinSvc := th.SetupTransformerFromSpinText(&CorsTransformerGenerator{}, s, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+	assert.Nil(t, spinSvc.GetSpinnakerConfig().Profiles["gate"])
+}
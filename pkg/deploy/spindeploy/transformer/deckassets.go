@@ -0,0 +1,91 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	deckAssetsBaseUrlEnvVar = "ASSETS_BASE_URL"
+	gateCorsPatternProp     = "cors.allowedOriginsPattern"
+)
+
+// deckAssetsTransformer serves Deck's static assets from object storage or a
+// CDN: Deck rewrites asset links to the external base URL and uploads the
+// assets after startup, Gate allows the CDN origin through CORS
+type deckAssetsTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type DeckAssetsTransformerGenerator struct{}
+
+func (g *DeckAssetsTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := deckAssetsTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *DeckAssetsTransformerGenerator) GetName() string {
+	return "DeckAssets"
+}
+
+func (t *deckAssetsTransformer) TransformConfig(ctx context.Context) error {
+	a := t.svc.GetDeckAssetsConfig()
+	if !a.Enabled || a.BaseUrl == "" {
+		return nil
+	}
+	u, err := url.Parse(a.BaseUrl)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("deck assets base URL %q is not a valid URL", a.BaseUrl)
+	}
+	cfg := t.svc.GetSpinnakerConfig()
+	// An explicit CORS pattern wins
+	if v, _ := cfg.GetServiceConfigPropString(ctx, "gate", gateCorsPatternProp); v != "" {
+		return nil
+	}
+	t.log.Info(fmt.Sprintf("allowing deck assets origin %s through gate CORS", u.Hostname()))
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	if cfg.Profiles["gate"] == nil {
+		cfg.Profiles["gate"] = interfaces.FreeForm{}
+	}
+	pattern := fmt.Sprintf("^https?://%s(:[0-9]+)?/?.*$", strings.Replace(u.Hostname(), ".", "\\.", -1))
+	return inspect.SetObjectProp(cfg.Profiles["gate"], gateCorsPatternProp, pattern)
+}
+
+func (t *deckAssetsTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	a := t.svc.GetDeckAssetsConfig()
+	if !a.Enabled || a.BaseUrl == "" || deploymentName != "deck" {
+		return nil
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		c := &deployment.Spec.Template.Spec.Containers[i]
+		appendOrMergeEnvVar(c, corev1.EnvVar{Name: deckAssetsBaseUrlEnvVar, Value: a.BaseUrl})
+		if a.SyncCommand == "" || c.Lifecycle != nil && c.Lifecycle.PostStart != nil {
+			continue
+		}
+		if c.Lifecycle == nil {
+			c.Lifecycle = &corev1.Lifecycle{}
+		}
+		// Upload the assets of this exact build once the container is up
+		c.Lifecycle.PostStart = &corev1.Handler{
+			Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", a.SyncCommand}},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var deckAssetsSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  deckAssets:
+    enabled: true
+    baseUrl: https://cdn.acme.com/spinnaker
+    syncCommand: aws s3 sync /opt/deck/html s3://acme-cdn/spinnaker
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func TestDeckAssets_gateCorsAndDeckEnv(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&DeckAssetsTransformerGenerator{}, deckAssetsSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	v, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "gate", gateCorsPatternProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "^https?://cdn\\.acme\\.com(:[0-9]+)?/?.*$", v)
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "deck", "testdata/input_deployment.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	c := gen.Config["deck"].Deployment.Spec.Template.Spec.Containers[0]
+	found := false
+	for _, e := range c.Env {
+		if e.Name == deckAssetsBaseUrlEnvVar {
+			found = true
+			assert.Equal(t, "https://cdn.acme.com/spinnaker", e.Value)
+		}
+	}
+	assert.True(t, found)
+	if assert.NotNil(t, c.Lifecycle) && assert.NotNil(t, c.Lifecycle.PostStart) {
+		assert.Contains(t, c.Lifecycle.PostStart.Exec.Command[2], "aws s3 sync")
+	}
+}
+
+func TestDeckAssets_otherServicesUntouched(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&DeckAssetsTransformerGenerator{}, deckAssetsSpinsvc, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	for _, e := range gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Env {
+		assert.NotEqual(t, deckAssetsBaseUrlEnvVar, e.Name)
+	}
+}
+
+func TestDeckAssets_userCorsPatternWins(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&DeckAssetsTransformerGenerator{}, `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  deckAssets:
+    enabled: true
+    baseUrl: https://cdn.acme.com/spinnaker
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    profiles:
+      gate:
+        cors:
+          allowedOriginsPattern: ^https://ui\.acme\.com$
+`, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	v, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "gate", gateCorsPatternProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "^https://ui\\.acme\\.com$", v)
+}
+
+func TestDeckAssets_disabledByDefault(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&DeckAssetsTransformerGenerator{}, drainSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+	v, _ := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "gate", gateCorsPatternProp)
+	assert.Equal(t, "", v)
+}
@@ -0,0 +1,116 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// drainSetting describes how a service hands off its work before terminating
+type drainSetting struct {
+	// command run in the preStop hook
+	preStop []string
+	// seconds the pod is given to finish in-flight work
+	gracePeriodSeconds int64
+}
+
+// work draining for services holding in-flight work, preventing orphaned
+// pipeline executions during redeploys
+var drainSettings = map[string]drainSetting{
+	// Disable the instance so Orca stops picking up work, then let running stages finish
+	"orca": {
+		preStop:            []string{"/bin/sh", "-c", "wget -q -O- --post-data='' http://localhost:8083/admin/instance/disable || true; sleep 60"},
+		gracePeriodSeconds: 600,
+	},
+	// Give Echo time to hand its scheduler leases over to another replica
+	"echo": {
+		preStop:            []string{"/bin/sh", "-c", "sleep 30"},
+		gracePeriodSeconds: 120,
+	},
+}
+
+// drainTransformer adds preStop hooks and rollout settings so Orca and Echo drain
+// their work before pods terminate, and applies the per-service overrides
+// declared under spec.lifecycle
+type drainTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type DrainTransformerGenerator struct{}
+
+func (g *DrainTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := drainTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *DrainTransformerGenerator) GetName() string {
+	return "Drain"
+}
+
+func (t *drainTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *drainTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	s, hasDefault := drainSettings[deploymentName]
+	override, hasOverride := t.svc.GetLifecycle()[deploymentName]
+	if !hasDefault && !hasOverride {
+		return nil
+	}
+	container := util.GetContainerInDeployment(deployment, deploymentName)
+	if container == nil {
+		return fmt.Errorf("unable to find container %s in deployment, cannot add drain hook", deploymentName)
+	}
+	if container.Lifecycle == nil {
+		container.Lifecycle = &corev1.Lifecycle{}
+	}
+	// Spec overrides always win over the built-in drain settings
+	if len(override.PreStopCommand) > 0 {
+		container.Lifecycle.PreStop = &corev1.Handler{
+			Exec: &corev1.ExecAction{Command: override.PreStopCommand},
+		}
+	} else if hasDefault && container.Lifecycle.PreStop == nil {
+		container.Lifecycle.PreStop = &corev1.Handler{
+			Exec: &corev1.ExecAction{Command: s.preStop},
+		}
+	}
+	if len(override.PostStartCommand) > 0 {
+		container.Lifecycle.PostStart = &corev1.Handler{
+			Exec: &corev1.ExecAction{Command: override.PostStartCommand},
+		}
+	}
+	spec := &deployment.Spec.Template.Spec
+	if override.TerminationGracePeriodSeconds != nil {
+		grace := *override.TerminationGracePeriodSeconds
+		spec.TerminationGracePeriodSeconds = &grace
+	} else if hasDefault && spec.TerminationGracePeriodSeconds == nil {
+		grace := s.gracePeriodSeconds
+		spec.TerminationGracePeriodSeconds = &grace
+	}
+	// Keep the old pod draining until its replacement is ready
+	if deployment.Spec.Strategy.Type == "" && deployment.Spec.Strategy.RollingUpdate == nil {
+		maxUnavailable := intstr.FromInt(0)
+		maxSurge := intstr.FromInt(1)
+		deployment.Spec.Strategy = v1.DeploymentStrategy{
+			Type: v1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &v1.RollingUpdateDeployment{
+				MaxUnavailable: &maxUnavailable,
+				MaxSurge:       &maxSurge,
+			},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+var drainSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func TestDrain_orcaHookAndStrategy(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&DrainTransformerGenerator{}, drainSpinsvc, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "orca", "testdata/input_deployment_orca.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	dep := gen.Config["orca"].Deployment
+	c := dep.Spec.Template.Spec.Containers[0]
+	if assert.NotNil(t, c.Lifecycle) && assert.NotNil(t, c.Lifecycle.PreStop) {
+		assert.Contains(t, c.Lifecycle.PreStop.Exec.Command[2], "admin/instance/disable")
+	}
+	if assert.NotNil(t, dep.Spec.Template.Spec.TerminationGracePeriodSeconds) {
+		assert.Equal(t, int64(600), *dep.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	}
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, dep.Spec.Strategy.Type)
+	assert.Equal(t, 0, dep.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue())
+}
+
+func TestDrain_lifecycleOverrides(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  lifecycle:
+    orca:
+      terminationGracePeriodSeconds: 1800
+      preStopCommand: ["/bin/sh", "-c", "sleep 300"]
+    gate:
+      terminationGracePeriodSeconds: 90
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&DrainTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "orca", "testdata/input_deployment_orca.yml", t)
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+
+	// The spec override wins over the built-in Orca drain settings
+	orca := gen.Config["orca"].Deployment
+	c := orca.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"/bin/sh", "-c", "sleep 300"}, c.Lifecycle.PreStop.Exec.Command)
+	assert.Equal(t, int64(1800), *orca.Spec.Template.Spec.TerminationGracePeriodSeconds)
+
+	// Services without built-in settings honor their override too
+	gate := gen.Config["gate"].Deployment
+	assert.Equal(t, int64(90), *gate.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	assert.Nil(t, gate.Spec.Template.Spec.Containers[0].Lifecycle.PreStop)
+}
+
+func TestDrain_otherServicesUntouched(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&DrainTransformerGenerator{}, drainSpinsvc, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	assert.Nil(t, gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Lifecycle)
+}
@@ -0,0 +1,59 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	persistentStoreTypeProp = "persistentStorage.persistentStoreType"
+	s3VersioningProp        = "spinnaker.s3.versioning"
+)
+
+// front50VersioningTransformer turns on Front50 object versioning when it
+// stores its objects in operator managed S3 storage, so pipeline history
+// survives overwrites and deletions
+type front50VersioningTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type Front50VersioningTransformerGenerator struct{}
+
+func (g *Front50VersioningTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := front50VersioningTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *Front50VersioningTransformerGenerator) GetName() string {
+	return "Front50Versioning"
+}
+
+func (t *front50VersioningTransformer) TransformConfig(ctx context.Context) error {
+	cfg := t.svc.GetSpinnakerConfig()
+	storeType, err := cfg.GetHalConfigPropString(ctx, persistentStoreTypeProp)
+	if err != nil || storeType != "s3" {
+		return nil
+	}
+	// An explicit profile setting wins
+	if v, _ := cfg.GetServiceConfigPropString(ctx, "front50", s3VersioningProp); v != "" {
+		return nil
+	}
+	t.log.Info("enabling Front50 object versioning on S3 storage")
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	if cfg.Profiles["front50"] == nil {
+		cfg.Profiles["front50"] = interfaces.FreeForm{}
+	}
+	return inspect.SetObjectProp(cfg.Profiles["front50"], s3VersioningProp, true)
+}
@@ -0,0 +1,74 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFront50Versioning_enabledOnS3(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&Front50VersioningTransformerGenerator{}, `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      persistentStorage:
+        persistentStoreType: s3
+        s3:
+          bucket: spin-pipelines
+`, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	v, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "front50", s3VersioningProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "true", v)
+}
+
+func TestFront50Versioning_userSettingWins(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&Front50VersioningTransformerGenerator{}, `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      persistentStorage:
+        persistentStoreType: s3
+    profiles:
+      front50:
+        spinnaker:
+          s3:
+            versioning: false
+`, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	v, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "front50", s3VersioningProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "false", v)
+}
+
+func TestFront50Versioning_otherStorageUntouched(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&Front50VersioningTransformerGenerator{}, `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      persistentStorage:
+        persistentStoreType: redis
+`, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	v, _ := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "front50", s3VersioningProp)
+	assert.Equal(t, "", v)
+}
@@ -0,0 +1,140 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	gateManagementPortProp = "management.server.port"
+	gateManagementSslProp  = "management.server.ssl.enabled"
+	gateManagementPortName = "management"
+	gateHealthPath         = "/health"
+	// defaultGateManagementPort serves the health endpoints without authn
+	// when the user did not pick a management port themselves
+	defaultGateManagementPort = int32(8085)
+)
+
+// authnMethods are the halconfig authentication methods that put Gate's
+// health endpoint behind a 401
+var authnMethods = []string{"oauth2", "saml", "ldap", "x509", "iap"}
+
+// gateProbesTransformer points Gate's probes at an unauthenticated management
+// port when authn is enabled, because the default probes against the server
+// port start failing with 401 and the pod never becomes ready
+type gateProbesTransformer struct {
+	*DefaultTransformer
+	svc      interfaces.SpinnakerService
+	log      logr.Logger
+	authn    bool
+	mgmtPort int32
+	scheme   corev1.URIScheme
+}
+
+type GateProbesTransformerGenerator struct{}
+
+func (g *GateProbesTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := gateProbesTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *GateProbesTransformerGenerator) GetName() string {
+	return "GateProbes"
+}
+
+// TransformConfig makes sure Gate exposes a management port when authn is
+// enabled, keeping the port the user configured when there is one
+func (t *gateProbesTransformer) TransformConfig(ctx context.Context) error {
+	cfg := t.svc.GetSpinnakerConfig()
+	for _, m := range authnMethods {
+		if b, _ := cfg.GetHalConfigPropBool(fmt.Sprintf("security.authn.%s.enabled", m), false); b {
+			t.authn = true
+			break
+		}
+	}
+	if !t.authn {
+		return nil
+	}
+
+	t.scheme = corev1.URISchemeHTTP
+	if ssl, _ := cfg.GetServiceConfigPropString(ctx, "gate", gateManagementSslProp); ssl == "true" {
+		t.scheme = corev1.URISchemeHTTPS
+	}
+
+	p, err := cfg.GetServiceConfigPropString(ctx, "gate", gateManagementPortProp)
+	if err != nil {
+		return err
+	}
+	if p != "" {
+		port, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid gate profile setting %s: %s", gateManagementPortProp, p)
+		}
+		t.mgmtPort = int32(port)
+		return nil
+	}
+	t.mgmtPort = defaultGateManagementPort
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	if cfg.Profiles["gate"] == nil {
+		cfg.Profiles["gate"] = interfaces.FreeForm{}
+	}
+	return inspect.SetObjectProp(cfg.Profiles["gate"], gateManagementPortProp, t.mgmtPort)
+}
+
+func (t *gateProbesTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	if !t.authn || deploymentName != "gate" {
+		return nil
+	}
+	container := util.GetContainerInDeployment(deployment, "gate")
+	if container == nil {
+		return fmt.Errorf("container gate not found in deployment %s", deployment.Name)
+	}
+	t.log.Info(fmt.Sprintf("authn is enabled, pointing gate probes at management port %d", t.mgmtPort))
+
+	hasPort := false
+	for _, p := range container.Ports {
+		if p.ContainerPort == t.mgmtPort {
+			hasPort = true
+			break
+		}
+	}
+	if !hasPort {
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			Name:          gateManagementPortName,
+			ContainerPort: t.mgmtPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+
+	handler := corev1.Handler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path:   gateHealthPath,
+			Port:   intstr.FromInt(int(t.mgmtPort)),
+			Scheme: t.scheme,
+		},
+	}
+	if container.ReadinessProbe == nil {
+		container.ReadinessProbe = &corev1.Probe{}
+	}
+	container.ReadinessProbe.Handler = handler
+	if container.LivenessProbe != nil {
+		container.LivenessProbe.Handler = handler
+	}
+	return nil
+}
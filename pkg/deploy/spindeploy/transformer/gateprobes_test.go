@@ -0,0 +1,117 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var gateProbesAuthnSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      security:
+        authn:
+          x509:
+            enabled: true
+`
+
+var gateProbesCustomPortSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      security:
+        authn:
+          oauth2:
+            enabled: true
+    profiles:
+      gate:
+        management:
+          server:
+            port: 8089
+            ssl:
+              enabled: true
+`
+
+func TestGateProbes_authnDefaultsManagementPort(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&GateProbesTransformerGenerator{}, gateProbesAuthnSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	// The management port ends up in the gate profile so Gate actually opens it
+	p, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "gate", gateManagementPortProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "8085", p)
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	c := gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	if assert.NotNil(t, c.ReadinessProbe.HTTPGet) {
+		assert.Equal(t, gateHealthPath, c.ReadinessProbe.HTTPGet.Path)
+		assert.Equal(t, int(defaultGateManagementPort), c.ReadinessProbe.HTTPGet.Port.IntValue())
+		assert.Equal(t, corev1.URISchemeHTTP, c.ReadinessProbe.HTTPGet.Scheme)
+	}
+	assert.Nil(t, c.ReadinessProbe.Exec)
+	ports := []int32{}
+	for _, p := range c.Ports {
+		ports = append(ports, p.ContainerPort)
+	}
+	assert.Contains(t, ports, defaultGateManagementPort)
+}
+
+func TestGateProbes_userManagementPortWins(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&GateProbesTransformerGenerator{}, gateProbesCustomPortSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	// The user's port is kept, not overwritten with the default
+	p, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "gate", gateManagementPortProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "8089", p)
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	c := gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	if assert.NotNil(t, c.ReadinessProbe.HTTPGet) {
+		assert.Equal(t, 8089, c.ReadinessProbe.HTTPGet.Port.IntValue())
+		assert.Equal(t, corev1.URISchemeHTTPS, c.ReadinessProbe.HTTPGet.Scheme)
+	}
+}
+
+func TestGateProbes_noAuthnLeavesProbesAlone(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&GateProbesTransformerGenerator{}, `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	c := gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	assert.NotNil(t, c.ReadinessProbe.Exec)
+	assert.Nil(t, c.ReadinessProbe.HTTPGet)
+}
@@ -0,0 +1,101 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// scale-out prerequisites per service, profile props set unless already present
+var haPrereqs = map[string]map[string]interface{}{
+	// Multiple Gate replicas need sessions any replica can read
+	"gate": {
+		"spring.session.store-type": "redis",
+	},
+	// Multiple Orca replicas need the queue to detect dead workers
+	"orca": {
+		"queue.zombieCheck.enabled": true,
+	},
+	// Multiple Igor replicas need distributed locking for pollers
+	"igor": {
+		"locking.enabled": true,
+	},
+}
+
+// haTransformer configures the session and locking prerequisites of services
+// scaled beyond one replica, so scaling out doesn't silently break schedulers
+type haTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type HaTransformerGenerator struct{}
+
+func (g *HaTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := haTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *HaTransformerGenerator) GetName() string {
+	return "HighAvailability"
+}
+
+func (t *haTransformer) TransformConfig(ctx context.Context) error {
+	cfg := t.svc.GetSpinnakerConfig()
+	for svcName, props := range haPrereqs {
+		replicas, err := serviceReplicas(cfg, svcName)
+		if err != nil {
+			return err
+		}
+		if replicas < 2 {
+			continue
+		}
+		for prop, val := range props {
+			cur, err := cfg.GetRawServiceConfigPropString(svcName, prop)
+			if err == nil && cur != "" {
+				// User already set a value, keep it
+				continue
+			}
+			t.log.Info(fmt.Sprintf("%s runs %d replicas, defaulting %s to %v", svcName, replicas, prop, val))
+			if cfg.Profiles == nil {
+				cfg.Profiles = map[string]interfaces.FreeForm{}
+			}
+			if _, ok := cfg.Profiles[svcName]; !ok {
+				cfg.Profiles[svcName] = interfaces.FreeForm{}
+			}
+			if err := cfg.SetServiceConfigProp(svcName, prop, val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// serviceReplicas reads the replica count requested in the service settings
+func serviceReplicas(cfg *interfaces.SpinnakerConfig, svcName string) (int, error) {
+	s, ok := cfg.ServiceSettings[svcName]
+	if !ok {
+		return 0, nil
+	}
+	v, err := inspect.GetObjectProp(s, "replicas")
+	if err != nil {
+		return 0, nil
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return int(v.Int()), nil
+	case reflect.Float32, reflect.Float64:
+		return int(v.Float()), nil
+	}
+	return 0, nil
+}
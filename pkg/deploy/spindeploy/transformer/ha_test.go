@@ -0,0 +1,68 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHa_prereqsSetWhenScaledOut(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    service-settings:
+      gate:
+        replicas: 3
+      igor:
+        replicas: 2
+      orca:
+        replicas: 1
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&HaTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	cfg := spinsvc.GetSpinnakerConfig()
+	v, err := cfg.GetRawServiceConfigPropString("gate", "spring.session.store-type")
+	assert.Nil(t, err)
+	assert.Equal(t, "redis", v)
+	v, err = cfg.GetRawServiceConfigPropString("igor", "locking.enabled")
+	assert.Nil(t, err)
+	assert.Equal(t, "true", v)
+	// Orca runs a single replica, no prereq needed
+	_, ok := cfg.Profiles["orca"]
+	assert.False(t, ok)
+}
+
+func TestHa_userSettingsKept(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    service-settings:
+      gate:
+        replicas: 3
+    profiles:
+      gate:
+        spring:
+          session:
+            store-type: jdbc
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&HaTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	v, err := spinsvc.GetSpinnakerConfig().GetRawServiceConfigPropString("gate", "spring.session.store-type")
+	assert.Nil(t, err)
+	assert.Equal(t, "jdbc", v)
+}
@@ -0,0 +1,158 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultManagementPort is where every Java service serves its management
+// endpoints when spec.management is enabled without an explicit port
+const defaultManagementPort = int32(8095)
+
+// globalProfileName is the Halyard profile applied to every Java service
+const globalProfileName = "spinnaker"
+
+// nonJavaServices don't run Spring Boot and have no management endpoints
+var nonJavaServices = map[string]bool{
+	"deck":  true,
+	"redis": true,
+}
+
+// managementPortsTransformer moves the Spring Boot management endpoints of
+// every Java service to a dedicated port, so /health and /metrics are not
+// exposed on the main service port
+type managementPortsTransformer struct {
+	*DefaultTransformer
+	svc  interfaces.SpinnakerService
+	log  logr.Logger
+	port int32
+}
+
+type ManagementPortsTransformerGenerator struct{}
+
+func (g *ManagementPortsTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := managementPortsTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *ManagementPortsTransformerGenerator) GetName() string {
+	return "ManagementPorts"
+}
+
+// TransformConfig sets management.server.port in the global profile shared by
+// all Java services, every pod has its own IP so a single port works everywhere
+func (t *managementPortsTransformer) TransformConfig(ctx context.Context) error {
+	m := t.svc.GetManagementConfig()
+	if !m.Enabled {
+		return nil
+	}
+	t.port = m.Port
+	if t.port == 0 {
+		t.port = defaultManagementPort
+	}
+	cfg := t.svc.GetSpinnakerConfig()
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	if cfg.Profiles[globalProfileName] == nil {
+		cfg.Profiles[globalProfileName] = interfaces.FreeForm{}
+	}
+	return inspect.SetObjectProp(cfg.Profiles[globalProfileName], gateManagementPortProp, t.port)
+}
+
+func (t *managementPortsTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	if t.port == 0 {
+		return nil
+	}
+	if err := t.DefaultTransformer.TransformManifests(ctx, gen); err != nil {
+		return err
+	}
+	// Let the management port through any network policy generated for the services
+	for _, cfg := range gen.Config {
+		for _, o := range cfg.Resources {
+			if np, ok := o.(*networkingv1.NetworkPolicy); ok {
+				t.allowManagementPort(np)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *managementPortsTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	if nonJavaServices[deploymentName] {
+		return nil
+	}
+	container := util.GetContainerInDeployment(deployment, deploymentName)
+	if container == nil {
+		return nil
+	}
+	t.log.Info(fmt.Sprintf("moving management endpoints of %s to port %d", deploymentName, t.port))
+	hasPort := false
+	for _, p := range container.Ports {
+		if p.ContainerPort == t.port {
+			hasPort = true
+			break
+		}
+	}
+	if !hasPort {
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			Name:          gateManagementPortName,
+			ContainerPort: t.port,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	// Probes follow the health endpoint to its new port
+	handler := corev1.Handler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path:   gateHealthPath,
+			Port:   intstr.FromInt(int(t.port)),
+			Scheme: corev1.URISchemeHTTP,
+		},
+	}
+	if container.ReadinessProbe == nil {
+		container.ReadinessProbe = &corev1.Probe{}
+	}
+	container.ReadinessProbe.Handler = handler
+	if container.LivenessProbe != nil {
+		container.LivenessProbe.Handler = handler
+	}
+	return nil
+}
+
+// allowManagementPort appends the management port to every ingress rule that
+// restricts ports, rules without a port list already allow it
+func (t *managementPortsTransformer) allowManagementPort(np *networkingv1.NetworkPolicy) {
+	tcp := corev1.ProtocolTCP
+	for i := range np.Spec.Ingress {
+		rule := &np.Spec.Ingress[i]
+		if len(rule.Ports) == 0 {
+			continue
+		}
+		found := false
+		for _, p := range rule.Ports {
+			if p.Port != nil && p.Port.IntValue() == int(t.port) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			port := intstr.FromInt(int(t.port))
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &port})
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var managementSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  management:
+    enabled: true
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func TestManagementPorts_profileAndProbes(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&ManagementPortsTransformerGenerator{}, managementSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	// All Java services read the port through the global profile
+	p, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), "spinnaker", "management.server.port")
+	assert.Nil(t, err)
+	assert.Equal(t, "8095", p)
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	c := gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	if assert.NotNil(t, c.ReadinessProbe.HTTPGet) {
+		assert.Equal(t, "/health", c.ReadinessProbe.HTTPGet.Path)
+		assert.Equal(t, int(defaultManagementPort), c.ReadinessProbe.HTTPGet.Port.IntValue())
+	}
+	ports := []int32{}
+	for _, port := range c.Ports {
+		ports = append(ports, port.ContainerPort)
+	}
+	assert.Contains(t, ports, defaultManagementPort)
+}
+
+func TestManagementPorts_networkPolicyAllowsPort(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&ManagementPortsTransformerGenerator{}, managementSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	serverPort := intstr.FromInt(8084)
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "german", Name: "spin-gate"},
+		Spec: networkingv1.NetworkPolicySpec{
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{Ports: []networkingv1.NetworkPolicyPort{{Port: &serverPort}}},
+			},
+		},
+	}
+	cfg := gen.Config["gate"]
+	cfg.Resources = append(cfg.Resources, np)
+	gen.Config["gate"] = cfg
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	if assert.Len(t, np.Spec.Ingress[0].Ports, 2) {
+		assert.Equal(t, int(defaultManagementPort), np.Spec.Ingress[0].Ports[1].Port.IntValue())
+	}
+}
+
+func TestManagementPorts_disabled(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&ManagementPortsTransformerGenerator{}, `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	c := gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	assert.NotNil(t, c.ReadinessProbe.Exec)
+}
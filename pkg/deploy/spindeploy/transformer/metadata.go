@@ -0,0 +1,103 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// metadataTransformer stamps the labels and annotations from spec.metadata on
+// every generated Deployment, Service and pod template, with per service
+// additions from spec.services.<name>.metadata. Keys already set by the
+// generation are kept so selectors and operator bookkeeping stay intact
+type metadataTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type MetadataTransformerGenerator struct{}
+
+func (g *MetadataTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := metadataTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *MetadataTransformerGenerator) GetName() string {
+	return "Metadata"
+}
+
+func (t *metadataTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	cfg := t.effectiveMetadata(svcName)
+	stampMetadata(&svc.ObjectMeta, cfg)
+	return nil
+}
+
+func (t *metadataTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	cfg := t.effectiveMetadata(deploymentName)
+	stampMetadata(&deployment.ObjectMeta, cfg)
+	stampMetadata(&deployment.Spec.Template.ObjectMeta, cfg)
+	return nil
+}
+
+// effectiveMetadata merges the per service metadata over the spec level one,
+// service values win on the same key
+func (t *metadataTransformer) effectiveMetadata(name string) interfaces.MetadataConfig {
+	cfg := interfaces.MetadataConfig{}
+	if c := t.svc.GetMetadataConfig(); c != nil {
+		cfg = *c.DeepCopy()
+	}
+	s, ok := t.svc.GetServiceSpecs()[name]
+	if !ok || s.Metadata == nil {
+		return cfg
+	}
+	if len(s.Metadata.Labels) > 0 {
+		if cfg.Labels == nil {
+			cfg.Labels = map[string]string{}
+		}
+		for k, v := range s.Metadata.Labels {
+			cfg.Labels[k] = v
+		}
+	}
+	if len(s.Metadata.Annotations) > 0 {
+		if cfg.Annotations == nil {
+			cfg.Annotations = map[string]string{}
+		}
+		for k, v := range s.Metadata.Annotations {
+			cfg.Annotations[k] = v
+		}
+	}
+	return cfg
+}
+
+func stampMetadata(m *metav1.ObjectMeta, cfg interfaces.MetadataConfig) {
+	if len(cfg.Labels) > 0 {
+		if m.Labels == nil {
+			m.Labels = map[string]string{}
+		}
+		for k, v := range cfg.Labels {
+			if _, ok := m.Labels[k]; !ok {
+				m.Labels[k] = v
+			}
+		}
+	}
+	if len(cfg.Annotations) > 0 {
+		if m.Annotations == nil {
+			m.Annotations = map[string]string{}
+		}
+		for k, v := range cfg.Annotations {
+			if _, ok := m.Annotations[k]; !ok {
+				m.Annotations[k] = v
+			}
+		}
+	}
+}
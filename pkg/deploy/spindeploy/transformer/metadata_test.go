@@ -0,0 +1,96 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata_stamped(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  metadata:
+    labels:
+      team: delivery
+    annotations:
+      sidecar.istio.io/inject: "false"
+`
+	tr, _ := th.SetupTransformerFromSpinText(&MetadataTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	test.AddServiceToGenConfig(gen, "deck", "testdata/input_service.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	dep := gen.Config["gate"].Deployment
+	assert.Equal(t, "delivery", dep.Labels["team"])
+	assert.Equal(t, "delivery", dep.Spec.Template.Labels["team"])
+	assert.Equal(t, "false", dep.Spec.Template.Annotations["sidecar.istio.io/inject"])
+	assert.Equal(t, "delivery", gen.Config["deck"].Service.Labels["team"])
+}
+
+func TestMetadata_serviceOverride(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  metadata:
+    labels:
+      team: delivery
+  services:
+    gate:
+      metadata:
+        labels:
+          team: edge
+`
+	tr, _ := th.SetupTransformerFromSpinText(&MetadataTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	assert.Equal(t, "edge", gen.Config["gate"].Deployment.Labels["team"])
+}
+
+func TestMetadata_existingKeysKept(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  metadata:
+    labels:
+      app: overridden
+`
+	tr, _ := th.SetupTransformerFromSpinText(&MetadataTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	existing := gen.Config["gate"].Deployment.Spec.Template.Labels["app"]
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	// The selector label set by the generation must not be clobbered
+	assert.Equal(t, existing, gen.Config["gate"].Deployment.Spec.Template.Labels["app"])
+}
@@ -0,0 +1,160 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	monitoringContainer     = "monitoring-daemon"
+	defaultMonitoringImage  = "gcr.io/spinnaker-marketplace/monitoring-daemon:0.19.0"
+	monitoringConfigDir     = "/opt/spinnaker-monitoring/config"
+	monitoringRegistryDir   = "/opt/spinnaker-monitoring/registry"
+	monitoringDaemonPort    = int32(8008)
+	monitoringMetricsPath   = "/prometheus_metrics"
+	prometheusScrapeAnnot   = "prometheus.io/scrape"
+	prometheusPortAnnot     = "prometheus.io/port"
+	prometheusPathAnnot     = "prometheus.io/path"
+	spectatorEndpointFormat = "http://localhost:%d/spectator/metrics"
+)
+
+// monitoredServicePorts are the services exposing a spectator metrics endpoint
+// and the port they serve it on; Deck and Redis have nothing to poll
+var monitoredServicePorts = map[string]int32{
+	"clouddriver": 7002,
+	"echo":        8089,
+	"fiat":        7003,
+	"front50":     8080,
+	"gate":        8084,
+	"igor":        8088,
+	"kayenta":     8090,
+	"orca":        8083,
+	"rosco":       8087,
+}
+
+// monitoringTransformer injects the spinnaker-monitoring daemon sidecar into
+// each service deployment and generates its ConfigMaps, replacing the
+// hand-written service-settings the daemon classically requires
+type monitoringTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type MonitoringTransformerGenerator struct{}
+
+func (g *MonitoringTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := monitoringTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *MonitoringTransformerGenerator) GetName() string {
+	return "Monitoring"
+}
+
+func (t *monitoringTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	c := t.svc.GetMonitoringConfig()
+	if !c.Enabled {
+		return nil
+	}
+	svcs := make([]string, 0, len(gen.Config))
+	for svcName := range gen.Config {
+		if _, ok := monitoredServicePorts[svcName]; ok {
+			svcs = append(svcs, svcName)
+		}
+	}
+	sort.Strings(svcs)
+	for _, svcName := range svcs {
+		cfg := gen.Config[svcName]
+		if cfg.Deployment == nil {
+			continue
+		}
+		t.injectDaemon(c, svcName, &cfg)
+		gen.Config[svcName] = cfg
+	}
+	return nil
+}
+
+func (t *monitoringTransformer) injectDaemon(c *interfaces.MonitoringConfig, svcName string, cfg *generated.ServiceConfig) {
+	spec := &cfg.Deployment.Spec.Template.Spec
+	if getContainerByName(spec, monitoringContainer) != nil {
+		return
+	}
+
+	cm := t.monitoringConfigMap(c, svcName, cfg.Deployment.Namespace)
+	cfg.Resources = append(cfg.Resources, cm)
+	configVol := v1.Volume{
+		Name: "monitoring-config",
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: cm.Name},
+			},
+		},
+	}
+	if !volumeInArray(configVol.Name, spec.Volumes) {
+		spec.Volumes = append(spec.Volumes, configVol)
+	}
+
+	image := c.Image
+	if image == "" {
+		image = defaultMonitoringImage
+	}
+	sidecar := v1.Container{
+		Name:  monitoringContainer,
+		Image: image,
+		Ports: []v1.ContainerPort{{Name: "monitoring", ContainerPort: monitoringDaemonPort}},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: configVol.Name, ReadOnly: true, MountPath: monitoringConfigDir},
+			{Name: configVol.Name, ReadOnly: true, MountPath: monitoringRegistryDir},
+		},
+		ReadinessProbe: &v1.Probe{
+			Handler: v1.Handler{
+				HTTPGet: &v1.HTTPGetAction{Path: monitoringMetricsPath, Port: intstr.FromInt(int(monitoringDaemonPort))},
+			},
+		},
+	}
+	spec.Containers = append(spec.Containers, sidecar)
+
+	if c.Prometheus {
+		if cfg.Deployment.Spec.Template.Annotations == nil {
+			cfg.Deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		cfg.Deployment.Spec.Template.Annotations[prometheusScrapeAnnot] = "true"
+		cfg.Deployment.Spec.Template.Annotations[prometheusPortAnnot] = fmt.Sprintf("%d", monitoringDaemonPort)
+		cfg.Deployment.Spec.Template.Annotations[prometheusPathAnnot] = monitoringMetricsPath
+	}
+}
+
+// monitoringConfigMap renders the daemon configuration and the registry entry
+// pointing it at the service's spectator endpoint in the same pod
+func (t *monitoringTransformer) monitoringConfigMap(c *interfaces.MonitoringConfig, svcName, namespace string) *v1.ConfigMap {
+	if namespace == "" {
+		namespace = t.svc.GetNamespace()
+	}
+	daemonConfig := fmt.Sprintf("server:\n  host: 0.0.0.0\n  port: %d\nmonitor:\n  period: 30\n  metric_store:\n    - prometheus\nprometheus:\n  enabled: %t\n", monitoringDaemonPort, c.Prometheus)
+	registry := fmt.Sprintf("metrics_url: %s\n", fmt.Sprintf(spectatorEndpointFormat, monitoredServicePorts[svcName]))
+	return &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("spin-%s-monitoring", svcName),
+			Namespace: namespace,
+		},
+		Data: map[string]string{
+			"spinnaker-monitoring.yml":     daemonConfig,
+			fmt.Sprintf("%s.yml", svcName): registry,
+		},
+	}
+}
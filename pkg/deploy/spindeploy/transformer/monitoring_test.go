@@ -0,0 +1,100 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestMonitoring_injected(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  monitoring:
+    enabled: true
+    prometheus: true
+`
+	tr, _ := th.SetupTransformerFromSpinText(&MonitoringTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	spec := gen.Config["gate"].Deployment.Spec.Template.Spec
+	if !assert.Equal(t, 2, len(spec.Containers)) {
+		return
+	}
+	daemon := spec.Containers[1]
+	assert.Equal(t, monitoringContainer, daemon.Name)
+	assert.Equal(t, defaultMonitoringImage, daemon.Image)
+	assert.Equal(t, monitoringDaemonPort, daemon.Ports[0].ContainerPort)
+
+	// The daemon config and the gate registry entry land in one ConfigMap
+	if assert.Equal(t, 1, len(gen.Config["gate"].Resources)) {
+		cm, ok := gen.Config["gate"].Resources[0].(*v1.ConfigMap)
+		if assert.True(t, ok) {
+			assert.Equal(t, "spin-gate-monitoring", cm.Name)
+			assert.Contains(t, cm.Data, "spinnaker-monitoring.yml")
+			assert.Contains(t, cm.Data["gate.yml"], "http://localhost:8084/spectator/metrics")
+		}
+	}
+
+	a := gen.Config["gate"].Deployment.Spec.Template.Annotations
+	assert.Equal(t, "true", a[prometheusScrapeAnnot])
+	assert.Equal(t, "8008", a[prometheusPortAnnot])
+	assert.Equal(t, monitoringMetricsPath, a[prometheusPathAnnot])
+}
+
+func TestMonitoring_disabled(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&MonitoringTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, 1, len(gen.Config["gate"].Deployment.Spec.Template.Spec.Containers))
+	assert.Equal(t, 0, len(gen.Config["gate"].Resources))
+}
+
+func TestMonitoring_unmonitoredServiceSkipped(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  monitoring:
+    enabled: true
+    prometheus: true
+`
+	tr, _ := th.SetupTransformerFromSpinText(&MonitoringTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	// Deck has no spectator endpoint to poll
+	test.AddDeploymentToGenConfig(gen, "deck", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, 1, len(gen.Config["deck"].Deployment.Spec.Template.Spec.Containers))
+}
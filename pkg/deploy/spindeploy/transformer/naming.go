@@ -0,0 +1,168 @@
+package transformer
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namingTransformer renames generated objects with the prefix and suffix from
+// spec.naming and stamps the labels from spec.naming.labels on every generated
+// workload. Renaming covers the generated secrets and the references between
+// services, e.g. the spin-clouddriver URL in the generated gate config
+type namingTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type NamingTransformerGenerator struct{}
+
+func (g *NamingTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := namingTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *NamingTransformerGenerator) GetName() string {
+	return "Naming"
+}
+
+func (t *namingTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	stamp := t.svc.GetNamingConfig().Labels
+	for name, cfg := range gen.Config {
+		if cfg.Deployment != nil {
+			cfg.Deployment.Labels = stampLabels(cfg.Deployment.Labels, stamp)
+			cfg.Deployment.Spec.Template.Labels = stampLabels(cfg.Deployment.Spec.Template.Labels, stamp)
+		}
+		if cfg.Service != nil {
+			cfg.Service.Labels = stampLabels(cfg.Service.Labels, stamp)
+		}
+		gen.Config[name] = cfg
+	}
+	n := t.svc.GetNamingConfig()
+	if (n.Prefix == "" || n.Prefix == util.GeneratedNamePrefix) && n.Suffix == "" {
+		return nil
+	}
+	refs := serviceReferencePattern(gen)
+	for name, cfg := range gen.Config {
+		if cfg.Deployment != nil {
+			t.renameDeployment(cfg.Deployment)
+		}
+		if cfg.Service != nil {
+			t.renameService(cfg.Service)
+		}
+		for i := range cfg.Resources {
+			t.renameResource(cfg.Resources[i], refs)
+		}
+		gen.Config[name] = cfg
+	}
+	return nil
+}
+
+func (t *namingTransformer) renameDeployment(deployment *v1.Deployment) {
+	deployment.Name = t.rename(deployment.Name)
+	t.renameLabels(deployment.Labels)
+	if deployment.Spec.Selector != nil {
+		t.renameLabels(deployment.Spec.Selector.MatchLabels)
+	}
+	t.renameLabels(deployment.Spec.Template.Labels)
+	// Mounted secrets and config maps are renamed with the resources
+	for i := range deployment.Spec.Template.Spec.Volumes {
+		v := &deployment.Spec.Template.Spec.Volumes[i]
+		if v.Secret != nil {
+			v.Secret.SecretName = t.rename(v.Secret.SecretName)
+		}
+		if v.ConfigMap != nil {
+			v.ConfigMap.Name = t.rename(v.ConfigMap.Name)
+		}
+	}
+}
+
+func (t *namingTransformer) renameService(svc *corev1.Service) {
+	svc.Name = t.rename(svc.Name)
+	t.renameLabels(svc.Labels)
+	t.renameLabels(svc.Spec.Selector)
+}
+
+// renameResource renames generated secrets and config maps and rewrites the
+// service references their data carries, so services still find each other
+// under the new names
+func (t *namingTransformer) renameResource(o runtime.Object, refs *regexp.Regexp) {
+	switch r := o.(type) {
+	case *corev1.Secret:
+		r.Name = t.rename(r.Name)
+		t.renameLabels(r.Labels)
+		for k, v := range r.Data {
+			r.Data[k] = []byte(t.rewriteReferences(string(v), refs))
+		}
+	case *corev1.ConfigMap:
+		r.Name = t.rename(r.Name)
+		t.renameLabels(r.Labels)
+		for k, v := range r.Data {
+			r.Data[k] = t.rewriteReferences(v, refs)
+		}
+	}
+}
+
+func (t *namingTransformer) rename(name string) string {
+	return util.RenameGeneratedObject(t.svc, name)
+}
+
+// renameLabels rewrites label values referencing the default object names, e.g. cluster: spin-gate
+func (t *namingTransformer) renameLabels(labels map[string]string) {
+	for k, v := range labels {
+		labels[k] = t.rename(v)
+	}
+}
+
+// serviceReferencePattern matches a default generated service name of this
+// deployment, e.g. spin-clouddriver in http://spin-clouddriver.ns1:7002,
+// without matching inside longer names like spin-clouddriver-caching
+func serviceReferencePattern(gen *generated.SpinnakerGeneratedConfig) *regexp.Regexp {
+	names := make([]string, 0, len(gen.Config))
+	for n := range gen.Config {
+		names = append(names, regexp.QuoteMeta(n))
+	}
+	// Longest first so spin-clouddriver-caching wins over spin-clouddriver
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return regexp.MustCompile(util.GeneratedNamePrefix + `-(` + strings.Join(names, "|") + `)($|[^a-zA-Z0-9-])`)
+}
+
+// rewriteReferences renames the generated service names referenced in a
+// config or secret value
+func (t *namingTransformer) rewriteReferences(content string, refs *regexp.Regexp) string {
+	return refs.ReplaceAllStringFunc(content, func(m string) string {
+		sub := refs.FindStringSubmatch(m)
+		return util.GeneratedObjectName(t.svc, sub[1]) + sub[2]
+	})
+}
+
+// stampLabels adds the configured labels without overriding what Halyard generated,
+// the selector-relevant labels in particular stay untouched
+func stampLabels(labels map[string]string, stamp map[string]string) map[string]string {
+	if len(stamp) == 0 {
+		return labels
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range stamp {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
@@ -0,0 +1,134 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestNaming_customPrefix(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  naming:
+    prefix: team1
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&NamingTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	dep := gen.Config["gate"].Deployment
+	assert.Equal(t, "team1-gate", dep.Name)
+	assert.Equal(t, "team1-gate", dep.Spec.Template.Labels["cluster"])
+}
+
+func TestNaming_prefixAndSuffixRenameResources(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  naming:
+    prefix: team1
+    suffix: prod
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&NamingTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	cfg := gen.Config["gate"]
+	cfg.Service = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "spin-gate"}}
+	cfg.Deployment.Spec.Template.Spec.Volumes = []corev1.Volume{{
+		Name: "gate-files",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: "spin-gate-files-283"},
+		},
+	}}
+	cfg.Resources = []runtime.Object{&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spin-gate-files-283"},
+		Data: map[string][]byte{
+			"gate.yml": []byte("services:\n  clouddriver:\n    baseUrl: http://spin-clouddriver.ns1:7002\n"),
+		},
+	}}
+	gen.Config["gate"] = cfg
+	gen.Config["clouddriver"] = generated.ServiceConfig{}
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	dep := gen.Config["gate"].Deployment
+	assert.Equal(t, "team1-gate-prod", dep.Name)
+	assert.Equal(t, "team1-gate-prod", gen.Config["gate"].Service.Name)
+	// The generated secret, its mount and the service references it carries follow the rename
+	assert.Equal(t, "team1-gate-files-283-prod", dep.Spec.Template.Spec.Volumes[0].Secret.SecretName)
+	sec := gen.Config["gate"].Resources[0].(*corev1.Secret)
+	assert.Equal(t, "team1-gate-files-283-prod", sec.Name)
+	assert.Contains(t, string(sec.Data["gate.yml"]), "http://team1-clouddriver-prod.ns1:7002")
+}
+
+func TestNaming_noPrefixIsNoop(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&NamingTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	assert.Equal(t, "spin-gate", gen.Config["gate"].Deployment.Name)
+}
+
+func TestNaming_stampsLabels(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  naming:
+    labels:
+      team: delivery
+      cost-center: cc-1234
+      cluster: should-not-win
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&NamingTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	test.AddServiceToGenConfig(gen, "deck", "testdata/input_service.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	dep := gen.Config["gate"].Deployment
+	assert.Equal(t, "delivery", dep.Labels["team"])
+	assert.Equal(t, "cc-1234", dep.Spec.Template.Labels["cost-center"])
+	// Existing labels are never overridden
+	assert.Equal(t, "spin-gate", dep.Spec.Template.Labels["cluster"])
+	assert.Equal(t, "delivery", gen.Config["deck"].Service.Labels["team"])
+}
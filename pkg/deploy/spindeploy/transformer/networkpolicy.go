@@ -0,0 +1,183 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	spinAppLabel     = "app"
+	spinAppValue     = "spin"
+	spinClusterLabel = "cluster"
+	// namespaceNameLabel is set by the apiserver on every namespace
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+	dnsPort            = 53
+)
+
+// networkPolicyTransformer generates a default-deny NetworkPolicy baseline for
+// the namespace receiving the deployment: everything is denied, then DNS,
+// traffic between the Spinnaker services and ingress to the exposed UI and API
+// are explicitly allowed
+type networkPolicyTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type NetworkPolicyTransformerGenerator struct{}
+
+func (g *NetworkPolicyTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := networkPolicyTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *NetworkPolicyTransformerGenerator) GetName() string {
+	return "NetworkPolicy"
+}
+
+func (t *networkPolicyTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	cfg := t.svc.GetNetworkPolicyConfig()
+	if !cfg.Enabled {
+		return nil
+	}
+	ns := t.targetNamespace(gen)
+	policies := []*networkingv1.NetworkPolicy{
+		t.denyAll(ns),
+		t.allowDNS(ns),
+		t.allowSpinnaker(ns),
+		t.allowExposed(ns, cfg.AllowedNamespaces),
+	}
+	t.log.Info(fmt.Sprintf("generating %d baseline network policies for namespace %s", len(policies), ns))
+	// Attach to the first generated service, the policies are namespace scoped
+	names := make([]string, 0, len(gen.Config))
+	for name := range gen.Config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		svcConfig := gen.Config[name]
+		if svcConfig.Deployment == nil {
+			continue
+		}
+		for _, p := range policies {
+			svcConfig.Resources = append(svcConfig.Resources, p)
+		}
+		gen.Config[name] = svcConfig
+		return nil
+	}
+	return nil
+}
+
+// targetNamespace is the namespace of the generated deployments, falling back
+// to the SpinnakerService namespace
+func (t *networkPolicyTransformer) targetNamespace(gen *generated.SpinnakerGeneratedConfig) string {
+	for _, cfg := range gen.Config {
+		if cfg.Deployment != nil && cfg.Deployment.GetNamespace() != "" {
+			return cfg.Deployment.GetNamespace()
+		}
+	}
+	return t.svc.GetNamespace()
+}
+
+func (t *networkPolicyTransformer) newPolicy(ns, name string) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", t.svc.GetName(), name),
+			Namespace: ns,
+		},
+	}
+}
+
+// denyAll blocks all ingress and egress of every pod in the namespace,
+// the allow policies below punch the holes Spinnaker needs
+func (t *networkPolicyTransformer) denyAll(ns string) *networkingv1.NetworkPolicy {
+	p := t.newPolicy(ns, "deny-all")
+	p.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+	}
+	return p
+}
+
+// allowDNS lets every pod resolve names, nothing works without it
+func (t *networkPolicyTransformer) allowDNS(ns string) *networkingv1.NetworkPolicy {
+	p := t.newPolicy(ns, "allow-dns")
+	udp := corev1.ProtocolUDP
+	tcp := corev1.ProtocolTCP
+	port := intstr.FromInt(dnsPort)
+	p.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		Egress: []networkingv1.NetworkPolicyEgressRule{{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &port},
+				{Protocol: &tcp, Port: &port},
+			},
+		}},
+	}
+	return p
+}
+
+// allowSpinnaker lets the generated services talk to each other and, since
+// Clouddriver and friends reach cloud provider APIs we cannot enumerate,
+// grants them unrestricted egress
+func (t *networkPolicyTransformer) allowSpinnaker(ns string) *networkingv1.NetworkPolicy {
+	p := t.newPolicy(ns, "allow-spinnaker")
+	spinPods := metav1.LabelSelector{MatchLabels: map[string]string{spinAppLabel: spinAppValue}}
+	p.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: spinPods,
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{{PodSelector: &spinPods}},
+		}},
+		Egress: []networkingv1.NetworkPolicyEgressRule{{}},
+	}
+	return p
+}
+
+// allowExposed opens the UI and API to the outside and to the explicitly
+// allowed namespaces
+func (t *networkPolicyTransformer) allowExposed(ns string, allowedNamespaces []string) *networkingv1.NetworkPolicy {
+	p := t.newPolicy(ns, "allow-exposed")
+	var from []networkingv1.NetworkPolicyPeer
+	for _, allowed := range allowedNamespaces {
+		from = append(from, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: allowed}},
+		})
+	}
+	p.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      spinClusterLabel,
+				Operator: metav1.LabelSelectorOpIn,
+				// The naming transformer rewrites the cluster label values, the
+				// selector has to match the renamed pods
+				Values: []string{
+					util.GeneratedObjectName(t.svc, "gate"),
+					util.GeneratedObjectName(t.svc, "deck"),
+				},
+			}},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		// An empty from allows ingress from everywhere, gate and deck are the
+		// endpoints users and webhooks hit
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{From: from}},
+	}
+	return p
+}
@@ -0,0 +1,127 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func networkPolicySpinSvc(extra string) string {
+	return `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  networkPolicy:
+` + extra + `
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+}
+
+func TestNetworkPolicy_baselineGenerated(t *testing.T) {
+	s := networkPolicySpinSvc(`    enabled: true
+`)
+	tr, _ := th.SetupTransformerFromSpinText(&NetworkPolicyTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	res := gen.Config["gate"].Resources
+	if !assert.Equal(t, 4, len(res)) {
+		return
+	}
+	names := map[string]*networkingv1.NetworkPolicy{}
+	for _, r := range res {
+		p, ok := r.(*networkingv1.NetworkPolicy)
+		if assert.True(t, ok) {
+			names[p.GetName()] = p
+		}
+	}
+	deny := names["spinnaker-deny-all"]
+	if assert.NotNil(t, deny) {
+		assert.Equal(t, 2, len(deny.Spec.PolicyTypes))
+		assert.Empty(t, deny.Spec.Ingress)
+		assert.Empty(t, deny.Spec.Egress)
+	}
+	allow := names["spinnaker-allow-spinnaker"]
+	if assert.NotNil(t, allow) {
+		assert.Equal(t, spinAppValue, allow.Spec.PodSelector.MatchLabels[spinAppLabel])
+	}
+	assert.NotNil(t, names["spinnaker-allow-dns"])
+	exposed := names["spinnaker-allow-exposed"]
+	if assert.NotNil(t, exposed) {
+		// No allowed namespaces configured: open to everywhere
+		assert.Empty(t, exposed.Spec.Ingress[0].From)
+	}
+}
+
+func TestNetworkPolicy_allowedNamespaces(t *testing.T) {
+	s := networkPolicySpinSvc(`    enabled: true
+    allowedNamespaces: ["ci", "tools"]
+`)
+	tr, _ := th.SetupTransformerFromSpinText(&NetworkPolicyTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	for _, r := range gen.Config["gate"].Resources {
+		p, ok := r.(*networkingv1.NetworkPolicy)
+		if ok && p.GetName() == "spinnaker-allow-exposed" {
+			if assert.Equal(t, 2, len(p.Spec.Ingress[0].From)) {
+				assert.Equal(t, "ci", p.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels[namespaceNameLabel])
+			}
+			return
+		}
+	}
+	t.Fatal("allow-exposed policy not found")
+}
+
+func TestNetworkPolicy_renamedExposedServices(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  naming:
+    prefix: team1
+    suffix: prod
+  networkPolicy:
+    enabled: true
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&NetworkPolicyTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	for _, r := range gen.Config["gate"].Resources {
+		p, ok := r.(*networkingv1.NetworkPolicy)
+		if ok && p.GetName() == "spinnaker-allow-exposed" {
+			// The selector follows the renamed cluster label values
+			assert.Equal(t, []string{"team1-gate-prod", "team1-deck-prod"}, p.Spec.PodSelector.MatchExpressions[0].Values)
+			return
+		}
+	}
+	t.Fatal("allow-exposed policy not found")
+}
+
+func TestNetworkPolicy_disabled(t *testing.T) {
+	s := networkPolicySpinSvc(`    enabled: false
+`)
+	tr, _ := th.SetupTransformerFromSpinText(&NetworkPolicyTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Empty(t, gen.Config["gate"].Resources)
+}
@@ -0,0 +1,65 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	osLabel = "kubernetes.io/os"
+	// OsNodeSelectorAnnotation overrides the os nodeSelector added to generated pods.
+	// Set to "none" to disable the selector entirely
+	OsNodeSelectorAnnotation = "spinnaker.io/os-node-selector"
+	defaultOs                = "linux"
+)
+
+// nodeOsTransformer pins all generated pods to a node OS so workloads are not
+// scheduled on Windows nodes in mixed-OS clusters
+type nodeOsTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type NodeOsTransformerGenerator struct{}
+
+func (g *NodeOsTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := nodeOsTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *NodeOsTransformerGenerator) GetName() string {
+	return "NodeOs"
+}
+
+func (t *nodeOsTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *nodeOsTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	os := defaultOs
+	if v, ok := t.svc.GetAnnotations()[OsNodeSelectorAnnotation]; ok {
+		if v == "none" {
+			return nil
+		}
+		os = v
+	}
+	spec := &deployment.Spec.Template.Spec
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	// Don't override a selector coming from the config
+	if _, ok := spec.NodeSelector[osLabel]; !ok {
+		spec.NodeSelector[osLabel] = os
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	orcaProfileName        = "orca"
+	queueThreadsProp       = "keiko.queue.handler.threadCount"
+	executionCleanupProp   = "pollers.oldPipelineCleanup.enabled"
+	executionThresholdProp = "pollers.oldPipelineCleanup.thresholdDays"
+	zombieCheckProp        = "queue.zombieCheck.enabled"
+)
+
+// orcaTuningTransformer maps spec.orca queue and execution retention settings
+// to the orca profile, explicit profile settings always win
+type orcaTuningTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type OrcaTuningTransformerGenerator struct{}
+
+func (g *OrcaTuningTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := orcaTuningTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *OrcaTuningTransformerGenerator) GetName() string {
+	return "OrcaTuning"
+}
+
+func (t *orcaTuningTransformer) TransformConfig(ctx context.Context) error {
+	o := t.svc.GetOrcaConfig()
+	if o.QueueThreads == 0 && o.ExecutionRetentionDays == 0 && !o.ZombieCleanup {
+		return nil
+	}
+	cfg := t.svc.GetSpinnakerConfig()
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	if cfg.Profiles[orcaProfileName] == nil {
+		cfg.Profiles[orcaProfileName] = interfaces.FreeForm{}
+	}
+	if o.QueueThreads > 0 {
+		if err := t.setUnlessSet(ctx, queueThreadsProp, int(o.QueueThreads)); err != nil {
+			return err
+		}
+	}
+	if o.ExecutionRetentionDays > 0 {
+		if err := t.setUnlessSet(ctx, executionCleanupProp, true); err != nil {
+			return err
+		}
+		if err := t.setUnlessSet(ctx, executionThresholdProp, int(o.ExecutionRetentionDays)); err != nil {
+			return err
+		}
+	}
+	if o.ZombieCleanup {
+		if err := t.setUnlessSet(ctx, zombieCheckProp, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *orcaTuningTransformer) setUnlessSet(ctx context.Context, prop string, value interface{}) error {
+	cfg := t.svc.GetSpinnakerConfig()
+	// An explicit profile setting wins
+	if v, _ := cfg.GetServiceConfigPropString(ctx, orcaProfileName, prop); v != "" {
+		return nil
+	}
+	t.log.Info("tuning orca", "property", prop)
+	return inspect.SetObjectProp(cfg.Profiles[orcaProfileName], prop, value)
+}
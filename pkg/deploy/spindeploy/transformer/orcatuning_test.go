@@ -0,0 +1,94 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrcaTuning_mapsToProfile(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  orca:
+    queueThreads: 40
+    executionRetentionDays: 30
+    zombieCleanup: true
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&OrcaTuningTransformerGenerator{}, s, t)
+
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	p := spinsvc.GetSpinnakerConfig().Profiles["orca"]
+	if !assert.NotNil(t, p) {
+		return
+	}
+	v, err := inspect.GetObjectPropString(context.TODO(), p, queueThreadsProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "40", v)
+	v, err = inspect.GetObjectPropString(context.TODO(), p, executionCleanupProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "true", v)
+	v, err = inspect.GetObjectPropString(context.TODO(), p, executionThresholdProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "30", v)
+	v, err = inspect.GetObjectPropString(context.TODO(), p, zombieCheckProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "true", v)
+}
+
+func TestOrcaTuning_userProfileWins(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    profiles:
+      orca:
+        keiko:
+          queue:
+            handler:
+              threadCount: 10
+  orca:
+    queueThreads: 40
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&OrcaTuningTransformerGenerator{}, s, t)
+
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	v, err := inspect.GetObjectPropString(context.TODO(), spinsvc.GetSpinnakerConfig().Profiles["orca"], queueThreadsProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "10", v)
+}
+
+func TestOrcaTuning_noopWithoutConfig(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&OrcaTuningTransformerGenerator{}, s, t)
+
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+	assert.Nil(t, spinsvc.GetSpinnakerConfig().Profiles["orca"])
+}
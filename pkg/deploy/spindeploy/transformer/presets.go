@@ -0,0 +1,133 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/bom"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// preset is a catalog of defaults expanded into the config before user overrides
+type preset struct {
+	// replicas per service, 0 means leave untouched
+	replicas map[string]int
+	// hal config props set unless already present
+	halConfig map[string]interface{}
+}
+
+var presets = map[string]preset{
+	"quickstart": {
+		replicas: uniformReplicas(1),
+	},
+	"production-ha": {
+		replicas: map[string]int{
+			"deck":        3,
+			"gate":        3,
+			"orca":        3,
+			"echo":        3,
+			"clouddriver": 2,
+			"front50":     2,
+			"rosco":       2,
+			"fiat":        2,
+			"kayenta":     2,
+			// igor does not support running multiple replicas
+			"igor": 1,
+		},
+	},
+	"airgapped": {
+		replicas: uniformReplicas(1),
+		halConfig: map[string]interface{}{
+			"telemetry.enabled": false,
+			"stats.enabled":     false,
+		},
+	},
+}
+
+func uniformReplicas(count int) map[string]int {
+	r := map[string]int{}
+	for name := range bom.Services {
+		r[name] = count
+	}
+	return r
+}
+
+// presetsTransformer expands spec.profile into service settings and hal config defaults
+type presetsTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type PresetsTransformerGenerator struct{}
+
+func (g *PresetsTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := presetsTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *PresetsTransformerGenerator) GetName() string {
+	return "Presets"
+}
+
+func (t *presetsTransformer) TransformConfig(ctx context.Context) error {
+	name := t.svc.GetProfile()
+	if name == "" {
+		return nil
+	}
+	p, ok := presets[name]
+	if !ok {
+		return fmt.Errorf("unknown profile \"%s\", available profiles: [%s]", name, strings.Join(presetNames(), ", "))
+	}
+	t.log.Info(fmt.Sprintf("expanding profile %s", name))
+	config := t.svc.GetSpinnakerConfig()
+	for svcName, replicas := range p.replicas {
+		if err := setServiceSettingDefault(config, svcName, "replicas", replicas); err != nil {
+			return err
+		}
+	}
+	for prop, val := range p.halConfig {
+		if _, err := inspect.GetObjectProp(config.Config, prop); err == nil {
+			// User already set a value, keep it
+			continue
+		}
+		if err := config.SetHalConfigProp(prop, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setServiceSettingDefault sets prop in the service settings of svcName unless the user set one
+func setServiceSettingDefault(config *interfaces.SpinnakerConfig, svcName, prop string, value interface{}) error {
+	if config.ServiceSettings == nil {
+		config.ServiceSettings = map[string]interfaces.FreeForm{}
+	}
+	s, ok := config.ServiceSettings[svcName]
+	if !ok {
+		s = interfaces.FreeForm{}
+		config.ServiceSettings[svcName] = s
+	}
+	if _, err := inspect.GetObjectProp(s, prop); err == nil {
+		return nil
+	}
+	return inspect.SetObjectProp(s, prop, value)
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for n := range presets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,79 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets_quickstart(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  profile: quickstart
+  spinnakerConfig:
+    service-settings:
+      gate:
+        replicas: 5
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&PresetsTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+
+	config := spinsvc.GetSpinnakerConfig()
+	// User override wins
+	r, err := inspect.GetObjectProp(config.ServiceSettings["gate"], "replicas")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, r.Interface())
+	// Preset default applied
+	r, err = inspect.GetObjectProp(config.ServiceSettings["orca"], "replicas")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, r.Interface())
+}
+
+func TestPresets_airgappedHalConfig(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  profile: airgapped
+  spinnakerConfig:
+    config:
+      telemetry:
+        enabled: true
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&PresetsTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+
+	config := spinsvc.GetSpinnakerConfig()
+	// User explicitly enabled telemetry, preset must not turn it off
+	b, err := config.GetHalConfigPropBool("telemetry.enabled", false)
+	assert.Nil(t, err)
+	assert.True(t, b)
+	b, err = config.GetHalConfigPropBool("stats.enabled", true)
+	assert.Nil(t, err)
+	assert.False(t, b)
+}
+
+func TestPresets_unknown(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  profile: notaprofile
+  spinnakerConfig: {}
+`
+	tr, _ := th.SetupTransformerFromSpinText(&PresetsTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,156 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/bom"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const javaOptsEnvVar = "JAVA_OPTS"
+
+// proxyTransformer injects the egress proxy declared in spec.proxy into every service,
+// as standard proxy env vars plus the equivalent JVM flags for java services
+type proxyTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type ProxyTransformerGenerator struct{}
+
+func (g *ProxyTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := proxyTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *ProxyTransformerGenerator) GetName() string {
+	return "Proxy"
+}
+
+func (t *proxyTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *proxyTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	p := t.svc.GetProxyConfig()
+	if p.Http == "" && p.Https == "" {
+		return nil
+	}
+	envVars, err := proxyEnvVars(p)
+	if err != nil {
+		return err
+	}
+	if isJavaService(deploymentName) {
+		opts, err := jvmProxyFlags(p)
+		if err != nil {
+			return err
+		}
+		envVars = append(envVars, corev1.EnvVar{Name: javaOptsEnvVar, Value: opts})
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		c := &deployment.Spec.Template.Spec.Containers[i]
+		for _, e := range envVars {
+			appendOrMergeEnvVar(c, e)
+		}
+	}
+	return nil
+}
+
+// proxyEnvVars returns the standard proxy environment variables in both cases
+func proxyEnvVars(p *interfaces.ProxyConfig) ([]corev1.EnvVar, error) {
+	https := p.Https
+	if https == "" {
+		https = p.Http
+	}
+	vars := make([]corev1.EnvVar, 0)
+	add := func(name, value string) {
+		if value == "" {
+			return
+		}
+		vars = append(vars,
+			corev1.EnvVar{Name: strings.ToUpper(name), Value: value},
+			corev1.EnvVar{Name: strings.ToLower(name), Value: value})
+	}
+	add("HTTP_PROXY", p.Http)
+	add("HTTPS_PROXY", https)
+	add("NO_PROXY", p.NoProxy)
+	return vars, nil
+}
+
+// jvmProxyFlags translates the proxy config into java system properties
+func jvmProxyFlags(p *interfaces.ProxyConfig) (string, error) {
+	flags := make([]string, 0)
+	if p.Http != "" {
+		h, port, err := splitProxyUrl(p.Http, "80")
+		if err != nil {
+			return "", err
+		}
+		flags = append(flags, fmt.Sprintf("-Dhttp.proxyHost=%s", h), fmt.Sprintf("-Dhttp.proxyPort=%s", port))
+	}
+	https := p.Https
+	if https == "" {
+		https = p.Http
+	}
+	if https != "" {
+		h, port, err := splitProxyUrl(https, "443")
+		if err != nil {
+			return "", err
+		}
+		flags = append(flags, fmt.Sprintf("-Dhttps.proxyHost=%s", h), fmt.Sprintf("-Dhttps.proxyPort=%s", port))
+	}
+	if p.NoProxy != "" {
+		hosts := strings.Split(p.NoProxy, ",")
+		for i := range hosts {
+			hosts[i] = strings.TrimSpace(hosts[i])
+		}
+		flags = append(flags, fmt.Sprintf("-Dhttp.nonProxyHosts=%s", strings.Join(hosts, "|")))
+	}
+	return strings.Join(flags, " "), nil
+}
+
+func splitProxyUrl(rawUrl, defaultPort string) (string, string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid proxy url %s: %w", rawUrl, err)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return "", "", fmt.Errorf("invalid proxy url %s", rawUrl)
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return host, port, nil
+}
+
+// appendOrMergeEnvVar adds the env var to the container, appending to the existing
+// value when the variable is already defined (e.g. user-provided JAVA_OPTS)
+func appendOrMergeEnvVar(c *corev1.Container, envVar corev1.EnvVar) {
+	for i := range c.Env {
+		if c.Env[i].Name == envVar.Name {
+			if c.Env[i].Value != "" && envVar.Name == javaOptsEnvVar {
+				c.Env[i].Value = c.Env[i].Value + " " + envVar.Value
+			}
+			return
+		}
+	}
+	c.Env = append(c.Env, envVar)
+}
+
+func isJavaService(name string) bool {
+	s, ok := bom.Services[name]
+	return ok && s.Type == "java"
+}
@@ -0,0 +1,96 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestProxy_envVarsAndJvmFlags(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  proxy:
+    http: http://proxy.acme.com:3128
+    noProxy: localhost,169.254.169.254
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ProxyTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	env := map[string]string{}
+	for _, e := range gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	assert.Equal(t, "http://proxy.acme.com:3128", env["HTTP_PROXY"])
+	assert.Equal(t, "http://proxy.acme.com:3128", env["http_proxy"])
+	// https defaults to the http proxy
+	assert.Equal(t, "http://proxy.acme.com:3128", env["HTTPS_PROXY"])
+	assert.Equal(t, "localhost,169.254.169.254", env["NO_PROXY"])
+	assert.Contains(t, env[javaOptsEnvVar], "-Dhttp.proxyHost=proxy.acme.com")
+	assert.Contains(t, env[javaOptsEnvVar], "-Dhttp.proxyPort=3128")
+	assert.Contains(t, env[javaOptsEnvVar], "-Dhttp.nonProxyHosts=localhost|169.254.169.254")
+}
+
+func TestProxy_mergesExistingJavaOpts(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  proxy:
+    http: http://proxy.acme.com:3128
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ProxyTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	c := &gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	c.Env = append(c.Env, corev1.EnvVar{Name: javaOptsEnvVar, Value: "-Xmx2g"})
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	for _, e := range gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Env {
+		if e.Name == javaOptsEnvVar {
+			assert.Contains(t, e.Value, "-Xmx2g")
+			assert.Contains(t, e.Value, "-Dhttp.proxyHost=proxy.acme.com")
+		}
+	}
+}
+
+func TestProxy_disabled(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ProxyTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	for _, e := range gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Env {
+		assert.NotEqual(t, "HTTP_PROXY", e.Name)
+	}
+}
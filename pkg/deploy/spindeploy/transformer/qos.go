@@ -0,0 +1,96 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// qosTransformer enforces the quality of service class requested in
+// spec.qos on generated deployments, preventing OOM-kill driven flakiness
+// of Burstable or BestEffort pods on busy nodes
+type qosTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type QosTransformerGenerator struct{}
+
+func (g *QosTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := qosTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *QosTransformerGenerator) GetName() string {
+	return "Qos"
+}
+
+func (t *qosTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *qosTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	q := t.svc.GetQosConfig()
+	if q.Class != interfaces.QosGuaranteed {
+		return nil
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		c := &deployment.Spec.Template.Spec.Containers[i]
+		if isGuaranteed(c) {
+			continue
+		}
+		if !q.Enforce {
+			t.log.Info(fmt.Sprintf("container %s of %s does not run with Guaranteed QoS", c.Name, deploymentName))
+			continue
+		}
+		enforceGuaranteed(c)
+		if !isGuaranteed(c) {
+			t.log.Info(fmt.Sprintf("container %s of %s declares no resources, unable to enforce Guaranteed QoS", c.Name, deploymentName))
+		}
+	}
+	return nil
+}
+
+// isGuaranteed returns true when cpu and memory requests equal their limits
+func isGuaranteed(c *corev1.Container) bool {
+	for _, r := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		l, lok := c.Resources.Limits[r]
+		req, rok := c.Resources.Requests[r]
+		if !lok || !rok || l.Cmp(req) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// enforceGuaranteed aligns requests and limits, preferring the declared limit
+func enforceGuaranteed(c *corev1.Container) {
+	for _, r := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		l, lok := c.Resources.Limits[r]
+		req, rok := c.Resources.Requests[r]
+		if !lok && !rok {
+			continue
+		}
+		if c.Resources.Limits == nil {
+			c.Resources.Limits = corev1.ResourceList{}
+		}
+		if c.Resources.Requests == nil {
+			c.Resources.Requests = corev1.ResourceList{}
+		}
+		if lok {
+			c.Resources.Requests[r] = l
+		} else {
+			c.Resources.Limits[r] = req
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var qosSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  qos:
+    class: Guaranteed
+    enforce: true
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func TestQos_enforceAlignsRequestsWithLimits(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&QosTransformerGenerator{}, qosSpinsvc, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	c := &gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	c.Resources.Limits = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	c.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("100m"),
+	}
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	c = &gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	// The limit wins when both are declared
+	assert.Equal(t, resource.MustParse("500m"), c.Resources.Requests[corev1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("1Gi"), c.Resources.Requests[corev1.ResourceMemory])
+	assert.True(t, isGuaranteed(c))
+}
+
+func TestQos_enforcePromotesRequestsToLimits(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&QosTransformerGenerator{}, qosSpinsvc, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	c := &gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	c.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("250m"),
+		corev1.ResourceMemory: resource.MustParse("512Mi"),
+	}
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	c = &gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, resource.MustParse("250m"), c.Resources.Limits[corev1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("512Mi"), c.Resources.Limits[corev1.ResourceMemory])
+	assert.True(t, isGuaranteed(c))
+}
+
+func TestQos_reportOnlyLeavesResourcesAlone(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  qos:
+    class: Guaranteed
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&QosTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	c := &gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	c.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("100m"),
+	}
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	c = &gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	assert.Empty(t, c.Resources.Limits)
+	assert.Equal(t, resource.MustParse("100m"), c.Resources.Requests[corev1.ResourceCPU])
+}
+
+func TestQos_noClassRequested(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&QosTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	assert.Empty(t, gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Resources.Limits)
+}
@@ -0,0 +1,95 @@
+package transformer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	redisPasswordEnvVar = "REDIS_PASSWORD"
+	redisPasswordKey    = "password"
+	redisPasswordProp   = "redis.password"
+	redisServiceName    = "redis"
+)
+
+// redisAuthTransformer configures Redis authentication and TLS uniformly for
+// all services. The password stays in its Kubernetes secret: services resolve
+// it from an injected env var, and the embedded Redis requires it on startup.
+type redisAuthTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type RedisAuthTransformerGenerator struct{}
+
+func (g *RedisAuthTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := redisAuthTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *RedisAuthTransformerGenerator) GetName() string {
+	return "RedisAuth"
+}
+
+func (t *redisAuthTransformer) TransformConfig(ctx context.Context) error {
+	r := t.svc.GetRedisConfig()
+	if r.PasswordSecret == "" && !r.Tls {
+		return nil
+	}
+	cfg := t.svc.GetSpinnakerConfig()
+	if r.Tls {
+		base, _ := cfg.GetServiceSettingsPropString(ctx, redisServiceName, redisBaseUrlProp)
+		if strings.HasPrefix(base, "redis://") {
+			cfg.ServiceSettings[redisServiceName][redisBaseUrlProp] = "rediss://" + strings.TrimPrefix(base, "redis://")
+		}
+	}
+	if r.PasswordSecret != "" {
+		t.log.Info("enabling Redis authentication for all services")
+		if cfg.Profiles == nil {
+			cfg.Profiles = map[string]interfaces.FreeForm{}
+		}
+		if cfg.Profiles[globalProfileName] == nil {
+			cfg.Profiles[globalProfileName] = interfaces.FreeForm{}
+		}
+		// Spring resolves the env var injected into every service below
+		return inspect.SetObjectProp(cfg.Profiles[globalProfileName], redisPasswordProp, "${"+redisPasswordEnvVar+"}")
+	}
+	return nil
+}
+
+func (t *redisAuthTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	r := t.svc.GetRedisConfig()
+	if r.PasswordSecret == "" {
+		return nil
+	}
+	env := corev1.EnvVar{
+		Name: redisPasswordEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: r.PasswordSecret},
+				Key:                  redisPasswordKey,
+			},
+		},
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		c := &deployment.Spec.Template.Spec.Containers[i]
+		appendOrMergeEnvVar(c, env)
+		// The embedded Redis requires the same password on startup
+		if deploymentName == redisServiceName {
+			c.Args = append(c.Args, "--requirepass", "$("+redisPasswordEnvVar+")")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var redisAuthSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  redis:
+    passwordSecret: redis-auth
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func TestRedisAuth_passwordInProfileAndEnv(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&RedisAuthTransformerGenerator{}, redisAuthSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	v, err := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), globalProfileName, redisPasswordProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "${REDIS_PASSWORD}", v)
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	c := gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	var env *corev1.EnvVar
+	for i := range c.Env {
+		if c.Env[i].Name == redisPasswordEnvVar {
+			env = &c.Env[i]
+		}
+	}
+	if assert.NotNil(t, env) && assert.NotNil(t, env.ValueFrom) {
+		assert.Equal(t, "redis-auth", env.ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, redisPasswordKey, env.ValueFrom.SecretKeyRef.Key)
+	}
+}
+
+func TestRedisAuth_embeddedRedisRequiresPassword(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&RedisAuthTransformerGenerator{}, redisAuthSpinsvc, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "redis", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	c := gen.Config["redis"].Deployment.Spec.Template.Spec.Containers[0]
+	assert.Contains(t, c.Args, "--requirepass")
+	assert.Contains(t, c.Args, "$(REDIS_PASSWORD)")
+}
+
+func TestRedisAuth_tlsRewritesBaseUrl(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&RedisAuthTransformerGenerator{}, `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  redis:
+    tls: true
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    service-settings:
+      redis:
+        baseUrl: redis://external-redis.acme.com:6379
+`, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	v, err := spinSvc.GetSpinnakerConfig().GetServiceSettingsPropString(context.TODO(), "redis", redisBaseUrlProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "rediss://external-redis.acme.com:6379", v)
+}
+
+func TestRedisAuth_disabledLeavesConfigAlone(t *testing.T) {
+	tr, spinSvc := th.SetupTransformerFromSpinText(&RedisAuthTransformerGenerator{}, drainSpinsvc, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+	v, _ := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(context.TODO(), globalProfileName, redisPasswordProp)
+	assert.Equal(t, "", v)
+}
@@ -0,0 +1,146 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RedisMigrationAnnotation triggers a migration of all services from the embedded
+	// Redis to the external backend given as value, or rolls it back with "rollback"
+	RedisMigrationAnnotation = "spinnaker.io/migrate-redis"
+	RedisMigrationRollback   = "rollback"
+
+	RedisMigrationPhaseSwitching  = "Switching"
+	RedisMigrationPhaseVerifying  = "Verifying"
+	RedisMigrationPhaseCompleted  = "Completed"
+	RedisMigrationPhaseRolledBack = "RolledBack"
+
+	redisBaseUrlProp = "baseUrl"
+	redisSkipProp    = "skipLifeCycleManagement"
+)
+
+// redisMigrationTransformer drives an annotation triggered migration from the embedded
+// Redis to an external backend: switch the global Redis settings, stop managing the
+// embedded Redis, verify service health and support rolling back
+type redisMigrationTransformer struct {
+	*DefaultTransformer
+	svc    interfaces.SpinnakerService
+	log    logr.Logger
+	client client.Client
+}
+
+type RedisMigrationTransformerGenerator struct{}
+
+func (g *RedisMigrationTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := redisMigrationTransformer{svc: svc, log: log, client: client, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *RedisMigrationTransformerGenerator) GetName() string {
+	return "RedisMigration"
+}
+
+func (t *redisMigrationTransformer) TransformConfig(ctx context.Context) error {
+	target := t.svc.GetAnnotations()[RedisMigrationAnnotation]
+	st := t.svc.GetStatus()
+	if target == "" {
+		return nil
+	}
+	if target == RedisMigrationRollback {
+		return t.rollback(st)
+	}
+	m := st.RedisMigration
+	if m == nil || m.TargetUrl != target {
+		// Record where we come from so the migration can be rolled back
+		prev, _ := t.svc.GetSpinnakerConfig().GetServiceSettingsPropString(ctx, "redis", redisBaseUrlProp)
+		m = &interfaces.RedisMigrationStatus{
+			Phase:       RedisMigrationPhaseSwitching,
+			TargetUrl:   target,
+			PreviousUrl: prev,
+		}
+		st.RedisMigration = m
+		t.log.Info(fmt.Sprintf("starting redis migration to %s", target))
+	}
+	if err := t.setRedisSettings(m.TargetUrl, true); err != nil {
+		return err
+	}
+	switch m.Phase {
+	case RedisMigrationPhaseSwitching:
+		m.Phase = RedisMigrationPhaseVerifying
+	case RedisMigrationPhaseVerifying:
+		healthy, err := t.servicesHealthy(ctx)
+		if err != nil {
+			return err
+		}
+		if healthy {
+			t.log.Info(fmt.Sprintf("redis migration to %s verified, all services healthy", m.TargetUrl))
+			m.Phase = RedisMigrationPhaseCompleted
+		}
+	}
+	return nil
+}
+
+// rollback restores the Redis settings recorded when the migration started
+func (t *redisMigrationTransformer) rollback(st *interfaces.SpinnakerServiceStatus) error {
+	m := st.RedisMigration
+	if m == nil {
+		return nil
+	}
+	if m.PreviousUrl != "" {
+		if err := t.setRedisSettings(m.PreviousUrl, true); err != nil {
+			return err
+		}
+	}
+	// Empty previous URL means the embedded Redis, which Halyard redeploys
+	// once the override is no longer applied
+	t.log.Info("rolling back redis migration")
+	m.Phase = RedisMigrationPhaseRolledBack
+	return nil
+}
+
+// setRedisSettings points every service at the given Redis and stops Halyard
+// from managing the embedded one
+func (t *redisMigrationTransformer) setRedisSettings(url string, skipLifecycle bool) error {
+	cfg := t.svc.GetSpinnakerConfig()
+	if cfg.ServiceSettings == nil {
+		cfg.ServiceSettings = map[string]interfaces.FreeForm{}
+	}
+	if _, ok := cfg.ServiceSettings["redis"]; !ok {
+		cfg.ServiceSettings["redis"] = interfaces.FreeForm{}
+	}
+	cfg.ServiceSettings["redis"][redisBaseUrlProp] = url
+	cfg.ServiceSettings["redis"][redisSkipProp] = skipLifecycle
+	return nil
+}
+
+// servicesHealthy returns true when all deployed Spinnaker services are fully available
+func (t *redisMigrationTransformer) servicesHealthy(ctx context.Context) (bool, error) {
+	deps := &appsv1.DeploymentList{}
+	opts := []client.ListOption{client.InNamespace(t.svc.GetNamespace()), client.MatchingLabels{"app": "spin"}}
+	if err := t.client.List(ctx, deps, opts...); err != nil {
+		return false, err
+	}
+	if len(deps.Items) == 0 {
+		return false, nil
+	}
+	for _, dep := range deps.Items {
+		var want int32 = 1
+		if dep.Spec.Replicas != nil {
+			want = *dep.Spec.Replicas
+		}
+		if dep.Status.ReadyReplicas < want {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,100 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var redisMigrationManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+  annotations:
+    spinnaker.io/migrate-redis: redis://external:6379
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func TestRedisMigration_switchAndVerify(t *testing.T) {
+	one := int32(1)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spin-gate",
+			Namespace: "ns1",
+			Labels:    map[string]string{"app": "spin"},
+		},
+		Spec:   appsv1.DeploymentSpec{Replicas: &one},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	tr, spinsvc := th.SetupTransformerFromSpinText(&RedisMigrationTransformerGenerator{}, redisMigrationManifest, t, dep)
+
+	// First pass records the previous settings and switches
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	m := spinsvc.GetStatus().RedisMigration
+	if assert.NotNil(t, m) {
+		assert.Equal(t, RedisMigrationPhaseVerifying, m.Phase)
+		assert.Equal(t, "redis://external:6379", m.TargetUrl)
+	}
+	v, err := spinsvc.GetSpinnakerConfig().GetServiceSettingsPropString(context.TODO(), "redis", "baseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "redis://external:6379", v)
+
+	// Second pass verifies service health and completes
+	err = tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, RedisMigrationPhaseCompleted, spinsvc.GetStatus().RedisMigration.Phase)
+}
+
+func TestRedisMigration_unhealthyStaysVerifying(t *testing.T) {
+	one := int32(1)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spin-gate",
+			Namespace: "ns1",
+			Labels:    map[string]string{"app": "spin"},
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: &one},
+	}
+	tr, spinsvc := th.SetupTransformerFromSpinText(&RedisMigrationTransformerGenerator{}, redisMigrationManifest, t, dep)
+
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+	assert.Equal(t, RedisMigrationPhaseVerifying, spinsvc.GetStatus().RedisMigration.Phase)
+}
+
+func TestRedisMigration_rollback(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+  annotations:
+    spinnaker.io/migrate-redis: rollback
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+status:
+  redisMigration:
+    phase: Verifying
+    targetUrl: redis://external:6379
+    previousUrl: redis://old:6379
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&RedisMigrationTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, RedisMigrationPhaseRolledBack, spinsvc.GetStatus().RedisMigration.Phase)
+	v, err := spinsvc.GetSpinnakerConfig().GetServiceSettingsPropString(context.TODO(), "redis", "baseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "redis://old:6379", v)
+}
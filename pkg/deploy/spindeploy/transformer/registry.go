@@ -0,0 +1,75 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// registryTransformer rewrites every container image reference of the
+// generated Deployments, sidecars and init containers included, to a private
+// registry mirror so air-gapped clusters never pull from the public registries
+type registryTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type RegistryTransformerGenerator struct{}
+
+func (g *RegistryTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := registryTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *RegistryTransformerGenerator) GetName() string {
+	return "ImageRegistry"
+}
+
+func (t *registryTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *registryTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	mirror := t.svc.GetImageRegistry()
+	if override, ok := t.svc.GetImageRegistryOverrides()[deploymentName]; ok {
+		mirror = override
+	}
+	if mirror == "" {
+		return nil
+	}
+	spec := &deployment.Spec.Template.Spec
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].Image = mirrorImage(spec.InitContainers[i].Image, mirror)
+	}
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		mirrored := mirrorImage(c.Image, mirror)
+		if mirrored != c.Image {
+			t.log.Info(fmt.Sprintf("rewriting image of container %s in %s to %s", c.Name, deploymentName, mirrored))
+			c.Image = mirrored
+		}
+	}
+	return nil
+}
+
+// mirrorImage replaces the registry of an image reference with the mirror.
+// Bare references like redis:5-alpine implicitly come from docker.io and get
+// the mirror prepended
+func mirrorImage(image, mirror string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return fmt.Sprintf("%s/%s", mirror, parts[1])
+	}
+	return fmt.Sprintf("%s/%s", mirror, image)
+}
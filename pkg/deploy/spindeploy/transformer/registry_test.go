@@ -0,0 +1,66 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_globalMirror(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  imageRegistry: mirror.internal:5000
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&RegistryTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, "mirror.internal:5000/armory/gate:1.9.0-83b6e52-193c7b9-edge3",
+		gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestRegistry_perServiceOverride(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  imageRegistry: mirror.internal:5000
+  imageRegistryOverrides:
+    gate: other.mirror
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&RegistryTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	test.AddDeploymentToGenConfig(gen, "echo", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, "other.mirror/armory/gate:1.9.0-83b6e52-193c7b9-edge3",
+		gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "mirror.internal:5000/armory/gate:1.9.0-83b6e52-193c7b9-edge3",
+		gen.Config["echo"].Deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestMirrorImage(t *testing.T) {
+	// Fully qualified references get their registry swapped
+	assert.Equal(t, "mirror/spinnaker-marketplace/gate:1.2.3", mirrorImage("gcr.io/spinnaker-marketplace/gate:1.2.3", "mirror"))
+	assert.Equal(t, "mirror/gate:1.2.3", mirrorImage("localhost:5000/gate:1.2.3", "mirror"))
+	// Bare docker.io references get the mirror prepended
+	assert.Equal(t, "mirror/redis:5-alpine", mirrorImage("redis:5-alpine", "mirror"))
+	assert.Equal(t, "mirror/armory/gate:1.2.3", mirrorImage("armory/gate:1.2.3", "mirror"))
+}
@@ -0,0 +1,89 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resourcesTransformer patches the generated Deployments with the compute
+// resources of spec.services, replacing the fragile service-settings route
+// through the halconfig blob
+type resourcesTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type ResourcesTransformerGenerator struct{}
+
+func (g *ResourcesTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := resourcesTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *ResourcesTransformerGenerator) GetName() string {
+	return "Resources"
+}
+
+func (t *resourcesTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	spec, ok := t.svc.GetServiceSpecs()[deploymentName]
+	if !ok || spec.Resources == nil {
+		return nil
+	}
+	container := util.GetContainerInDeployment(deployment, deploymentName)
+	if container == nil {
+		return fmt.Errorf("unable to find container %s in deployment to set resources", deploymentName)
+	}
+	requests, err := parseResourceList(spec.Resources.Requests)
+	if err != nil {
+		return fmt.Errorf("invalid resource requests for %s: %w", deploymentName, err)
+	}
+	limits, err := parseResourceList(spec.Resources.Limits)
+	if err != nil {
+		return fmt.Errorf("invalid resource limits for %s: %w", deploymentName, err)
+	}
+	container.Resources.Requests = mergeResourceList(container.Resources.Requests, requests)
+	container.Resources.Limits = mergeResourceList(container.Resources.Limits, limits)
+	return nil
+}
+
+func parseResourceList(m map[string]string) (corev1.ResourceList, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	l := corev1.ResourceList{}
+	for name, val := range m {
+		q, err := resource.ParseQuantity(val)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s quantity %q: %v", name, val, err)
+		}
+		l[corev1.ResourceName(name)] = q
+	}
+	return l, nil
+}
+
+// mergeResourceList overrides the resources named in over, keeping the rest
+func mergeResourceList(base, over corev1.ResourceList) corev1.ResourceList {
+	if len(over) == 0 {
+		return base
+	}
+	if base == nil {
+		base = corev1.ResourceList{}
+	}
+	for name, q := range over {
+		base[name] = q
+	}
+	return base
+}
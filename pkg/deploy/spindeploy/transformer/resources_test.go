@@ -0,0 +1,93 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestResources_applied(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  services:
+    gate:
+      resources:
+        requests:
+          cpu: 500m
+          memory: 1Gi
+        limits:
+          memory: 2Gi
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ResourcesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	c := util.GetContainerInDeployment(gen.Config["gate"].Deployment, "gate")
+	if !assert.NotNil(t, c) {
+		return
+	}
+	assert.Equal(t, resource.MustParse("500m"), c.Resources.Requests["cpu"])
+	assert.Equal(t, resource.MustParse("1Gi"), c.Resources.Requests["memory"])
+	assert.Equal(t, resource.MustParse("2Gi"), c.Resources.Limits["memory"])
+}
+
+func TestResources_invalidQuantity(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  services:
+    gate:
+      resources:
+        requests:
+          cpu: lots
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ResourcesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "lots")
+	}
+}
+
+func TestResources_noConfig(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ResourcesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	c := util.GetContainerInDeployment(gen.Config["gate"].Deployment, "gate")
+	assert.Equal(t, 0, len(c.Resources.Requests))
+}
@@ -0,0 +1,86 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// schedulingTransformer injects the nodeSelector, tolerations and affinity from
+// spec.scheduling into every generated pod template so Spinnaker can be pinned
+// to a dedicated node pool. Services can override the spec level settings with
+// spec.services.<name>.scheduling
+type schedulingTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type SchedulingTransformerGenerator struct{}
+
+func (g *SchedulingTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := schedulingTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *SchedulingTransformerGenerator) GetName() string {
+	return "Scheduling"
+}
+
+func (t *schedulingTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *schedulingTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	cfg := t.effectiveScheduling(deploymentName)
+	spec := &deployment.Spec.Template.Spec
+	if len(cfg.NodeSelector) > 0 {
+		if spec.NodeSelector == nil {
+			spec.NodeSelector = map[string]string{}
+		}
+		// Don't override a selector coming from the config
+		for k, v := range cfg.NodeSelector {
+			if _, ok := spec.NodeSelector[k]; !ok {
+				spec.NodeSelector[k] = v
+			}
+		}
+	}
+	for i := range cfg.Tolerations {
+		spec.Tolerations = append(spec.Tolerations, *cfg.Tolerations[i].DeepCopy())
+	}
+	if cfg.Affinity != nil && spec.Affinity == nil {
+		spec.Affinity = cfg.Affinity.DeepCopy()
+	}
+	return nil
+}
+
+// effectiveScheduling resolves the scheduling settings for a service, each
+// field set on the per service override replaces the spec level value
+func (t *schedulingTransformer) effectiveScheduling(deploymentName string) interfaces.SchedulingConfig {
+	cfg := interfaces.SchedulingConfig{}
+	if c := t.svc.GetSchedulingConfig(); c != nil {
+		cfg = *c
+	}
+	s, ok := t.svc.GetServiceSpecs()[deploymentName]
+	if !ok || s.Scheduling == nil {
+		return cfg
+	}
+	if len(s.Scheduling.NodeSelector) > 0 {
+		cfg.NodeSelector = s.Scheduling.NodeSelector
+	}
+	if len(s.Scheduling.Tolerations) > 0 {
+		cfg.Tolerations = s.Scheduling.Tolerations
+	}
+	if s.Scheduling.Affinity != nil {
+		cfg.Affinity = s.Scheduling.Affinity
+	}
+	return cfg
+}
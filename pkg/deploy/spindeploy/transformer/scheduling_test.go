@@ -0,0 +1,132 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduling_applied(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  scheduling:
+    nodeSelector:
+      pool: spinnaker
+    tolerations:
+    - key: dedicated
+      operator: Equal
+      value: spinnaker
+      effect: NoSchedule
+    affinity:
+      nodeAffinity:
+        requiredDuringSchedulingIgnoredDuringExecution:
+          nodeSelectorTerms:
+          - matchExpressions:
+            - key: pool
+              operator: In
+              values:
+              - spinnaker
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SchedulingTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	spec := gen.Config["gate"].Deployment.Spec.Template.Spec
+	assert.Equal(t, "spinnaker", spec.NodeSelector["pool"])
+	if assert.Equal(t, 1, len(spec.Tolerations)) {
+		assert.Equal(t, "dedicated", spec.Tolerations[0].Key)
+	}
+	if assert.NotNil(t, spec.Affinity) && assert.NotNil(t, spec.Affinity.NodeAffinity) {
+		terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		assert.Equal(t, 1, len(terms))
+	}
+}
+
+func TestScheduling_serviceOverride(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  scheduling:
+    nodeSelector:
+      pool: spinnaker
+  services:
+    gate:
+      scheduling:
+        nodeSelector:
+          pool: gateway
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SchedulingTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	assert.Equal(t, "gateway", gen.Config["gate"].Deployment.Spec.Template.Spec.NodeSelector["pool"])
+}
+
+func TestScheduling_existingSelectorKept(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  scheduling:
+    nodeSelector:
+      pool: spinnaker
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SchedulingTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	gen.Config["gate"].Deployment.Spec.Template.Spec.NodeSelector = map[string]string{"pool": "custom"}
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	assert.Equal(t, "custom", gen.Config["gate"].Deployment.Spec.Template.Spec.NodeSelector["pool"])
+}
+
+func TestScheduling_noConfig(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SchedulingTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	spec := gen.Config["gate"].Deployment.Spec.Template.Spec
+	assert.Equal(t, 0, len(spec.NodeSelector))
+	assert.Equal(t, 0, len(spec.Tolerations))
+	assert.Nil(t, spec.Affinity)
+}
@@ -0,0 +1,233 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	secretAgentVault = "vault"
+	secretAgentSpire = "spire"
+
+	secretAgentContainer    = "secret-agent"
+	secretAgentConfigDir    = "/etc/secret-agent"
+	defaultSecretAgentMount = "/opt/spinnaker/secret-agent"
+	defaultVaultImage       = "vault:1.2.3"
+	defaultSpireImage       = "gcr.io/spiffe-io/spiffe-helper:0.4"
+	defaultSpireSocketPath  = "/run/spire/sockets"
+)
+
+// secretAgentTransformer injects a Vault Agent or SPIRE helper sidecar into
+// service deployments and renders per-service secret templates into a shared
+// in-memory volume, as an alternative to operator-side secret resolution
+type secretAgentTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type SecretAgentTransformerGenerator struct{}
+
+func (g *SecretAgentTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := secretAgentTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *SecretAgentTransformerGenerator) GetName() string {
+	return "SecretAgent"
+}
+
+func (t *secretAgentTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	c := t.svc.GetSecretAgentConfig()
+	if c.Type == "" {
+		return nil
+	}
+	if c.Type != secretAgentVault && c.Type != secretAgentSpire {
+		return fmt.Errorf("unknown secret agent type %q, expected %q or %q", c.Type, secretAgentVault, secretAgentSpire)
+	}
+	for _, svcName := range t.targetServices(c) {
+		cfg, ok := gen.Config[svcName]
+		if !ok || cfg.Deployment == nil {
+			return fmt.Errorf("no deployment generated for service %s, cannot inject secret agent", svcName)
+		}
+		if err := t.injectAgent(c, svcName, &cfg); err != nil {
+			return err
+		}
+		gen.Config[svcName] = cfg
+	}
+	return nil
+}
+
+// targetServices returns the services getting the sidecar: those with
+// templates plus the ones explicitly listed, sorted and deduplicated
+func (t *secretAgentTransformer) targetServices(c *interfaces.SecretAgentConfig) []string {
+	set := map[string]bool{}
+	for svc := range c.Templates {
+		set[svc] = true
+	}
+	for _, svc := range c.Services {
+		set[svc] = true
+	}
+	svcs := make([]string, 0, len(set))
+	for svc := range set {
+		svcs = append(svcs, svc)
+	}
+	sort.Strings(svcs)
+	return svcs
+}
+
+func (t *secretAgentTransformer) injectAgent(c *interfaces.SecretAgentConfig, svcName string, cfg *generated.ServiceConfig) error {
+	container := util.GetContainerInDeployment(cfg.Deployment, svcName)
+	if container == nil {
+		return fmt.Errorf("unable to find container %s in deployment, cannot inject secret agent", svcName)
+	}
+	mountPath := c.MountPath
+	if mountPath == "" {
+		mountPath = defaultSecretAgentMount
+	}
+	spec := &cfg.Deployment.Spec.Template.Spec
+
+	// Rendered secrets live in memory only, shared between the agent and the service
+	renderedVol := v1.Volume{
+		Name: "secret-agent-rendered",
+		VolumeSource: v1.VolumeSource{
+			EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory},
+		},
+	}
+	if !volumeInArray(renderedVol.Name, spec.Volumes) {
+		spec.Volumes = append(spec.Volumes, renderedVol)
+	}
+	container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+		Name:      renderedVol.Name,
+		ReadOnly:  true,
+		MountPath: mountPath,
+	})
+
+	cm := t.agentConfigMap(c, svcName, cfg.Deployment.Namespace, mountPath)
+	cfg.Resources = append(cfg.Resources, cm)
+	configVol := v1.Volume{
+		Name: "secret-agent-config",
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: cm.Name},
+			},
+		},
+	}
+	if !volumeInArray(configVol.Name, spec.Volumes) {
+		spec.Volumes = append(spec.Volumes, configVol)
+	}
+
+	sidecar := v1.Container{
+		Name: secretAgentContainer,
+		VolumeMounts: []v1.VolumeMount{
+			{Name: configVol.Name, ReadOnly: true, MountPath: secretAgentConfigDir},
+			{Name: renderedVol.Name, MountPath: mountPath},
+		},
+	}
+	switch c.Type {
+	case secretAgentVault:
+		sidecar.Image = c.Image
+		if sidecar.Image == "" {
+			sidecar.Image = defaultVaultImage
+		}
+		sidecar.Command = []string{"vault", "agent", fmt.Sprintf("-config=%s/agent.hcl", secretAgentConfigDir)}
+		sidecar.Env = []v1.EnvVar{{Name: "VAULT_ADDR", Value: c.VaultAddress}}
+	case secretAgentSpire:
+		sidecar.Image = c.Image
+		if sidecar.Image == "" {
+			sidecar.Image = defaultSpireImage
+		}
+		sidecar.Command = []string{"/spiffe-helper", "-config", fmt.Sprintf("%s/helper.conf", secretAgentConfigDir)}
+		socketPath := c.SpireSocketPath
+		if socketPath == "" {
+			socketPath = defaultSpireSocketPath
+		}
+		socketVol := v1.Volume{
+			Name: "spire-agent-socket",
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{Path: socketPath},
+			},
+		}
+		if !volumeInArray(socketVol.Name, spec.Volumes) {
+			spec.Volumes = append(spec.Volumes, socketVol)
+		}
+		sidecar.VolumeMounts = append(sidecar.VolumeMounts, v1.VolumeMount{
+			Name:      socketVol.Name,
+			ReadOnly:  true,
+			MountPath: defaultSpireSocketPath,
+		})
+	}
+	spec.Containers = append(spec.Containers, sidecar)
+	return nil
+}
+
+// agentConfigMap holds the agent configuration and the service's secret templates
+func (t *secretAgentTransformer) agentConfigMap(c *interfaces.SecretAgentConfig, svcName, namespace, mountPath string) *v1.ConfigMap {
+	if namespace == "" {
+		namespace = t.svc.GetNamespace()
+	}
+	data := map[string]string{}
+	switch c.Type {
+	case secretAgentVault:
+		data["agent.hcl"] = vaultAgentConfig(c, svcName, mountPath)
+		for name, tpl := range c.Templates[svcName] {
+			data[name] = tpl
+		}
+	case secretAgentSpire:
+		data["helper.conf"] = spireHelperConfig(mountPath)
+	}
+	return &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("spin-%s-secret-agent", svcName),
+			Namespace: namespace,
+		},
+		Data: data,
+	}
+}
+
+// vaultAgentConfig renders the Vault Agent configuration with a kubernetes
+// auto auth stanza and one template stanza per secret template
+func vaultAgentConfig(c *interfaces.SecretAgentConfig, svcName, mountPath string) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "exit_after_auth = false\npid_file = \"/tmp/vault-agent.pid\"\n\n")
+	fmt.Fprintf(b, "vault {\n  address = %q\n}\n\n", c.VaultAddress)
+	fmt.Fprintf(b, "auto_auth {\n  method \"kubernetes\" {\n    config = {\n      role = %q\n    }\n  }\n}\n", c.VaultRole)
+	names := make([]string, 0, len(c.Templates[svcName]))
+	for name := range c.Templates[svcName] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(b, "\ntemplate {\n  source      = %q\n  destination = %q\n}\n",
+			path.Join(secretAgentConfigDir, name), path.Join(mountPath, name))
+	}
+	return b.String()
+}
+
+// spireHelperConfig renders the spiffe-helper configuration writing SVIDs
+// into the shared volume
+func spireHelperConfig(mountPath string) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "agent_address = %q\n", path.Join(defaultSpireSocketPath, "agent.sock"))
+	fmt.Fprintf(b, "cert_dir = %q\n", mountPath)
+	fmt.Fprintf(b, "svid_file_name = \"svid.pem\"\n")
+	fmt.Fprintf(b, "svid_key_file_name = \"svid_key.pem\"\n")
+	fmt.Fprintf(b, "svid_bundle_file_name = \"svid_bundle.pem\"\n")
+	return b.String()
+}
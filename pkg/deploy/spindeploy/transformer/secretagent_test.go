@@ -0,0 +1,134 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+var vaultAgentSpinsvc = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  secretAgent:
+    type: vault
+    vaultAddress: https://vault.example.com:8200
+    vaultRole: spinnaker
+    templates:
+      gate:
+        saml.properties: '{{ with secret "secret/spinnaker/gate" }}{{ .Data.saml }}{{ end }}'
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+
+func TestSecretAgent_vaultSidecar(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&SecretAgentTransformerGenerator{}, vaultAgentSpinsvc, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+
+	spec := gen.Config["gate"].Deployment.Spec.Template.Spec
+	if !assert.Equal(t, 2, len(spec.Containers)) {
+		return
+	}
+	sidecar := spec.Containers[1]
+	assert.Equal(t, "secret-agent", sidecar.Name)
+	assert.Equal(t, defaultVaultImage, sidecar.Image)
+	assert.Equal(t, []string{"vault", "agent", "-config=/etc/secret-agent/agent.hcl"}, sidecar.Command)
+
+	// The service container sees the rendered secrets read-only
+	gate := spec.Containers[0]
+	found := false
+	for _, m := range gate.VolumeMounts {
+		if m.Name == "secret-agent-rendered" {
+			found = true
+			assert.True(t, m.ReadOnly)
+			assert.Equal(t, defaultSecretAgentMount, m.MountPath)
+		}
+	}
+	assert.True(t, found)
+
+	if assert.Equal(t, 1, len(gen.Config["gate"].Resources)) {
+		cm, ok := gen.Config["gate"].Resources[0].(*v1.ConfigMap)
+		if assert.True(t, ok) {
+			assert.Equal(t, "spin-gate-secret-agent", cm.Name)
+			assert.Contains(t, cm.Data["agent.hcl"], `address = "https://vault.example.com:8200"`)
+			assert.Contains(t, cm.Data["agent.hcl"], `role = "spinnaker"`)
+			assert.Contains(t, cm.Data["agent.hcl"], `source      = "/etc/secret-agent/saml.properties"`)
+			assert.Contains(t, cm.Data["agent.hcl"], `destination = "/opt/spinnaker/secret-agent/saml.properties"`)
+			assert.Contains(t, cm.Data, "saml.properties")
+		}
+	}
+}
+
+func TestSecretAgent_spireSidecar(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  secretAgent:
+    type: spire
+    services: ["gate"]
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SecretAgentTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+
+	spec := gen.Config["gate"].Deployment.Spec.Template.Spec
+	if !assert.Equal(t, 2, len(spec.Containers)) {
+		return
+	}
+	sidecar := spec.Containers[1]
+	assert.Equal(t, defaultSpireImage, sidecar.Image)
+	assert.True(t, volumeInArray("spire-agent-socket", spec.Volumes))
+	for _, vol := range spec.Volumes {
+		if vol.Name == "spire-agent-socket" {
+			assert.Equal(t, defaultSpireSocketPath, vol.HostPath.Path)
+		}
+	}
+}
+
+func TestSecretAgent_unknownType(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  secretAgent:
+    type: doppler
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SecretAgentTransformerGenerator{}, s, t)
+	err := tr.TransformManifests(context.TODO(), &generated.SpinnakerGeneratedConfig{})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown secret agent type")
+	}
+}
+
+func TestSecretAgent_missingDeployment(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&SecretAgentTransformerGenerator{}, vaultAgentSpinsvc, t)
+	err := tr.TransformManifests(context.TODO(), &generated.SpinnakerGeneratedConfig{})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "no deployment generated for service gate")
+	}
+}
@@ -0,0 +1,178 @@
+package transformer
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// EnvelopeProviderAnnotation names the provider that wrapped the data encryption key
+	EnvelopeProviderAnnotation = "spinnaker.io/envelope-provider"
+	// EnvelopeKeyIdAnnotation identifies the wrapping key used
+	EnvelopeKeyIdAnnotation = "spinnaker.io/envelope-key-id"
+	// EnvelopeWrappedDekAnnotation holds the base64 encoded wrapped data encryption key
+	EnvelopeWrappedDekAnnotation = "spinnaker.io/envelope-wrapped-dek"
+	// kekSecretKey is the data key holding the key encryption key in the "secret" provider
+	kekSecretKey = "key"
+	dekLength    = 32
+)
+
+// EnvelopeEncrypter wraps a data encryption key with a provider managed key
+type EnvelopeEncrypter interface {
+	WrapKey(ctx context.Context, dek []byte) ([]byte, error)
+}
+
+// EnvelopeEncrypterBuilder creates the encrypter of a provider for the configured key id
+type EnvelopeEncrypterBuilder func(ctx context.Context, c client.Client, ns string, keyId string) (EnvelopeEncrypter, error)
+
+// EnvelopeEncrypters maps provider names to their builders. Cloud KMS providers
+// register here, mirroring how secret engines are registered.
+var EnvelopeEncrypters = map[string]EnvelopeEncrypterBuilder{
+	"secret": newSecretKekEncrypter,
+}
+
+// secretEncryptionTransformer envelope encrypts the values of generated Secrets
+// before they are applied, for clusters without etcd encryption at rest. Each
+// Secret gets a fresh data encryption key; the wrapped key and provider details
+// are stored in annotations for an init container or CSI provider to decrypt.
+type secretEncryptionTransformer struct {
+	*DefaultTransformer
+	svc    interfaces.SpinnakerService
+	client client.Client
+	log    logr.Logger
+}
+
+type SecretEncryptionTransformerGenerator struct{}
+
+func (g *SecretEncryptionTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := secretEncryptionTransformer{svc: svc, client: client, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *SecretEncryptionTransformerGenerator) GetName() string {
+	return "SecretEncryption"
+}
+
+func (t *secretEncryptionTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	cfg := t.svc.GetSecretEncryptionConfig()
+	if cfg.Provider == "" {
+		return nil
+	}
+	builder, ok := EnvelopeEncrypters[cfg.Provider]
+	if !ok {
+		return fmt.Errorf("no envelope encryption provider %s registered", cfg.Provider)
+	}
+	enc, err := builder(ctx, t.client, t.svc.GetNamespace(), cfg.KeyId)
+	if err != nil {
+		return err
+	}
+	for _, svcCfg := range gen.Config {
+		for _, r := range svcCfg.Resources {
+			sec, ok := r.(*v1.Secret)
+			if !ok {
+				continue
+			}
+			if err := t.encryptSecret(ctx, enc, cfg, sec); err != nil {
+				return fmt.Errorf("unable to encrypt generated secret %s: %w", sec.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *secretEncryptionTransformer) encryptSecret(ctx context.Context, enc EnvelopeEncrypter, cfg *interfaces.SecretEncryptionConfig, sec *v1.Secret) error {
+	if sec.Annotations[EnvelopeWrappedDekAnnotation] != "" {
+		// Already encrypted
+		return nil
+	}
+	dek := make([]byte, dekLength)
+	if _, err := rand.Read(dek); err != nil {
+		return err
+	}
+	for k, v := range sec.Data {
+		sealed, err := gcmSeal(dek, v)
+		if err != nil {
+			return err
+		}
+		sec.Data[k] = sealed
+	}
+	for k, v := range sec.StringData {
+		sealed, err := gcmSeal(dek, []byte(v))
+		if err != nil {
+			return err
+		}
+		if sec.Data == nil {
+			sec.Data = map[string][]byte{}
+		}
+		sec.Data[k] = sealed
+		delete(sec.StringData, k)
+	}
+	wrapped, err := enc.WrapKey(ctx, dek)
+	if err != nil {
+		return err
+	}
+	if sec.Annotations == nil {
+		sec.Annotations = map[string]string{}
+	}
+	sec.Annotations[EnvelopeProviderAnnotation] = cfg.Provider
+	sec.Annotations[EnvelopeKeyIdAnnotation] = cfg.KeyId
+	sec.Annotations[EnvelopeWrappedDekAnnotation] = base64.StdEncoding.EncodeToString(wrapped)
+	return nil
+}
+
+// gcmSeal encrypts plain with AES-GCM under key, the random nonce is prepended
+func gcmSeal(key, plain []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return append(nonce, gcm.Seal(nil, nonce, plain, nil)...), nil
+}
+
+// secretKekEncrypter wraps data encryption keys with a key encryption key read
+// from a Kubernetes Secret, e.g. one synced from a cloud KMS by a CSI provider
+type secretKekEncrypter struct {
+	kek []byte
+}
+
+func newSecretKekEncrypter(ctx context.Context, c client.Client, ns string, keyId string) (EnvelopeEncrypter, error) {
+	if keyId == "" {
+		return nil, fmt.Errorf("the \"secret\" envelope encryption provider needs the name of the Secret holding the key encryption key in keyId")
+	}
+	sec := &v1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: keyId}, sec); err != nil {
+		return nil, fmt.Errorf("unable to read key encryption key secret %s: %w", keyId, err)
+	}
+	kek := sec.Data[kekSecretKey]
+	if len(kek) != dekLength {
+		return nil, fmt.Errorf("key encryption key secret %s must hold a %d byte key under %q", keyId, dekLength, kekSecretKey)
+	}
+	return &secretKekEncrypter{kek: kek}, nil
+}
+
+func (e *secretKekEncrypter) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	return gcmSeal(e.kek, dek)
+}
@@ -0,0 +1,140 @@
+package transformer
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var secretEncryptionManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  secretEncryption:
+    provider: secret
+    keyId: spinnaker-kek
+`
+
+func kekSecret() *v1.Secret {
+	kek := make([]byte, 32)
+	for i := range kek {
+		kek[i] = byte(i)
+	}
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-kek", Namespace: "ns1"},
+		Data:       map[string][]byte{"key": kek},
+	}
+}
+
+func genConfigWithSecret() *generated.SpinnakerGeneratedConfig {
+	return &generated.SpinnakerGeneratedConfig{
+		Config: map[string]generated.ServiceConfig{
+			"gate": {
+				Resources: []runtime.Object{
+					&v1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: "spin-gate-files", Namespace: "ns1"},
+						Data:       map[string][]byte{"gate.yml": []byte("password: hunter2")},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSecretEncryption_encrypts(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&SecretEncryptionTransformerGenerator{}, secretEncryptionManifest, t, kekSecret())
+	gen := genConfigWithSecret()
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	sec := gen.Config["gate"].Resources[0].(*v1.Secret)
+	assert.NotEqual(t, []byte("password: hunter2"), sec.Data["gate.yml"])
+	assert.Equal(t, "secret", sec.Annotations[EnvelopeProviderAnnotation])
+	assert.Equal(t, "spinnaker-kek", sec.Annotations[EnvelopeKeyIdAnnotation])
+
+	// The value must decrypt back with the unwrapped data encryption key
+	wrapped, err := base64.StdEncoding.DecodeString(sec.Annotations[EnvelopeWrappedDekAnnotation])
+	assert.Nil(t, err)
+	dek, err := gcmOpen(kekSecret().Data["key"], wrapped)
+	assert.Nil(t, err)
+	plain, err := gcmOpen(dek, sec.Data["gate.yml"])
+	assert.Nil(t, err)
+	assert.Equal(t, "password: hunter2", string(plain))
+}
+
+func TestSecretEncryption_disabledByDefault(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SecretEncryptionTransformerGenerator{}, s, t)
+	gen := genConfigWithSecret()
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	sec := gen.Config["gate"].Resources[0].(*v1.Secret)
+	assert.Equal(t, []byte("password: hunter2"), sec.Data["gate.yml"])
+}
+
+func TestSecretEncryption_unknownProvider(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  secretEncryption:
+    provider: aws-kms
+    keyId: arn:aws:kms:us-west-2:123456789012:key/abc
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SecretEncryptionTransformerGenerator{}, s, t)
+	err := tr.TransformManifests(context.TODO(), genConfigWithSecret())
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "aws-kms")
+	}
+}
+
+func TestSecretEncryption_missingKek(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&SecretEncryptionTransformerGenerator{}, secretEncryptionManifest, t)
+	err := tr.TransformManifests(context.TODO(), genConfigWithSecret())
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "spinnaker-kek")
+	}
+}
+
+// gcmOpen reverses gcmSeal for the tests
+func gcmOpen(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
@@ -0,0 +1,87 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// secretFilesTransformer mounts secret files declared in spec.secretFiles only into the
+// service that needs them, instead of Halyard's default of giving every file to every service
+type secretFilesTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type SecretFilesTransformerGenerator struct{}
+
+func (g *SecretFilesTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := secretFilesTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *SecretFilesTransformerGenerator) GetName() string {
+	return "SecretFiles"
+}
+
+func (t *secretFilesTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	for _, f := range t.svc.GetSecretFiles() {
+		cfg, ok := gen.Config[f.Service]
+		if !ok || cfg.Deployment == nil {
+			return fmt.Errorf("no deployment generated for service %s, cannot mount secret %s", f.Service, f.SecretName)
+		}
+		container := util.GetContainerInDeployment(cfg.Deployment, f.Service)
+		if container == nil {
+			return fmt.Errorf("unable to find container %s in deployment, cannot mount secret %s", f.Service, f.SecretName)
+		}
+		vol := secretFileVolume(f)
+		if !volumeInArray(vol.Name, cfg.Deployment.Spec.Template.Spec.Volumes) {
+			cfg.Deployment.Spec.Template.Spec.Volumes = append(cfg.Deployment.Spec.Template.Spec.Volumes, vol)
+		}
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      vol.Name,
+			ReadOnly:  true,
+			MountPath: secretFileMountPath(f),
+		})
+	}
+	return nil
+}
+
+func secretFileVolume(f interfaces.SecretFile) v1.Volume {
+	src := &v1.SecretVolumeSource{SecretName: f.SecretName}
+	if f.Key != "" {
+		src.Items = []v1.KeyToPath{{Key: f.Key, Path: f.Key}}
+	}
+	return v1.Volume{
+		Name:         fmt.Sprintf("volume-%s", f.SecretName),
+		VolumeSource: v1.VolumeSource{Secret: src},
+	}
+}
+
+func secretFileMountPath(f interfaces.SecretFile) string {
+	if f.MountPath != "" {
+		return f.MountPath
+	}
+	return path.Join(fmt.Sprintf("/opt/%s/secrets", f.Service), f.SecretName)
+}
+
+func volumeInArray(name string, a []v1.Volume) bool {
+	for _, vol := range a {
+		if vol.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretFiles_mountedOnlyInTargetService(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  secretFiles:
+  - service: gate
+    secretName: saml-keystore
+    key: saml.jks
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SecretFilesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	test.AddDeploymentToGenConfig(gen, "clouddriver", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	found := false
+	for _, vol := range gen.Config["gate"].Deployment.Spec.Template.Spec.Volumes {
+		if vol.Name == "volume-saml-keystore" {
+			found = true
+			assert.Equal(t, "saml-keystore", vol.Secret.SecretName)
+			if assert.Len(t, vol.Secret.Items, 1) {
+				assert.Equal(t, "saml.jks", vol.Secret.Items[0].Key)
+			}
+		}
+	}
+	assert.True(t, found)
+	assert.False(t, volumeInArray("volume-saml-keystore", gen.Config["clouddriver"].Deployment.Spec.Template.Spec.Volumes))
+}
+
+func TestSecretFiles_mountPath(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  secretFiles:
+  - service: gate
+    secretName: saml-keystore
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SecretFilesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	c := gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0]
+	mounted := false
+	for _, m := range c.VolumeMounts {
+		if m.Name == "volume-saml-keystore" {
+			mounted = true
+			assert.Equal(t, "/opt/gate/secrets/saml-keystore", m.MountPath)
+			assert.True(t, m.ReadOnly)
+		}
+	}
+	assert.True(t, mounted)
+}
+
+func TestSecretFiles_unknownService(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  secretFiles:
+  - service: unknown
+    secretName: saml-keystore
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SecretFilesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.NotNil(t, err)
+}
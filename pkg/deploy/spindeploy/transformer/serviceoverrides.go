@@ -0,0 +1,93 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// serviceOverridesTransformer applies per-service overrides to the generated
+// Service objects, covering load balancer knobs the expose configuration does
+// not, like externalTrafficPolicy or source ranges
+type serviceOverridesTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type ServiceOverridesTransformerGenerator struct{}
+
+func (g *ServiceOverridesTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := serviceOverridesTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *ServiceOverridesTransformerGenerator) GetName() string {
+	return "ServiceOverrides"
+}
+
+func (t *serviceOverridesTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	for name, ov := range t.svc.GetServiceOverrides() {
+		cfg, ok := gen.Config[name]
+		if !ok || cfg.Service == nil {
+			return fmt.Errorf("service not generated for %s, unable to apply service override", name)
+		}
+		applyServiceOverride(cfg.Service, ov)
+		gen.Config[name] = cfg
+	}
+	return nil
+}
+
+func applyServiceOverride(svc *v1.Service, ov interfaces.ServiceOverride) {
+	if len(ov.Annotations) > 0 {
+		if svc.Annotations == nil {
+			svc.Annotations = map[string]string{}
+		}
+		for k, v := range ov.Annotations {
+			svc.Annotations[k] = v
+		}
+	}
+	if ov.ExternalTrafficPolicy != "" {
+		svc.Spec.ExternalTrafficPolicy = v1.ServiceExternalTrafficPolicyType(ov.ExternalTrafficPolicy)
+	}
+	if len(ov.LoadBalancerSourceRanges) > 0 {
+		svc.Spec.LoadBalancerSourceRanges = ov.LoadBalancerSourceRanges
+	}
+	for _, p := range ov.Ports {
+		applyPortOverride(svc, p)
+	}
+}
+
+// applyPortOverride updates the port matching the override's name, or adds it
+func applyPortOverride(svc *v1.Service, p interfaces.ServicePortOverride) {
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Name != p.Name {
+			continue
+		}
+		if p.Port != 0 {
+			svc.Spec.Ports[i].Port = p.Port
+		}
+		if p.TargetPort != 0 {
+			svc.Spec.Ports[i].TargetPort = intstr.FromInt(int(p.TargetPort))
+		}
+		if p.NodePort != 0 {
+			svc.Spec.Ports[i].NodePort = p.NodePort
+		}
+		return
+	}
+	np := v1.ServicePort{Name: p.Name, Port: p.Port, NodePort: p.NodePort}
+	if p.TargetPort != 0 {
+		np.TargetPort = intstr.FromInt(int(p.TargetPort))
+	}
+	svc.Spec.Ports = append(svc.Spec.Ports, np)
+}
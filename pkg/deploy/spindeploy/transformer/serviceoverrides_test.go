@@ -0,0 +1,106 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestServiceOverrides_applied(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  serviceOverrides:
+    gate:
+      annotations:
+        service.beta.kubernetes.io/aws-load-balancer-type: nlb
+      externalTrafficPolicy: Local
+      loadBalancerSourceRanges:
+      - 10.0.0.0/8
+      ports:
+      - name: monitoring
+        port: 8008
+        targetPort: 8008
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ServiceOverridesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddServiceToGenConfig(gen, "gate", "testdata/input_service.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	svc := gen.Config["gate"].Service
+	assert.Equal(t, "nlb", svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-type"])
+	assert.Equal(t, corev1.ServiceExternalTrafficPolicyTypeLocal, svc.Spec.ExternalTrafficPolicy)
+	assert.Equal(t, []string{"10.0.0.0/8"}, svc.Spec.LoadBalancerSourceRanges)
+	if assert.Equal(t, 2, len(svc.Spec.Ports)) {
+		assert.Equal(t, "monitoring", svc.Spec.Ports[1].Name)
+		assert.Equal(t, int32(8008), svc.Spec.Ports[1].Port)
+	}
+}
+
+func TestServiceOverrides_namedPortUpdated(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  serviceOverrides:
+    gate:
+      ports:
+      - name: http
+        port: 443
+        targetPort: 8084
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ServiceOverridesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddServiceToGenConfig(gen, "gate", "testdata/input_service.yml", t)
+	gen.Config["gate"].Service.Spec.Ports[0].Name = "http"
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	svc := gen.Config["gate"].Service
+	if assert.Equal(t, 1, len(svc.Spec.Ports)) {
+		assert.Equal(t, int32(443), svc.Spec.Ports[0].Port)
+		assert.Equal(t, 8084, svc.Spec.Ports[0].TargetPort.IntValue())
+	}
+}
+
+func TestServiceOverrides_missingService(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  serviceOverrides:
+    orca:
+      externalTrafficPolicy: Local
+`
+	tr, _ := th.SetupTransformerFromSpinText(&ServiceOverridesTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddServiceToGenConfig(gen, "gate", "testdata/input_service.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "orca")
+	}
+}
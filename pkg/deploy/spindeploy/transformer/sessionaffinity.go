@@ -0,0 +1,68 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const sessionStoreTypeProp = "spring.session.store-type"
+
+// sessionAffinityTransformer applies spec.expose.service.sessionAffinity to Gate's
+// service and switches Gate to Redis-backed sessions so any replica can serve a session
+type sessionAffinityTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type SessionAffinityTransformerGenerator struct{}
+
+func (g *SessionAffinityTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := sessionAffinityTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *SessionAffinityTransformerGenerator) GetName() string {
+	return "SessionAffinity"
+}
+
+func (t *sessionAffinityTransformer) TransformConfig(ctx context.Context) error {
+	if t.svc.GetExposeConfig().Service.SessionAffinity == "" {
+		return nil
+	}
+	// Store Gate sessions in Redis unless the user picked a store already
+	cfg := t.svc.GetSpinnakerConfig()
+	cur, err := cfg.GetRawServiceConfigPropString("gate", sessionStoreTypeProp)
+	if err == nil && cur != "" {
+		return nil
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	if _, ok := cfg.Profiles["gate"]; !ok {
+		cfg.Profiles["gate"] = interfaces.FreeForm{}
+	}
+	return cfg.SetServiceConfigProp("gate", sessionStoreTypeProp, "redis")
+}
+
+func (t *sessionAffinityTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	affinity := t.svc.GetExposeConfig().Service.SessionAffinity
+	if affinity == "" || svcName != "gate" {
+		return nil
+	}
+	svc.Spec.SessionAffinity = corev1.ServiceAffinity(affinity)
+	return nil
+}
+
+func (t *sessionAffinityTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	return nil
+}
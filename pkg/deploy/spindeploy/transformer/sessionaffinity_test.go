@@ -0,0 +1,88 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSessionAffinity_gateServiceAndProfile(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  expose:
+    type: service
+    service:
+      type: LoadBalancer
+      sessionAffinity: ClientIP
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&SessionAffinityTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	st, err := spinsvc.GetSpinnakerConfig().GetRawServiceConfigPropString("gate", sessionStoreTypeProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "redis", st)
+
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddServiceToGenConfig(gen, "gate", "testdata/input_service.yml", t)
+	err = tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+	assert.Equal(t, corev1.ServiceAffinityClientIP, gen.Config["gate"].Service.Spec.SessionAffinity)
+}
+
+func TestSessionAffinity_userStoreKept(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    profiles:
+      gate:
+        spring:
+          session:
+            store-type: jdbc
+  expose:
+    type: service
+    service:
+      type: LoadBalancer
+      sessionAffinity: ClientIP
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&SessionAffinityTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	st, err := spinsvc.GetSpinnakerConfig().GetRawServiceConfigPropString("gate", sessionStoreTypeProp)
+	assert.Nil(t, err)
+	assert.Equal(t, "jdbc", st)
+}
+
+func TestSessionAffinity_disabled(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, spinsvc := th.SetupTransformerFromSpinText(&SessionAffinityTransformerGenerator{}, s, t)
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	_, ok := spinsvc.GetSpinnakerConfig().Profiles["gate"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,81 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sidecarsTransformer appends the containers declared under
+// spec.services.<name>.sidecars (e.g. a cloudsql proxy or vault agent) and the
+// volumes they share to the generated Deployment of that service
+type sidecarsTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type SidecarsTransformerGenerator struct{}
+
+func (g *SidecarsTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := sidecarsTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *SidecarsTransformerGenerator) GetName() string {
+	return "Sidecars"
+}
+
+func (t *sidecarsTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *sidecarsTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	s, ok := t.svc.GetServiceSpecs()[deploymentName]
+	if !ok || (len(s.Sidecars) == 0 && len(s.Volumes) == 0) {
+		return nil
+	}
+	spec := &deployment.Spec.Template.Spec
+	for i := range s.Sidecars {
+		sc := s.Sidecars[i]
+		if getContainerByName(spec, sc.Name) != nil {
+			return fmt.Errorf("sidecar %s of service %s collides with an existing container", sc.Name, deploymentName)
+		}
+		spec.Containers = append(spec.Containers, *sc.DeepCopy())
+	}
+	for i := range s.Volumes {
+		v := s.Volumes[i]
+		if getVolumeByName(spec, v.Name) != nil {
+			continue
+		}
+		spec.Volumes = append(spec.Volumes, *v.DeepCopy())
+	}
+	return nil
+}
+
+func getContainerByName(spec *corev1.PodSpec, name string) *corev1.Container {
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == name {
+			return &spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+func getVolumeByName(spec *corev1.PodSpec, name string) *corev1.Volume {
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == name {
+			return &spec.Volumes[i]
+		}
+	}
+	return nil
+}
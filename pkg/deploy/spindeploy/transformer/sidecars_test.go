@@ -0,0 +1,99 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSidecars_appended(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  services:
+    gate:
+      sidecars:
+      - name: cloudsql-proxy
+        image: gcr.io/cloudsql-docker/gce-proxy:1.16
+        command: ["/cloud_sql_proxy"]
+        volumeMounts:
+        - name: cloudsql-creds
+          mountPath: /secrets/cloudsql
+      volumes:
+      - name: cloudsql-creds
+        secret:
+          secretName: cloudsql-creds
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SidecarsTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	spec := gen.Config["gate"].Deployment.Spec.Template.Spec
+	if !assert.Equal(t, 2, len(spec.Containers)) {
+		return
+	}
+	assert.Equal(t, "cloudsql-proxy", spec.Containers[1].Name)
+	assert.Equal(t, "gcr.io/cloudsql-docker/gce-proxy:1.16", spec.Containers[1].Image)
+	if assert.Equal(t, 3, len(spec.Volumes)) {
+		added := spec.Volumes[2]
+		assert.Equal(t, "cloudsql-creds", added.Name)
+		assert.Equal(t, "cloudsql-creds", added.Secret.SecretName)
+	}
+}
+
+func TestSidecars_nameCollision(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  services:
+    gate:
+      sidecars:
+      - name: gate
+        image: envoyproxy/envoy:v1.12.0
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SidecarsTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "collides")
+	}
+}
+
+func TestSidecars_noConfig(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SidecarsTransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, 1, len(gen.Config["gate"].Deployment.Spec.Template.Spec.Containers))
+}
@@ -0,0 +1,159 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	prometheusRuleApiVersion = "monitoring.coreos.com/v1"
+	prometheusRuleKind       = "PrometheusRule"
+	// Queue depth an Orca replica is expected to work through without alerting
+	orcaQueueDepthPerReplica = 100
+)
+
+// sloRulesTransformer emits a PrometheusRule object with recording and
+// alerting rules for the golden signals of the deployed services, Gate
+// latency, Orca queue depth and Clouddriver cache age, giving day one
+// observability when the Prometheus operator runs in the cluster
+type sloRulesTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type SloRulesTransformerGenerator struct{}
+
+func (g *SloRulesTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := sloRulesTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *SloRulesTransformerGenerator) GetName() string {
+	return "SloRules"
+}
+
+func (t *sloRulesTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	cfg := t.svc.GetMonitoringConfig()
+	if !cfg.PrometheusRules {
+		return nil
+	}
+	var groups []interface{}
+	for _, svcName := range []string{"gate", "orca", "clouddriver"} {
+		svcConfig, ok := gen.Config[svcName]
+		if !ok || svcConfig.Deployment == nil {
+			continue
+		}
+		replicas := int32(1)
+		if svcConfig.Deployment.Spec.Replicas != nil {
+			replicas = *svcConfig.Deployment.Spec.Replicas
+		}
+		groups = append(groups, t.ruleGroup(svcName, replicas))
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	labels := map[string]interface{}{}
+	for k, v := range cfg.RuleLabels {
+		labels[k] = v
+	}
+	rule := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": prometheusRuleApiVersion,
+		"kind":       prometheusRuleKind,
+		"metadata": map[string]interface{}{
+			"name":      fmt.Sprintf("%s-slo-rules", t.svc.GetName()),
+			"namespace": t.svc.GetNamespace(),
+			"labels":    labels,
+		},
+		"spec": map[string]interface{}{
+			"groups": groups,
+		},
+	}}
+	t.log.Info(fmt.Sprintf("generating PrometheusRule with %d rule groups", len(groups)))
+	// Attach to the first monitored service present, there is exactly one rule object
+	for _, svcName := range []string{"gate", "orca", "clouddriver"} {
+		if svcConfig, ok := gen.Config[svcName]; ok && svcConfig.Deployment != nil {
+			svcConfig.Resources = append(svcConfig.Resources, rule)
+			gen.Config[svcName] = svcConfig
+			break
+		}
+	}
+	return nil
+}
+
+// ruleGroup returns the golden signal rules of a service, thresholds scale
+// with the deployed replica count
+func (t *sloRulesTransformer) ruleGroup(svcName string, replicas int32) map[string]interface{} {
+	var rules []interface{}
+	switch svcName {
+	case "gate":
+		rules = []interface{}{
+			map[string]interface{}{
+				"record": "spinnaker:gate:request_latency:p99_5m",
+				"expr":   `histogram_quantile(0.99, sum by (le) (rate(http_server_requests_seconds_bucket{service="spin-gate"}[5m])))`,
+			},
+			map[string]interface{}{
+				"alert": "SpinnakerGateLatencyHigh",
+				"expr":  "spinnaker:gate:request_latency:p99_5m > 2",
+				"for":   "10m",
+				"labels": map[string]interface{}{
+					"severity": "warning",
+				},
+				"annotations": map[string]interface{}{
+					"summary": "Gate p99 request latency is above 2s",
+				},
+			},
+		}
+	case "orca":
+		threshold := orcaQueueDepthPerReplica * int(replicas)
+		rules = []interface{}{
+			map[string]interface{}{
+				"record": "spinnaker:orca:queue_depth:avg_5m",
+				"expr":   `avg_over_time(queue_ready_depth{service="spin-orca"}[5m])`,
+			},
+			map[string]interface{}{
+				"alert": "SpinnakerOrcaQueueBacklog",
+				"expr":  fmt.Sprintf("spinnaker:orca:queue_depth:avg_5m > %d", threshold),
+				"for":   "15m",
+				"labels": map[string]interface{}{
+					"severity": "warning",
+				},
+				"annotations": map[string]interface{}{
+					"summary": fmt.Sprintf("Orca queue backlog exceeds %d for the deployed %d replica(s)", threshold, replicas),
+				},
+			},
+		}
+	case "clouddriver":
+		rules = []interface{}{
+			map[string]interface{}{
+				"record": "spinnaker:clouddriver:cache_age:max",
+				"expr":   `max(cats_module_cache_age_seconds{service="spin-clouddriver"})`,
+			},
+			map[string]interface{}{
+				"alert": "SpinnakerClouddriverCacheStale",
+				"expr":  "spinnaker:clouddriver:cache_age:max > 600",
+				"for":   "10m",
+				"labels": map[string]interface{}{
+					"severity": "warning",
+				},
+				"annotations": map[string]interface{}{
+					"summary": "Clouddriver caching agents have not refreshed in over 10 minutes",
+				},
+			},
+		}
+	}
+	return map[string]interface{}{
+		"name":  fmt.Sprintf("spinnaker-%s.rules", svcName),
+		"rules": rules,
+	}
+}
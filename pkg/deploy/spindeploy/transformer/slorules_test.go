@@ -0,0 +1,83 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var sloRulesManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  monitoring:
+    prometheusRules: true
+    ruleLabels:
+      prometheus: main
+`
+
+func TestSloRules_generated(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&SloRulesTransformerGenerator{}, sloRulesManifest, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+	test.AddDeploymentToGenConfig(gen, "orca", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	resources := gen.Config["gate"].Resources
+	if !assert.Equal(t, 1, len(resources)) {
+		return
+	}
+	rule, ok := resources[0].(*unstructured.Unstructured)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "PrometheusRule", rule.GetKind())
+	assert.Equal(t, "spinnaker-slo-rules", rule.GetName())
+	assert.Equal(t, "ns1", rule.GetNamespace())
+	assert.Equal(t, "main", rule.GetLabels()["prometheus"])
+	groups, ok, err := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	// Only the deployed services get rule groups
+	assert.Equal(t, 2, len(groups))
+}
+
+func TestSloRules_disabledByDefault(t *testing.T) {
+	m := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, _ := th.SetupTransformerFromSpinText(&SloRulesTransformerGenerator{}, m, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, 0, len(gen.Config["gate"].Resources))
+}
+
+func TestSloRules_noMonitoredService(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&SloRulesTransformerGenerator{}, sloRulesManifest, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "front50", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+	assert.Equal(t, 0, len(gen.Config["front50"].Resources))
+}
@@ -0,0 +1,119 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	statefulVolumeName       = "data"
+	defaultStatefulMountPath = "/data"
+)
+
+// statefulSetTransformer converts the generated Deployment of selected
+// services into a StatefulSet with stable identity and an optional PVC
+// template, for services with local state such as embedded Redis or MinIO
+type statefulSetTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type StatefulSetTransformerGenerator struct{}
+
+func (g *StatefulSetTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := statefulSetTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *StatefulSetTransformerGenerator) GetName() string {
+	return "StatefulSet"
+}
+
+func (t *statefulSetTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	for svcName, cfg := range t.svc.GetStatefulServices() {
+		if !cfg.Enabled {
+			continue
+		}
+		config, ok := gen.Config[svcName]
+		if !ok || config.Deployment == nil {
+			continue
+		}
+		sts, err := t.toStatefulSet(svcName, cfg, config.Deployment, config.Service)
+		if err != nil {
+			return err
+		}
+		t.log.Info(fmt.Sprintf("generating %s as a StatefulSet", svcName))
+		config.Deployment = nil
+		config.Resources = append(config.Resources, sts)
+		gen.Config[svcName] = config
+	}
+	return nil
+}
+
+func (t *statefulSetTransformer) toStatefulSet(svcName string, cfg interfaces.StatefulServiceConfig, dep *appsv1.Deployment, svc *corev1.Service) (*appsv1.StatefulSet, error) {
+	// The service generated for the spinnaker service governs identity. Use
+	// its actual name so renaming transformers stay in effect, the deployment
+	// carries the same name when no service was generated
+	governingService := dep.Name
+	if svc != nil {
+		governingService = svc.Name
+	}
+	sts := &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: *dep.ObjectMeta.DeepCopy(),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    dep.Spec.Replicas,
+			Selector:    dep.Spec.Selector.DeepCopy(),
+			Template:    *dep.Spec.Template.DeepCopy(),
+			ServiceName: governingService,
+		},
+	}
+	if cfg.StorageSize == "" {
+		return sts, nil
+	}
+	size, err := resource.ParseQuantity(cfg.StorageSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage size %q for stateful service %s: %w", cfg.StorageSize, svcName, err)
+	}
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: statefulVolumeName},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	if cfg.StorageClassName != "" {
+		sc := cfg.StorageClassName
+		pvc.Spec.StorageClassName = &sc
+	}
+	sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{pvc}
+
+	mountPath := cfg.MountPath
+	if mountPath == "" {
+		mountPath = defaultStatefulMountPath
+	}
+	for i := range sts.Spec.Template.Spec.Containers {
+		c := &sts.Spec.Template.Spec.Containers[i]
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: statefulVolumeName, MountPath: mountPath})
+	}
+	return sts, nil
+}
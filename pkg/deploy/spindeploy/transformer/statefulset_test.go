@@ -0,0 +1,126 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var statefulManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  statefulServices:
+    redis:
+      enabled: true
+      storageSize: 10Gi
+      storageClassName: fast
+      mountPath: /redis-data
+`
+
+func TestStatefulSet_convertsDeployment(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&StatefulSetTransformerGenerator{}, statefulManifest, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "redis", "testdata/input_deployment.yml", t)
+	cfg := gen.Config["redis"]
+	cfg.Service = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "spin-redis"}}
+	gen.Config["redis"] = cfg
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	assert.Nil(t, gen.Config["redis"].Deployment)
+	resources := gen.Config["redis"].Resources
+	if !assert.Equal(t, 1, len(resources)) {
+		return
+	}
+	sts, ok := resources[0].(*appsv1.StatefulSet)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "spin-redis", sts.Spec.ServiceName)
+	if assert.Equal(t, 1, len(sts.Spec.VolumeClaimTemplates)) {
+		pvc := sts.Spec.VolumeClaimTemplates[0]
+		size := pvc.Spec.Resources.Requests["storage"]
+		assert.Equal(t, "10Gi", size.String())
+		if assert.NotNil(t, pvc.Spec.StorageClassName) {
+			assert.Equal(t, "fast", *pvc.Spec.StorageClassName)
+		}
+	}
+	c := sts.Spec.Template.Spec.Containers[0]
+	found := false
+	for _, m := range c.VolumeMounts {
+		if m.Name == statefulVolumeName {
+			assert.Equal(t, "/redis-data", m.MountPath)
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestStatefulSet_renamedGoverningService(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&StatefulSetTransformerGenerator{}, statefulManifest, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "redis", "testdata/input_deployment.yml", t)
+	cfg := gen.Config["redis"]
+	cfg.Service = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "myorg-redis"}}
+	gen.Config["redis"] = cfg
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	if !assert.Equal(t, 1, len(gen.Config["redis"].Resources)) {
+		return
+	}
+	sts, ok := gen.Config["redis"].Resources[0].(*appsv1.StatefulSet)
+	if assert.True(t, ok) {
+		assert.Equal(t, "myorg-redis", sts.Spec.ServiceName)
+	}
+}
+
+func TestStatefulSet_otherServicesUntouched(t *testing.T) {
+	tr, _ := th.SetupTransformerFromSpinText(&StatefulSetTransformerGenerator{}, statefulManifest, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "testdata/input_deployment.yml", t)
+
+	assert.Nil(t, tr.TransformManifests(context.TODO(), gen))
+
+	assert.NotNil(t, gen.Config["gate"].Deployment)
+	assert.Equal(t, 0, len(gen.Config["gate"].Resources))
+}
+
+func TestStatefulSet_invalidSize(t *testing.T) {
+	m := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  statefulServices:
+    redis:
+      enabled: true
+      storageSize: lots
+`
+	tr, _ := th.SetupTransformerFromSpinText(&StatefulSetTransformerGenerator{}, m, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "redis", "testdata/input_deployment.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid storage size")
+	}
+}
@@ -31,7 +31,11 @@ func (g *TargetTransformerGenerator) GetName() string {
 
 // TransformConfig is a nop
 func (t *targetTransformer) TransformConfig(ctx context.Context) error {
-	err := t.svc.GetSpinnakerConfig().SetHalConfigProp("deploymentEnvironment.location", t.svc.GetNamespace())
+	ns := t.svc.GetTargetNamespaceConfig().Name
+	if ns == "" {
+		ns = t.svc.GetNamespace()
+	}
+	err := t.svc.GetSpinnakerConfig().SetHalConfigProp("deploymentEnvironment.location", ns)
 	if err != nil {
 		return err
 	}
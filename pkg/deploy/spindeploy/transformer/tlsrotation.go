@@ -0,0 +1,104 @@
+package transformer
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TlsSecretsHashAnnotation is stamped on pod templates so rotating a mounted TLS
+// secret rolls the pods that use it
+const TlsSecretsHashAnnotation = "spinnaker.io/tls-secrets-hash"
+
+// tlsRotationTransformer annotates deployments with a hash of the TLS secrets they mount
+type tlsRotationTransformer struct {
+	*DefaultTransformer
+	svc    interfaces.SpinnakerService
+	log    logr.Logger
+	client client.Client
+}
+
+type TlsRotationTransformerGenerator struct{}
+
+func (g *TlsRotationTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := tlsRotationTransformer{svc: svc, log: log, client: client, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *TlsRotationTransformerGenerator) GetName() string {
+	return "TlsRotation"
+}
+
+func (t *tlsRotationTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *tlsRotationTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	names := secretNamesInVolumes(deployment)
+	if len(names) == 0 {
+		return nil
+	}
+	m := md5.New()
+	found := false
+	for _, n := range names {
+		sec := &corev1.Secret{}
+		err := t.client.Get(ctx, types.NamespacedName{Namespace: t.svc.GetNamespace(), Name: n}, sec)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if sec.Type != corev1.SecretTypeTLS {
+			continue
+		}
+		found = true
+		m.Write([]byte(n))
+		keys := make([]string, 0, len(sec.Data))
+		for k := range sec.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			m.Write([]byte(k))
+			m.Write(sec.Data[k])
+		}
+	}
+	if !found {
+		return nil
+	}
+	tpl := &deployment.Spec.Template
+	if tpl.Annotations == nil {
+		tpl.Annotations = map[string]string{}
+	}
+	tpl.Annotations[TlsSecretsHashAnnotation] = hex.EncodeToString(m.Sum(nil))
+	return nil
+}
+
+// secretNamesInVolumes returns the sorted names of secrets mounted by the deployment
+func secretNamesInVolumes(deployment *v1.Deployment) []string {
+	seen := map[string]bool{}
+	names := make([]string, 0)
+	for _, vol := range deployment.Spec.Template.Spec.Volumes {
+		if vol.Secret == nil || seen[vol.Secret.SecretName] {
+			continue
+		}
+		seen[vol.Secret.SecretName] = true
+		names = append(names, vol.Secret.SecretName)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,86 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tuningTransformer maps the connection pool settings of spec.tuning onto
+// the profile properties the services actually read, so operators don't have
+// to remember the property names of every service
+type tuningTransformer struct {
+	*DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type TuningTransformerGenerator struct{}
+
+func (g *TuningTransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (Transformer, error) {
+	base := &DefaultTransformer{}
+	tr := tuningTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (g *TuningTransformerGenerator) GetName() string {
+	return "Tuning"
+}
+
+func (t *tuningTransformer) TransformConfig(ctx context.Context) error {
+	tuning := t.svc.GetTuning()
+	if len(tuning) == 0 {
+		return nil
+	}
+	cfg := t.svc.GetSpinnakerConfig()
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	for svcName, s := range tuning {
+		props := tuningProperties(s)
+		if len(props) == 0 {
+			continue
+		}
+		// Explicit profile entries win over the tuning block
+		cfg.Profiles[svcName] = inspect.Overlay(props, cfg.Profiles[svcName])
+	}
+	return nil
+}
+
+// tuningProperties expands the tuning settings into the redis and SQL
+// properties shared by the services through kork
+func tuningProperties(s interfaces.ServiceTuning) map[string]interface{} {
+	props := map[string]interface{}{}
+	if s.RedisPoolMaxTotal > 0 {
+		_ = inspect.SetObjectProp(props, "redis.poolConfig.maxTotal", s.RedisPoolMaxTotal)
+	}
+	if s.RedisPoolMaxIdle > 0 {
+		_ = inspect.SetObjectProp(props, "redis.poolConfig.maxIdle", s.RedisPoolMaxIdle)
+	}
+	if s.RedisTimeoutMs > 0 {
+		_ = inspect.SetObjectProp(props, "redis.timeout", s.RedisTimeoutMs)
+	}
+	if s.SqlPoolMaxSize > 0 {
+		_ = inspect.SetObjectProp(props, "sql.connectionPools.default.maxPoolSize", s.SqlPoolMaxSize)
+	}
+	if s.SqlConnectionTimeoutMs > 0 {
+		_ = inspect.SetObjectProp(props, "sql.connectionPools.default.connectionTimeoutMs", s.SqlConnectionTimeoutMs)
+	}
+	return props
+}
+
+func (t *tuningTransformer) transformServiceManifest(ctx context.Context, svcName string, svc *corev1.Service) error {
+	return nil
+}
+
+func (t *tuningTransformer) transformDeploymentManifest(ctx context.Context, deploymentName string, deployment *v1.Deployment) error {
+	return nil
+}
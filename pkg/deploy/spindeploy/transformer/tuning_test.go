@@ -0,0 +1,80 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTuning_mapsPoolSettingsToProfiles(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  tuning:
+    orca:
+      redisPoolMaxTotal: 100
+      redisPoolMaxIdle: 50
+      redisTimeoutMs: 5000
+    front50:
+      sqlPoolMaxSize: 20
+      sqlConnectionTimeoutMs: 3000
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, spinSvc := th.SetupTransformerFromSpinText(&TuningTransformerGenerator{}, s, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	profiles := spinSvc.GetSpinnakerConfig().Profiles
+	maxTotal, err := inspect.GetRawObjectPropString(profiles["orca"], "redis.poolConfig.maxTotal")
+	assert.Nil(t, err)
+	assert.Equal(t, "100", maxTotal)
+	timeout, err := inspect.GetRawObjectPropString(profiles["orca"], "redis.timeout")
+	assert.Nil(t, err)
+	assert.Equal(t, "5000", timeout)
+
+	poolSize, err := inspect.GetRawObjectPropString(profiles["front50"], "sql.connectionPools.default.maxPoolSize")
+	assert.Nil(t, err)
+	assert.Equal(t, "20", poolSize)
+
+	// Services not tuned get no profile
+	assert.Nil(t, profiles["clouddriver"])
+}
+
+func TestTuning_explicitProfileWins(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  tuning:
+    orca:
+      redisPoolMaxTotal: 100
+      redisTimeoutMs: 5000
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    profiles:
+      orca:
+        redis:
+          poolConfig:
+            maxTotal: 42
+`
+	tr, spinSvc := th.SetupTransformerFromSpinText(&TuningTransformerGenerator{}, s, t)
+	assert.Nil(t, tr.TransformConfig(context.TODO()))
+
+	p := spinSvc.GetSpinnakerConfig().Profiles["orca"]
+	maxTotal, err := inspect.GetRawObjectPropString(p, "redis.poolConfig.maxTotal")
+	assert.Nil(t, err)
+	assert.Equal(t, "42", maxTotal)
+	// Settings the profile doesn't override are still applied
+	timeout, err := inspect.GetRawObjectPropString(p, "redis.timeout")
+	assert.Nil(t, err)
+	assert.Equal(t, "5000", timeout)
+}
@@ -0,0 +1,109 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// upgradeFirstServices are deployed and waited on before the rest during a version
+// upgrade: they own persisted state and the other services depend on their schema
+var upgradeFirstServices = []string{"front50", "clouddriver"}
+
+// versionUpgrade returns whether the target version differs from the one currently
+// deployed, along with the deployed version
+func (d *Deployer) versionUpgrade(target string, svc interfaces.SpinnakerService) (bool, string) {
+	current := svc.GetStatus().Version
+	return current != "" && target != "" && current != target, current
+}
+
+// validateUpgradeTarget resolves the target version's bill of materials before any
+// manifest is applied, so a bad version fails upfront instead of halfway through
+func (d *Deployer) validateUpgradeTarget(ctx context.Context, version string) error {
+	bg, ok := d.m.(deploy.BomAwareGenerator)
+	if !ok {
+		return nil
+	}
+	bom, err := bg.GetBOM(ctx, version)
+	if err != nil {
+		return fmt.Errorf("unable to validate upgrade target version %s: %w", version, err)
+	}
+	if len(bom) == 0 {
+		return fmt.Errorf("no bill of materials found for version %s", version)
+	}
+	return nil
+}
+
+// deployUpgradeStage applies the stateful services of a version upgrade first and
+// reports whether the remaining services may follow. While the stateful services
+// are still rolling out it returns false and the reconcile is requeued; the next
+// pass re-enters here until they are ready.
+func (d *Deployer) deployUpgradeStage(ctx context.Context, scheme *runtime.Scheme, gen *generated.SpinnakerGeneratedConfig, svc interfaces.SpinnakerService, target string, logger logr.Logger) (bool, error) {
+	if err := d.validateUpgradeTarget(ctx, target); err != nil {
+		return false, err
+	}
+	first := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{}}
+	for _, name := range upgradeFirstServices {
+		if cfg, ok := gen.Config[name]; ok {
+			first.Config[name] = cfg
+		}
+	}
+	if len(first.Config) == 0 {
+		return true, nil
+	}
+	logger.Info(fmt.Sprintf("upgrading to %s, deploying stateful services first", target))
+	ad, err := d.applyDeployer(ctx, svc)
+	if err != nil {
+		return false, err
+	}
+	if svc.GetTargetClusterConfig().KubeconfigSecret != "" {
+		stripOwnerReferences(first, svc)
+	}
+	if err := ad.applyConfig(ctx, scheme, first, svc, logger); err != nil {
+		return false, err
+	}
+	for name, cfg := range first.Config {
+		if cfg.Deployment == nil {
+			continue
+		}
+		// Readiness is checked where the deployment actually runs
+		ready, err := ad.isDeploymentReady(ctx, cfg.Deployment)
+		if err != nil {
+			return false, err
+		}
+		if !ready {
+			d.evtRecorder.Eventf(svc, v1.EventTypeNormal, "UpgradeProgress", "Waiting for %s to become ready before upgrading the remaining services", name)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isDeploymentReady returns whether the deployment's current generation has all
+// replicas updated and ready
+func (d *Deployer) isDeploymentReady(ctx context.Context, dep *appsv1.Deployment) (bool, error) {
+	current := &appsv1.Deployment{}
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: dep.GetNamespace(), Name: dep.GetName()}, current)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	expected := int32(1)
+	if current.Spec.Replicas != nil {
+		expected = *current.Spec.Replicas
+	}
+	return current.Status.ObservedGeneration >= current.Generation &&
+		current.Status.UpdatedReplicas >= expected &&
+		current.Status.ReadyReplicas >= expected, nil
+}
@@ -0,0 +1,117 @@
+package spindeploy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+type bomStub struct {
+	bom map[string]interface{}
+	err error
+}
+
+func (b *bomStub) Generate(ctx context.Context, spinConfig *interfaces.SpinnakerConfig) (*generated.SpinnakerGeneratedConfig, error) {
+	return nil, nil
+}
+
+func (b *bomStub) GetBOM(ctx context.Context, version string) (map[string]interface{}, error) {
+	return b.bom, b.err
+}
+
+func TestVersionUpgrade(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{log: log.Log.WithName("spinnakerservice")}
+
+	// First deployment, nothing to compare against
+	up, _ := d.versionUpgrade("1.17.1", spinsvc)
+	assert.False(t, up)
+
+	spinsvc.GetStatus().Version = "1.17.1"
+	up, _ = d.versionUpgrade("1.17.1", spinsvc)
+	assert.False(t, up)
+
+	up, current := d.versionUpgrade("1.18.0", spinsvc)
+	assert.True(t, up)
+	assert.Equal(t, "1.17.1", current)
+}
+
+func TestValidateUpgradeTarget(t *testing.T) {
+	d := &Deployer{m: &bomStub{bom: map[string]interface{}{"version": "1.18.0"}}, log: log.Log.WithName("spinnakerservice")}
+	assert.Nil(t, d.validateUpgradeTarget(context.TODO(), "1.18.0"))
+
+	d = &Deployer{m: &bomStub{err: errors.New("not found")}, log: log.Log.WithName("spinnakerservice")}
+	err := d.validateUpgradeTarget(context.TODO(), "9.9.9")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "9.9.9")
+	}
+
+	d = &Deployer{m: &bomStub{}, log: log.Log.WithName("spinnakerservice")}
+	assert.NotNil(t, d.validateUpgradeTarget(context.TODO(), "9.9.9"))
+}
+
+func TestDeployUpgradeStage_noStatefulServices(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(rollbackManifest, t)
+	d := &Deployer{
+		m:           &bomStub{bom: map[string]interface{}{"version": "1.18.0"}},
+		client:      test.FakeClient(t),
+		log:         log.Log.WithName("spinnakerservice"),
+		evtRecorder: &record.FakeRecorder{},
+	}
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddDeploymentToGenConfig(gen, "gate", "transformer/testdata/input_deployment.yml", t)
+
+	done, err := d.deployUpgradeStage(context.TODO(), scheme.Scheme, gen, spinsvc, "1.18.0", d.log)
+	assert.Nil(t, err)
+	assert.True(t, done)
+}
+
+func TestIsDeploymentReady(t *testing.T) {
+	one := int32(1)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "spin-front50", Namespace: "ns1", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: &one},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			UpdatedReplicas:    1,
+			ReadyReplicas:      1,
+		},
+	}
+	d := &Deployer{client: test.FakeClient(t, dep), log: log.Log.WithName("spinnakerservice")}
+	ready, err := d.isDeploymentReady(context.TODO(), dep)
+	assert.Nil(t, err)
+	assert.True(t, ready)
+
+	dep.Status.ReadyReplicas = 0
+	d = &Deployer{client: test.FakeClient(t, dep), log: log.Log.WithName("spinnakerservice")}
+	ready, err = d.isDeploymentReady(context.TODO(), dep)
+	assert.Nil(t, err)
+	assert.False(t, ready)
+
+	missing := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spin-orca", Namespace: "ns1"}}
+	d = &Deployer{client: test.FakeClient(t), log: log.Log.WithName("spinnakerservice")}
+	ready, err = d.isDeploymentReady(context.TODO(), missing)
+	assert.Nil(t, err)
+	assert.False(t, ready)
+}
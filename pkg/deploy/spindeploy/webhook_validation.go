@@ -0,0 +1,58 @@
+package spindeploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/util"
+)
+
+// webhookValidationPayload is the request body sent to the external validation webhook
+type webhookValidationPayload struct {
+	Name      string                              `json:"name"`
+	Namespace string                              `json:"namespace"`
+	Config    *interfaces.SpinnakerConfig         `json:"config"`
+	Manifests *generated.SpinnakerGeneratedConfig `json:"manifests"`
+}
+
+// validateWithWebhook sends the resolved config and generated manifests to the webhook
+// configured in spec.validation.webhookUrl and blocks the deployment on a non-2xx response
+func (d *Deployer) validateWithWebhook(ctx context.Context, svc interfaces.SpinnakerService, gen *generated.SpinnakerGeneratedConfig) error {
+	url := svc.GetSpinnakerValidation().WebhookUrl
+	if url == "" {
+		return nil
+	}
+	d.log.Info(fmt.Sprintf("validating configuration with external webhook at %s", url))
+	payload := webhookValidationPayload{
+		Name:      svc.GetName(),
+		Namespace: svc.GetNamespace(),
+		Config:    svc.GetSpinnakerConfig(),
+		Manifests: gen,
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	h := &util.HttpService{}
+	req, err := h.Request(ctx, util.POST, url, nil, map[string]string{"Content-Type": "application/json"}, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	resp, err := h.Execute(ctx, req)
+	if err != nil {
+		return fmt.Errorf("error calling validation webhook at \"%s\":\n  %w", url, err)
+	}
+	body, err := h.ParseResponseBody(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("validation webhook at \"%s\" rejected the configuration with status %d: %s", url, resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package spindeploy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestValidateWithWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"accepted", http.StatusOK, false},
+		{"rejected", http.StatusForbidden, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+			s := fmt.Sprintf(`
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  validation:
+    webhookUrl: %s
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`, srv.URL)
+			spinsvc := test.ManifestToSpinService(s, t)
+			d := &Deployer{log: log.Log.WithName("spinnakerservice")}
+			err := d.validateWithWebhook(context.TODO(), spinsvc, &generated.SpinnakerGeneratedConfig{})
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWithWebhook_noUrl(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	d := &Deployer{log: log.Log.WithName("spinnakerservice")}
+	err := d.validateWithWebhook(context.TODO(), spinsvc, &generated.SpinnakerGeneratedConfig{})
+	assert.Nil(t, err)
+}
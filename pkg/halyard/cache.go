@@ -0,0 +1,101 @@
+package halyard
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// BomCacheDirEnvVar points the BOM cache at a directory, ideally a
+	// persistent volume so operator restarts reuse it; empty keeps the cache
+	// in memory only
+	BomCacheDirEnvVar = "HALYARD_BOM_CACHE_DIR"
+	// BomCacheTTLEnvVar overrides how long cached BOMs and version lists stay
+	// fresh, defaults to 1h
+	BomCacheTTLEnvVar  = "HALYARD_BOM_CACHE_TTL"
+	defaultBomCacheTTL = 1 * time.Hour
+)
+
+// cacheEntry is a cached upstream response with the time it was fetched
+type cacheEntry struct {
+	StoredAt time.Time       `json:"storedAt"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// bomCache memoizes resolved BOMs and version metadata in memory and
+// optionally on disk, so repeated reconciles and operator restarts do not
+// re-fetch from the upstream buckets or hammer an air-gapped mirror
+type bomCache struct {
+	mtx sync.Mutex
+	dir string
+	ttl time.Duration
+	mem map[string]cacheEntry
+}
+
+func newBomCache() *bomCache {
+	ttl := defaultBomCacheTTL
+	if v := os.Getenv(BomCacheTTLEnvVar); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			ttl = d
+		}
+	}
+	return &bomCache{
+		dir: os.Getenv(BomCacheDirEnvVar),
+		ttl: ttl,
+		mem: map[string]cacheEntry{},
+	}
+}
+
+// get unmarshals the cached value of key into out and reports whether a fresh
+// entry was found, reading through to disk for entries of a previous process
+func (c *bomCache) get(key string, out interface{}) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	e, ok := c.mem[key]
+	if !ok && c.dir != "" {
+		b, err := ioutil.ReadFile(c.fileFor(key))
+		if err != nil {
+			return false
+		}
+		if err := json.Unmarshal(b, &e); err != nil {
+			return false
+		}
+		c.mem[key] = e
+		ok = true
+	}
+	if !ok || time.Since(e.StoredAt) > c.ttl {
+		return false
+	}
+	return json.Unmarshal(e.Data, out) == nil
+}
+
+// put stores value under key, persisting it best effort when a cache
+// directory is configured
+func (c *bomCache) put(key string, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	e := cacheEntry{StoredAt: time.Now(), Data: b}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.mem[key] = e
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+	if b, err = json.Marshal(e); err == nil {
+		_ = ioutil.WriteFile(c.fileFor(key), b, 0644)
+	}
+}
+
+func (c *bomCache) fileFor(key string) string {
+	return filepath.Join(c.dir, strings.Replace(key, "/", "_", -1)+".json")
+}
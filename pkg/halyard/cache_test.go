@@ -0,0 +1,73 @@
+package halyard
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBomCache_roundTrip(t *testing.T) {
+	c := &bomCache{ttl: time.Hour, mem: map[string]cacheEntry{}}
+	c.put("versions", []string{"1.16.0", "1.17.1"})
+
+	var got []string
+	assert.True(t, c.get("versions", &got))
+	assert.Equal(t, []string{"1.16.0", "1.17.1"}, got)
+}
+
+func TestBomCache_expired(t *testing.T) {
+	c := &bomCache{ttl: time.Hour, mem: map[string]cacheEntry{}}
+	c.put("versions", []string{"1.17.1"})
+	e := c.mem["versions"]
+	e.StoredAt = time.Now().Add(-2 * time.Hour)
+	c.mem["versions"] = e
+
+	var got []string
+	assert.False(t, c.get("versions", &got))
+}
+
+func TestBomCache_survivesRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bomcache")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	c := &bomCache{dir: dir, ttl: time.Hour, mem: map[string]cacheEntry{}}
+	c.put("bom-1.17.1", map[string]interface{}{"version": "1.17.1"})
+
+	// A fresh cache with the same directory stands in for a restarted operator
+	c2 := &bomCache{dir: dir, ttl: time.Hour, mem: map[string]cacheEntry{}}
+	got := make(map[string]interface{})
+	assert.True(t, c2.get("bom-1.17.1", &got))
+	assert.Equal(t, "1.17.1", got["version"])
+}
+
+func TestGetBOM_cached(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"version": "1.17.1"}`)
+	}))
+	defer ts.Close()
+
+	s := &Service{url: ts.URL, cache: &bomCache{ttl: time.Hour, mem: map[string]cacheEntry{}}}
+	for i := 0; i < 3; i++ {
+		b, err := s.GetBOM(context.TODO(), "1.17.1")
+		assert.Nil(t, err)
+		assert.Equal(t, "1.17.1", b["version"])
+	}
+	assert.Equal(t, 1, calls)
+
+	// Another version is a different cache entry
+	_, err := s.GetBOM(context.TODO(), "1.16.0")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+}
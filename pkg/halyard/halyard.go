@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/chaos"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
@@ -18,15 +20,28 @@ import (
 // Service is the Halyard implementation of the ManifestGenerator
 type Service struct {
 	url string
+	// generateTimeout cancels generations that exceed it, 0 means the default
+	generateTimeout time.Duration
+	// heartbeatInterval paces the progress callbacks, 0 means the default
+	heartbeatInterval time.Duration
+	// cache keeps resolved BOMs and version metadata, nil disables caching
+	cache *bomCache
 }
 
 // NewService returns a new Halyard service
 func NewService() *Service {
-	return &Service{url: "http://localhost:8064"}
+	return &Service{url: "http://localhost:8064", generateTimeout: generateTimeoutFromEnv(), cache: newBomCache()}
 }
 
 // Generate calls Halyard to generate the required files and return a list of parsed objects
 func (s *Service) Generate(ctx context.Context, spinConfig *interfaces.SpinnakerConfig) (*generated.SpinnakerGeneratedConfig, error) {
+	if err := chaos.Fire(ctx, chaos.PointHalyardGenerate); err != nil {
+		return nil, err
+	}
+	spinConfig, err := s.resolveSecrets(ctx, spinConfig)
+	if err != nil {
+		return nil, err
+	}
 	req, err := s.buildGenManifestsRequest(ctx, spinConfig)
 	if err != nil {
 		return nil, err
@@ -117,6 +132,10 @@ func (s *Service) writeDeckProfile(deckProfile interface{}, writer *multipart.Wr
 }
 
 func (s *Service) GetAllVersions(ctx context.Context) ([]string, error) {
+	var cached []string
+	if s.cache != nil && s.cache.get("versions", &cached) {
+		return cached, nil
+	}
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/versions/?daemon=false", s.url), nil)
 	if err != nil {
 		return nil, err
@@ -137,10 +156,18 @@ func (s *Service) GetAllVersions(ctx context.Context) ([]string, error) {
 	for _, versionInstance := range parsed.Versions {
 		versionsList = append(versionsList, versionInstance["version"])
 	}
+	if s.cache != nil {
+		s.cache.put("versions", versionsList)
+	}
 	return versionsList, nil
 }
 
 func (s *Service) GetBOM(ctx context.Context, version string) (map[string]interface{}, error) {
+	cacheKey := fmt.Sprintf("bom-%s", version)
+	cached := make(map[string]interface{})
+	if s.cache != nil && s.cache.get(cacheKey, &cached) {
+		return cached, nil
+	}
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/versions/bom?daemon=false&version=%s", s.url, version), nil)
 	if err != nil {
 		return nil, err
@@ -154,5 +181,8 @@ func (s *Service) GetBOM(ctx context.Context, version string) (map[string]interf
 	if err != nil {
 		return nil, err
 	}
+	if s.cache != nil {
+		s.cache.put(cacheKey, result)
+	}
 	return result, nil
 }
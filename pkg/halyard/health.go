@@ -0,0 +1,185 @@
+package halyard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+const (
+	// HealthIntervalEnvVar overrides how often the Halyard daemon is probed.
+	// Accepts a Go duration, defaults to 30s
+	HealthIntervalEnvVar = "HALYARD_HEALTH_INTERVAL"
+	// RestartThresholdEnvVar overrides how many consecutive failed probes or
+	// connection errors trigger a restart of the Halyard companion, defaults to 5
+	RestartThresholdEnvVar = "HALYARD_RESTART_THRESHOLD"
+
+	defaultHealthInterval   = 30 * time.Second
+	defaultRestartThreshold = 5
+
+	podNameEnvVar        = "POD_NAME"
+	watchNamespaceEnvVar = "WATCH_NAMESPACE"
+	namespaceFile        = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+var halyardRestarts = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "spinnaker_operator_halyard_restarts_total",
+	Help: "Number of times the Halyard companion was restarted because it was unreachable",
+})
+
+func init() {
+	metrics.Registry.MustRegister(halyardRestarts)
+}
+
+// failureTracker counts consecutive transport level failures of calls to
+// Halyard, any successful call resets it
+type failureTracker struct {
+	mtx   sync.Mutex
+	count int
+}
+
+func (t *failureTracker) failed() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.count++
+}
+
+func (t *failureTracker) succeeded() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.count = 0
+}
+
+func (t *failureTracker) consecutive() int {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.count
+}
+
+// generateFailures feeds the connection errors seen by executeRequest into
+// the health monitor
+var generateFailures = &failureTracker{}
+
+// HealthMonitor probes the Halyard companion and recreates the operator pod
+// when probes or generation calls consistently fail with connection errors.
+// Halyard runs as a second container in the pod and only the kubelet can
+// restart it, deleting the pod gives both containers a clean slate
+type HealthMonitor struct {
+	client       kubernetes.Interface
+	url          string
+	interval     time.Duration
+	threshold    int
+	podName      string
+	podNamespace string
+	log          logr.Logger
+	// probeFailures counts consecutive failed health probes, only touched
+	// from the monitor goroutine
+	probeFailures int
+}
+
+// NewHealthMonitor returns a monitor for the Halyard daemon next to the
+// operator, tunable through HALYARD_HEALTH_INTERVAL and HALYARD_RESTART_THRESHOLD
+func NewHealthMonitor(client kubernetes.Interface) *HealthMonitor {
+	return &HealthMonitor{
+		client:       client,
+		url:          "http://localhost:8064",
+		interval:     healthIntervalFromEnv(),
+		threshold:    restartThresholdFromEnv(),
+		podName:      os.Getenv(podNameEnvVar),
+		podNamespace: operatorNamespace(),
+		log:          logf.Log.WithName("halyard-health"),
+	}
+}
+
+// Start implements manager.Runnable, probing Halyard until the manager stops
+func (m *HealthMonitor) Start(stop <-chan struct{}) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+func (m *HealthMonitor) check() {
+	if err := m.probe(); err != nil {
+		m.probeFailures++
+		m.log.Info(fmt.Sprintf("halyard health probe failed (%d/%d): %s", m.probeFailures, m.threshold, err.Error()))
+	} else {
+		m.probeFailures = 0
+	}
+	if m.probeFailures >= m.threshold || generateFailures.consecutive() >= m.threshold {
+		m.restart()
+		m.probeFailures = 0
+		generateFailures.succeeded()
+	}
+}
+
+func (m *HealthMonitor) probe() error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s/health", m.url))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("halyard health returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// restart recreates the operator pod so the kubelet starts a fresh Halyard
+func (m *HealthMonitor) restart() {
+	if m.client == nil || m.podName == "" || m.podNamespace == "" {
+		m.log.Info("halyard is unreachable but the operator pod cannot be identified, not restarting")
+		return
+	}
+	halyardRestarts.Inc()
+	m.log.Info(fmt.Sprintf("halyard failed %d consecutive checks, recreating pod %s/%s", m.threshold, m.podNamespace, m.podName))
+	if err := m.client.CoreV1().Pods(m.podNamespace).Delete(m.podName, &metav1.DeleteOptions{}); err != nil {
+		m.log.Error(err, "unable to delete the operator pod")
+	}
+}
+
+// operatorNamespace is read from the service account, falling back to the
+// watch namespace which matches it in namespaced deployments
+func operatorNamespace() string {
+	if b, err := ioutil.ReadFile(namespaceFile); err == nil {
+		return strings.TrimSpace(string(b))
+	}
+	return os.Getenv(watchNamespaceEnvVar)
+}
+
+func healthIntervalFromEnv() time.Duration {
+	if v := os.Getenv(HealthIntervalEnvVar); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return defaultHealthInterval
+}
+
+func restartThresholdFromEnv() int {
+	if v := os.Getenv(RestartThresholdEnvVar); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultRestartThreshold
+}
@@ -0,0 +1,97 @@
+package halyard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestFailureTracker(t *testing.T) {
+	tr := &failureTracker{}
+	tr.failed()
+	tr.failed()
+	assert.Equal(t, 2, tr.consecutive())
+	tr.succeeded()
+	assert.Equal(t, 0, tr.consecutive())
+}
+
+func TestProbe(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+	m := &HealthMonitor{url: ok.URL, log: logf.Log.WithName("halyard-health")}
+	assert.Nil(t, m.probe())
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+	m.url = broken.URL
+	assert.NotNil(t, m.probe())
+}
+
+func TestCheck_restartsAfterThreshold(t *testing.T) {
+	generateFailures.succeeded()
+	// A server that is immediately closed: connection refused
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	dead.Close()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-operator-abc", Namespace: "opns"}}
+	client := fake.NewSimpleClientset(pod)
+	m := &HealthMonitor{
+		client:       client,
+		url:          dead.URL,
+		interval:     time.Second,
+		threshold:    2,
+		podName:      pod.Name,
+		podNamespace: pod.Namespace,
+		log:          logf.Log.WithName("halyard-health"),
+	}
+
+	m.check()
+	_, err := client.CoreV1().Pods("opns").Get(pod.Name, metav1.GetOptions{})
+	assert.Nil(t, err, "one failed probe must not restart the pod")
+
+	m.check()
+	_, err = client.CoreV1().Pods("opns").Get(pod.Name, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err), "pod should be recreated after hitting the threshold")
+	assert.Equal(t, 0, m.probeFailures)
+}
+
+func TestCheck_generateFailuresTrigger(t *testing.T) {
+	generateFailures.succeeded()
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-operator-abc", Namespace: "opns"}}
+	client := fake.NewSimpleClientset(pod)
+	m := &HealthMonitor{
+		client:       client,
+		url:          ok.URL,
+		threshold:    3,
+		podName:      pod.Name,
+		podNamespace: pod.Namespace,
+		log:          logf.Log.WithName("halyard-health"),
+	}
+
+	// Healthy probes but generation calls keep failing at the transport level
+	generateFailures.failed()
+	generateFailures.failed()
+	generateFailures.failed()
+	m.check()
+	_, err := client.CoreV1().Pods("opns").Get(pod.Name, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	assert.Equal(t, 0, generateFailures.consecutive())
+}
@@ -0,0 +1,75 @@
+package halyard
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+)
+
+const (
+	// GenerateTimeoutEnvVar caps how long a manifest generation may take before
+	// it is considered hung and cancelled, defaults to 10m
+	GenerateTimeoutEnvVar     = "HALYARD_GENERATE_TIMEOUT"
+	defaultGenerateTimeout    = 10 * time.Minute
+	defaultHeartbeatInterval  = 30 * time.Second
+	generationPhaseGenerating = "generating"
+)
+
+var _ deploy.ProgressAwareGenerator = &Service{}
+
+// GenerateWithProgress calls Halyard like Generate but emits a heartbeat on
+// every interval so very long generations can be told apart from hangs, which
+// are cancelled after the configured timeout
+func (s *Service) GenerateWithProgress(ctx context.Context, spinConfig *interfaces.SpinnakerConfig, progress func(deploy.GenerationProgress)) (*generated.SpinnakerGeneratedConfig, error) {
+	timeout := s.generateTimeout
+	if timeout == 0 {
+		timeout = defaultGenerateTimeout
+	}
+	interval := s.heartbeatInterval
+	if interval == 0 {
+		interval = defaultHeartbeatInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	type result struct {
+		gen *generated.SpinnakerGeneratedConfig
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		g, err := s.Generate(ctx, spinConfig)
+		done <- result{gen: g, err: err}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case r := <-done:
+			return r.gen, r.err
+		case <-ticker.C:
+			if progress != nil {
+				progress(deploy.GenerationProgress{Phase: generationPhaseGenerating, Elapsed: time.Since(start)})
+			}
+		case <-ctx.Done():
+			return nil, fmt.Errorf("manifest generation did not complete after %s, cancelled", timeout)
+		}
+	}
+}
+
+// generateTimeoutFromEnv reads the generation timeout override
+func generateTimeoutFromEnv() time.Duration {
+	if v := os.Getenv(GenerateTimeoutEnvVar); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return defaultGenerateTimeout
+}
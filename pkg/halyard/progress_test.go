@@ -0,0 +1,55 @@
+package halyard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/deploy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateWithProgress_heartbeats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("config: {}"))
+	}))
+	defer ts.Close()
+	s := &Service{url: ts.URL, heartbeatInterval: 20 * time.Millisecond}
+
+	beats := 0
+	var lastElapsed time.Duration
+	_, err := s.GenerateWithProgress(context.TODO(), makeBasicSpinnakerConfig(t), func(p deploy.GenerationProgress) {
+		beats++
+		lastElapsed = p.Elapsed
+		assert.Equal(t, generationPhaseGenerating, p.Phase)
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, beats > 0)
+	assert.True(t, lastElapsed > 0)
+}
+
+func TestGenerateWithProgress_timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1 * time.Second)
+	}))
+	defer ts.Close()
+	s := &Service{url: ts.URL, generateTimeout: 50 * time.Millisecond, heartbeatInterval: 10 * time.Millisecond}
+
+	_, err := s.GenerateWithProgress(context.TODO(), makeBasicSpinnakerConfig(t), nil)
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "did not complete after 50ms")
+	}
+}
+
+func TestGenerateTimeoutFromEnv(t *testing.T) {
+	assert.Equal(t, defaultGenerateTimeout, generateTimeoutFromEnv())
+	_ = os.Setenv(GenerateTimeoutEnvVar, "2m")
+	defer func() { _ = os.Unsetenv(GenerateTimeoutEnvVar) }()
+	assert.Equal(t, 2*time.Minute, generateTimeoutFromEnv())
+}
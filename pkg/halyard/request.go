@@ -32,8 +32,12 @@ func (s *Service) executeRequest(req *http.Request, ctx context.Context) respons
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
+		// A transport level error means the daemon may be gone, let the
+		// health monitor know
+		generateFailures.failed()
 		return responseHolder{Err: err}
 	}
+	generateFailures.succeeded()
 	defer resp.Body.Close()
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -0,0 +1,39 @@
+package halyard
+
+import (
+	"context"
+
+	secups "github.com/armory/go-yaml-tools/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+)
+
+// resolveSecrets returns a copy of the config with every "encrypted:k8s..."
+// token in the halconfig replaced by the value of the referenced Secret, so
+// credentials never have to live in the plain halconfig ConfigMap. File
+// references and other secret engines are passed through untouched, they are
+// resolved by the services at runtime. Profiles and service settings are left
+// alone on purpose, the secrets transformer maps their references to env vars.
+func (s *Service) resolveSecrets(ctx context.Context, spinConfig *interfaces.SpinnakerConfig) (*interfaces.SpinnakerConfig, error) {
+	cfg := spinConfig.DeepCopy()
+	h := func(val string) (string, error) {
+		if !secups.IsEncryptedSecret(val) {
+			return val, nil
+		}
+		e, f, _ := secups.GetEngine(val)
+		if e != "k8s" || f {
+			return val, nil
+		}
+		v, _, err := secrets.Decode(ctx, val)
+		return v, err
+	}
+	res, err := inspect.InspectStrings(cfg.Config, h)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := res.(interfaces.FreeForm); ok {
+		cfg.Config = m
+	}
+	return cfg, nil
+}
@@ -0,0 +1,58 @@
+package halyard
+
+import (
+	"context"
+	"testing"
+
+	secups "github.com/armory/go-yaml-tools/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	cfg := `
+config:
+  version: 1.17.1
+  providers:
+    dockerRegistry:
+      accounts:
+      - name: dockerhub
+        password: encrypted:k8s!n:testsecret!k:dockerPass
+        kubeconfigFile: encryptedFile:k8s!n:testsecret!k:kubeconfig
+        token: encrypted:s3!r:us-west-2!b:bucket!f:token
+`
+	spinCfg := &interfaces.SpinnakerConfig{}
+	if !assert.Nil(t, yaml.Unmarshal([]byte(cfg), spinCfg)) {
+		return
+	}
+	secups.Engines["k8s"] = func(ctx context.Context, isFile bool, params string) (secups.Decrypter, error) {
+		_, k, err := secrets.ParseKubernetesSecretParams(params)
+		if err != nil {
+			return nil, err
+		}
+		return &test.DummyK8sSecretEngine{Secret: k, File: isFile}, nil
+	}
+	ctx := secrets.NewContext(context.TODO(), nil, "")
+	s := &Service{}
+
+	resolved, err := s.resolveSecrets(ctx, spinCfg)
+	if !assert.Nil(t, err) {
+		return
+	}
+	b, err := yaml.Marshal(resolved.Config)
+	assert.Nil(t, err)
+	out := string(b)
+	assert.Contains(t, out, "password: dockerPass")
+
+	// File references and other engines are left for the services to resolve
+	assert.Contains(t, out, "kubeconfigFile: encryptedFile:k8s!n:testsecret!k:kubeconfig")
+	assert.Contains(t, out, "token: encrypted:s3!r:us-west-2!b:bucket!f:token")
+
+	// The original config still holds the reference, not the plain value
+	b, err = yaml.Marshal(spinCfg.Config)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "password: encrypted:k8s!n:testsecret!k:dockerPass")
+}
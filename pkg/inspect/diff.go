@@ -0,0 +1,69 @@
+package inspect
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DiffEntry records a single difference between two documents at Path.
+// A nil Left means the property only exists on the right side and vice versa.
+type DiffEntry struct {
+	Path  string      `json:"path"`
+	Left  interface{} `json:"left,omitempty"`
+	Right interface{} `json:"right,omitempty"`
+}
+
+// Diff compares two untyped documents and returns the properties that differ,
+// using the same dot notation as the prop getters (e.g. providers.aws.enabled)
+func Diff(left, right map[string]interface{}) []DiffEntry {
+	entries := diffAt("", left, right)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries
+}
+
+func diffAt(path string, left, right map[string]interface{}) []DiffEntry {
+	entries := make([]DiffEntry, 0)
+	for k, lv := range left {
+		p := joinPath(path, k)
+		rv, ok := right[k]
+		if !ok {
+			entries = append(entries, DiffEntry{Path: p, Left: lv})
+			continue
+		}
+		lm, lok := asStringMap(lv)
+		rm, rok := asStringMap(rv)
+		if lok && rok {
+			entries = append(entries, diffAt(p, lm, rm)...)
+			continue
+		}
+		if !reflect.DeepEqual(lv, rv) {
+			entries = append(entries, DiffEntry{Path: p, Left: lv, Right: rv})
+		}
+	}
+	for k, rv := range right {
+		if _, ok := left[k]; !ok {
+			entries = append(entries, DiffEntry{Path: joinPath(path, k), Right: rv})
+		}
+	}
+	return entries
+}
+
+func asStringMap(i interface{}) (map[string]interface{}, bool) {
+	v := reflect.ValueOf(i)
+	if !v.IsValid() || v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	m := make(map[string]interface{}, v.Len())
+	for _, k := range v.MapKeys() {
+		m[k.String()] = v.MapIndex(k).Interface()
+	}
+	return m, true
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return fmt.Sprintf("%s.%s", path, key)
+}
@@ -0,0 +1,47 @@
+package inspect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	left := map[string]interface{}{
+		"version": "1.17.1",
+		"providers": map[string]interface{}{
+			"kubernetes": map[string]interface{}{
+				"enabled": true,
+			},
+		},
+		"onlyLeft": "a",
+	}
+	right := map[string]interface{}{
+		"version": "1.18.0",
+		"providers": map[string]interface{}{
+			"kubernetes": map[string]interface{}{
+				"enabled": true,
+			},
+		},
+		"onlyRight": "b",
+	}
+	entries := Diff(left, right)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "onlyLeft", entries[0].Path)
+		assert.Equal(t, "a", entries[0].Left)
+		assert.Nil(t, entries[0].Right)
+		assert.Equal(t, "onlyRight", entries[1].Path)
+		assert.Nil(t, entries[1].Left)
+		assert.Equal(t, "version", entries[2].Path)
+		assert.Equal(t, "1.17.1", entries[2].Left)
+		assert.Equal(t, "1.18.0", entries[2].Right)
+	}
+}
+
+func TestDiff_identical(t *testing.T) {
+	m := map[string]interface{}{
+		"a": []interface{}{"x", "y"},
+		"b": map[string]interface{}{"c": 1},
+	}
+	assert.Len(t, Diff(m, m), 0)
+}
@@ -118,6 +118,26 @@ func Merge(a, b map[string]interface{}) map[string]interface{} {
 	return result
 }
 
+// Overlay copies values from b over a. Maps are merged recursively, any other
+// value in b - including arrays - replaces the one in a. Keys only present in
+// a are preserved, making it safe to merge a partial document into a full one.
+func Overlay(a, b map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, av := range a {
+		result[k] = av
+	}
+	for k, bv := range b {
+		if bvm, ok := bv.(map[string]interface{}); ok {
+			if avm, ok := result[k].(map[string]interface{}); ok {
+				result[k] = Overlay(avm, bvm)
+				continue
+			}
+		}
+		result[k] = bv
+	}
+	return result
+}
+
 // toSpecificArray converts an array of one type to an array of a desired type if it's assignable.
 func toSpecificArray(array reflect.Value, target reflect.Type) (reflect.Value, error) {
 	result := reflect.MakeSlice(reflect.SliceOf(target.Elem()), 0, array.Cap())
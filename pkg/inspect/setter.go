@@ -63,6 +63,26 @@ func UpsertInSlice(obj map[string]interface{}, prop string, value interface{}, f
 	return nil
 }
 
+// RemoveFromSlice removes the first element matching f from the array at prop,
+// returning whether an element was removed. A missing array is not an error.
+func RemoveFromSlice(obj map[string]interface{}, prop string, f func(elem interface{}) bool) (bool, error) {
+	v, err := GetObjectProp(obj, prop)
+	if err != nil {
+		return false, nil
+	}
+	if v.Kind() != reflect.Slice {
+		return false, fmt.Errorf("no array found at %s", prop)
+	}
+	for j := 0; j < v.Len(); j++ {
+		if !f(v.Index(j).Interface()) {
+			continue
+		}
+		sl := reflect.AppendSlice(v.Slice(0, j), v.Slice(j+1, v.Len()))
+		return true, SetObjectProp(obj, prop, sl.Interface())
+	}
+	return false, nil
+}
+
 func inspectPropertyOrSet(v reflect.Value, key string, value interface{}, onlyDefault bool) (reflect.Value, error) {
 	var i reflect.Value
 	switch v.Kind() {
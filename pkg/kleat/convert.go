@@ -0,0 +1,59 @@
+package kleat
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+)
+
+// halToService maps classic hal config sections to the service whose
+// kleat-style config file they belong in
+var halToService = map[string]string{
+	"providers":             "clouddriver",
+	"artifacts":             "clouddriver",
+	"features":              "clouddriver",
+	"security.authn":        "gate",
+	"security.authz":        "gate",
+	"security.apiSecurity":  "gate",
+	"persistentStorage":     "front50",
+	"ci":                    "igor",
+	"canary":                "kayenta",
+	"notifications":         "echo",
+	"pubsub":                "echo",
+	"stats":                 "echo",
+	"webhook":               "orca",
+	"deploymentEnvironment": "clouddriver",
+}
+
+// FromHal splits a classic hal config into kleat-style per-service configs,
+// keyed by service name. Sections without an equivalent are left out.
+func FromHal(hc interfaces.FreeForm) (map[string]interfaces.FreeForm, error) {
+	out := map[string]interfaces.FreeForm{}
+	props := make([]string, 0, len(halToService))
+	for p := range halToService {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+	for _, prop := range props {
+		v, err := inspect.GetObjectProp(hc, prop)
+		if err != nil || !v.IsValid() {
+			continue
+		}
+		if v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			continue
+		}
+		svc := halToService[prop]
+		if _, ok := out[svc]; !ok {
+			out[svc] = interfaces.FreeForm{}
+		}
+		if err := inspect.SetObjectProp(out[svc], prop, v.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package kleat
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFromHal(t *testing.T) {
+	var hc interfaces.FreeForm
+	err := yaml.Unmarshal([]byte(`
+version: 1.17.1
+providers:
+  kubernetes:
+    enabled: true
+security:
+  authn:
+    oauth2:
+      enabled: true
+persistentStorage:
+  persistentStoreType: s3
+  s3:
+    bucket: spinnaker
+`), &hc)
+	assert.Nil(t, err)
+
+	services, err := FromHal(hc)
+	assert.Nil(t, err)
+
+	cd, ok := services["clouddriver"]
+	if assert.True(t, ok) {
+		providers, ok := cd["providers"].(map[string]interface{})
+		if assert.True(t, ok) {
+			assert.Contains(t, providers, "kubernetes")
+		}
+	}
+	gate, ok := services["gate"]
+	if assert.True(t, ok) {
+		sec, ok := gate["security"].(map[string]interface{})
+		if assert.True(t, ok) {
+			assert.Contains(t, sec, "authn")
+		}
+	}
+	_, ok = services["front50"]
+	assert.True(t, ok)
+	// Sections without hal config are not emitted
+	_, ok = services["kayenta"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,66 @@
+package kleat
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformer"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// servicesKey holds kleat-style per-service configs inside spinnakerConfig.config
+const servicesKey = "services"
+
+type TransformerGenerator struct{}
+
+func (tg *TransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (transformer.Transformer, error) {
+	base := &transformer.DefaultTransformer{}
+	tr := kleatTransformer{svc: svc, log: log, DefaultTransformer: base}
+	base.ChildTransformer = &tr
+	return &tr, nil
+}
+
+func (tg *TransformerGenerator) GetName() string {
+	return "Kleat"
+}
+
+// kleatTransformer accepts kleat-style per-service configs under
+// spinnakerConfig.config.services and turns them into service profiles, so
+// modern config can be used ahead of the Halyard deprecation
+type kleatTransformer struct {
+	*transformer.DefaultTransformer
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+func (t *kleatTransformer) TransformConfig(ctx context.Context) error {
+	cfg := t.svc.GetSpinnakerConfig()
+	raw, ok := cfg.Config[servicesKey]
+	if !ok {
+		return nil
+	}
+	services := map[string]interfaces.FreeForm{}
+	if err := inspect.Convert(raw, &services); err != nil {
+		return fmt.Errorf("unable to parse kleat-style service configs: %w", err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]interfaces.FreeForm{}
+	}
+	for name, sc := range services {
+		existing, ok := cfg.Profiles[name]
+		if !ok {
+			cfg.Profiles[name] = sc
+			continue
+		}
+		// Explicit profile entries win over the kleat config file
+		cfg.Profiles[name] = inspect.Overlay(sc, existing)
+	}
+	// Halyard does not know about the services section
+	delete(cfg.Config, servicesKey)
+	return nil
+}
@@ -0,0 +1,101 @@
+package kleat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformertest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformConfig_servicesBecomeProfiles(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      services:
+        gate:
+          server:
+            port: 9090
+        orca:
+          tasks:
+            useManagedServiceAccounts: true
+`
+	tr, spinsvc := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, s, t)
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+
+	cfg := spinsvc.GetSpinnakerConfig()
+	v, err := cfg.GetRawServiceConfigPropString("gate", "server.port")
+	assert.Nil(t, err)
+	assert.Equal(t, "9090", v)
+	v, err = cfg.GetRawServiceConfigPropString("orca", "tasks.useManagedServiceAccounts")
+	assert.Nil(t, err)
+	assert.Equal(t, "true", v)
+	// The services section is stripped before the config reaches Halyard
+	_, ok := cfg.Config[servicesKey]
+	assert.False(t, ok)
+}
+
+func TestTransformConfig_profileEntriesWin(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      services:
+        gate:
+          server:
+            port: 9090
+          default:
+            apiPort: 8085
+    profiles:
+      gate:
+        server:
+          port: 8084
+`
+	tr, spinsvc := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, s, t)
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+
+	cfg := spinsvc.GetSpinnakerConfig()
+	v, err := cfg.GetRawServiceConfigPropString("gate", "server.port")
+	assert.Nil(t, err)
+	assert.Equal(t, "8084", v)
+	// Non conflicting kleat settings are kept
+	v, err = cfg.GetRawServiceConfigPropString("gate", "default.apiPort")
+	assert.Nil(t, err)
+	assert.Equal(t, "8085", v)
+}
+
+func TestTransformConfig_noServicesSection(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	tr, spinsvc := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, s, t)
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Empty(t, spinsvc.GetSpinnakerConfig().Profiles)
+}
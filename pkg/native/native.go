@@ -0,0 +1,209 @@
+package native
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	yaml "gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// defaultImageFormat is the upstream release image of a service, overridable
+	// per service through the service-settings artifactId like with Halyard
+	defaultImageFormat = "gcr.io/spinnaker-marketplace/%s:spinnaker-%s"
+	defaultRedisImage  = "redis:5-alpine"
+	configMountPath    = "/opt/spinnaker/config"
+)
+
+// servicePorts maps every renderable service to the port it serves on
+var servicePorts = map[string]int32{
+	"clouddriver": 7002,
+	"deck":        9000,
+	"echo":        8089,
+	"fiat":        7003,
+	"front50":     8080,
+	"gate":        8084,
+	"igor":        8088,
+	"kayenta":     8090,
+	"orca":        8083,
+	"rosco":       8087,
+	"redis":       6379,
+}
+
+// coreServices are always part of a deployment
+var coreServices = []string{"clouddriver", "deck", "echo", "front50", "gate", "orca", "rosco"}
+
+// Generator renders service manifests directly from the halconfig in Go,
+// without a Halyard daemon. It trades Halyard's full config validation and
+// templating for speed, profiles and service-settings are passed through as-is
+type Generator struct{}
+
+var _ deploy.ManifestGenerator = &Generator{}
+
+// NewGenerator returns the Halyard-less manifest generation backend
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Generate renders a Deployment, Service and config ConfigMap per enabled service
+func (g *Generator) Generate(ctx context.Context, spinConfig *interfaces.SpinnakerConfig) (*generated.SpinnakerGeneratedConfig, error) {
+	version, err := spinConfig.GetHalConfigPropString(ctx, "version")
+	if err != nil || version == "" {
+		return nil, fmt.Errorf("no version found in config, the native generator requires one")
+	}
+	gen := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{}}
+	for _, svc := range g.enabledServices(ctx, spinConfig) {
+		cfg, err := g.renderService(ctx, spinConfig, svc, version)
+		if err != nil {
+			return nil, err
+		}
+		gen.Config[svc] = cfg
+	}
+	return gen, nil
+}
+
+// enabledServices returns the services the config asks for: the core set plus
+// the ones switched on by their halconfig flags, plus redis unless external
+func (g *Generator) enabledServices(ctx context.Context, cfg *interfaces.SpinnakerConfig) []string {
+	svcs := append([]string{}, coreServices...)
+	if b, _ := cfg.GetHalConfigPropBool("security.authz.enabled", false); b {
+		svcs = append(svcs, "fiat")
+	}
+	if b, _ := cfg.GetHalConfigPropBool("canary.enabled", false); b {
+		svcs = append(svcs, "kayenta")
+	}
+	for _, ci := range []string{"jenkins", "travis", "wercker", "concourse"} {
+		if b, _ := cfg.GetHalConfigPropBool(fmt.Sprintf("ci.%s.enabled", ci), false); b {
+			svcs = append(svcs, "igor")
+			break
+		}
+	}
+	// An overridden base URL points at an external redis
+	if s, _ := cfg.GetServiceSettingsPropString(ctx, "redis", "overrideBaseUrl"); s == "" {
+		svcs = append(svcs, "redis")
+	}
+	sort.Strings(svcs)
+	return svcs
+}
+
+func (g *Generator) renderService(ctx context.Context, cfg *interfaces.SpinnakerConfig, svc, version string) (generated.ServiceConfig, error) {
+	name := fmt.Sprintf("spin-%s", svc)
+	labels := map[string]string{"app": "spin", "cluster": name}
+	port := servicePorts[svc]
+
+	image := fmt.Sprintf(defaultImageFormat, svc, version)
+	if svc == "redis" {
+		image = defaultRedisImage
+	}
+	if override, _ := cfg.GetServiceSettingsPropString(ctx, svc, "artifactId"); override != "" {
+		image = override
+	}
+
+	sc := generated.ServiceConfig{}
+	container := corev1.Container{
+		Name:  svc,
+		Image: image,
+		Ports: []corev1.ContainerPort{{Name: svc, ContainerPort: port}},
+	}
+	var volumes []corev1.Volume
+	if svc != "redis" {
+		cm, err := g.configMap(ctx, cfg, svc, name)
+		if err != nil {
+			return sc, err
+		}
+		sc.Resources = append(sc.Resources, cm)
+		volumes = append(volumes, corev1.Volume{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: cm.Name},
+				},
+			},
+		})
+		container.VolumeMounts = []corev1.VolumeMount{{Name: "config", ReadOnly: true, MountPath: configMountPath}}
+	}
+	if svc == "deck" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "API_HOST",
+			Value: fmt.Sprintf("http://spin-gate:%d", servicePorts["gate"]),
+		})
+	}
+
+	replicas := int32(1)
+	sc.Deployment = &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{container},
+					Volumes:    volumes,
+				},
+			},
+		},
+	}
+	sc.Service = &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Name: "http", Port: port, TargetPort: intstr.FromInt(int(port))}},
+		},
+	}
+	return sc, nil
+}
+
+// configMap renders the config files of a service: the shared service
+// discovery block plus the user supplied profile, passed through untouched
+func (g *Generator) configMap(ctx context.Context, cfg *interfaces.SpinnakerConfig, svc, name string) (*corev1.ConfigMap, error) {
+	data := map[string]string{}
+	discovery, err := yaml.Marshal(serviceDiscovery())
+	if err != nil {
+		return nil, err
+	}
+	data["spinnaker.yml"] = string(discovery)
+	if profile, ok := cfg.Profiles[svc]; ok {
+		if svc == "deck" {
+			data["settings-local.js"] = fmt.Sprintf("%v", profile["settings-local.js"])
+		} else {
+			b, err := yaml.Marshal(profile)
+			if err != nil {
+				return nil, err
+			}
+			data[fmt.Sprintf("%s-local.yml", svc)] = string(b)
+		}
+	}
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-files", name), Labels: map[string]string{"app": "spin", "cluster": name}},
+		Data:       data,
+	}, nil
+}
+
+// serviceDiscovery is the services block every java service reads its peer
+// base URLs from
+func serviceDiscovery() map[string]interface{} {
+	services := map[string]interface{}{}
+	names := make([]string, 0, len(servicePorts))
+	for svc := range servicePorts {
+		names = append(names, svc)
+	}
+	sort.Strings(names)
+	for _, svc := range names {
+		services[svc] = map[string]interface{}{
+			"baseUrl": fmt.Sprintf("http://spin-%s:%d", svc, servicePorts[svc]),
+		}
+	}
+	return map[string]interface{}{"services": services}
+}
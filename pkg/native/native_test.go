@@ -0,0 +1,119 @@
+package native
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGenerate_coreServices(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	svc := test.ManifestToSpinService(s, t)
+	gen, err := NewGenerator().Generate(context.TODO(), svc.GetSpinnakerConfig())
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, name := range coreServices {
+		cfg, ok := gen.Config[name]
+		if !assert.True(t, ok, name) {
+			continue
+		}
+		assert.Equal(t, "spin-"+name, cfg.Deployment.GetName())
+		assert.Equal(t, "spin-"+name, cfg.Service.GetName())
+	}
+	// Redis is rendered, optional services are not
+	assert.Contains(t, gen.Config, "redis")
+	assert.NotContains(t, gen.Config, "fiat")
+	assert.NotContains(t, gen.Config, "kayenta")
+	assert.NotContains(t, gen.Config, "igor")
+
+	gate := gen.Config["gate"]
+	assert.Equal(t, "gcr.io/spinnaker-marketplace/gate:spinnaker-1.17.1", gate.Deployment.Spec.Template.Spec.Containers[0].Image)
+	if assert.Equal(t, 1, len(gate.Resources)) {
+		cm, ok := gate.Resources[0].(*corev1.ConfigMap)
+		if assert.True(t, ok) {
+			assert.Contains(t, cm.Data["spinnaker.yml"], "http://spin-front50:8080")
+		}
+	}
+}
+
+func TestGenerate_optionalServices(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      security:
+        authz:
+          enabled: true
+      canary:
+        enabled: true
+      ci:
+        jenkins:
+          enabled: true
+`
+	svc := test.ManifestToSpinService(s, t)
+	gen, err := NewGenerator().Generate(context.TODO(), svc.GetSpinnakerConfig())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Contains(t, gen.Config, "fiat")
+	assert.Contains(t, gen.Config, "kayenta")
+	assert.Contains(t, gen.Config, "igor")
+}
+
+func TestGenerate_overrides(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    service-settings:
+      gate:
+        artifactId: my.registry/gate:custom
+      redis:
+        overrideBaseUrl: redis://external:6379
+`
+	svc := test.ManifestToSpinService(s, t)
+	gen, err := NewGenerator().Generate(context.TODO(), svc.GetSpinnakerConfig())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "my.registry/gate:custom", gen.Config["gate"].Deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.NotContains(t, gen.Config, "redis")
+}
+
+func TestGenerate_noVersion(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config: {}
+`
+	svc := test.ManifestToSpinService(s, t)
+	_, err := NewGenerator().Generate(context.TODO(), svc.GetSpinnakerConfig())
+	assert.NotNil(t, err)
+}
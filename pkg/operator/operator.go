@@ -4,11 +4,16 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/admin"
 	"github.com/armory/spinnaker-operator/pkg/controller"
 	"github.com/armory/spinnaker-operator/pkg/controller/accountvalidating"
 	"github.com/armory/spinnaker-operator/pkg/controller/spinnakerservice"
 	"github.com/armory/spinnaker-operator/pkg/controller/spinnakervalidating"
 	"github.com/armory/spinnaker-operator/pkg/controller/webhook"
+	"github.com/armory/spinnaker-operator/pkg/deploy"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy"
+	"github.com/armory/spinnaker-operator/pkg/halyard"
+	"github.com/armory/spinnaker-operator/pkg/native"
 	"github.com/armory/spinnaker-operator/pkg/version"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	kubemetrics "github.com/operator-framework/operator-sdk/pkg/kube-metrics"
@@ -22,6 +27,7 @@ import (
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -58,10 +64,17 @@ func Start(apiScheme func(s *kruntime.Scheme) error) {
 
 	fs := flag.FlagSet{}
 	var disableAdmission bool
+	var adminPort int
+	var watchNamespace string
+	var manifestGenerator string
 
 	defaultCertsDir := filepath.Join(getHome(), "spinnaker-operator-certs")
 	fs.BoolVar(&disableAdmission, "disable-admission-controller", false, "Set to disable admission controller")
+	fs.IntVar(&adminPort, "admin-port", 0, "Port of the admin API (config compare). Disabled when 0")
+	fs.BoolVar(&spindeploy.ObserverMode, "observer-mode", false, "Render, validate and report drift without ever applying changes")
 	fs.StringVar(&webhook.CertsDir, "certs-dir", defaultCertsDir, "Directory where tls.crt, tls.key and ca.crt files are found. Default: $HOME/spinnaker-operator-certs")
+	fs.StringVar(&watchNamespace, "watch-namespace", "", "Namespace to watch for SpinnakerServices. Overrides the WATCH_NAMESPACE env var, use \"*\" to watch the whole cluster")
+	fs.StringVar(&manifestGenerator, "manifest-generator", "halyard", "Manifest generation backend: \"halyard\" or the experimental Halyard-less \"native\"")
 	pflag.CommandLine.AddGoFlagSet(&fs)
 
 	pflag.Parse()
@@ -78,7 +91,25 @@ func Start(apiScheme func(s *kruntime.Scheme) error) {
 
 	printVersion()
 
+	switch manifestGenerator {
+	case "", "halyard":
+	case "native":
+		log.Info("using the native manifest generation backend")
+		spinnakerservice.ManifestGeneratorFactory = func() deploy.ManifestGenerator { return native.NewGenerator() }
+	default:
+		log.Error(fmt.Errorf("unknown manifest generator %q, expected \"halyard\" or \"native\"", manifestGenerator), "")
+		os.Exit(1)
+	}
+
 	namespace, _ := k8sutil.GetWatchNamespace()
+	// The flag wins over the env var so a deployment can force the scope.
+	// Each namespace runs an independent Spinnaker; a cluster-scoped operator
+	// manages them all, a namespace-scoped one only its own
+	if watchNamespace == "*" {
+		namespace = ""
+	} else if watchNamespace != "" {
+		namespace = watchNamespace
+	}
 	if namespace != "" {
 		log.Info(fmt.Sprintf("Watching Spinnaker configuration in %s", namespace))
 	} else {
@@ -140,6 +171,21 @@ func Start(apiScheme func(s *kruntime.Scheme) error) {
 		}
 	}
 
+	// Watch the Halyard companion and recreate the pod when it is gone for good
+	if manifestGenerator == "" || manifestGenerator == "halyard" {
+		if err := mgr.Add(halyard.NewHealthMonitor(kubernetes.NewForConfigOrDie(cfg))); err != nil {
+			log.Error(err, "error adding halyard health monitor")
+			os.Exit(1)
+		}
+	}
+
+	if adminPort > 0 {
+		if err := mgr.Add(&admin.Server{Port: int32(adminPort), Client: mgr.GetClient()}); err != nil {
+			log.Error(err, "error adding admin server")
+			os.Exit(1)
+		}
+	}
+
 	gvks, err := getGVKs(mgr)
 	if err != nil {
 		log.Error(err, "unable to get GroupVersionKind")
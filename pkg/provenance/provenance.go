@@ -0,0 +1,106 @@
+package provenance
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// SpecSource is the source recorded for values set by the user in the
+// SpinnakerService spec
+const SpecSource = "spec"
+
+// Record states which source produced the effective value at a config path
+type Record struct {
+	// Dot path of the value, prefixed with the section it lives in,
+	// e.g. config.providers.kubernetes.enabled or profiles.gate.server.port
+	Path string `json:"path"`
+	// Source that last wrote the value: "spec" or a transformer name
+	Source string `json:"source"`
+}
+
+var (
+	mtx      sync.RWMutex
+	registry = map[string][]Record{}
+)
+
+// Set stores the provenance records of the last deployment of a service,
+// keyed by "namespace/name"
+func Set(key string, records []Record) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	registry[key] = records
+}
+
+// Get returns the provenance records of the last deployment of a service
+func Get(key string) []Record {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	return registry[key]
+}
+
+// Tracker attributes every effective config value to the source that wrote it
+// by diffing the config after each step of the transformation pipeline
+type Tracker struct {
+	last    *interfaces.SpinnakerConfig
+	sources map[string]string
+}
+
+// NewTracker snapshots the user supplied config, attributing all its values to the spec
+func NewTracker(cfg *interfaces.SpinnakerConfig) *Tracker {
+	t := &Tracker{sources: map[string]string{}}
+	t.Observe(SpecSource, cfg)
+	return t
+}
+
+// Observe records source as the origin of every value changed since the last observation
+func (t *Tracker) Observe(source string, cfg *interfaces.SpinnakerConfig) {
+	prev := t.last
+	if prev == nil {
+		prev = &interfaces.SpinnakerConfig{}
+	}
+	d := interfaces.CompareConfigs(prev, cfg)
+	for _, e := range d.Config {
+		t.record(fmt.Sprintf("config.%s", e.Path), e.Right, source)
+	}
+	for svc, entries := range d.Profiles {
+		for _, e := range entries {
+			t.record(fmt.Sprintf("profiles.%s.%s", svc, e.Path), e.Right, source)
+		}
+	}
+	for svc, entries := range d.ServiceSettings {
+		for _, e := range entries {
+			t.record(fmt.Sprintf("service-settings.%s.%s", svc, e.Path), e.Right, source)
+		}
+	}
+	for _, f := range d.Files {
+		t.sources[fmt.Sprintf("files.%s", f)] = source
+	}
+	t.last = cfg.DeepCopy()
+}
+
+// record attributes path to source, expanding whole subtrees added in one
+// step down to their leaf values
+func (t *Tracker) record(path string, value interface{}, source string) {
+	v := reflect.ValueOf(value)
+	if v.IsValid() && v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String && v.Len() > 0 {
+		for _, k := range v.MapKeys() {
+			t.record(fmt.Sprintf("%s.%s", path, k.String()), v.MapIndex(k).Interface(), source)
+		}
+		return
+	}
+	t.sources[path] = source
+}
+
+// Records returns the attribution of every config value, sorted by path
+func (t *Tracker) Records() []Record {
+	records := make([]Record, 0, len(t.sources))
+	for p, s := range t.sources {
+		records = append(records, Record{Path: p, Source: s})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Path < records[j].Path })
+	return records
+}
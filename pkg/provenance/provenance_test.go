@@ -0,0 +1,67 @@
+package provenance
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func parseConfig(t *testing.T, s string) *interfaces.SpinnakerConfig {
+	cfg := &interfaces.SpinnakerConfig{}
+	assert.Nil(t, yaml.Unmarshal([]byte(s), cfg))
+	return cfg
+}
+
+func TestTracker_attributesChanges(t *testing.T) {
+	cfg := parseConfig(t, `
+config:
+  version: 1.17.1
+  providers:
+    kubernetes:
+      enabled: true
+`)
+	tr := NewTracker(cfg)
+
+	// A transformer overrides a value and adds a profile entry
+	assert.Nil(t, cfg.SetHalConfigProp("providers.kubernetes.enabled", false))
+	cfg.Profiles = map[string]interfaces.FreeForm{
+		"gate": {"server": map[string]interface{}{"port": 9090}},
+	}
+	tr.Observe("Expose", cfg)
+
+	sources := map[string]string{}
+	for _, r := range tr.Records() {
+		sources[r.Path] = r.Source
+	}
+	assert.Equal(t, SpecSource, sources["config.version"])
+	assert.Equal(t, "Expose", sources["config.providers.kubernetes.enabled"])
+	assert.Equal(t, "Expose", sources["profiles.gate.server.port"])
+}
+
+func TestTracker_lastWriterWins(t *testing.T) {
+	cfg := parseConfig(t, `
+config:
+  version: 1.17.1
+`)
+	tr := NewTracker(cfg)
+
+	assert.Nil(t, cfg.SetHalConfigProp("version", "1.17.2"))
+	tr.Observe("First", cfg)
+	assert.Nil(t, cfg.SetHalConfigProp("version", "1.17.3"))
+	tr.Observe("Second", cfg)
+	// Untouched observation does not steal attribution
+	tr.Observe("Third", cfg)
+
+	records := tr.Records()
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, "config.version", records[0].Path)
+	assert.Equal(t, "Second", records[0].Source)
+}
+
+func TestRegistry(t *testing.T) {
+	Set("ns1/spinnaker", []Record{{Path: "config.version", Source: SpecSource}})
+	assert.Equal(t, 1, len(Get("ns1/spinnaker")))
+	assert.Nil(t, Get("ns1/other"))
+}
@@ -0,0 +1,123 @@
+// Package rendertest renders the manifest set the operator would deploy for a
+// SpinnakerService, without a cluster or a running Halyard. Platform teams can
+// snapshot the output of Snapshot for their configs and diff it across
+// operator upgrades to catch unexpected manifest changes.
+//
+// The generation step is replaced by a fixture: capture the generated manifest
+// set once (the yaml form of generated.SpinnakerGeneratedConfig) and the
+// harness runs the operator's manifest transformer chain over it with a fake
+// client, exactly in the order the deployer applies it.
+package rendertest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformer"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	yaml2 "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/yaml"
+)
+
+func init() {
+	v1alpha2.RegisterTypes()
+	// Owner references and lookups need the CRD types in the client scheme
+	_ = v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+}
+
+// Render parses a SpinnakerService manifest and a generated manifest set
+// fixture, runs the manifest transformer chain and returns the transformed
+// set. The objs are the cluster objects the transformers can look up
+func Render(spinSvcManifest string, baseManifests string, objs ...runtime.Object) (*generated.SpinnakerGeneratedConfig, error) {
+	svc := interfaces.DefaultTypesFactory.NewService()
+	if err := yaml.Unmarshal([]byte(spinSvcManifest), svc); err != nil {
+		return nil, fmt.Errorf("unable to parse SpinnakerService manifest: %v", err)
+	}
+	gen := &generated.SpinnakerGeneratedConfig{}
+	if err := yaml2.Unmarshal([]byte(baseManifests), gen); err != nil {
+		return nil, fmt.Errorf("unable to parse generated manifest fixture: %v", err)
+	}
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, objs...)
+	logger := log.Log.WithName("rendertest")
+
+	transformers := make([]transformer.Transformer, 0)
+	for _, g := range spindeploy.TransformerGenerators {
+		tr, err := g.NewTransformer(svc, c, logger, scheme.Scheme)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build %s transformer: %v", g.GetName(), err)
+		}
+		transformers = append(transformers, tr)
+	}
+	ctx := context.TODO()
+	// Same as the deployer: manifest transformers run in reverse registration order
+	for i := range transformers {
+		if err := transformers[len(transformers)-i-1].TransformManifests(ctx, gen); err != nil {
+			return nil, err
+		}
+	}
+	return gen, nil
+}
+
+// Snapshot renders the manifest set and serializes it to a stable multi
+// document yaml, suitable for golden file comparisons
+func Snapshot(spinSvcManifest string, baseManifests string, objs ...runtime.Object) (string, error) {
+	gen, err := Render(spinSvcManifest, baseManifests, objs...)
+	if err != nil {
+		return "", err
+	}
+	return ToYaml(gen)
+}
+
+// ToYaml serializes the manifest set ordered by service name so the output is
+// deterministic across runs
+func ToYaml(gen *generated.SpinnakerGeneratedConfig) (string, error) {
+	names := make([]string, 0, len(gen.Config))
+	for n := range gen.Config {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	docs := make([]string, 0)
+	for _, n := range names {
+		cfg := gen.Config[n]
+		objs := make([]runtime.Object, 0)
+		if cfg.Deployment != nil {
+			objs = append(objs, cfg.Deployment)
+		}
+		if cfg.Service != nil {
+			objs = append(objs, cfg.Service)
+		}
+		objs = append(objs, cfg.Resources...)
+		for _, o := range objs {
+			setGVK(o)
+			b, err := yaml.Marshal(o)
+			if err != nil {
+				return "", err
+			}
+			docs = append(docs, string(b))
+		}
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// setGVK fills in apiVersion and kind, which go clients strip on decode
+func setGVK(o runtime.Object) {
+	if !o.GetObjectKind().GroupVersionKind().Empty() {
+		return
+	}
+	gvks, _, err := scheme.Scheme.ObjectKinds(o)
+	if err != nil || len(gvks) == 0 {
+		return
+	}
+	o.GetObjectKind().SetGroupVersionKind(gvks[0])
+}
@@ -0,0 +1,105 @@
+package rendertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var spinSvcManifest = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      persistentStorage:
+        persistentStoreType: s3
+  metadata:
+    labels:
+      team: delivery
+`
+
+var baseManifests = `
+config:
+  gate:
+    service:
+      kind: Service
+      apiVersion: v1
+      metadata:
+        name: spin-gate
+        namespace: ns1
+        labels:
+          app: spin
+          cluster: spin-gate
+      spec:
+        selector:
+          app: spin
+          cluster: spin-gate
+        type: ClusterIP
+        ports:
+        - protocol: TCP
+          port: 8084
+          targetPort: 8084
+    deployment:
+      apiVersion: apps/v1
+      kind: Deployment
+      metadata:
+        name: spin-gate
+        namespace: ns1
+        labels:
+          app: spin
+          cluster: spin-gate
+      spec:
+        replicas: 1
+        selector:
+          matchLabels:
+            app: spin
+            cluster: spin-gate
+        template:
+          metadata:
+            labels:
+              app: spin
+              cluster: spin-gate
+          spec:
+            containers:
+            - name: gate
+              image: gcr.io/spinnaker-marketplace/gate:1.5.1
+              ports:
+              - containerPort: 8084
+`
+
+func TestRender_transformersApplied(t *testing.T) {
+	gen, err := Render(spinSvcManifest, baseManifests)
+	if !assert.Nil(t, err) {
+		return
+	}
+	dep := gen.Config["gate"].Deployment
+	if !assert.NotNil(t, dep) {
+		return
+	}
+	// The metadata transformer stamped the configured label
+	assert.Equal(t, "delivery", dep.Labels["team"])
+	// The node os transformer pinned the pods to linux nodes
+	assert.Equal(t, "linux", dep.Spec.Template.Spec.NodeSelector["kubernetes.io/os"])
+}
+
+func TestSnapshot_deterministic(t *testing.T) {
+	first, err := Snapshot(spinSvcManifest, baseManifests)
+	if !assert.Nil(t, err) {
+		return
+	}
+	second, err := Snapshot(spinSvcManifest, baseManifests)
+	assert.Nil(t, err)
+	assert.Equal(t, first, second)
+	assert.Contains(t, first, "kind: Deployment")
+	assert.Contains(t, first, "kind: Service")
+}
+
+func TestRender_badSpinSvc(t *testing.T) {
+	_, err := Render("not: [valid", baseManifests)
+	assert.NotNil(t, err)
+}
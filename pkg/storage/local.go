@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// localStore keeps objects as files under a root directory, typically a
+// mounted PVC, the default store for air gapped installs
+type localStore struct {
+	root string
+}
+
+func newLocalStore() (Store, error) {
+	root := os.Getenv(PathEnvVar)
+	if root == "" {
+		root = filepath.Join(os.Getenv("OPERATOR_HOME"), "storage")
+	}
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	return &localStore{root: root}, nil
+}
+
+// resolve maps a key to a path under the root, rejecting traversal
+func (s *localStore) resolve(key string) (string, error) {
+	p := filepath.Join(s.root, filepath.Clean("/"+key))
+	if !strings.HasPrefix(p, s.root) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return p, nil
+}
+
+func (s *localStore) Put(ctx context.Context, key string, data []byte) error {
+	p, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, data, 0600)
+}
+
+func (s *localStore) Get(ctx context.Context, key string) ([]byte, error) {
+	p, err := s.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return b, err
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	p, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStore) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	err := filepath.Walk(s.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		key, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key = filepath.ToSlash(key)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	return keys, err
+}
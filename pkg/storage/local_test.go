@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempStore(t *testing.T) (Store, func()) {
+	dir, err := ioutil.TempDir("", "operator-storage")
+	assert.Nil(t, err)
+	_ = os.Setenv(TypeEnvVar, TypeLocal)
+	_ = os.Setenv(PathEnvVar, dir)
+	s, err := FromEnv()
+	assert.Nil(t, err)
+	return s, func() {
+		_ = os.Unsetenv(TypeEnvVar)
+		_ = os.Unsetenv(PathEnvVar)
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestLocalStore_roundTrip(t *testing.T) {
+	s, cleanup := tempStore(t)
+	defer cleanup()
+	ctx := context.TODO()
+
+	assert.Nil(t, s.Put(ctx, "deploy-state/ns1/spinnaker.yml", []byte("status: ok")))
+	b, err := s.Get(ctx, "deploy-state/ns1/spinnaker.yml")
+	assert.Nil(t, err)
+	assert.Equal(t, "status: ok", string(b))
+
+	keys, err := s.List(ctx, "deploy-state/")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"deploy-state/ns1/spinnaker.yml"}, keys)
+
+	assert.Nil(t, s.Delete(ctx, "deploy-state/ns1/spinnaker.yml"))
+	_, err = s.Get(ctx, "deploy-state/ns1/spinnaker.yml")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestLocalStore_rejectsTraversal(t *testing.T) {
+	s, cleanup := tempStore(t)
+	defer cleanup()
+
+	b, err := s.Get(context.TODO(), "../../etc/passwd")
+	assert.Nil(t, b)
+	// Traversal is normalized under the root, the key simply does not exist
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestFromEnv_unconfigured(t *testing.T) {
+	_ = os.Unsetenv(TypeEnvVar)
+	s, err := FromEnv()
+	assert.Nil(t, err)
+	assert.Nil(t, s)
+}
+
+func TestFromEnv_unknownType(t *testing.T) {
+	_ = os.Setenv(TypeEnvVar, "s3")
+	defer func() { _ = os.Unsetenv(TypeEnvVar) }()
+	_, err := FromEnv()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown storage type")
+}
+
+func TestRegister(t *testing.T) {
+	Register("memory", func() (Store, error) { return &localStore{root: os.TempDir()}, nil })
+	defer delete(factories, "memory")
+	_ = os.Setenv(TypeEnvVar, "memory")
+	defer func() { _ = os.Unsetenv(TypeEnvVar) }()
+	s, err := FromEnv()
+	assert.Nil(t, err)
+	assert.NotNil(t, s)
+}
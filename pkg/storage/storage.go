@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// TypeEnvVar selects the operator level object store, empty disables it
+	TypeEnvVar = "OPERATOR_STORAGE_TYPE"
+	// PathEnvVar points local storage at a directory, e.g. a mounted PVC
+	PathEnvVar = "OPERATOR_STORAGE_PATH"
+
+	TypeLocal = "local"
+)
+
+// ErrNotFound is returned by Get for keys that were never stored
+var ErrNotFound = errors.New("object not found")
+
+// Store is the object storage abstraction shared by the backup, debug bundle,
+// manifest cache and export subsystems. Implementations for cloud providers
+// (S3, GCS, Azure Blob) register themselves with Register, local PVC backed
+// storage is built in.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Factory builds a store from the operator environment
+type Factory func() (Store, error)
+
+var factories = map[string]Factory{
+	TypeLocal: newLocalStore,
+}
+
+// Register makes a store type available to FromEnv
+func Register(storeType string, f Factory) {
+	factories[storeType] = f
+}
+
+// FromEnv returns the store configured at the operator level, nil when none
+// is configured
+func FromEnv() (Store, error) {
+	t := os.Getenv(TypeEnvVar)
+	if t == "" {
+		return nil, nil
+	}
+	f, ok := factories[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown storage type %q set in %s", t, TypeEnvVar)
+	}
+	return f()
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// ScaleHalConfig synthesizes a hal config with the requested number of
+// Kubernetes accounts and docker registries. It is meant for benchmarks of
+// the halconfig accessors and the transformer chain, where hand-written
+// fixtures are too small to be representative.
+func ScaleHalConfig(k8sAccounts int, dockerRegistries int) *interfaces.SpinnakerConfig {
+	accounts := make([]interface{}, 0, k8sAccounts)
+	for i := 0; i < k8sAccounts; i++ {
+		accounts = append(accounts, map[string]interface{}{
+			"name":                 fmt.Sprintf("kube-%04d", i),
+			"providerVersion":      "V2",
+			"kubeconfigFile":       fmt.Sprintf("/kube/config-%04d", i),
+			"onlySpinnakerManaged": true,
+			"cacheThreads":         1,
+		})
+	}
+	registries := make([]interface{}, 0, dockerRegistries)
+	for i := 0; i < dockerRegistries; i++ {
+		registries = append(registries, map[string]interface{}{
+			"name":         fmt.Sprintf("registry-%04d", i),
+			"address":      fmt.Sprintf("https://registry-%04d.example.com", i),
+			"username":     "spinnaker",
+			"password":     fmt.Sprintf("password-%04d", i),
+			"repositories": []interface{}{"org/app"},
+		})
+	}
+	return &interfaces.SpinnakerConfig{
+		Config: interfaces.FreeForm{
+			"version": "1.17.1",
+			"persistentStorage": map[string]interface{}{
+				"persistentStoreType": "s3",
+				"s3": map[string]interface{}{
+					"bucket": "spin-fixtures",
+				},
+			},
+			"providers": map[string]interface{}{
+				"kubernetes": map[string]interface{}{
+					"enabled":  k8sAccounts > 0,
+					"accounts": accounts,
+				},
+				"dockerRegistry": map[string]interface{}{
+					"enabled":  dockerRegistries > 0,
+					"accounts": registries,
+				},
+			},
+		},
+	}
+}
+
+// ScaleSpinService wraps ScaleHalConfig into a SpinnakerService so benchmarks
+// can run transformers against it directly.
+func ScaleSpinService(ns string, name string, k8sAccounts int, dockerRegistries int) interfaces.SpinnakerService {
+	svc := TypesFactory.NewService()
+	svc.SetNamespace(ns)
+	svc.SetName(name)
+	ScaleHalConfig(k8sAccounts, dockerRegistries).DeepCopyInto(svc.GetSpinnakerConfig())
+	return svc
+}
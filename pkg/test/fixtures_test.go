@@ -0,0 +1,30 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaleHalConfigShape(t *testing.T) {
+	cfg := ScaleHalConfig(25, 5)
+
+	accounts, err := cfg.GetHalConfigObjectArray(context.TODO(), "providers.kubernetes.accounts")
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 25)
+
+	registries, err := cfg.GetHalConfigObjectArray(context.TODO(), "providers.dockerRegistry.accounts")
+	assert.Nil(t, err)
+	assert.Len(t, registries, 5)
+}
+
+func BenchmarkGetHalConfigObjectArrayAtScale(b *testing.B) {
+	cfg := ScaleHalConfig(500, 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cfg.GetHalConfigObjectArray(context.TODO(), "providers.kubernetes.accounts"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
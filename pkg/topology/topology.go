@@ -0,0 +1,204 @@
+package topology
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Node is a generated resource, identified by "Kind/name"
+type Node struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Service is the Spinnaker service the resource was generated for
+	Service string `json:"service,omitempty"`
+}
+
+// Edge is a directed relation between two nodes referenced by their id.
+// "exposes" points from a Service to the Deployment it fronts, "mounts" and
+// "env" from a Deployment to the Secret or ConfigMap feeding its pods, and
+// "connects" from a Deployment to the endpoint of the service it calls
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// Graph is the dependency graph of the resources generated for one deployment
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+var (
+	mtx      sync.RWMutex
+	registry = map[string]Graph{}
+)
+
+// Set stores the topology of the last deployment of a service, keyed by "namespace/name"
+func Set(key string, g Graph) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	registry[key] = g
+}
+
+// Get returns the topology of the last deployment of a service
+func Get(key string) Graph {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	return registry[key]
+}
+
+// serviceDependencies is the static call graph between Spinnaker services,
+// used to draw "connects" edges between the ones actually generated
+var serviceDependencies = map[string][]string{
+	"deck":  {"gate"},
+	"gate":  {"clouddriver", "orca", "front50", "igor", "echo", "fiat", "rosco", "kayenta"},
+	"orca":  {"clouddriver", "front50", "rosco", "echo", "kayenta", "fiat"},
+	"echo":  {"orca", "front50"},
+	"igor":  {"echo"},
+	"fiat":  {"clouddriver", "front50"},
+	"rosco": {"clouddriver"},
+}
+
+// Build derives the dependency graph of a set of generated manifests
+func Build(gen *generated.SpinnakerGeneratedConfig) Graph {
+	b := &builder{nodes: map[string]Node{}}
+	svcs := make([]string, 0, len(gen.Config))
+	for n := range gen.Config {
+		svcs = append(svcs, n)
+	}
+	sort.Strings(svcs)
+	for _, svcName := range svcs {
+		cfg := gen.Config[svcName]
+		dep := ""
+		if cfg.Deployment != nil {
+			dep = b.add("Deployment", cfg.Deployment.GetName(), svcName)
+			b.podEdges(dep, cfg.Deployment.Spec.Template.Spec, svcName)
+		}
+		if cfg.Service != nil {
+			svc := b.add("Service", cfg.Service.GetName(), svcName)
+			if dep != "" {
+				b.edge(svc, dep, "exposes")
+			}
+		}
+		for _, res := range cfg.Resources {
+			if a, err := meta.Accessor(res); err == nil {
+				b.add(kindOf(res), a.GetName(), svcName)
+			}
+		}
+	}
+	for _, svcName := range svcs {
+		from := gen.Config[svcName]
+		if from.Deployment == nil {
+			continue
+		}
+		for _, target := range serviceDependencies[svcName] {
+			to, ok := gen.Config[target]
+			if !ok {
+				continue
+			}
+			// Calls go through the target's service endpoint when it has one
+			if to.Service != nil {
+				b.edge(nodeId("Deployment", from.Deployment.GetName()), nodeId("Service", to.Service.GetName()), "connects")
+			} else if to.Deployment != nil {
+				b.edge(nodeId("Deployment", from.Deployment.GetName()), nodeId("Deployment", to.Deployment.GetName()), "connects")
+			}
+		}
+	}
+	return b.graph()
+}
+
+type builder struct {
+	nodes map[string]Node
+	edges []Edge
+}
+
+func nodeId(kind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+func (b *builder) add(kind, name, svc string) string {
+	id := nodeId(kind, name)
+	if _, ok := b.nodes[id]; !ok {
+		b.nodes[id] = Node{Kind: kind, Name: name, Service: svc}
+	}
+	return id
+}
+
+func (b *builder) edge(from, to, typ string) {
+	for _, e := range b.edges {
+		if e.From == from && e.To == to && e.Type == typ {
+			return
+		}
+	}
+	b.edges = append(b.edges, Edge{From: from, To: to, Type: typ})
+}
+
+// podEdges draws the edges from a deployment to the secrets and config maps
+// its pod spec consumes, through volumes, envFrom or individual env values
+func (b *builder) podEdges(dep string, spec corev1.PodSpec, svc string) {
+	for _, v := range spec.Volumes {
+		if v.Secret != nil {
+			b.edge(dep, b.add("Secret", v.Secret.SecretName, svc), "mounts")
+		}
+		if v.ConfigMap != nil {
+			b.edge(dep, b.add("ConfigMap", v.ConfigMap.Name, svc), "mounts")
+		}
+	}
+	cs := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range cs {
+		for _, ef := range c.EnvFrom {
+			if ef.SecretRef != nil {
+				b.edge(dep, b.add("Secret", ef.SecretRef.Name, svc), "env")
+			}
+			if ef.ConfigMapRef != nil {
+				b.edge(dep, b.add("ConfigMap", ef.ConfigMapRef.Name, svc), "env")
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if e.ValueFrom.SecretKeyRef != nil {
+				b.edge(dep, b.add("Secret", e.ValueFrom.SecretKeyRef.Name, svc), "env")
+			}
+			if e.ValueFrom.ConfigMapKeyRef != nil {
+				b.edge(dep, b.add("ConfigMap", e.ValueFrom.ConfigMapKeyRef.Name, svc), "env")
+			}
+		}
+	}
+}
+
+func (b *builder) graph() Graph {
+	nodes := make([]Node, 0, len(b.nodes))
+	for _, n := range b.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodeId(nodes[i].Kind, nodes[i].Name) < nodeId(nodes[j].Kind, nodes[j].Name)
+	})
+	return Graph{Nodes: nodes, Edges: b.edges}
+}
+
+// kindOf resolves the kind of an arbitrary generated resource
+func kindOf(obj runtime.Object) string {
+	if k := obj.GetObjectKind().GroupVersionKind().Kind; k != "" {
+		return k
+	}
+	if kinds, _, err := scheme.Scheme.ObjectKinds(obj); err == nil && len(kinds) > 0 {
+		return kinds[0].Kind
+	}
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
@@ -0,0 +1,74 @@
+package topology
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func deployment(name, secret string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{
+						Name:         secret,
+						VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secret}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func service(name string) *corev1.Service {
+	return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func TestBuild(t *testing.T) {
+	gen := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{
+		"gate": {Deployment: deployment("spin-gate", "spin-gate-files"), Service: service("spin-gate")},
+		"deck": {Deployment: deployment("spin-deck", "spin-deck-files"), Service: service("spin-deck")},
+	}}
+	g := Build(gen)
+
+	assert.Contains(t, g.Nodes, Node{Kind: "Deployment", Name: "spin-gate", Service: "gate"})
+	assert.Contains(t, g.Nodes, Node{Kind: "Secret", Name: "spin-gate-files", Service: "gate"})
+	assert.Contains(t, g.Edges, Edge{From: "Service/spin-gate", To: "Deployment/spin-gate", Type: "exposes"})
+	assert.Contains(t, g.Edges, Edge{From: "Deployment/spin-gate", To: "Secret/spin-gate-files", Type: "mounts"})
+	// Deck talks to Gate through its service endpoint
+	assert.Contains(t, g.Edges, Edge{From: "Deployment/spin-deck", To: "Service/spin-gate", Type: "connects"})
+	// Gate does not call back into Deck
+	assert.NotContains(t, g.Edges, Edge{From: "Deployment/spin-gate", To: "Service/spin-deck", Type: "connects"})
+}
+
+func TestBuild_envEdges(t *testing.T) {
+	d := deployment("spin-echo", "spin-echo-files")
+	d.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name: "echo",
+		EnvFrom: []corev1.EnvFromSource{{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "echo-env"}},
+		}},
+		Env: []corev1.EnvVar{{
+			Name: "TOKEN",
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "echo-cm"}, Key: "token"},
+			},
+		}},
+	}}
+	g := Build(&generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{"echo": {Deployment: d}}})
+
+	assert.Contains(t, g.Edges, Edge{From: "Deployment/spin-echo", To: "Secret/echo-env", Type: "env"})
+	assert.Contains(t, g.Edges, Edge{From: "Deployment/spin-echo", To: "ConfigMap/echo-cm", Type: "env"})
+}
+
+func TestSetGet(t *testing.T) {
+	Set("ns1/spinnaker", Graph{Nodes: []Node{{Kind: "Deployment", Name: "spin-gate"}}})
+	assert.Len(t, Get("ns1/spinnaker").Nodes, 1)
+	assert.Empty(t, Get("ns1/other").Nodes)
+}
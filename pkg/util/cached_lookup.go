@@ -0,0 +1,161 @@
+package util
+
+import (
+	"sort"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	informerResyncPeriod = 10 * time.Minute
+	// Deployments are labeled by the operator itself
+	managedByOperatorSelector = "app.kubernetes.io/managed-by=spinnaker-operator"
+	// Pods and replica sets carry the labels of the Halyard generated pod template
+	spinnakerAppSelector = "app=spin"
+	nameLabel            = "app.kubernetes.io/name"
+)
+
+// CachedK8sLookup is an Ik8sLookup backed by label filtered shared informers,
+// keeping API load flat as installs grow instead of issuing GET and LIST
+// requests on every status check. Before the informers have synced it falls
+// back to the API through the embedded K8sLookup.
+type CachedK8sLookup struct {
+	K8sLookup
+	deployments cache.SharedIndexInformer
+	pods        cache.SharedIndexInformer
+	replicaSets cache.SharedIndexInformer
+	synced      func() bool
+}
+
+// NewCachedK8sLookup returns a lookup serving reads from shared informers.
+// Add it to the manager so the informers run and stop with it.
+func NewCachedK8sLookup(c client.Client, clientset kubernetes.Interface) *CachedK8sLookup {
+	indexers := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+	l := &CachedK8sLookup{
+		K8sLookup: NewK8sLookup(c),
+		deployments: cache.NewSharedIndexInformer(filteredListWatch(
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return clientset.AppsV1().Deployments(metav1.NamespaceAll).List(options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return clientset.AppsV1().Deployments(metav1.NamespaceAll).Watch(options)
+			},
+			managedByOperatorSelector), &appsv1.Deployment{}, informerResyncPeriod, indexers),
+		pods: cache.NewSharedIndexInformer(filteredListWatch(
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return clientset.CoreV1().Pods(metav1.NamespaceAll).List(options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return clientset.CoreV1().Pods(metav1.NamespaceAll).Watch(options)
+			},
+			spinnakerAppSelector), &v1.Pod{}, informerResyncPeriod, indexers),
+		replicaSets: cache.NewSharedIndexInformer(filteredListWatch(
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return clientset.AppsV1().ReplicaSets(metav1.NamespaceAll).List(options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return clientset.AppsV1().ReplicaSets(metav1.NamespaceAll).Watch(options)
+			},
+			spinnakerAppSelector), &appsv1.ReplicaSet{}, informerResyncPeriod, indexers),
+	}
+	l.synced = func() bool {
+		return l.deployments.HasSynced() && l.pods.HasSynced() && l.replicaSets.HasSynced()
+	}
+	return l
+}
+
+// filteredListWatch returns a ListWatch restricted to the given label selector
+// so only Spinnaker objects are listed, watched and cached
+func filteredListWatch(list cache.ListFunc, w cache.WatchFunc, selector string) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = selector
+			return list(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = selector
+			return w(options)
+		},
+	}
+}
+
+// Start implements manager.Runnable, running the informers until the manager stops
+func (l *CachedK8sLookup) Start(stop <-chan struct{}) error {
+	go l.deployments.Run(stop)
+	go l.pods.Run(stop)
+	go l.replicaSets.Run(stop)
+	cache.WaitForCacheSync(stop, l.deployments.HasSynced, l.pods.HasSynced, l.replicaSets.HasSynced)
+	<-stop
+	return nil
+}
+
+func (l *CachedK8sLookup) GetSpinnakerDeployments(instance interfaces.SpinnakerService) ([]appsv1.Deployment, error) {
+	if !l.synced() {
+		return l.K8sLookup.GetSpinnakerDeployments(instance)
+	}
+	objs, err := l.deployments.GetIndexer().ByIndex(cache.NamespaceIndex, instance.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	ds := make([]appsv1.Deployment, 0, len(objs))
+	for _, o := range objs {
+		if d, ok := o.(*appsv1.Deployment); ok {
+			ds = append(ds, *d.DeepCopy())
+		}
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i].Name < ds[j].Name })
+	return ds, nil
+}
+
+func (l *CachedK8sLookup) GetPodsByDeployment(instance interfaces.SpinnakerService, deployment appsv1.Deployment) ([]v1.Pod, error) {
+	if !l.synced() {
+		return l.K8sLookup.GetPodsByDeployment(instance, deployment)
+	}
+	objs, err := l.pods.GetIndexer().ByIndex(cache.NamespaceIndex, instance.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]v1.Pod, 0)
+	for _, o := range objs {
+		p, ok := o.(*v1.Pod)
+		if !ok || p.Labels[nameLabel] != deployment.Labels[nameLabel] {
+			continue
+		}
+		pods = append(pods, *p.DeepCopy())
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods, nil
+}
+
+func (l *CachedK8sLookup) GetReplicaSetByPod(instance interfaces.SpinnakerService, pod v1.Pod) (*appsv1.ReplicaSet, error) {
+	rsName := ""
+	for _, or := range pod.GetOwnerReferences() {
+		if or.Kind == "ReplicaSet" {
+			rsName = or.Name
+		}
+	}
+	if o, ok, err := l.replicaSets.GetIndexer().GetByKey(instance.GetNamespace() + "/" + rsName); err == nil && ok {
+		if rs, ok := o.(*appsv1.ReplicaSet); ok {
+			return rs.DeepCopy(), nil
+		}
+	}
+	// Fall back to the API on a cache miss, e.g. before the first sync
+	return l.K8sLookup.GetReplicaSetByPod(instance, pod)
+}
+
+func (l *CachedK8sLookup) HasExceededMaxWaitingTime(instance interfaces.SpinnakerService, pod v1.Pod) (bool, error) {
+	rs, err := l.GetReplicaSetByPod(instance, pod)
+	if err != nil {
+		return false, err
+	}
+	return exceededMaxWaitingTime(rs), nil
+}
@@ -0,0 +1,107 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCachedK8sLookup_fallsBackBeforeSync(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("../controller/spinnakerservice/testdata/spinsvc.yml", t)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "spin-clouddriver",
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "spinnaker-operator"},
+		},
+	}
+	l := NewCachedK8sLookup(fake.NewFakeClient(dep), nil)
+
+	// Informers have not synced, reads go through the API
+	got, err := l.GetSpinnakerDeployments(spinSvc)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(got)) {
+		assert.Equal(t, "spin-clouddriver", got[0].Name)
+	}
+}
+
+func TestCachedK8sLookup_servesFromCache(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("../controller/spinnakerservice/testdata/spinsvc.yml", t)
+	l := NewCachedK8sLookup(fake.NewFakeClient(), nil)
+	l.synced = func() bool { return true }
+
+	assert.Nil(t, l.deployments.GetIndexer().Add(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "spin-gate",
+			Labels:    map[string]string{"app.kubernetes.io/name": "gate"},
+		},
+	}))
+	assert.Nil(t, l.pods.GetIndexer().Add(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "spin-gate-abc123-xyz",
+			Labels:    map[string]string{"app.kubernetes.io/name": "gate"},
+		},
+	}))
+	assert.Nil(t, l.pods.GetIndexer().Add(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "spin-orca-abc123-xyz",
+			Labels:    map[string]string{"app.kubernetes.io/name": "orca"},
+		},
+	}))
+
+	deps, err := l.GetSpinnakerDeployments(spinSvc)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(deps)) {
+		assert.Equal(t, "spin-gate", deps[0].Name)
+	}
+
+	pods, err := l.GetPodsByDeployment(spinSvc, deps[0])
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(pods)) {
+		assert.Equal(t, "spin-gate-abc123-xyz", pods[0].Name)
+	}
+}
+
+func TestCachedK8sLookup_replicaSetCacheHitAndMiss(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("../controller/spinnakerservice/testdata/spinsvc.yml", t)
+	cachedRs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns1",
+			Name:              "spin-gate-abc123",
+			CreationTimestamp: metav1.Time{Time: time.Now().AddDate(0, 0, -1)},
+		},
+		Status: appsv1.ReplicaSetStatus{Replicas: 2, ReadyReplicas: 0, AvailableReplicas: 0},
+	}
+	apiRs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "spin-orca-def456"},
+	}
+	l := NewCachedK8sLookup(fake.NewFakeClient(apiRs), nil)
+	assert.Nil(t, l.replicaSets.GetIndexer().Add(cachedRs))
+
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       "ns1",
+		Name:            "spin-gate-abc123-xyz",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "spin-gate-abc123"}},
+	}}
+	rs, err := l.GetReplicaSetByPod(spinSvc, pod)
+	assert.Nil(t, err)
+	assert.Equal(t, "spin-gate-abc123", rs.Name)
+	timedOut, err := l.HasExceededMaxWaitingTime(spinSvc, pod)
+	assert.Nil(t, err)
+	assert.True(t, timedOut)
+
+	// Cache misses still resolve through the API
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "spin-orca-def456"}}
+	rs, err = l.GetReplicaSetByPod(spinSvc, pod)
+	assert.Nil(t, err)
+	assert.Equal(t, "spin-orca-def456", rs.Name)
+}
@@ -12,4 +12,6 @@ const (
 	DeckSSLEnabledProp      = "security.uiSecurity.ssl.enabled"
 	DeckDefaultPort         = int32(9000)
 	ClouddriverName         = "clouddriver"
+	ClouddriverServiceName  = "spin-clouddriver"
+	ClouddriverDefaultPort  = int32(7002)
 )
@@ -33,7 +33,7 @@ func FindSpinnakerService(c client.Client, ns string, builder interfaces.TypesFa
 
 func FindDeployment(c client.Client, spinsvc interfaces.SpinnakerService, service string) (*v12.Deployment, error) {
 	dep := &v12.Deployment{}
-	err := c.Get(context.TODO(), client.ObjectKey{Namespace: spinsvc.GetNamespace(), Name: fmt.Sprintf("spin-%s", service)}, dep)
+	err := c.Get(context.TODO(), client.ObjectKey{Namespace: spinsvc.GetNamespace(), Name: GeneratedObjectName(spinsvc, service)}, dep)
 	return dep, err
 }
 
@@ -109,6 +109,21 @@ func UpdateSecret(secret *v1.Secret, svc string, settings map[string]interface{}
 	return nil
 }
 
+// GetSecretProfileSettings reads back the settings previously stored by UpdateSecret,
+// nil when the secret has no settings for the service yet
+func GetSecretProfileSettings(secret *v1.Secret, svc string, profileName string) (map[string]interface{}, error) {
+	k := fmt.Sprintf("%s-%s.yml", svc, profileName)
+	b, ok := secret.Data[k]
+	if !ok {
+		return nil, nil
+	}
+	settings := make(map[string]interface{})
+	if err := yaml.Unmarshal(b, &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
 // GetServiceAccountData returns the service account token and temp path to root ca
 func GetServiceAccountData(ctx context.Context, name, ns string, c client.Client) (string, string, error) {
 	list := &v1.SecretList{}
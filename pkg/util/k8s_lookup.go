@@ -101,13 +101,14 @@ func (l K8sLookup) HasExceededMaxWaitingTime(instance interfaces.SpinnakerServic
 	if err != nil {
 		return false, err
 	}
+	return exceededMaxWaitingTime(rs), nil
+}
 
+// exceededMaxWaitingTime validate if a replicaset has exceeded max waiting time
+func exceededMaxWaitingTime(rs *appsv1.ReplicaSet) bool {
 	if rs.Status.AvailableReplicas != rs.Status.Replicas || rs.Status.ReadyReplicas != rs.Status.Replicas {
 		diff := time.Now().Sub(rs.CreationTimestamp.Time)
-		if diff.Minutes() > MaxChecksWaitingForSpinnakerStability {
-			return true, nil
-		}
-		return false, nil
+		return diff.Minutes() > MaxChecksWaitingForSpinnakerStability
 	}
-	return false, nil
+	return false
 }
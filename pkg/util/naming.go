@@ -0,0 +1,60 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// GeneratedNamePrefix is the prefix Halyard puts on every generated object
+const GeneratedNamePrefix = "spin"
+
+// generatedNaming returns the effective prefix and suffix of generated object
+// names under spec.naming
+func generatedNaming(spinsvc interfaces.SpinnakerService) (string, string) {
+	n := spinsvc.GetNamingConfig()
+	prefix := n.Prefix
+	if prefix == "" {
+		prefix = GeneratedNamePrefix
+	}
+	return prefix, n.Suffix
+}
+
+// GeneratedObjectName returns the name the generated objects of a Spinnaker
+// service carry, e.g. spin-gate by default or team1-gate-prod under spec.naming
+func GeneratedObjectName(spinsvc interfaces.SpinnakerService, service string) string {
+	return RenameGeneratedObject(spinsvc, fmt.Sprintf("%s-%s", GeneratedNamePrefix, service))
+}
+
+// RenameGeneratedObject rewrites a name Halyard generated with the default
+// prefix, e.g. spin-gate-files-283, to carry the prefix and suffix from
+// spec.naming. Names without the default prefix are returned unchanged
+func RenameGeneratedObject(spinsvc interfaces.SpinnakerService, name string) string {
+	prefix, suffix := generatedNaming(spinsvc)
+	if !strings.HasPrefix(name, GeneratedNamePrefix+"-") {
+		return name
+	}
+	name = fmt.Sprintf("%s-%s", prefix, strings.TrimPrefix(name, GeneratedNamePrefix+"-"))
+	if suffix != "" && !strings.HasSuffix(name, "-"+suffix) {
+		name = fmt.Sprintf("%s-%s", name, suffix)
+	}
+	return name
+}
+
+// BaseServiceName returns the Spinnaker service name behind a generated object
+// name, e.g. gate for spin-gate or team1-gate-prod, whether or not the object
+// was renamed yet
+func BaseServiceName(spinsvc interfaces.SpinnakerService, objectName string) string {
+	prefix, suffix := generatedNaming(spinsvc)
+	name := objectName
+	if strings.HasPrefix(name, prefix+"-") {
+		name = strings.TrimPrefix(name, prefix+"-")
+	} else {
+		name = strings.TrimPrefix(name, GeneratedNamePrefix+"-")
+	}
+	if suffix != "" {
+		name = strings.TrimSuffix(name, "-"+suffix)
+	}
+	return name
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedNames(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  naming:
+    prefix: team1
+    suffix: prod
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	assert.Equal(t, "team1-gate-prod", GeneratedObjectName(spinsvc, "gate"))
+	assert.Equal(t, "team1-gate-files-283-prod", RenameGeneratedObject(spinsvc, "spin-gate-files-283"))
+	assert.Equal(t, "not-generated", RenameGeneratedObject(spinsvc, "not-generated"))
+	assert.Equal(t, "gate", BaseServiceName(spinsvc, "team1-gate-prod"))
+	assert.Equal(t, "gate", BaseServiceName(spinsvc, "spin-gate"))
+}
+
+func TestGeneratedNamesDefault(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	assert.Equal(t, "spin-gate", GeneratedObjectName(spinsvc, "gate"))
+	assert.Equal(t, "spin-gate", RenameGeneratedObject(spinsvc, "spin-gate"))
+	assert.Equal(t, "gate", BaseServiceName(spinsvc, "spin-gate"))
+}
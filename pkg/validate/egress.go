@@ -0,0 +1,165 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/ghodss/yaml"
+)
+
+const (
+	// EgressAllowlistEnvVar points at the egress allowlist file, defaults to
+	// $OPERATOR_HOME/egress-allowlist.yml
+	EgressAllowlistEnvVar = "OPERATOR_EGRESS_ALLOWLIST_FILE"
+	defaultEgressFile     = "egress-allowlist.yml"
+	// dockerRegistryAccountsProp lists the registry accounts whose addresses
+	// may come without a scheme
+	dockerRegistryAccountsProp = "providers.dockerRegistry.accounts"
+)
+
+// EgressAllowlist lists the external hosts Spinnaker is allowed to call from
+// an egress-locked cluster. Like the quota policy it is read from a file
+// shipped with the operator, not from the SpinnakerService itself.
+type EgressAllowlist struct {
+	// AllowedHosts the resolved config may reference, glob patterns matched
+	// against the host without its port
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+}
+
+// egressValidator collects every external endpoint the resolved config will
+// cause Spinnaker to call (registries, git hosts, CI masters, webhooks...)
+// and rejects the config if any of them falls outside the allowlist, so a
+// deployment fails at admission instead of timing out behind a closed egress.
+type egressValidator struct {
+	// allowlist overrides the file based allowlist in tests
+	allowlist *EgressAllowlist
+}
+
+func (v *egressValidator) Validate(spinSvc interfaces.SpinnakerService, opts Options) ValidationResult {
+	allowlist := v.allowlist
+	if allowlist == nil {
+		a, err := loadEgressAllowlist()
+		if err != nil {
+			return NewResultFromError(fmt.Errorf("unable to read operator egress allowlist:\n  %w", err), true)
+		}
+		allowlist = a
+	}
+	if allowlist == nil {
+		return ValidationResult{}
+	}
+	var denied []string
+	for _, host := range collectEgressHosts(spinSvc.GetSpinnakerConfig()) {
+		if !allowlist.allows(host) {
+			denied = append(denied, host)
+		}
+	}
+	if len(denied) > 0 {
+		return NewResultFromError(fmt.Errorf("config references external endpoints outside the egress allowlist: %s", strings.Join(denied, ", ")), true)
+	}
+	return ValidationResult{}
+}
+
+// loadEgressAllowlist reads the allowlist file, a missing file means egress
+// validation is disabled
+func loadEgressAllowlist() (*EgressAllowlist, error) {
+	path := os.Getenv(EgressAllowlistEnvVar)
+	if path == "" {
+		home := os.Getenv("OPERATOR_HOME")
+		if home == "" {
+			return nil, nil
+		}
+		path = filepath.Join(home, defaultEgressFile)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	a := &EgressAllowlist{}
+	if err := yaml.Unmarshal(b, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *EgressAllowlist) allows(host string) bool {
+	for _, pattern := range a.AllowedHosts {
+		if ok, err := filepath.Match(pattern, host); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// collectEgressHosts returns the sorted, deduplicated hosts of every URL
+// referenced anywhere in the hal config and service profiles, plus docker
+// registry addresses which may come without a scheme
+func collectEgressHosts(cfg *interfaces.SpinnakerConfig) []string {
+	hosts := map[string]bool{}
+	walkForHosts(map[string]interface{}(cfg.Config), hosts)
+	for _, p := range cfg.Profiles {
+		walkForHosts(map[string]interface{}(p), hosts)
+	}
+	if accounts, err := cfg.GetHalConfigObjectArray(context.TODO(), dockerRegistryAccountsProp); err == nil {
+		for _, a := range accounts {
+			if addr, ok := a["address"].(string); ok && addr != "" {
+				addHost(addr, hosts)
+			}
+		}
+	}
+	sorted := make([]string, 0, len(hosts))
+	for h := range hosts {
+		sorted = append(sorted, h)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func walkForHosts(node interface{}, hosts map[string]bool) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for _, v := range n {
+			walkForHosts(v, hosts)
+		}
+	case map[interface{}]interface{}:
+		for _, v := range n {
+			walkForHosts(v, hosts)
+		}
+	case []interface{}:
+		for _, v := range n {
+			walkForHosts(v, hosts)
+		}
+	case string:
+		if strings.HasPrefix(n, "http://") || strings.HasPrefix(n, "https://") || strings.HasPrefix(n, "ssh://") {
+			addHost(n, hosts)
+		}
+	}
+}
+
+// addHost records the host of a URL, or the raw value stripped of any port
+// when it does not parse as a URL (bare registry addresses)
+func addHost(raw string, hosts map[string]bool) {
+	if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+		hosts[u.Hostname()] = true
+		return
+	}
+	host := raw
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.Index(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	if host != "" {
+		hosts[host] = true
+	}
+}
@@ -0,0 +1,68 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const egressManifest = `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+      providers:
+        dockerRegistry:
+          enabled: true
+          accounts:
+          - name: dockerhub
+            address: index.docker.io
+      ci:
+        jenkins:
+          enabled: true
+          masters:
+          - name: master
+            address: https://jenkins.internal.example.com/
+    profiles:
+      echo:
+        rest:
+          enabled: true
+          endpoints:
+          - url: https://hooks.chat.example.com/notify
+`
+
+func TestEgress_collectsAllHosts(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, egressManifest)
+	hosts := collectEgressHosts(spinsvc.GetSpinnakerConfig())
+	assert.Equal(t, []string{"hooks.chat.example.com", "index.docker.io", "jenkins.internal.example.com"}, hosts)
+}
+
+func TestEgress_deniesHostOutsideAllowlist(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, egressManifest)
+	v := &egressValidator{allowlist: &EgressAllowlist{AllowedHosts: []string{"*.example.com"}}}
+
+	r := v.Validate(spinsvc, Options{})
+	if assert.True(t, r.HasFatalErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "outside the egress allowlist: index.docker.io")
+	}
+}
+
+func TestEgress_allowsMatchingHosts(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, egressManifest)
+	v := &egressValidator{allowlist: &EgressAllowlist{AllowedHosts: []string{"*.example.com", "index.docker.io"}}}
+
+	r := v.Validate(spinsvc, Options{})
+	assert.False(t, r.HasErrors())
+}
+
+func TestEgress_noAllowlistDisablesValidation(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, egressManifest)
+	v := &egressValidator{}
+
+	r := v.Validate(spinsvc, Options{})
+	assert.False(t, r.HasErrors())
+}
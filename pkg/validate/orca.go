@@ -0,0 +1,35 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+const orcaSqlEnabledProp = "sql.enabled"
+
+// orcaValidator checks that spec.orca tuning values are sane and compatible
+// with the execution repository backing Orca
+type orcaValidator struct{}
+
+func (v *orcaValidator) Validate(spinSvc interfaces.SpinnakerService, options Options) ValidationResult {
+	o := spinSvc.GetOrcaConfig()
+	if o.QueueThreads == 0 && o.ExecutionRetentionDays == 0 && !o.ZombieCleanup {
+		return ValidationResult{}
+	}
+	if o.QueueThreads < 0 {
+		return NewResultFromError(fmt.Errorf("spec.orca.queueThreads must be positive, got %d", o.QueueThreads), true)
+	}
+	if o.ExecutionRetentionDays < 0 {
+		return NewResultFromError(fmt.Errorf("spec.orca.executionRetentionDays must be positive, got %d", o.ExecutionRetentionDays), true)
+	}
+	if o.ExecutionRetentionDays > 0 {
+		// The cleanup pollers only work against the Redis execution repository,
+		// SQL deployments handle retention through their own cleanup agents
+		sql, _ := spinSvc.GetSpinnakerConfig().GetServiceConfigPropString(options.Ctx, "orca", orcaSqlEnabledProp)
+		if sql == "true" {
+			return NewResultFromError(fmt.Errorf("spec.orca.executionRetentionDays is only supported with the Redis execution repository, the orca profile enables SQL"), true)
+		}
+	}
+	return ValidationResult{}
+}
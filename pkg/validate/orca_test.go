@@ -0,0 +1,60 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrca_retentionWithSqlRejected(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    profiles:
+      orca:
+        sql:
+          enabled: true
+  orca:
+    executionRetentionDays: 30
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	v := &orcaValidator{}
+
+	res := v.Validate(spinsvc, Options{Ctx: context.TODO()})
+
+	assert.True(t, res.HasFatalErrors())
+	assert.Contains(t, res.Errors[0].Error(), "only supported with the Redis execution repository")
+}
+
+func TestOrca_validTuningAccepted(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+  orca:
+    queueThreads: 40
+    executionRetentionDays: 30
+`
+	spinsvc := test.ManifestToSpinService(s, t)
+	v := &orcaValidator{}
+
+	res := v.Validate(spinsvc, Options{Ctx: context.TODO()})
+
+	assert.False(t, res.HasFatalErrors())
+	assert.Empty(t, res.Errors)
+}
@@ -0,0 +1,171 @@
+package validate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/ghodss/yaml"
+)
+
+const (
+	// QuotaFileEnvVar points at the quota policy file, defaults to $OPERATOR_HOME/quotas.yml
+	QuotaFileEnvVar  = "OPERATOR_QUOTAS_FILE"
+	defaultQuotaFile = "quotas.yml"
+)
+
+// QuotaPolicy is the operator level policy limiting what teams may put in
+// their SpinnakerService. It is read from a file shipped with the operator,
+// not from the SpinnakerService itself, so tenants cannot lift their own limits.
+type QuotaPolicy struct {
+	Rules []QuotaRule `json:"rules,omitempty"`
+}
+
+// QuotaRule restricts the SpinnakerServices of the matching namespaces
+type QuotaRule struct {
+	// Namespaces the rule applies to, glob patterns, empty means all namespaces
+	Namespaces []string `json:"namespaces,omitempty"`
+	// DenyLoadBalancer forbids exposing Spinnaker through LoadBalancer services
+	DenyLoadBalancer bool `json:"denyLoadBalancer,omitempty"`
+	// AllowedVersions restricts the Spinnaker version to these glob patterns,
+	// empty means any version
+	AllowedVersions []string `json:"allowedVersions,omitempty"`
+	// DenyPlugins forbids installing plugins through service profiles
+	DenyPlugins bool `json:"denyPlugins,omitempty"`
+	// RequiredLabels must be present in spec.naming.labels so generated
+	// workloads carry them, e.g. team or cost-center
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+}
+
+// quotaValidator enforces the operator level quota policy
+type quotaValidator struct {
+	// policy overrides the file based policy in tests
+	policy *QuotaPolicy
+}
+
+func (v *quotaValidator) Validate(spinSvc interfaces.SpinnakerService, opts Options) ValidationResult {
+	policy := v.policy
+	if policy == nil {
+		p, err := loadQuotaPolicy()
+		if err != nil {
+			return NewResultFromError(fmt.Errorf("unable to read operator quota policy:\n  %w", err), true)
+		}
+		policy = p
+	}
+	if policy == nil {
+		return ValidationResult{}
+	}
+	ns := spinSvc.GetNamespace()
+	for _, rule := range policy.Rules {
+		if !rule.matches(ns) {
+			continue
+		}
+		if err := rule.check(spinSvc, opts, ns); err != nil {
+			return NewResultFromError(err, true)
+		}
+	}
+	return ValidationResult{}
+}
+
+// loadQuotaPolicy reads the policy file, a missing file means no policy
+func loadQuotaPolicy() (*QuotaPolicy, error) {
+	path := os.Getenv(QuotaFileEnvVar)
+	if path == "" {
+		home := os.Getenv("OPERATOR_HOME")
+		if home == "" {
+			return nil, nil
+		}
+		path = filepath.Join(home, defaultQuotaFile)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	p := &QuotaPolicy{}
+	if err := yaml.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *QuotaRule) matches(ns string) bool {
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range r.Namespaces {
+		if ok, _ := filepath.Match(pattern, ns); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *QuotaRule) check(spinSvc interfaces.SpinnakerService, opts Options, ns string) error {
+	if r.DenyLoadBalancer && usesLoadBalancer(spinSvc) {
+		return fmt.Errorf("namespace %s is not allowed to expose Spinnaker through a LoadBalancer, ask your operator administrator or use a different expose type", ns)
+	}
+	if len(r.AllowedVersions) > 0 {
+		version, _ := spinSvc.GetSpinnakerConfig().GetRawHalConfigPropString("version")
+		if version != "" && !matchesAny(version, r.AllowedVersions) {
+			return fmt.Errorf("namespace %s may only deploy Spinnaker versions matching [%s], got %s", ns, strings.Join(r.AllowedVersions, ", "), version)
+		}
+	}
+	if r.DenyPlugins {
+		if svc := firstServiceWithPlugins(spinSvc); svc != "" {
+			return fmt.Errorf("namespace %s is not allowed to install plugins, found plugins in the %s profile", ns, svc)
+		}
+	}
+	if len(r.RequiredLabels) > 0 {
+		missing := make([]string, 0)
+		for _, l := range r.RequiredLabels {
+			if spinSvc.GetNamingConfig().Labels[l] == "" {
+				missing = append(missing, l)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("namespace %s must set the labels [%s] under spec.naming.labels", ns, strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
+
+func usesLoadBalancer(spinSvc interfaces.SpinnakerService) bool {
+	exp := spinSvc.GetExposeConfig()
+	if strings.EqualFold(exp.Service.Type, "LoadBalancer") {
+		return true
+	}
+	for _, o := range exp.Service.Overrides {
+		if strings.EqualFold(o.Type, "LoadBalancer") {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(version string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, version); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// firstServiceWithPlugins returns a service whose profile declares plugins
+func firstServiceWithPlugins(spinSvc interfaces.SpinnakerService) string {
+	for svc, profile := range spinSvc.GetSpinnakerConfig().Profiles {
+		plugins, err := inspect.GetObjectProp(profile, "spinnaker.extensibility.plugins")
+		if err == nil && plugins.Kind() == reflect.Map && len(plugins.MapKeys()) > 0 {
+			return svc
+		}
+	}
+	return ""
+}
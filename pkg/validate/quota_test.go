@@ -0,0 +1,159 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func quotaSpinSvc(t *testing.T, manifest string) interfaces.SpinnakerService {
+	spinsvc := interfaces.DefaultTypesFactory.NewService()
+	assert.Nil(t, yaml.Unmarshal([]byte(manifest), spinsvc))
+	return spinsvc
+}
+
+func TestQuota_deniesLoadBalancer(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-dev
+spec:
+  expose:
+    type: service
+    service:
+      type: LoadBalancer
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`)
+	v := &quotaValidator{policy: &QuotaPolicy{Rules: []QuotaRule{
+		{Namespaces: []string{"team-*"}, DenyLoadBalancer: true},
+	}}}
+
+	r := v.Validate(spinsvc, Options{})
+	if assert.True(t, r.HasErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "team-dev is not allowed to expose Spinnaker through a LoadBalancer")
+	}
+}
+
+func TestQuota_restrictsVersions(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-dev
+spec:
+  spinnakerConfig:
+    config:
+      version: master-latest-unvalidated
+`)
+	v := &quotaValidator{policy: &QuotaPolicy{Rules: []QuotaRule{
+		{AllowedVersions: []string{"1.*"}},
+	}}}
+
+	r := v.Validate(spinsvc, Options{})
+	if assert.True(t, r.HasErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "may only deploy Spinnaker versions matching [1.*]")
+	}
+}
+
+func TestQuota_deniesPlugins(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-dev
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+    profiles:
+      gate:
+        spinnaker:
+          extensibility:
+            plugins:
+              Armory.MyPlugin:
+                enabled: true
+`)
+	v := &quotaValidator{policy: &QuotaPolicy{Rules: []QuotaRule{
+		{DenyPlugins: true},
+	}}}
+
+	r := v.Validate(spinsvc, Options{})
+	if assert.True(t, r.HasErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "not allowed to install plugins")
+		assert.Contains(t, r.GetErrorMessage(), "gate profile")
+	}
+}
+
+func TestQuota_otherNamespacesUnaffected(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: platform
+spec:
+  expose:
+    type: service
+    service:
+      type: LoadBalancer
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`)
+	v := &quotaValidator{policy: &QuotaPolicy{Rules: []QuotaRule{
+		{Namespaces: []string{"team-*"}, DenyLoadBalancer: true, AllowedVersions: []string{"1.16.*"}},
+	}}}
+
+	r := v.Validate(spinsvc, Options{})
+	assert.False(t, r.HasErrors())
+}
+
+func TestQuota_noPolicyFile(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-dev
+spec:
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`)
+	v := &quotaValidator{}
+	r := v.Validate(spinsvc, Options{})
+	assert.False(t, r.HasErrors())
+}
+
+func TestQuota_requiredLabels(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-dev
+spec:
+  naming:
+    labels:
+      team: delivery
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`)
+	v := &quotaValidator{policy: &QuotaPolicy{Rules: []QuotaRule{
+		{RequiredLabels: []string{"team", "cost-center"}},
+	}}}
+
+	r := v.Validate(spinsvc, Options{})
+	if assert.True(t, r.HasErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "must set the labels [cost-center] under spec.naming.labels")
+	}
+
+	v = &quotaValidator{policy: &QuotaPolicy{Rules: []QuotaRule{
+		{RequiredLabels: []string{"team"}},
+	}}}
+	r = v.Validate(spinsvc, Options{})
+	assert.False(t, r.HasErrors())
+}
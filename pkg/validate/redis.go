@@ -0,0 +1,40 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const redisPasswordSecretKey = "password"
+
+// redisValidator checks the Redis authentication and TLS settings before
+// deployment: the password secret must exist with the expected key, and TLS
+// needs an external Redis to point at
+type redisValidator struct{}
+
+func (v *redisValidator) Validate(spinSvc interfaces.SpinnakerService, options Options) ValidationResult {
+	r := spinSvc.GetRedisConfig()
+	if r.PasswordSecret == "" && !r.Tls {
+		return ValidationResult{}
+	}
+	if r.Tls {
+		base, _ := spinSvc.GetSpinnakerConfig().GetServiceSettingsPropString(options.Ctx, "redis", "baseUrl")
+		if base == "" {
+			return NewResultFromError(fmt.Errorf("redis TLS requires an external Redis configured through the \"baseUrl\" redis service setting"), true)
+		}
+	}
+	if r.PasswordSecret != "" && options.Client != nil {
+		sec := &v1.Secret{}
+		key := types.NamespacedName{Namespace: spinSvc.GetNamespace(), Name: r.PasswordSecret}
+		if err := options.Client.Get(options.Ctx, key, sec); err != nil {
+			return NewResultFromError(fmt.Errorf("unable to read Redis password secret %s: %w", r.PasswordSecret, err), true)
+		}
+		if len(sec.Data[redisPasswordSecretKey]) == 0 {
+			return NewResultFromError(fmt.Errorf("redis password secret %s has no %q key", r.PasswordSecret, redisPasswordSecretKey), true)
+		}
+	}
+	return ValidationResult{}
+}
@@ -0,0 +1,82 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRedis_missingPasswordSecret(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  redis:
+    passwordSecret: redis-auth
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`)
+	v := &redisValidator{}
+
+	r := v.Validate(spinsvc, Options{Ctx: context.TODO(), Client: test.FakeClient(t)})
+	if assert.True(t, r.HasFatalErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "unable to read Redis password secret redis-auth")
+	}
+}
+
+func TestRedis_secretMustHavePasswordKey(t *testing.T) {
+	sec := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "redis-auth", Namespace: "ns1"},
+		Data:       map[string][]byte{"pass": []byte("secret")},
+	}
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  redis:
+    passwordSecret: redis-auth
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`)
+	v := &redisValidator{}
+
+	r := v.Validate(spinsvc, Options{Ctx: context.TODO(), Client: test.FakeClient(t, sec)})
+	if assert.True(t, r.HasFatalErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "has no \"password\" key")
+	}
+
+	sec.Data["password"] = []byte("secret")
+	r = v.Validate(spinsvc, Options{Ctx: context.TODO(), Client: test.FakeClient(t, sec)})
+	assert.False(t, r.HasErrors())
+}
+
+func TestRedis_tlsRequiresExternalRedis(t *testing.T) {
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  redis:
+    tls: true
+  spinnakerConfig:
+    config:
+      version: 1.17.1
+`)
+	v := &redisValidator{}
+
+	r := v.Validate(spinsvc, Options{Ctx: context.TODO()})
+	if assert.True(t, r.HasFatalErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "redis TLS requires an external Redis")
+	}
+}
@@ -0,0 +1,39 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// targetNamespaceValidator guards the generated deployment names: two
+// SpinnakerServices resolving to the same deploy namespace would both own
+// spin-* deployments and clobber each other. Instances in separate namespaces
+// are independent and allowed
+type targetNamespaceValidator struct{}
+
+func (v *targetNamespaceValidator) Validate(spinSvc interfaces.SpinnakerService, opts Options) ValidationResult {
+	ns := effectiveDeployNamespace(spinSvc)
+	ss := opts.TypesFactory.NewServiceList()
+	if err := opts.Client.List(opts.Ctx, ss); err != nil {
+		return NewResultFromError(fmt.Errorf("Target namespace validator detected an error:\n  %w", err), true)
+	}
+	for _, other := range ss.GetItems() {
+		if other.GetNamespace() == spinSvc.GetNamespace() && other.GetName() == spinSvc.GetName() {
+			continue
+		}
+		if effectiveDeployNamespace(other) == ns {
+			return NewResultFromError(fmt.Errorf("SpinnakerService %s/%s already deploys to namespace %s, the generated spin-* deployments would collide", other.GetNamespace(), other.GetName(), ns), true)
+		}
+	}
+	return ValidationResult{}
+}
+
+// effectiveDeployNamespace is where the generated manifests end up: the
+// configured target namespace, or the namespace of the SpinnakerService itself
+func effectiveDeployNamespace(svc interfaces.SpinnakerService) string {
+	if n := svc.GetTargetNamespaceConfig().Name; n != "" {
+		return n
+	}
+	return svc.GetNamespace()
+}
@@ -0,0 +1,86 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func init() {
+	_ = v1alpha2.SchemeBuilder.AddToScheme(scheme.Scheme)
+}
+
+func TestTargetNamespace_independentNamespacesAllowed(t *testing.T) {
+	existing := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-a
+spec: {}
+`)
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-b
+spec: {}
+`)
+	v := &targetNamespaceValidator{}
+	r := v.Validate(spinsvc, Options{
+		Ctx:          context.TODO(),
+		Client:       test.FakeClient(t, existing),
+		TypesFactory: test.TypesFactory,
+	})
+	assert.False(t, r.HasErrors())
+}
+
+func TestTargetNamespace_collisionDenied(t *testing.T) {
+	existing := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-a
+spec:
+  targetNamespace:
+    name: spinnaker-prod
+`)
+	spinsvc := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-b
+spec:
+  targetNamespace:
+    name: spinnaker-prod
+`)
+	v := &targetNamespaceValidator{}
+	r := v.Validate(spinsvc, Options{
+		Ctx:          context.TODO(),
+		Client:       test.FakeClient(t, existing),
+		TypesFactory: test.TypesFactory,
+	})
+	if assert.True(t, r.HasErrors()) {
+		assert.Contains(t, r.GetErrorMessage(), "spin-* deployments would collide")
+	}
+}
+
+func TestTargetNamespace_updateOfItselfAllowed(t *testing.T) {
+	existing := quotaSpinSvc(t, `
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: team-a
+spec: {}
+`)
+	v := &targetNamespaceValidator{}
+	r := v.Validate(existing, Options{
+		Ctx:          context.TODO(),
+		Client:       test.FakeClient(t, existing),
+		TypesFactory: test.TypesFactory,
+	})
+	assert.False(t, r.HasErrors())
+}
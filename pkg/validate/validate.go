@@ -16,6 +16,8 @@ import (
 var ParallelValidators = []SpinnakerValidator{
 	&versionValidator{},
 	&dockerRegistryValidator{},
+	&redisValidator{},
+	&orcaValidator{},
 }
 
 type SpinnakerValidator interface {
@@ -50,6 +52,19 @@ func ValidateAll(spinSvc interfaces.SpinnakerService, options Options) Validatio
 	if r.Fatal {
 		return r
 	}
+	t := &targetNamespaceValidator{}
+	if r = t.Validate(spinSvc, options); r.Fatal {
+		return r
+	}
+	// Operator level guardrails run before anything expensive
+	q := &quotaValidator{}
+	if r = q.Validate(spinSvc, options); r.Fatal {
+		return r
+	}
+	e := &egressValidator{}
+	if r = e.Validate(spinSvc, options); r.Fatal {
+		return r
+	}
 	vs, err := generateParallelValidators(spinSvc, options)
 	if err != nil {
 		return NewResultFromError(err, true)